@@ -0,0 +1,73 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package chiadapter connects SuperTokens to chi:
+//
+//	router := chi.NewRouter()
+//	router.Use(chiadapter.Middleware(nil))
+//	router.With(chiadapter.VerifySession(nil)).Get("/sessioninfo", chiadapter.Handle(func(w http.ResponseWriter, r *http.Request) error {
+//		sessionContainer := chiadapter.GetSession(r)
+//		...
+//	}))
+package chiadapter
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// Middleware serves the SuperTokens APIs, and passes every other request on to the next handler. It
+// uses instance, or the one created by supertokens.Init if instance is nil.
+func Middleware(instance *supertokens.Instance) func(http.Handler) http.Handler {
+	if instance == nil {
+		return supertokens.Middleware
+	}
+	return instance.Middleware
+}
+
+// VerifySession is session.VerifySession as a chi middleware. The session is stored in the context of
+// the request (see GetSession) before the next handler is called.
+func VerifySession(options *sessmodels.VerifySessionOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return session.VerifySession(options, next.ServeHTTP)
+	}
+}
+
+// GetSession returns the session that was verified by VerifySession, or nil if there is none (for
+// example if SessionRequired is false in the options and there is no session).
+func GetSession(r *http.Request) sessmodels.SessionContainer {
+	return session.GetSessionFromRequestContext(r.Context())
+}
+
+// HandlerFunc is an http.HandlerFunc that can return an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle converts handler to an http.HandlerFunc. The response for SuperTokens errors returned by the
+// handler (for example the ones returned by session.GetSession) is sent using supertokens.ErrorHandler,
+// and other errors are sent as a 500 response.
+func Handle(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := handler(w, r)
+		if err == nil {
+			return
+		}
+		if err = supertokens.ErrorHandler(err, r, w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
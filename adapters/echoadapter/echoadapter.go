@@ -0,0 +1,106 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package echoadapter connects SuperTokens to echo:
+//
+//	e := echo.New()
+//	e.HTTPErrorHandler = echoadapter.HTTPErrorHandler(e.DefaultHTTPErrorHandler)
+//	e.Use(echoadapter.Middleware(nil))
+//	e.GET("/sessioninfo", func(c echo.Context) error {
+//		sessionContainer := echoadapter.GetSession(c)
+//		...
+//	}, echoadapter.VerifySession(nil))
+package echoadapter
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// SessionKey is the key of the session in the echo context.
+const SessionKey = "supertokens.session"
+
+// Middleware serves the SuperTokens APIs, and passes every other request on to the next handler. It
+// uses instance, or the one created by supertokens.Init if instance is nil.
+func Middleware(instance *supertokens.Instance) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var nextErr error
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// the request has the SuperTokens instance in its context, which the recipes use
+				c.SetRequest(r)
+				nextErr = next(c)
+			})
+			if instance == nil {
+				supertokens.Middleware(handler).ServeHTTP(c.Response(), c.Request())
+			} else {
+				instance.Middleware(handler).ServeHTTP(c.Response(), c.Request())
+			}
+			return nextErr
+		}
+	}
+}
+
+// VerifySession is session.VerifySession as an echo middleware. The session is stored in the echo
+// context (see GetSession) before the next handler is called. If the session can't be verified, the
+// response is sent using supertokens.ErrorHandler.
+func VerifySession(options *sessmodels.VerifySessionOptions) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var nextErr error
+			session.VerifySession(options, func(w http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				if sessionContainer := session.GetSessionFromRequestContext(r.Context()); sessionContainer != nil {
+					c.Set(SessionKey, sessionContainer)
+				}
+				nextErr = next(c)
+			}).ServeHTTP(c.Response(), c.Request())
+			return nextErr
+		}
+	}
+}
+
+// GetSession returns the session that was verified by VerifySession, or nil if there is none (for
+// example if SessionRequired is false in the options and there is no session).
+func GetSession(c echo.Context) sessmodels.SessionContainer {
+	sessionContainer, ok := c.Get(SessionKey).(sessmodels.SessionContainer)
+	if !ok {
+		return nil
+	}
+	return sessionContainer
+}
+
+// HTTPErrorHandler sends the response for SuperTokens errors returned by handlers (for example the ones
+// returned by session.GetSession), and calls next for all other errors. next defaults to echo's
+// DefaultHTTPErrorHandler.
+func HTTPErrorHandler(next echo.HTTPErrorHandler) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		if supertokens.ErrorHandler(err, c.Request(), c.Response()) == nil {
+			return
+		}
+		if next == nil {
+			c.Echo().DefaultHTTPErrorHandler(err, c)
+			return
+		}
+		next(err, c)
+	}
+}
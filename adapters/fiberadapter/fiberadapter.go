@@ -0,0 +1,141 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package fiberadapter connects SuperTokens to fiber. Since fiber is built on fasthttp, the requests
+// are converted to net/http requests for SuperTokens, and the responses are copied back:
+//
+//	app := fiber.New(fiber.Config{
+//		ErrorHandler: fiberadapter.ErrorHandler(nil),
+//	})
+//	app.Use(fiberadapter.Middleware(nil))
+//	app.Get("/sessioninfo", fiberadapter.VerifySession(nil), func(c *fiber.Ctx) error {
+//		sessionContainer := fiberadapter.GetSession(c)
+//		...
+//	})
+package fiberadapter
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// SessionKey is the key of the session in the locals of the fiber context.
+const SessionKey = "supertokens.session"
+
+// instanceKey is the key of the instance that Middleware was created with in the locals of the fiber
+// context. It is needed since every handler gets a new net/http request.
+const instanceKey = "supertokens.instance"
+
+// Middleware serves the SuperTokens APIs, and passes every other request on to the next handler. It
+// uses instance, or the one created by supertokens.Init if instance is nil.
+func Middleware(instance *supertokens.Instance) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		calledNext := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calledNext = true
+		})
+		var middleware http.Handler
+		if instance == nil {
+			middleware = supertokens.Middleware(handler)
+		} else {
+			middleware = instance.Middleware(handler)
+		}
+		if err := adaptor.HTTPHandler(middleware)(c); err != nil {
+			return err
+		}
+		if !calledNext {
+			return nil
+		}
+		if instance != nil {
+			c.Locals(instanceKey, instance)
+		}
+		return c.Next()
+	}
+}
+
+// withInstance adds the instance that Middleware was created with (if any) to the context of the
+// request, so that the recipes use it
+func withInstance(c *fiber.Ctx, handler http.HandlerFunc) http.Handler {
+	instance, ok := c.Locals(instanceKey).(*supertokens.Instance)
+	if !ok {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, supertokens.SetInstanceInRequest(r, instance))
+	})
+}
+
+// VerifySession is session.VerifySession as a fiber handler. The session is stored in the locals of
+// the fiber context (see GetSession) before the next handler is called. If the session can't be
+// verified, the response is sent using supertokens.ErrorHandler.
+func VerifySession(options *sessmodels.VerifySessionOptions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		calledNext := false
+		var sessionContainer sessmodels.SessionContainer
+		handler := session.VerifySession(options, func(w http.ResponseWriter, r *http.Request) {
+			calledNext = true
+			sessionContainer = session.GetSessionFromRequestContext(r.Context())
+		})
+		if err := adaptor.HTTPHandler(withInstance(c, handler))(c); err != nil {
+			return err
+		}
+		if !calledNext {
+			return nil
+		}
+		if sessionContainer != nil {
+			c.Locals(SessionKey, sessionContainer)
+		}
+		return c.Next()
+	}
+}
+
+// GetSession returns the session that was verified by VerifySession, or nil if there is none (for
+// example if SessionRequired is false in the options and there is no session).
+func GetSession(c *fiber.Ctx) sessmodels.SessionContainer {
+	sessionContainer, ok := c.Locals(SessionKey).(sessmodels.SessionContainer)
+	if !ok {
+		return nil
+	}
+	return sessionContainer
+}
+
+// ErrorHandler sends the response for SuperTokens errors returned by handlers (for example the ones
+// returned by session.GetSession), and calls next for all other errors. next defaults to
+// fiber.DefaultErrorHandler.
+func ErrorHandler(next fiber.ErrorHandler) fiber.ErrorHandler {
+	if next == nil {
+		next = fiber.DefaultErrorHandler
+	}
+	return func(c *fiber.Ctx, err error) error {
+		handled := true
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if supertokens.ErrorHandler(err, r, w) != nil {
+				handled = false
+			}
+		})
+		if handlerErr := adaptor.HTTPHandler(withInstance(c, handler))(c); handlerErr != nil {
+			return handlerErr
+		}
+		if handled {
+			return nil
+		}
+		return next(c, err)
+	}
+}
@@ -0,0 +1,102 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package fiberadapter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newApp(instance *supertokens.Instance) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: ErrorHandler(nil),
+	})
+	app.Use(Middleware(instance))
+	app.Get("/sessioninfo", VerifySession(nil), func(c *fiber.Ctx) error {
+		return c.SendString(GetSession(c).GetUserID())
+	})
+	app.Get("/error", func(c *fiber.Ctx) error {
+		return errors.UnauthorizedError{Msg: "unauthorised"}
+	})
+	app.Get("/teapot", func(c *fiber.Ctx) error {
+		return fiber.NewError(http.StatusTeapot)
+	})
+	return app
+}
+
+func send(t *testing.T, app *fiber.App, req *http.Request) (*http.Response, string) {
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return res, string(body)
+}
+
+func TestThatTheSuperTokensAPIsAreServed(t *testing.T) {
+	app := newApp(coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)))
+
+	body := `{"formFields":[{"id":"email","value":"test@example.com"},{"id":"password","value":"password123"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", strings.NewReader(body))
+	req.Header.Set("st-auth-mode", "header")
+	res, resBody := send(t, app, req)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Contains(t, resBody, `"status":"OK"`)
+	accessToken := res.Header.Get("st-access-token")
+	assert.NotEmpty(t, accessToken)
+
+	req = httptest.NewRequest(http.MethodGet, "/sessioninfo", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	res, resBody = send(t, app, req)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NotEmpty(t, resBody)
+}
+
+func TestThatVerifySessionRejectsRequestsWithoutASession(t *testing.T) {
+	app := newApp(coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)))
+
+	res, resBody := send(t, app, httptest.NewRequest(http.MethodGet, "/sessioninfo", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Contains(t, resBody, "unauthorised")
+}
+
+func TestThatErrorHandlerSendsTheResponseForSuperTokensErrors(t *testing.T) {
+	app := newApp(coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)))
+
+	res, _ := send(t, app, httptest.NewRequest(http.MethodGet, "/error", nil))
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// other errors are handled by fiber
+	res, _ = send(t, app, httptest.NewRequest(http.MethodGet, "/teapot", nil))
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+}
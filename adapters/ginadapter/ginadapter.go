@@ -0,0 +1,109 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package ginadapter connects SuperTokens to gin:
+//
+//	router := gin.New()
+//	router.Use(ginadapter.Middleware(nil), ginadapter.ErrorHandler())
+//	router.GET("/sessioninfo", ginadapter.VerifySession(nil), func(c *gin.Context) {
+//		sessionContainer := ginadapter.GetSession(c)
+//		...
+//	})
+package ginadapter
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// SessionKey is the key of the session in the gin context.
+const SessionKey = "supertokens.session"
+
+// Middleware serves the SuperTokens APIs, and passes every other request on to the next handler. It
+// uses instance, or the one created by supertokens.Init if instance is nil.
+func Middleware(instance *supertokens.Instance) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		calledNext := false
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calledNext = true
+			// the request has the SuperTokens instance in its context, which the recipes use
+			c.Request = r
+			c.Next()
+		})
+		if instance == nil {
+			supertokens.Middleware(handler).ServeHTTP(c.Writer, c.Request)
+		} else {
+			instance.Middleware(handler).ServeHTTP(c.Writer, c.Request)
+		}
+		if !calledNext {
+			c.Abort()
+		}
+	}
+}
+
+// VerifySession is session.VerifySession as a gin handler. The session is stored in the gin context
+// (see GetSession) before the next handler is called. If the session can't be verified, the response
+// is sent using supertokens.ErrorHandler and the chain is aborted.
+func VerifySession(options *sessmodels.VerifySessionOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		calledNext := false
+		session.VerifySession(options, func(w http.ResponseWriter, r *http.Request) {
+			calledNext = true
+			c.Request = r
+			if sessionContainer := session.GetSessionFromRequestContext(r.Context()); sessionContainer != nil {
+				c.Set(SessionKey, sessionContainer)
+			}
+			c.Next()
+		}).ServeHTTP(c.Writer, c.Request)
+		if !calledNext {
+			c.Abort()
+		}
+	}
+}
+
+// GetSession returns the session that was verified by VerifySession, or nil if there is none (for
+// example if SessionRequired is false in the options and there is no session).
+func GetSession(c *gin.Context) sessmodels.SessionContainer {
+	value, ok := c.Get(SessionKey)
+	if !ok {
+		return nil
+	}
+	sessionContainer, ok := value.(sessmodels.SessionContainer)
+	if !ok {
+		return nil
+	}
+	return sessionContainer
+}
+
+// ErrorHandler sends the response for SuperTokens errors that the next handlers added to the gin
+// context using c.Error (for example the ones returned by session.GetSession). Other errors are left in
+// c.Errors.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		if supertokens.ErrorHandler(err, c.Request, c.Writer) != nil {
+			return
+		}
+		c.Errors = c.Errors[:len(c.Errors)-1]
+	}
+}
@@ -0,0 +1,86 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package ginadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newRouter(instance *supertokens.Instance) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Middleware(instance), ErrorHandler())
+	router.GET("/sessioninfo", VerifySession(nil), func(c *gin.Context) {
+		c.String(http.StatusOK, GetSession(c).GetUserID())
+	})
+	router.GET("/error", func(c *gin.Context) {
+		c.Error(errors.UnauthorizedError{Msg: "unauthorised"})
+	})
+	return router
+}
+
+func TestThatTheSuperTokensAPIsAreServed(t *testing.T) {
+	router := newRouter(coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)))
+
+	body := `{"formFields":[{"id":"email","value":"test@example.com"},{"id":"password","value":"password123"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", strings.NewReader(body))
+	req.Header.Set("st-auth-mode", "header")
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"status":"OK"`)
+	accessToken := res.Header().Get("st-access-token")
+	assert.NotEmpty(t, accessToken)
+
+	req = httptest.NewRequest(http.MethodGet, "/sessioninfo", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	res = httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.NotEmpty(t, res.Body.String())
+}
+
+func TestThatVerifySessionRejectsRequestsWithoutASession(t *testing.T) {
+	router := newRouter(coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)))
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/sessioninfo", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+	assert.Contains(t, res.Body.String(), "unauthorised")
+}
+
+func TestThatErrorHandlerSendsTheResponseForSuperTokensErrors(t *testing.T) {
+	router := newRouter(coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)))
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/error", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
@@ -0,0 +1,97 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package gozeroadapter connects SuperTokens to go-zero. go-zero only runs middlewares for the routes
+// that are added to the server, so the SuperTokens APIs are served by the not found handler instead:
+//
+//	server := rest.MustNewServer(config, rest.WithNotFoundHandler(gozeroadapter.NotFoundHandler(nil, nil)))
+//	server.Use(gozeroadapter.Middleware(nil))
+//	server.AddRoutes(rest.WithMiddleware(gozeroadapter.VerifySession(nil), rest.Route{
+//		Method: http.MethodGet,
+//		Path:   "/sessioninfo",
+//		Handler: gozeroadapter.Handle(func(w http.ResponseWriter, r *http.Request) error {
+//			sessionContainer := gozeroadapter.GetSession(r)
+//			...
+//		}),
+//	}))
+//
+// The middlewares have the same type as rest.Middleware, so this package does not depend on go-zero.
+package gozeroadapter
+
+import (
+	"net/http"
+
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func superTokensMiddleware(instance *supertokens.Instance, next http.Handler) http.Handler {
+	if instance == nil {
+		return supertokens.Middleware(next)
+	}
+	return instance.Middleware(next)
+}
+
+// NotFoundHandler serves the SuperTokens APIs, and passes every other request on to next (or
+// http.NotFoundHandler if next is nil). It uses instance, or the one created by supertokens.Init if
+// instance is nil.
+func NotFoundHandler(instance *supertokens.Instance, next http.Handler) http.Handler {
+	if next == nil {
+		next = http.NotFoundHandler()
+	}
+	return superTokensMiddleware(instance, next)
+}
+
+// Middleware adds instance (or the one created by supertokens.Init if instance is nil) to the requests
+// for the routes of the server, so that the recipes use it. It also serves the SuperTokens APIs if
+// routes are added for them.
+func Middleware(instance *supertokens.Instance) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return superTokensMiddleware(instance, next).ServeHTTP
+	}
+}
+
+// VerifySession is session.VerifySession as a go-zero middleware. The session is stored in the context
+// of the request (see GetSession) before the next handler is called.
+func VerifySession(options *sessmodels.VerifySessionOptions) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return session.VerifySession(options, next)
+	}
+}
+
+// GetSession returns the session that was verified by VerifySession, or nil if there is none (for
+// example if SessionRequired is false in the options and there is no session).
+func GetSession(r *http.Request) sessmodels.SessionContainer {
+	return session.GetSessionFromRequestContext(r.Context())
+}
+
+// HandlerFunc is an http.HandlerFunc that can return an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handle converts handler to an http.HandlerFunc. The response for SuperTokens errors returned by the
+// handler (for example the ones returned by session.GetSession) is sent using supertokens.ErrorHandler,
+// and other errors are sent as a 500 response.
+func Handle(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := handler(w, r)
+		if err == nil {
+			return
+		}
+		if err = supertokens.ErrorHandler(err, r, w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
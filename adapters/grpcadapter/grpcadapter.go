@@ -0,0 +1,221 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package grpcadapter verifies sessions for gRPC servers. The access token is read from the
+// authorization metadata of the call (as "Bearer <token>", like with header based token transfer), so
+// the tokens must be created and refreshed using the header based token transfer method:
+//
+//	server := grpc.NewServer(
+//		grpc.UnaryInterceptor(grpcadapter.UnaryServerInterceptor(nil)),
+//		grpc.StreamInterceptor(grpcadapter.StreamServerInterceptor(nil)),
+//	)
+//
+// The session is added to the context of the call, see GetSession.
+package grpcadapter
+
+import (
+	"context"
+	"encoding/json"
+	defaultErrors "errors"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details of the errors returned by the interceptors. The
+// reason is the type of the error (e.g. errors.TryRefreshTokenErrorStr).
+const ErrorDomain = "supertokens.com"
+
+// Config is the configuration of the interceptors.
+type Config struct {
+	// Instance is the SuperTokens instance that sessions are verified with. If nil, the instance
+	// created by supertokens.Init is used.
+	Instance *supertokens.Instance
+
+	// Options are used to verify the session for the methods that are not in MethodOptions.
+	Options *sessmodels.VerifySessionOptions
+
+	// MethodOptions are used to verify the session for specific methods. The keys are the full
+	// method names, e.g. "/package.Service/Method".
+	MethodOptions map[string]*sessmodels.VerifySessionOptions
+
+	// ShouldVerifySession returns whether the session is verified for a method (with its full method
+	// name). Sessions are verified for all methods if nil.
+	ShouldVerifySession func(fullMethod string) bool
+}
+
+// UnaryServerInterceptor verifies the session (including the global claim validators) before the
+// handler of a unary call is called.
+func UnaryServerInterceptor(config *Config) grpc.UnaryServerInterceptor {
+	if config == nil {
+		config = &Config{}
+	}
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := verifySession(ctx, config, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor verifies the session (including the global claim validators) before the
+// handler of a streaming call is called.
+func StreamServerInterceptor(config *Config) grpc.StreamServerInterceptor {
+	if config == nil {
+		config = &Config{}
+	}
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := verifySession(stream.Context(), config, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// GetSession returns the session that was verified by the interceptors, or nil if there is none (for
+// example if SessionRequired is false in the options and there is no session).
+func GetSession(ctx context.Context) sessmodels.SessionContainer {
+	return session.GetSessionFromRequestContext(ctx)
+}
+
+// StatusError converts the session errors (errors.TryRefreshTokenError, errors.UnauthorizedError,
+// errors.TokenTheftDetectedError and errors.InvalidClaimError) to a gRPC status error with an ErrorInfo
+// detail. The validation errors of the claims are in the "claimValidationErrors" metadata of the
+// ErrorInfo, as JSON. The reason of the ErrorInfo is the error code of the error (see supertokens.GetErrorCode),
+// and wrapped errors are converted as well. Other errors are returned as is.
+func StatusError(err error) error {
+	var code codes.Code
+	errorMetadata := map[string]string{}
+	var tokenTheftErr errors.TokenTheftDetectedError
+	var invalidClaimErr errors.InvalidClaimError
+	if defaultErrors.Is(err, errors.ErrTryRefreshToken) || defaultErrors.Is(err, errors.ErrUnauthorised) {
+		code = codes.Unauthenticated
+	} else if defaultErrors.As(err, &tokenTheftErr) {
+		code = codes.Unauthenticated
+		errorMetadata["sessionHandle"] = tokenTheftErr.Payload.SessionHandle
+		errorMetadata["userId"] = tokenTheftErr.Payload.UserID
+	} else if defaultErrors.As(err, &invalidClaimErr) {
+		code = codes.PermissionDenied
+		claimValidationErrors, jsonErr := json.Marshal(invalidClaimErr.InvalidClaims)
+		if jsonErr != nil {
+			return jsonErr
+		}
+		errorMetadata["claimValidationErrors"] = string(claimValidationErrors)
+	} else {
+		return err
+	}
+	reason := string(supertokens.GetErrorCode(err))
+	st, detailsErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   ErrorDomain,
+		Metadata: errorMetadata,
+	})
+	if detailsErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+func verifySession(ctx context.Context, config *Config, fullMethod string) (context.Context, error) {
+	if config.Instance != nil {
+		ctx = supertokens.SetInstanceInContext(ctx, config.Instance)
+	}
+	if config.ShouldVerifySession != nil && !config.ShouldVerifySession(fullMethod) {
+		return ctx, nil
+	}
+
+	options := getOptions(config, fullMethod)
+	userContext := supertokens.SetContextInUserContext(nil, ctx)
+
+	accessToken := getAccessToken(ctx)
+	if accessToken == "" {
+		if !*options.SessionRequired {
+			return ctx, nil
+		}
+		return nil, StatusError(errors.UnauthorizedError{
+			Msg: "Session does not exist. Are you sending the access token in the authorization metadata?",
+		})
+	}
+
+	sessionContainer, err := session.GetSessionWithoutRequestResponse(accessToken, nil, options, userContext)
+	if err != nil {
+		err = StatusError(err)
+		if _, ok := status.FromError(err); !ok {
+			supertokens.LogError(userContext, "grpcadapter: verifying the session failed", "method", fullMethod, "error", err.Error())
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return nil, err
+	}
+	if sessionContainer == nil {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, sessmodels.SessionContext, sessionContainer), nil
+}
+
+// getOptions returns a copy of the options for the method, with the defaults for gRPC calls: the
+// session is required, and there is no anti-csrf check since the tokens are not sent as cookies.
+func getOptions(config *Config, fullMethod string) *sessmodels.VerifySessionOptions {
+	options := config.Options
+	if methodOptions, ok := config.MethodOptions[fullMethod]; ok {
+		options = methodOptions
+	}
+	result := sessmodels.VerifySessionOptions{}
+	if options != nil {
+		result = *options
+	}
+	if result.SessionRequired == nil {
+		True := true
+		result.SessionRequired = &True
+	}
+	if result.AntiCsrfCheck == nil {
+		False := false
+		result.AntiCsrfCheck = &False
+	}
+	return &result
+}
+
+func getAccessToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		value = strings.TrimSpace(value)
+		if len(value) > len("bearer ") && strings.EqualFold(value[:len("bearer ")], "bearer ") {
+			return strings.TrimSpace(value[len("bearer "):])
+		}
+	}
+	return ""
+}
+
+// serverStream is a grpc.ServerStream with the context that has the session in it
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,221 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package grpcadapter
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newAccessToken(t *testing.T, instance *supertokens.Instance) string {
+	userContext := supertokens.SetInstanceInUserContext(nil, instance)
+	sessionContainer, err := session.CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return sessionContainer.GetAccessToken()
+}
+
+// healthServer answers with the user ID of the session (if any) in the trailer
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func setUserIDTrailer(ctx context.Context) {
+	userID := ""
+	if sessionContainer := GetSession(ctx); sessionContainer != nil {
+		userID = sessionContainer.GetUserIDWithContext(supertokens.SetContextInUserContext(nil, ctx))
+	}
+	grpc.SetTrailer(ctx, metadata.Pairs("user-id", userID))
+}
+
+func (s *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	setUserIDTrailer(ctx)
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func (s *healthServer) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	setUserIDTrailer(stream.Context())
+	return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING})
+}
+
+func newClient(t *testing.T, config *Config) grpc_health_v1.HealthClient {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(config)),
+		grpc.StreamInterceptor(StreamServerInterceptor(config)),
+	)
+	grpc_health_v1.RegisterHealthServer(server, &healthServer{})
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func withAccessToken(accessToken string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+accessToken)
+}
+
+func getErrorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("not a status error: %v", err)
+	}
+	for _, detail := range st.Details() {
+		if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok {
+			return errorInfo
+		}
+	}
+	t.Fatal("no ErrorInfo in the status")
+	return nil
+}
+
+func TestThatTheSessionIsAddedToTheContextOfUnaryCalls(t *testing.T) {
+	instance := coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil))
+	client := newClient(t, &Config{Instance: instance})
+
+	var trailer metadata.MD
+	_, err := client.Check(withAccessToken(newAccessToken(t, instance)), &grpc_health_v1.HealthCheckRequest{}, grpc.Trailer(&trailer))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"userId"}, trailer.Get("user-id"))
+}
+
+func TestThatTheSessionIsAddedToTheContextOfStreamingCalls(t *testing.T) {
+	instance := coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil))
+	client := newClient(t, &Config{Instance: instance})
+
+	stream, err := client.Watch(withAccessToken(newAccessToken(t, instance)), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"userId"}, stream.Trailer().Get("user-id"))
+}
+
+func TestThatCallsWithoutASessionAreUnauthenticated(t *testing.T) {
+	client := newClient(t, &Config{Instance: coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil))})
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.UnauthorizedErrorStr, getErrorInfo(t, err).Reason)
+
+	_, err = client.Check(withAccessToken("invalid"), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.UnauthorizedErrorStr, getErrorInfo(t, err).Reason)
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestThatTheOptionsOfTheMethodAreUsed(t *testing.T) {
+	False := false
+	client := newClient(t, &Config{
+		Instance: coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)),
+		MethodOptions: map[string]*sessmodels.VerifySessionOptions{
+			grpc_health_v1.Health_Check_FullMethodName: {
+				SessionRequired: &False,
+			},
+		},
+	})
+
+	var trailer metadata.MD
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{}, grpc.Trailer(&trailer))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, trailer.Get("user-id"))
+
+	// the session is still required for the other methods
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestThatShouldVerifySessionSkipsMethods(t *testing.T) {
+	client := newClient(t, &Config{
+		Instance: coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil)),
+		ShouldVerifySession: func(fullMethod string) bool {
+			return fullMethod != grpc_health_v1.Health_Check_FullMethodName
+		},
+	})
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+}
+
+func TestThatInvalidClaimsArePermissionDenied(t *testing.T) {
+	instance := coreemulator.NewTestInstance(t, emailpassword.Init(nil), session.Init(nil))
+	client := newClient(t, &Config{
+		Instance: instance,
+		Options: &sessmodels.VerifySessionOptions{
+			OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+				return append(globalClaimValidators, claims.SessionClaimValidator{
+					ID: "test-claim",
+					Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+						return claims.ClaimValidationResult{IsValid: false, Reason: "wrong value"}
+					},
+				}), nil
+			},
+		},
+	})
+
+	_, err := client.Check(withAccessToken(newAccessToken(t, instance)), &grpc_health_v1.HealthCheckRequest{})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	errorInfo := getErrorInfo(t, err)
+	assert.Equal(t, errors.InvalidClaimsErrorStr, errorInfo.Reason)
+	assert.Equal(t, ErrorDomain, errorInfo.Domain)
+	assert.JSONEq(t, `[{"id":"test-claim","reason":"wrong value"}]`, errorInfo.Metadata["claimValidationErrors"])
+}
+
+func TestThatStatusErrorConvertsSessionErrors(t *testing.T) {
+	err := StatusError(errors.TryRefreshTokenError{Msg: "try refresh token"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, errors.TryRefreshTokenErrorStr, getErrorInfo(t, err).Reason)
+
+	otherErr := context.Canceled
+	assert.Equal(t, otherErr, StatusError(otherErr))
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/supertokens/supertokens-golang/recipe/dashboard"
 
@@ -25,6 +24,8 @@ func main() {
 			APIDomain:     "http://localhost:3001",
 			WebsiteDomain: "http://localhost:3000",
 		},
+		// the middleware answers preflight requests from the website, and adds the CORS headers to all responses
+		CORS: &supertokens.CORSConfig{},
 		RecipeList: []supertokens.Recipe{
 			emailverification.Init(evmodels.TypeInput{
 				Mode: evmodels.ModeRequired,
@@ -159,29 +160,14 @@ func main() {
 		panic(err.Error())
 	}
 
-	http.ListenAndServe(":3001", corsMiddleware(
-		supertokens.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-			// Handle your APIs..
+	http.ListenAndServe(":3001", supertokens.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// Handle your APIs..
 
-			if r.URL.Path == "/sessioninfo" {
-				session.VerifySession(nil, sessioninfo).ServeHTTP(rw, r)
-				return
-			}
-		}))))
-}
-
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(response http.ResponseWriter, r *http.Request) {
-		response.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
-		response.Header().Set("Access-Control-Allow-Credentials", "true")
-		if r.Method == "OPTIONS" {
-			response.Header().Set("Access-Control-Allow-Headers", strings.Join(append([]string{"Content-Type"}, supertokens.GetAllCORSHeaders()...), ","))
-			response.Header().Set("Access-Control-Allow-Methods", "*")
-			response.Write([]byte(""))
-		} else {
-			next.ServeHTTP(response, r)
+		if r.URL.Path == "/sessioninfo" {
+			session.VerifySession(nil, sessioninfo).ServeHTTP(rw, r)
+			return
 		}
-	})
+	})))
 }
 
 func sessioninfo(w http.ResponseWriter, r *http.Request) {
@@ -20,6 +20,8 @@ import (
 	"crypto/tls"
 	"fmt"
 
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/gomail.v2"
 )
 
@@ -37,9 +39,38 @@ func MakeIngredient(config TypeInputWithService) Ingredient {
 		result.IngredientInterfaceImpl = config.Override(result.IngredientInterfaceImpl)
 	}
 
+	if result.IngredientInterfaceImpl.SendEmail != nil {
+		sendEmail := *result.IngredientInterfaceImpl.SendEmail
+		instrumentedSendEmail := func(input EmailType, userContext supertokens.UserContext) error {
+			emailType := getEmailTypeName(input)
+			span := supertokens.StartSpan(userContext, "emaildelivery.SendEmail", attribute.String("supertokens.email_type", emailType))
+			err := sendEmail(input, userContext)
+			span.End(err)
+			if err != nil {
+				supertokens.LogError(userContext, "emaildelivery: failed to send email", "emailType", emailType, "error", err)
+				supertokens.IncrementCounterMetric(supertokens.MetricEmailSendFailures, map[string]string{
+					"email_type": emailType,
+				})
+			}
+			return err
+		}
+		result.IngredientInterfaceImpl.SendEmail = &instrumentedSendEmail
+	}
+
 	return result
 }
 
+func getEmailTypeName(input EmailType) string {
+	if input.EmailVerification != nil {
+		return "EMAIL_VERIFICATION"
+	} else if input.PasswordReset != nil {
+		return "PASSWORD_RESET"
+	} else if input.PasswordlessLogin != nil {
+		return "PASSWORDLESS_LOGIN"
+	}
+	return "UNKNOWN"
+}
+
 func SendSMTPEmail(settings SMTPSettings, content EmailContent) error {
 	m := gomail.NewMessage()
 	m.SetHeader("From", fmt.Sprintf("%s <%s>", settings.From.Name, settings.From.Email))
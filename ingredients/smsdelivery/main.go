@@ -19,6 +19,7 @@ package smsdelivery
 import (
 	"errors"
 
+	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/twilio/twilio-go"
 	openapi "github.com/twilio/twilio-go/rest/api/v2010"
 )
@@ -36,6 +37,21 @@ func MakeIngredient(config TypeInputWithService) Ingredient {
 		result.IngredientInterfaceImpl = config.Override(result.IngredientInterfaceImpl)
 	}
 
+	if result.IngredientInterfaceImpl.SendSms != nil {
+		sendSms := *result.IngredientInterfaceImpl.SendSms
+		instrumentedSendSms := func(input SmsType, userContext supertokens.UserContext) error {
+			span := supertokens.StartSpan(userContext, "smsdelivery.SendSms")
+			err := sendSms(input, userContext)
+			span.End(err)
+			if err != nil {
+				supertokens.LogError(userContext, "smsdelivery: failed to send SMS", "error", err)
+				supertokens.IncrementCounterMetric(supertokens.MetricSmsSendFailures, nil)
+			}
+			return err
+		}
+		result.IngredientInterfaceImpl.SendSms = &instrumentedSendSms
+	}
+
 	return result
 }
 
@@ -19,7 +19,7 @@ type analyticsPostRequestBody struct {
 }
 
 func AnalyticsPost(apiInterface dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (analyticsPostResponse, error) {
-	supertokensInstance, instanceError := supertokens.GetInstanceOrThrowError()
+	supertokensInstance, instanceError := supertokens.GetInstanceOrThrowError(userContext)
 
 	if supertokens.IsRunningInTestMode() {
 		return analyticsPostResponse{
@@ -76,12 +76,13 @@ func AnalyticsPost(apiInterface dashboardmodels.APIInterface, tenantId string, o
 		"dashboardVersion": *readBody.DashboardVersion,
 	}
 
-	querier, err := supertokens.GetNewQuerierInstanceOrThrowError("")
+	querier, err := supertokens.GetNewQuerierInstanceOrThrowError("", userContext)
 	if err != nil {
 		return analyticsPostResponse{}, err
 	}
 
-	response, err := querier.SendGetRequest("/telemetry", nil, userContext)
+	var response supertokens.CoreTelemetryResponse
+	err = querier.SendGetRequestTyped("/telemetry", nil, &response, userContext)
 	if err != nil {
 		// We don't send telemetry events if this fails
 		return analyticsPostResponse{
@@ -89,13 +90,11 @@ func AnalyticsPost(apiInterface dashboardmodels.APIInterface, tenantId string, o
 		}, nil
 	}
 
-	exists := response["exists"].(bool)
-
-	if exists {
-		data["telemetryId"] = response["telemetryId"].(string)
+	if response.Exists {
+		data["telemetryId"] = response.TelemetryId
 	}
 
-	numberOfUsers, err := supertokens.GetUserCount(nil, nil)
+	numberOfUsers, err := supertokens.GetUserCount(nil, nil, userContext)
 	if err != nil {
 		// We don't send telemetry events if this fails
 		return analyticsPostResponse{
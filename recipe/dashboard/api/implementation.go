@@ -41,7 +41,7 @@ func MakeAPIImplementation() dashboardmodels.APIInterface {
 
 		bundleDomain := normalizedDomain.GetAsStringDangerous() + normalizedPath.GetAsStringDangerous()
 
-		stInstance, err := supertokens.GetInstanceOrThrowError()
+		stInstance, err := supertokens.GetInstanceOrThrowError(userContext)
 		if err != nil {
 			return "", err
 		}
@@ -56,7 +56,7 @@ func MakeAPIImplementation() dashboardmodels.APIInterface {
 		authMode := string(options.Config.AuthMode)
 
 		isSearchEnabled := false
-		querier, err := supertokens.GetNewQuerierInstanceOrThrowError(options.RecipeID)
+		querier, err := supertokens.GetNewQuerierInstanceOrThrowError(options.RecipeID, userContext)
 		if err != nil {
 			return "", err
 		}
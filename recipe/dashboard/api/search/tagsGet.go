@@ -21,24 +21,25 @@ import (
 )
 
 type searchTagsResponse struct {
-	Status string        `json:"status"`
-	Tags   []interface{} `json:"tags"`
+	Status string   `json:"status"`
+	Tags   []string `json:"tags"`
 }
 
 func SearchTagsGet(apiImplementation dashboardmodels.APIInterface, tenantId string, options dashboardmodels.APIOptions, userContext supertokens.UserContext) (searchTagsResponse, error) {
-	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard")
+	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard", userContext)
 
 	if querierErr != nil {
 		return searchTagsResponse{}, querierErr
 	}
 
-	apiResponse, apiErr := querier.SendGetRequest("/user/search/tags", nil, userContext)
+	var apiResponse supertokens.CoreSearchTagsResponse
+	apiErr := querier.SendGetRequestTyped("/user/search/tags", nil, &apiResponse, userContext)
 	if apiErr != nil {
 		return searchTagsResponse{}, apiErr
 	}
 
 	return searchTagsResponse{
 		Status: "OK",
-		Tags:   apiResponse["tags"].([]interface{}),
+		Tags:   apiResponse.Tags,
 	}, nil
 }
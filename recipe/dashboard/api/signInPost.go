@@ -43,34 +43,35 @@ func SignInPost(apiInterface dashboardmodels.APIInterface, options dashboardmode
 		}
 	}
 
-	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard")
+	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard", userContext)
 
 	if querierErr != nil {
 		return querierErr
 	}
 
-	apiResponse, apiErr := querier.SendPostRequest("/recipe/dashboard/signin", map[string]interface{}{
-		"email":    *readBody.Email,
-		"password": *readBody.Password,
-	}, userContext)
+	var apiResponse supertokens.CoreDashboardSignInResponse
+	apiErr := querier.SendPostRequestTyped("/recipe/dashboard/signin", supertokens.CoreDashboardSignInRequest{
+		Email:    *readBody.Email,
+		Password: *readBody.Password,
+	}, &apiResponse, userContext)
 
 	if apiErr != nil {
 		return apiErr
 	}
 
-	status := apiResponse["status"]
+	status := apiResponse.Status
 
 	if status == "OK" {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status":    "OK",
-			"sessionId": apiResponse["sessionId"].(string),
+			"sessionId": apiResponse.SessionId,
 		})
 	}
 
 	if status == "USER_SUSPENDED_ERROR" {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
 			"status":  "USER_SUSPENDED_ERROR",
-			"message": apiResponse["message"].(string),
+			"message": apiResponse.Message,
 		})
 	}
 
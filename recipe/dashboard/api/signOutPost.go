@@ -24,15 +24,16 @@ func SignOutPost(apiInterface dashboardmodels.APIInterface, tenantId string, opt
 	keyParts := strings.Split(sessionIdFromHeader, " ")
 	sessionIdFromHeader = keyParts[len(keyParts)-1]
 
-	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard")
+	querier, querierErr := supertokens.GetNewQuerierInstanceOrThrowError("dashboard", userContext)
 
 	if querierErr != nil {
 		return signOutPostResponse{}, querierErr
 	}
 
-	_, apiError := querier.SendDeleteRequest("/recipe/dashboard/session", map[string]interface{}{}, map[string]string{
+	var apiResponse supertokens.CoreStatusResponse
+	apiError := querier.SendDeleteRequestTyped("/recipe/dashboard/session", nil, map[string]string{
 		"sessionId": sessionIdFromHeader,
-	}, userContext)
+	}, &apiResponse, userContext)
 
 	if apiError != nil {
 		return signOutPostResponse{}, apiError
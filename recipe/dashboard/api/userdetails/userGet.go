@@ -53,7 +53,7 @@ func UserGet(apiImplementation dashboardmodels.APIInterface, tenantId string, op
 		}
 	}
 
-	if !api.IsRecipeInitialised(recipeId) {
+	if !api.IsRecipeInitialised(recipeId, userContext) {
 		return UserGetResponse{
 			Status: "RECIPE_NOT_INITIALISED",
 		}, nil
@@ -67,7 +67,7 @@ func UserGet(apiImplementation dashboardmodels.APIInterface, tenantId string, op
 		}, nil
 	}
 
-	_, err := usermetadata.GetRecipeInstanceOrThrowError()
+	_, err := usermetadata.GetRecipeInstanceOrThrowError(userContext)
 
 	if err != nil {
 		// If metadata is not enabled then the frontend will show this as the name
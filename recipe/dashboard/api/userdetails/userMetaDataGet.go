@@ -36,7 +36,7 @@ func UserMetaDataGet(apiInterface dashboardmodels.APIInterface, tenantId string,
 		}
 	}
 
-	_, instanceError := usermetadata.GetRecipeInstanceOrThrowError()
+	_, instanceError := usermetadata.GetRecipeInstanceOrThrowError(userContext)
 
 	if instanceError != nil {
 		return userMetaDataGetResponse{
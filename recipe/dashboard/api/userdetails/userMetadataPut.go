@@ -57,7 +57,7 @@ func UserMetaDataPut(apiInterface dashboardmodels.APIInterface, tenantId string,
 		}
 	}
 
-	_, instanceError := usermetadata.GetRecipeInstanceOrThrowError()
+	_, instanceError := usermetadata.GetRecipeInstanceOrThrowError(userContext)
 
 	// This is so that the API exists early if the recipe has not been initialised
 	if instanceError != nil {
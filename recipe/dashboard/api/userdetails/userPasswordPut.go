@@ -62,7 +62,7 @@ func UserPasswordPut(apiInterface dashboardmodels.APIInterface, tenantId string,
 
 	recipeToUse := "none"
 
-	emailPasswordInstance := emailpassword.GetRecipeInstance()
+	emailPasswordInstance := emailpassword.GetRecipeInstance(userContext)
 
 	if emailPasswordInstance != nil {
 		recipeToUse = "emailpassword"
@@ -57,7 +57,7 @@ func updateEmailForRecipeId(recipeId string, userId string, email string, tenant
 	if recipeId == "emailpassword" {
 		var emailField epmodels.NormalisedFormField
 
-		for _, value := range emailpassword.GetRecipeInstance().Config.SignUpFeature.FormFields {
+		for _, value := range emailpassword.GetRecipeInstance(userContext).Config.SignUpFeature.FormFields {
 			if value.ID == "email" {
 				emailField = value
 			}
@@ -97,7 +97,7 @@ func updateEmailForRecipeId(recipeId string, userId string, email string, tenant
 		isValidEmail := true
 		validationError := ""
 
-		passwordlessConfig := passwordless.GetRecipeInstance().Config
+		passwordlessConfig := passwordless.GetRecipeInstance(userContext).Config
 
 		if passwordlessConfig.ContactMethodPhone.Enabled {
 			validationResult := passwordless.DefaultValidateEmailAddress(email, tenantId)
@@ -158,7 +158,7 @@ func updatePhoneForRecipeId(recipeId string, userId string, phone string, tenant
 		isValidPhone := true
 		validationError := ""
 
-		passwordlessConfig := passwordless.GetRecipeInstance().Config
+		passwordlessConfig := passwordless.GetRecipeInstance(userContext).Config
 
 		if passwordlessConfig.ContactMethodEmail.Enabled {
 			validationResult := passwordless.DefaultValidatePhoneNumber(phone, tenantId)
@@ -271,7 +271,7 @@ func UserPut(apiInterface dashboardmodels.APIInterface, tenantId string, options
 	if *readBody.FirstName != "" || *readBody.LastName != "" {
 		isRecipeInitialised := false
 
-		_, err = usermetadata.GetRecipeInstanceOrThrowError()
+		_, err = usermetadata.GetRecipeInstanceOrThrowError(userContext)
 
 		if err == nil {
 			isRecipeInitialised = true
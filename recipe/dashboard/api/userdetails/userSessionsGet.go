@@ -20,6 +20,7 @@ import (
 
 	"github.com/supertokens/supertokens-golang/recipe/dashboard/dashboardmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
@@ -30,6 +31,8 @@ type SessionType struct {
 	Expiry                           uint64      `json:"expiry"`
 	TimeCreated                      uint64      `json:"timeCreated"`
 	SessionHandle                    string      `json:"sessionHandle"`
+	// DeviceInfo is nil if the device was not recorded when the session was created
+	DeviceInfo *sessmodels.DeviceInfo `json:"deviceInfo,omitempty"`
 }
 
 type userSessionsGetResponse struct {
@@ -80,6 +83,7 @@ func UserSessionsGet(apiInterface dashboardmodels.APIInterface, tenantId string,
 					Expiry:                           sessionResponse.Expiry,
 					TimeCreated:                      sessionResponse.TimeCreated,
 					SessionHandle:                    sessionResponse.SessionHandle,
+					DeviceInfo:                       sessionResponse.DeviceInfo,
 				})
 			}
 
@@ -83,17 +83,17 @@ func UsersGet(apiImplementation dashboardmodels.APIInterface, tenantId string, o
 	}
 
 	if len(queryParamsObject) != 0 {
-		usersResponse, err = supertokens.GetUsersWithSearchParams(tenantId, timeJoinedOrder, paginationTokenPtr, &limit, nil, queryParamsObject)
+		usersResponse, err = supertokens.GetUsersWithSearchParams(tenantId, timeJoinedOrder, paginationTokenPtr, &limit, nil, queryParamsObject, userContext)
 	} else if timeJoinedOrder == "ASC" {
-		usersResponse, err = supertokens.GetUsersOldestFirst(tenantId, paginationTokenPtr, &limit, nil, nil)
+		usersResponse, err = supertokens.GetUsersOldestFirst(tenantId, paginationTokenPtr, &limit, nil, nil, userContext)
 	} else {
-		usersResponse, err = supertokens.GetUsersNewestFirst(tenantId, paginationTokenPtr, &limit, nil, nil)
+		usersResponse, err = supertokens.GetUsersNewestFirst(tenantId, paginationTokenPtr, &limit, nil, nil, userContext)
 	}
 	if err != nil {
 		return UsersGetResponse{}, err
 	}
 
-	_, err = usermetadata.GetRecipeInstanceOrThrowError()
+	_, err = usermetadata.GetRecipeInstanceOrThrowError(userContext)
 	if err != nil {
 		return UsersGetResponse{
 			Status:              "OK",
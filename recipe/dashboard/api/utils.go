@@ -77,23 +77,23 @@ func GetUserForRecipeId(userId string, recipeId string, userContext supertokens.
 	return userToReturn, recipeToReturn
 }
 
-func IsRecipeInitialised(recipeId string) bool {
+func IsRecipeInitialised(recipeId string, userContext ...supertokens.UserContext) bool {
 	isRecipeInitialised := false
 
 	if recipeId == emailpassword.RECIPE_ID {
-		_, err := emailpassword.GetRecipeInstanceOrThrowError()
+		_, err := emailpassword.GetRecipeInstanceOrThrowError(userContext...)
 
 		if err == nil {
 			isRecipeInitialised = true
 		}
 	} else if recipeId == passwordless.RECIPE_ID {
-		_, err := passwordless.GetRecipeInstanceOrThrowError()
+		_, err := passwordless.GetRecipeInstanceOrThrowError(userContext...)
 
 		if err == nil {
 			isRecipeInitialised = true
 		}
 	} else if recipeId == thirdparty.RECIPE_ID {
-		_, err := thirdparty.GetRecipeInstanceOrThrowError()
+		_, err := thirdparty.GetRecipeInstanceOrThrowError(userContext...)
 
 		if err == nil {
 			isRecipeInitialised = true
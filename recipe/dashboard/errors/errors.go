@@ -1,5 +1,17 @@
 package errors
 
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+// The error codes of the dashboard errors (see supertokens.GetErrorCode)
+const (
+	ErrorCodeForbiddenAccess supertokens.ErrorCode = "FORBIDDEN_ACCESS"
+)
+
+// Sentinel errors for the dashboard errors, to be used with errors.Is
+var (
+	ErrForbiddenAccess = supertokens.NewSentinelError(ErrorCodeForbiddenAccess)
+)
+
 type ForbiddenAccessError struct {
 	Msg string
 }
@@ -7,3 +19,11 @@ type ForbiddenAccessError struct {
 func (err ForbiddenAccessError) Error() string {
 	return err.Msg
 }
+
+func (err ForbiddenAccessError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeForbiddenAccess
+}
+
+func (err ForbiddenAccessError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
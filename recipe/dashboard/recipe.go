@@ -36,40 +36,40 @@ type Recipe struct {
 	APIImpl      dashboardmodels.APIInterface
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *dashboardmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 	verifiedConfig := validateAndNormaliseUserInput(appInfo, config)
 	r.Config = verifiedConfig
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
 
 	recipeImplementation := makeRecipeImplementation(*querierInstance)
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
 	r.RecipeModule = recipeModuleInstance
 
-	r.RecipeModule.ResetForTest = resetForTest
-
 	return *r, nil
 }
 
 func recipeInit(config *dashboardmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, errors.New("Dashboard recipe has already been initialised. Please check your code for bugs.")
 	}
@@ -355,7 +355,3 @@ func (r *Recipe) getAllCORSHeaders() []string {
 func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
 	return false, nil
 }
-
-func resetForTest() {
-	singletonInstance = nil
-}
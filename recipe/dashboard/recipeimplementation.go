@@ -39,17 +39,16 @@ func makeRecipeImplementation(querier supertokens.Querier) dashboardmodels.Recip
 			keyParts := strings.Split(authHeaderValue, " ")
 			authHeaderValue = keyParts[len(keyParts)-1]
 
-			verifyResponse, err := querier.SendPostRequest("/recipe/dashboard/session/verify", map[string]interface{}{
-				"sessionId": authHeaderValue,
-			}, userContext)
+			var verifyResponse supertokens.CoreVerifyDashboardSessionResponse
+			err := querier.SendPostRequestTyped("/recipe/dashboard/session/verify", supertokens.CoreDashboardSessionRequest{
+				SessionId: authHeaderValue,
+			}, &verifyResponse, userContext)
 
 			if err != nil {
 				return false, err
 			}
 
-			status, ok := verifyResponse["status"]
-
-			if !ok || status != "OK" {
+			if verifyResponse.Status != "OK" {
 				return false, nil
 			}
 
@@ -78,14 +77,14 @@ func makeRecipeImplementation(querier supertokens.Querier) dashboardmodels.Recip
 					}
 				}
 
-				userEmail, emailOk := verifyResponse["email"]
+				userEmail := verifyResponse.Email
 
-				if !emailOk || userEmail.(string) == "" {
+				if userEmail == "" {
 					supertokens.LogDebugMessage("User Dashboard: Returning Unauthorised because no email was returned from the core. Should never come here")
 					return false, nil
 				}
 
-				if !supertokens.DoesSliceContainString(userEmail.(string), *admins) {
+				if !supertokens.DoesSliceContainString(userEmail, *admins) {
 					supertokens.LogDebugMessage("User Dashboard: Throwing OPERATION_NOT_ALLOWED because user is not an admin")
 					return false, errors.ForbiddenAccessError{
 						Msg: "You are not permitted to perform this operation",
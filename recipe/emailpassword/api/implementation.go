@@ -144,6 +144,7 @@ func MakeAPIImplementation() epmodels.APIInterface {
 		}
 
 		user := response.OK.User
+		userContext = session.SetAuthenticationMethodInUserContext(userContext, options.RecipeID)
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return epmodels.SignInPOSTResponse{}, err
@@ -183,6 +184,7 @@ func MakeAPIImplementation() epmodels.APIInterface {
 
 		user := response.OK.User
 
+		userContext = session.SetAuthenticationMethodInUserContext(userContext, options.RecipeID)
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return epmodels.SignUpPOSTResponse{}, err
@@ -198,11 +200,51 @@ func MakeAPIImplementation() epmodels.APIInterface {
 			},
 		}, nil
 	}
+	reauthenticatePOST := func(formFields []epmodels.TypeFormField, sessionContainer sessmodels.SessionContainer, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) (epmodels.ReauthenticatePOSTResponse, error) {
+		var password string
+		for _, formField := range formFields {
+			if formField.ID == "password" {
+				password = formField.Value
+			}
+		}
+
+		user, err := (*options.RecipeImplementation.GetUserByID)(sessionContainer.GetUserIDWithContext(userContext), userContext)
+		if err != nil {
+			return epmodels.ReauthenticatePOSTResponse{}, err
+		}
+		if user == nil {
+			// the user of the session did not sign up with an email and password
+			return epmodels.ReauthenticatePOSTResponse{
+				WrongCredentialsError: &struct{}{},
+			}, nil
+		}
+
+		// SignIn is used so that its overrides (e.g. to lock out users) also apply to re-authentication
+		response, err := (*options.RecipeImplementation.SignIn)(user.Email, password, sessionContainer.GetTenantIdWithContext(userContext), session.SetReauthenticationInUserContext(userContext))
+		if err != nil {
+			return epmodels.ReauthenticatePOSTResponse{}, err
+		}
+		if response.WrongCredentialsError != nil || response.OK.User.ID != user.ID {
+			return epmodels.ReauthenticatePOSTResponse{
+				WrongCredentialsError: &struct{}{},
+			}, nil
+		}
+
+		err = session.RecordReauthentication(sessionContainer, options.RecipeID, userContext)
+		if err != nil {
+			return epmodels.ReauthenticatePOSTResponse{}, err
+		}
+		return epmodels.ReauthenticatePOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
 	return epmodels.APIInterface{
 		EmailExistsGET:                 &emailExistsGET,
 		GeneratePasswordResetTokenPOST: &generatePasswordResetTokenPOST,
 		PasswordResetPOST:              &passwordResetPOST,
 		SignInPOST:                     &signInPOST,
 		SignUpPOST:                     &signUpPOST,
+		ReauthenticatePOST:             &reauthenticatePOST,
 	}
 }
@@ -39,7 +39,7 @@ func PasswordReset(apiImplementation epmodels.APIInterface, tenantId string, opt
 		return err
 	}
 
-	formFields, err := validateFormFieldsOrThrowError(options.Config.ResetPasswordUsingTokenFeature.FormFieldsForPasswordResetForm, formFieldsRaw["formFields"], tenantId)
+	formFields, err := validateFormFieldsOrThrowError(options.Config.ResetPasswordUsingTokenFeature.FormFieldsForPasswordResetForm, formFieldsRaw["formFields"], tenantId, userContext)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,97 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// GetReauthenticateFormFields returns the form fields of the re-authentication API, which is only the password
+// field of the sign in form
+func GetReauthenticateFormFields(config epmodels.TypeNormalisedInput) []epmodels.NormalisedFormField {
+	formFields := []epmodels.NormalisedFormField{}
+	for _, formField := range config.SignInFeature.FormFields {
+		if formField.ID == "password" {
+			formFields = append(formFields, formField)
+		}
+	}
+	return formFields
+}
+
+func ReauthenticateAPI(apiImplementation epmodels.APIInterface, tenantId string, options epmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.ReauthenticatePOST == nil || (*apiImplementation.ReauthenticatePOST) == nil {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+	// The API is disabled unless the session recipe records the RecentAuthClaim that it updates
+	sessionRecipe, err := session.GetRecipeInstanceOrThrowError(userContext)
+	if err != nil || !sessionRecipe.Config.RecordRecentAuth {
+		options.OtherHandler(options.Res, options.Req)
+		return nil
+	}
+
+	// The claim validators are not run, since the user may be re-authenticating to pass them
+	sessionRequired := true
+	sessionContainer, err := session.GetSession(options.Req, options.Res, &sessmodels.VerifySessionOptions{
+		SessionRequired: &sessionRequired,
+		OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+			return []claims.SessionClaimValidator{}, nil
+		},
+	}, userContext)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var formFieldsRaw map[string]interface{}
+	err = json.Unmarshal(body, &formFieldsRaw)
+	if err != nil {
+		return err
+	}
+
+	formFields, err := validateFormFieldsOrThrowError(GetReauthenticateFormFields(options.Config), formFieldsRaw["formFields"], tenantId, userContext)
+	if err != nil {
+		return err
+	}
+
+	result, err := (*apiImplementation.ReauthenticatePOST)(formFields, sessionContainer, tenantId, options, userContext)
+	if err != nil {
+		return err
+	}
+	if result.WrongCredentialsError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":     "WRONG_CREDENTIALS_ERROR",
+			"message":    supertokens.Translate(supertokens.MessageKeyWrongCredentials, tenantId, userContext),
+			"messageKey": supertokens.MessageKeyWrongCredentials,
+		})
+	} else if result.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+		})
+	} else if result.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*result.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
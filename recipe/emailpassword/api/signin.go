@@ -38,7 +38,7 @@ func SignInAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 		return err
 	}
 
-	formFields, err := validateFormFieldsOrThrowError(options.Config.SignInFeature.FormFields, formFieldsRaw["formFields"], tenantId)
+	formFields, err := validateFormFieldsOrThrowError(options.Config.SignInFeature.FormFields, formFieldsRaw["formFields"], tenantId, userContext)
 	if err != nil {
 		return err
 	}
@@ -49,7 +49,9 @@ func SignInAPI(apiImplementation epmodels.APIInterface, tenantId string, options
 	}
 	if result.WrongCredentialsError != nil {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
-			"status": "WRONG_CREDENTIALS_ERROR",
+			"status":     "WRONG_CREDENTIALS_ERROR",
+			"message":    supertokens.Translate(supertokens.MessageKeyWrongCredentials, tenantId, userContext),
+			"messageKey": supertokens.MessageKeyWrongCredentials,
 		})
 	} else if result.OK != nil {
 		return supertokens.Send200Response(options.Res, map[string]interface{}{
@@ -26,7 +26,7 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
-func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormField, formFieldsRaw interface{}, tenantId string) ([]epmodels.TypeFormField, error) {
+func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormField, formFieldsRaw interface{}, tenantId string, userContext supertokens.UserContext) ([]epmodels.TypeFormField, error) {
 	if formFieldsRaw == nil {
 		return nil, supertokens.BadInputError{
 			Msg: "Missing input param: formFields",
@@ -87,10 +87,12 @@ func validateFormFieldsOrThrowError(configFormFields []epmodels.NormalisedFormFi
 		}
 	}
 
-	return formFields, validateFormOrThrowError(configFormFields, formFields, tenantId)
+	return formFields, validateFormOrThrowError(configFormFields, formFields, tenantId, userContext)
 }
 
-func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, inputs []epmodels.TypeFormField, tenantId string) error {
+// validateFormOrThrowError validates the form fields, and returns a FieldError with the localised messages of the
+// failed validations (see supertokens.LocaliseMessage)
+func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, inputs []epmodels.TypeFormField, tenantId string, userContext supertokens.UserContext) error {
 	var validationErrors []errors.ErrorPayload
 	if len(configFormFields) != len(inputs) {
 		return supertokens.BadInputError{
@@ -106,14 +108,11 @@ func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, i
 			}
 		}
 		if input.Value == "" && !field.Optional {
-			validationErrors = append(validationErrors, errors.ErrorPayload{ID: field.ID, ErrorMsg: "Field is not optional"})
+			validationErrors = append(validationErrors, makeErrorPayload(field.ID, "Field is not optional", tenantId, userContext))
 		} else {
 			err := field.Validate(input.Value, tenantId)
 			if err != nil {
-				validationErrors = append(validationErrors, errors.ErrorPayload{
-					ID:       field.ID,
-					ErrorMsg: *err,
-				})
+				validationErrors = append(validationErrors, makeErrorPayload(field.ID, *err, tenantId, userContext))
 			}
 		}
 	}
@@ -126,6 +125,15 @@ func validateFormOrThrowError(configFormFields []epmodels.NormalisedFormField, i
 	return nil
 }
 
+func makeErrorPayload(id string, message string, tenantId string, userContext supertokens.UserContext) errors.ErrorPayload {
+	key, localisedMessage := supertokens.LocaliseMessage(message, tenantId, userContext)
+	return errors.ErrorPayload{
+		ID:       id,
+		ErrorMsg: localisedMessage,
+		ErrorKey: string(key),
+	}
+}
+
 func GetPasswordResetLink(appInfo supertokens.NormalisedAppinfo, token string, tenantId string, request *http.Request, userContext supertokens.UserContext) (string, error) {
 	websiteDomain, err := appInfo.GetOrigin(request, userContext)
 	if err != nil {
@@ -22,4 +22,5 @@ const (
 	PasswordResetAPI              = "/user/password/reset"
 	SignupEmailExistsAPIOld       = "/signup/email/exists"
 	SignupEmailExistsAPI          = "/emailpassword/email/exists"
+	ReauthenticateAPI             = "/reauthenticate"
 )
@@ -40,6 +40,9 @@ type APIInterface struct {
 	PasswordResetPOST              *func(formFields []TypeFormField, token string, tenantId string, options APIOptions, userContext supertokens.UserContext) (ResetPasswordPOSTResponse, error)
 	SignInPOST                     *func(formFields []TypeFormField, tenantId string, options APIOptions, userContext supertokens.UserContext) (SignInPOSTResponse, error)
 	SignUpPOST                     *func(formFields []TypeFormField, tenantId string, options APIOptions, userContext supertokens.UserContext) (SignUpPOSTResponse, error)
+	// ReauthenticatePOST checks the password of the user of the session, and updates the RecentAuthClaim of
+	// the session (see RecordRecentAuth in the config of the session recipe)
+	ReauthenticatePOST *func(formFields []TypeFormField, sessionContainer sessmodels.SessionContainer, tenantId string, options APIOptions, userContext supertokens.UserContext) (ReauthenticatePOSTResponse, error)
 }
 
 type ResetPasswordPOSTResponse struct {
@@ -77,3 +80,9 @@ type GeneratePasswordResetTokenPOSTResponse struct {
 	OK           *struct{}
 	GeneralError *supertokens.GeneralErrorResponse
 }
+
+type ReauthenticatePOSTResponse struct {
+	OK                    *struct{}
+	WrongCredentialsError *struct{}
+	GeneralError          *supertokens.GeneralErrorResponse
+}
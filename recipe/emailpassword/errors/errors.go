@@ -15,6 +15,18 @@
 
 package errors
 
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+// The error codes of the emailpassword errors (see supertokens.GetErrorCode)
+const (
+	ErrorCodeFieldError supertokens.ErrorCode = "FIELD_ERROR"
+)
+
+// Sentinel errors for the emailpassword errors, to be used with errors.Is
+var (
+	ErrFieldError = supertokens.NewSentinelError(ErrorCodeFieldError)
+)
+
 type FieldError struct {
 	Msg     string
 	Payload []ErrorPayload
@@ -23,8 +35,18 @@ type FieldError struct {
 type ErrorPayload struct {
 	ID       string `json:"id"`
 	ErrorMsg string `json:"error"`
+	// ErrorKey is the message key of ErrorMsg, if it is a message of the SDK or from the translations (see supertokens.LocaliseMessage)
+	ErrorKey string `json:"errorKey,omitempty"`
 }
 
 func (err FieldError) Error() string {
 	return err.Msg
 }
+
+func (err FieldError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeFieldError
+}
+
+func (err FieldError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
@@ -0,0 +1,63 @@
+package emailpassword
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func TestThatFormFieldErrorsAreLocalisedForTheAcceptLanguageHeader(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "https://supertokens.io",
+			APIDomain:     "https://api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+			session.Init(nil),
+		},
+		I18n: &supertokens.I18nConfig{
+			Translations: map[string]map[supertokens.MessageKey]string{
+				"de": {supertokens.MessageKeyEmailInvalid: "E-Mail ist ungültig"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer instance.Close()
+	handler := instance.Middleware(nil)
+
+	signUp := func(acceptLanguage string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodPost, "/auth/signup", strings.NewReader(`{"formFields":[{"id":"email","value":"invalid"},{"id":"password","value":"validpass123"}]}`))
+		req.Header.Set("Accept-Language", acceptLanguage)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusOK, res.Code)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		assert.Equal(t, "FIELD_ERROR", body["status"])
+		return body["formFields"].([]interface{})[0].(map[string]interface{})
+	}
+
+	formField := signUp("de-DE,de;q=0.9,en;q=0.8")
+	assert.Equal(t, "E-Mail ist ungültig", formField["error"])
+	assert.Equal(t, "EMAIL_INVALID", formField["errorKey"])
+
+	formField = signUp("fr")
+	assert.Equal(t, "Email is invalid", formField["error"])
+	assert.Equal(t, "EMAIL_INVALID", formField["errorKey"])
+}
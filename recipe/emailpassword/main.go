@@ -28,7 +28,7 @@ func Init(config *epmodels.TypeInput) supertokens.Recipe {
 }
 
 func SignUp(tenantId string, email string, password string, userContext ...supertokens.UserContext) (epmodels.SignUpResponse, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return epmodels.SignUpResponse{}, err
 	}
@@ -39,7 +39,7 @@ func SignUp(tenantId string, email string, password string, userContext ...super
 }
 
 func SignIn(tenantId string, email string, password string, userContext ...supertokens.UserContext) (epmodels.SignInResponse, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return epmodels.SignInResponse{}, err
 	}
@@ -50,7 +50,7 @@ func SignIn(tenantId string, email string, password string, userContext ...super
 }
 
 func GetUserByID(userID string, userContext ...supertokens.UserContext) (*epmodels.User, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +61,7 @@ func GetUserByID(userID string, userContext ...supertokens.UserContext) (*epmode
 }
 
 func GetUserByEmail(tenantId string, email string, userContext ...supertokens.UserContext) (*epmodels.User, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func GetUserByEmail(tenantId string, email string, userContext ...supertokens.Us
 }
 
 func CreateResetPasswordToken(tenantId string, userID string, userContext ...supertokens.UserContext) (epmodels.CreateResetPasswordTokenResponse, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return epmodels.CreateResetPasswordTokenResponse{}, err
 	}
@@ -83,7 +83,7 @@ func CreateResetPasswordToken(tenantId string, userID string, userContext ...sup
 }
 
 func ResetPasswordUsingToken(tenantId string, token string, newPassword string, userContext ...supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return epmodels.ResetPasswordUsingTokenResponse{}, nil
 	}
@@ -94,7 +94,7 @@ func ResetPasswordUsingToken(tenantId string, token string, newPassword string,
 }
 
 func UpdateEmailOrPassword(userId string, email *string, password *string, applyPasswordPolicy *bool, tenantIdForPasswordPolicy *string, userContext ...supertokens.UserContext) (epmodels.UpdateEmailOrPasswordResponse, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return epmodels.UpdateEmailOrPasswordResponse{}, nil
 	}
@@ -109,7 +109,7 @@ func UpdateEmailOrPassword(userId string, email *string, password *string, apply
 }
 
 func SendEmail(input emaildelivery.EmailType, userContext ...supertokens.UserContext) error {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return err
 	}
@@ -133,7 +133,7 @@ func CreateResetPasswordLink(tenantId string, userID string, userContext ...supe
 		}, nil
 	}
 
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return epmodels.CreateResetPasswordLinkResponse{}, err
 	}
@@ -0,0 +1,170 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailpassword
+
+import (
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/api"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+var userSchema = &supertokens.OpenAPISchema{
+	Type: "object",
+	Properties: map[string]*supertokens.OpenAPISchema{
+		"id":         {Type: "string"},
+		"email":      {Type: "string"},
+		"timeJoined": {Type: "integer"},
+		"tenantIds":  {Type: "array", Items: &supertokens.OpenAPISchema{Type: "string"}},
+	},
+}
+
+var fieldErrorSchema = &supertokens.OpenAPISchema{
+	Type:        "array",
+	Description: "Set if the status is FIELD_ERROR",
+	Items: &supertokens.OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*supertokens.OpenAPISchema{
+			"id":       {Type: "string"},
+			"error":    {Type: "string"},
+			"errorKey": {Type: "string", Description: "The message key of the error, if it is a message of the SDK or from the translations"},
+		},
+	},
+}
+
+// formFieldsSchema describes the formFields property of the request body for the configured form fields
+func formFieldsSchema(formFields []epmodels.NormalisedFormField) *supertokens.OpenAPISchema {
+	ids := []string{}
+	required := []string{}
+	for _, formField := range formFields {
+		ids = append(ids, formField.ID)
+		if !formField.Optional {
+			required = append(required, formField.ID)
+		}
+	}
+	return &supertokens.OpenAPISchema{
+		Type:        "array",
+		Description: "The values of the form fields. Required: " + strings.Join(required, ", "),
+		Items: &supertokens.OpenAPISchema{
+			Type:     "object",
+			Required: []string{"id", "value"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"id":    {Type: "string", Enum: ids},
+				"value": {},
+			},
+		},
+	}
+}
+
+func formFieldsAPISpec(summary string, formFields []epmodels.NormalisedFormField, statuses []string, response *supertokens.OpenAPISchema) *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: summary,
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:     "object",
+			Required: []string{"formFields"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"formFields": formFieldsSchema(formFields),
+			},
+		},
+		Statuses: statuses,
+		Response: response,
+	}
+}
+
+func signUpAPISpec(config epmodels.TypeNormalisedInput) *supertokens.APISpec {
+	return formFieldsAPISpec("Signs up a user with the form fields, and creates a session", config.SignUpFeature.FormFields,
+		[]string{"OK", "FIELD_ERROR"},
+		&supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"user":       userSchema,
+				"formFields": fieldErrorSchema,
+			},
+		})
+}
+
+func signInAPISpec(config epmodels.TypeNormalisedInput) *supertokens.APISpec {
+	return formFieldsAPISpec("Signs in a user with their email and password, and creates a session", config.SignInFeature.FormFields,
+		[]string{"OK", "WRONG_CREDENTIALS_ERROR", "FIELD_ERROR"},
+		&supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"user":       userSchema,
+				"formFields": fieldErrorSchema,
+				"messageKey": {Type: "string", Description: "Set if the status is WRONG_CREDENTIALS_ERROR"},
+			},
+		})
+}
+
+func reauthenticateAPISpec(config epmodels.TypeNormalisedInput) *supertokens.APISpec {
+	spec := formFieldsAPISpec("Checks the password of the user of the session, and records the re-authentication in the session", api.GetReauthenticateFormFields(config),
+		[]string{"OK", "WRONG_CREDENTIALS_ERROR", "FIELD_ERROR"},
+		&supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"formFields": fieldErrorSchema,
+				"messageKey": {Type: "string", Description: "Set if the status is WRONG_CREDENTIALS_ERROR"},
+			},
+		})
+	spec.SessionRequired = true
+	return spec
+}
+
+func generatePasswordResetTokenAPISpec(config epmodels.TypeNormalisedInput) *supertokens.APISpec {
+	return formFieldsAPISpec("Sends a password reset email", config.ResetPasswordUsingTokenFeature.FormFieldsForGenerateTokenForm,
+		[]string{"OK", "FIELD_ERROR"},
+		&supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"formFields": fieldErrorSchema,
+			},
+		})
+}
+
+func passwordResetAPISpec(config epmodels.TypeNormalisedInput) *supertokens.APISpec {
+	spec := formFieldsAPISpec("Resets the password of a user using the token from the password reset email", config.ResetPasswordUsingTokenFeature.FormFieldsForPasswordResetForm,
+		[]string{"OK", "RESET_PASSWORD_INVALID_TOKEN_ERROR", "FIELD_ERROR"},
+		&supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"formFields": fieldErrorSchema,
+			},
+		})
+	spec.RequestBody.Required = append(spec.RequestBody.Required, "token")
+	spec.RequestBody.Properties["token"] = &supertokens.OpenAPISchema{Type: "string"}
+	spec.RequestBody.Properties["method"] = &supertokens.OpenAPISchema{Type: "string", Enum: []string{"token"}}
+	return spec
+}
+
+func emailExistsAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Checks whether an email password user with the email exists",
+		QueryParameters: []supertokens.OpenAPIParameter{{
+			Name:     "email",
+			In:       "query",
+			Required: true,
+			Schema:   &supertokens.OpenAPISchema{Type: "string"},
+		}},
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"exists": {Type: "boolean"},
+			},
+		},
+	}
+}
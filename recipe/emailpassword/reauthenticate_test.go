@@ -0,0 +1,208 @@
+package emailpassword
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func TestThatReauthenticatingUpdatesTheRecentAuthClaimOfTheSession(t *testing.T) {
+	instance := coreemulator.NewTestInstance(t, Init(nil), session.Init(&sessmodels.TypeInput{RecordRecentAuth: true}))
+	handler := instance.Middleware(nil)
+	userContext := supertokens.SetInstanceInUserContext(nil, instance)
+
+	call := func(path string, accessToken string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("st-auth-mode", "header")
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		var result map[string]interface{}
+		json.Unmarshal(res.Body.Bytes(), &result)
+		return res, result
+	}
+	validateRecentAuth := func(sessionHandle string) []claims.ClaimValidationError {
+		result, err := session.ValidateClaimsForSessionHandle(sessionHandle, func(globalClaimValidators []claims.SessionClaimValidator, sessionInfo sessmodels.SessionInformation, userContext supertokens.UserContext) []claims.SessionClaimValidator {
+			return []claims.SessionClaimValidator{sessionclaims.RecentAuthClaimValidators.RecentAuthWithin(60, RECIPE_ID)}
+		}, userContext)
+		assert.NoError(t, err)
+		return result.OK.InvalidClaims
+	}
+
+	res, body := call("/auth/signup", "", `{"formFields":[{"id":"email","value":"john@example.com"},{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	accessToken := res.Header().Get("st-access-token")
+	sessionContainer, err := session.GetSessionWithoutRequestResponse(accessToken, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, RECIPE_ID, sessionContainer.GetClaimValue(sessionclaims.RecentAuthClaim))
+	assert.Empty(t, validateRecentAuth(sessionContainer.GetHandle()))
+
+	// the user signed in a while ago
+	_, err = session.MergeIntoAccessTokenPayload(sessionContainer.GetHandle(), map[string]interface{}{
+		"st-auth": map[string]interface{}{"v": RECIPE_ID, "t": time.Now().Add(-time.Hour).UnixMilli()},
+	}, userContext)
+	assert.NoError(t, err)
+	assert.Len(t, validateRecentAuth(sessionContainer.GetHandle()), 1)
+
+	_, body = call("/auth/reauthenticate", accessToken, `{"formFields":[{"id":"password","value":"wrongpass123"}]}`)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", body["status"])
+	assert.Len(t, validateRecentAuth(sessionContainer.GetHandle()), 1)
+
+	res, body = call("/auth/reauthenticate", accessToken, `{"formFields":[{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	assert.NotEmpty(t, res.Header().Get("st-access-token"))
+	assert.Empty(t, validateRecentAuth(sessionContainer.GetHandle()))
+
+	// the session was not replaced
+	handles, err := session.GetAllSessionHandlesForUser(sessionContainer.GetUserID(), nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{sessionContainer.GetHandle()}, handles)
+
+	res, _ = call("/auth/reauthenticate", "", `{"formFields":[{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
+
+func TestThatReauthenticatingIsDisabledIfTheSessionRecipeDoesNotRecordRecentAuth(t *testing.T) {
+	instance := coreemulator.NewTestInstance(t, Init(nil), session.Init(nil))
+	handler := instance.Middleware(http.NotFoundHandler())
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", strings.NewReader(`{"formFields":[{"id":"email","value":"john@example.com"},{"id":"password","value":"validpass123"}]}`))
+	req.Header.Set("st-auth-mode", "header")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/auth/reauthenticate", strings.NewReader(`{"formFields":[{"id":"password","value":"validpass123"}]}`))
+	req.Header.Set("Authorization", "Bearer "+res.Header().Get("st-access-token"))
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func TestThatReauthenticatingIsNotCountedAsASignIn(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	registry := supertokens.NewInMemoryMetricsRegistry()
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+			session.Init(&sessmodels.TypeInput{RecordRecentAuth: true}),
+		},
+		Metrics: &supertokens.MetricsConfig{
+			Registry: registry,
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer instance.Close()
+	defer supertokens.ResetForTest()
+	handler := instance.Middleware(http.NotFoundHandler())
+
+	call := func(path string, accessToken string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("st-auth-mode", "header")
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		var result map[string]interface{}
+		json.Unmarshal(res.Body.Bytes(), &result)
+		return res, result
+	}
+
+	res, body := call("/auth/signup", "", `{"formFields":[{"id":"email","value":"john@example.com"},{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	accessToken := res.Header().Get("st-access-token")
+
+	_, body = call("/auth/reauthenticate", accessToken, `{"formFields":[{"id":"password","value":"wrongpass123"}]}`)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", body["status"])
+	_, body = call("/auth/reauthenticate", accessToken, `{"formFields":[{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+
+	labels := map[string]string{"recipe": RECIPE_ID, "tenant_id": "public"}
+	assert.Equal(t, float64(1), registry.GetCounterValue(supertokens.MetricSignUps, labels))
+	assert.Equal(t, float64(0), registry.GetCounterValue(supertokens.MetricSignIns, labels))
+	assert.Equal(t, float64(0), registry.GetCounterValue(supertokens.MetricFailedSignIns, labels))
+
+	_, body = call("/auth/signin", "", `{"formFields":[{"id":"email","value":"john@example.com"},{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	assert.Equal(t, float64(1), registry.GetCounterValue(supertokens.MetricSignIns, labels))
+}
+
+func TestThatReauthenticatingGoesThroughTheOverridesOfSignIn(t *testing.T) {
+	lockedOut := false
+	reauthentications := 0
+	instance := coreemulator.NewTestInstance(t, Init(&epmodels.TypeInput{
+		Override: &epmodels.OverrideStruct{
+			Functions: func(originalImplementation epmodels.RecipeInterface) epmodels.RecipeInterface {
+				originalSignIn := *originalImplementation.SignIn
+				*originalImplementation.SignIn = func(email string, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
+					if session.IsReauthenticationInUserContext(userContext) {
+						reauthentications++
+					}
+					if lockedOut {
+						return epmodels.SignInResponse{WrongCredentialsError: &struct{}{}}, nil
+					}
+					return originalSignIn(email, password, tenantId, userContext)
+				}
+				return originalImplementation
+			},
+		},
+	}), session.Init(&sessmodels.TypeInput{RecordRecentAuth: true}))
+	handler := instance.Middleware(nil)
+
+	call := func(path string, accessToken string, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("st-auth-mode", "header")
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		var result map[string]interface{}
+		json.Unmarshal(res.Body.Bytes(), &result)
+		return res, result
+	}
+
+	res, body := call("/auth/signup", "", `{"formFields":[{"id":"email","value":"john@example.com"},{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	accessToken := res.Header().Get("st-access-token")
+
+	_, body = call("/auth/signin", "", `{"formFields":[{"id":"email","value":"john@example.com"},{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	assert.Equal(t, 0, reauthentications)
+
+	_, body = call("/auth/reauthenticate", accessToken, `{"formFields":[{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "OK", body["status"])
+	assert.Equal(t, 1, reauthentications)
+
+	// the user is locked out by the override
+	lockedOut = true
+	_, body = call("/auth/reauthenticate", accessToken, `{"formFields":[{"id":"password","value":"validpass123"}]}`)
+	assert.Equal(t, "WRONG_CREDENTIALS_ERROR", body["status"])
+	assert.Equal(t, 2, reauthentications)
+}
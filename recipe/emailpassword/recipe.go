@@ -40,13 +40,11 @@ type Recipe struct {
 	EmailDelivery emaildelivery.Ingredient
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *epmodels.TypeInput, emailDeliveryIngredient *emaildelivery.Ingredient, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 	r.RecipeModule = supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
@@ -57,6 +55,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 		return verifiedConfig
 	}
 	r.RecipeImpl = verifiedConfig.Override.Functions(MakeRecipeImplementation(*querierInstance, getEmailPasswordConfig))
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 
 	if emailDeliveryIngredient != nil {
 		r.EmailDelivery = *emailDeliveryIngredient
@@ -65,7 +64,8 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	}
 
 	supertokens.AddPostInitCallback(func() error {
-		emailVerificationRecipe := emailverification.GetRecipeInstance()
+		userContext := supertokens.MakeUserContextForAppInfo(appInfo)
+		emailVerificationRecipe := emailverification.GetRecipeInstance(userContext)
 		if emailVerificationRecipe != nil {
 			emailVerificationRecipe.AddGetEmailForUserIdFunc(r.getEmailForUserId)
 		}
@@ -80,27 +80,32 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 
 func recipeInit(config *epmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, nil, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, defaultErrors.New("emailpassword recipe has already been initialised. Please check your code for bugs.")
 	}
 }
 
-func GetRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func GetRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
-	return nil, defaultErrors.New("initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "initialisation not done. Did you forget to call the init function?"}
 }
 
-func GetRecipeInstance() *Recipe {
-	return singletonInstance
+func GetRecipeInstance(userContext ...supertokens.UserContext) *Recipe {
+	instance, _ := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	return instance
 }
 
 // implement RecipeModule
@@ -130,36 +135,52 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	reauthenticateAPI, err := supertokens.NewNormalisedURLPath(constants.ReauthenticateAPI)
+	if err != nil {
+		return nil, err
+	}
 	return []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: signUpAPI,
 		ID:                     constants.SignUpAPI,
 		Disabled:               r.APIImpl.SignUpPOST == nil,
+		Spec:                   signUpAPISpec(r.Config),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: signInAPI,
 		ID:                     constants.SignInAPI,
 		Disabled:               r.APIImpl.SignInPOST == nil,
+		Spec:                   signInAPISpec(r.Config),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: generatePasswordResetTokenAPI,
 		ID:                     constants.GeneratePasswordResetTokenAPI,
 		Disabled:               r.APIImpl.GeneratePasswordResetTokenPOST == nil,
+		Spec:                   generatePasswordResetTokenAPISpec(r.Config),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: passwordResetAPI,
 		ID:                     constants.PasswordResetAPI,
 		Disabled:               r.APIImpl.PasswordResetPOST == nil,
+		Spec:                   passwordResetAPISpec(r.Config),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: signupEmailExistsAPIOld,
 		ID:                     constants.SignupEmailExistsAPIOld,
 		Disabled:               r.APIImpl.EmailExistsGET == nil,
+		Spec:                   emailExistsAPISpec(),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: signupEmailExistsAPI,
 		ID:                     constants.SignupEmailExistsAPI,
 		Disabled:               r.APIImpl.EmailExistsGET == nil,
+		Spec:                   emailExistsAPISpec(),
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: reauthenticateAPI,
+		ID:                     constants.ReauthenticateAPI,
+		Disabled:               r.APIImpl.ReauthenticatePOST == nil,
+		Spec:                   reauthenticateAPISpec(r.Config),
 	}}, nil
 }
 
@@ -184,6 +205,8 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 		return api.PasswordReset(r.APIImpl, tenantId, options, userContext)
 	} else if id == constants.SignupEmailExistsAPIOld || id == constants.SignupEmailExistsAPI {
 		return api.EmailExists(r.APIImpl, tenantId, options, userContext)
+	} else if id == constants.ReauthenticateAPI {
+		return api.ReauthenticateAPI(r.APIImpl, tenantId, options, userContext)
 	}
 	return defaultErrors.New("should never come here")
 }
@@ -193,8 +216,8 @@ func (r *Recipe) getAllCORSHeaders() []string {
 }
 
 func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
-	if defaultErrors.As(err, &errors.FieldError{}) {
-		errs := err.(errors.FieldError)
+	var errs errors.FieldError
+	if defaultErrors.As(err, &errs) {
 		return true, supertokens.Send200Response(res, map[string]interface{}{
 			"status":     "FIELD_ERROR",
 			"formFields": errs.Payload,
@@ -221,7 +244,6 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 }
 
 func resetForTest() {
-	singletonInstance = nil
 	PasswordResetEmailSentForTest = false
 	PasswordResetDataForTest = struct {
 		User                      epmodels.User
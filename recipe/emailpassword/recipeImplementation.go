@@ -17,26 +17,24 @@ package emailpassword
 
 import (
 	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
 func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfig func() epmodels.TypeNormalisedInput) epmodels.RecipeInterface {
 	signUp := func(email, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignUpResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signup", map[string]interface{}{
-			"email":    email,
-			"password": password,
-		}, userContext)
+		var response supertokens.CoreEmailPasswordUserResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signup", supertokens.CoreSignUpOrSignInRequest{
+			Email:    email,
+			Password: password,
+		}, &response, userContext)
 		if err != nil {
 			return epmodels.SignUpResponse{}, err
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
-			user, err := parseUser(response["user"])
-			if err != nil {
-				return epmodels.SignUpResponse{}, err
-			}
+		if response.Status == "OK" {
+			supertokens.RecordSignInUpMetric(RECIPE_ID, tenantId, true)
 			return epmodels.SignUpResponse{
-				OK: &struct{ User epmodels.User }{User: *user},
+				OK: &struct{ User epmodels.User }{User: epmodels.User(*response.User)},
 			}, nil
 		}
 		return epmodels.SignUpResponse{
@@ -45,75 +43,80 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	signIn := func(email, password string, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signin", map[string]interface{}{
-			"email":    email,
-			"password": password,
-		}, userContext)
+		var response supertokens.CoreEmailPasswordUserResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signin", supertokens.CoreSignUpOrSignInRequest{
+			Email:    email,
+			Password: password,
+		}, &response, userContext)
 		if err != nil {
 			return epmodels.SignInResponse{}, err
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
-			user, err := parseUser(response["user"])
-			if err != nil {
-				return epmodels.SignInResponse{}, err
+		// re-authenticating the user of a session is not a sign in
+		countAsSignIn := !session.IsReauthenticationInUserContext(userContext)
+		if response.Status == "OK" {
+			if countAsSignIn {
+				supertokens.RecordSignInUpMetric(RECIPE_ID, tenantId, false)
 			}
 			return epmodels.SignInResponse{
-				OK: &struct{ User epmodels.User }{User: *user},
+				OK: &struct{ User epmodels.User }{User: epmodels.User(*response.User)},
 			}, nil
 		}
+		if countAsSignIn {
+			supertokens.IncrementCounterMetric(supertokens.MetricFailedSignIns, map[string]string{
+				"recipe":    RECIPE_ID,
+				"tenant_id": tenantId,
+			})
+		}
 		return epmodels.SignInResponse{
 			WrongCredentialsError: &struct{}{},
 		}, nil
 	}
 
 	getUserByID := func(userID string, userContext supertokens.UserContext) (*epmodels.User, error) {
-		response, err := querier.SendGetRequest("/recipe/user", map[string]string{
+		cachedUser := &epmodels.User{}
+		if querier.GetFromCache(supertokens.CacheNamespaceEmailPasswordUser, userID, cachedUser) {
+			return cachedUser, nil
+		}
+		var response supertokens.CoreEmailPasswordUserResponse
+		err := querier.SendGetRequestTyped("/recipe/user", map[string]string{
 			"userId": userID,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
-			user, err := parseUser(response["user"])
-			if err != nil {
-				return nil, err
-			}
+		if response.Status == "OK" {
+			user := (*epmodels.User)(response.User)
+			querier.SetInCache(supertokens.CacheNamespaceEmailPasswordUser, userID, user)
 			return user, nil
 		}
 		return nil, nil
 	}
 
 	getUserByEmail := func(email string, tenantId string, userContext supertokens.UserContext) (*epmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
+		var response supertokens.CoreEmailPasswordUserResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/user", map[string]string{
 			"email": email,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
-			user, err := parseUser(response["user"])
-			if err != nil {
-				return nil, err
-			}
-			return user, nil
+		if response.Status == "OK" {
+			return (*epmodels.User)(response.User), nil
 		}
 		return nil, nil
 	}
 
 	createResetPasswordToken := func(userID string, tenantId string, userContext supertokens.UserContext) (epmodels.CreateResetPasswordTokenResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/user/password/reset/token", map[string]interface{}{
-			"userId": userID,
-		}, userContext)
+		var response supertokens.CoreCreateResetPasswordTokenResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/user/password/reset/token", supertokens.CoreCreateResetPasswordTokenRequest{
+			UserId: userID,
+		}, &response, userContext)
 		if err != nil {
 			return epmodels.CreateResetPasswordTokenResponse{}, err
 		}
-		status, ok := response["status"]
-		if ok && status.(string) == "OK" {
+		if response.Status == "OK" {
 			return epmodels.CreateResetPasswordTokenResponse{
-				OK: &struct{ Token string }{Token: response["token"].(string)},
+				OK: &struct{ Token string }{Token: response.Token},
 			}, nil
 		}
 		return epmodels.CreateResetPasswordTokenResponse{
@@ -122,35 +125,25 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	resetPasswordUsingToken := func(token, newPassword string, tenantId string, userContext supertokens.UserContext) (epmodels.ResetPasswordUsingTokenResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/user/password/reset", map[string]interface{}{
-			"method":      "token",
-			"token":       token,
-			"newPassword": newPassword,
-		}, userContext)
+		var response supertokens.CoreResetPasswordUsingTokenResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/user/password/reset", supertokens.CoreResetPasswordUsingTokenRequest{
+			Method:      "token",
+			Token:       token,
+			NewPassword: newPassword,
+		}, &response, userContext)
 		if err != nil {
 			return epmodels.ResetPasswordUsingTokenResponse{}, nil
 		}
 
-		if response["status"].(string) == "OK" {
-			userId, ok := response["userId"]
-			if ok {
-				// using CDI >= 2.12
-				userIdStr := userId.(string)
-				return epmodels.ResetPasswordUsingTokenResponse{
-					OK: &struct {
-						UserId *string
-					}{
-						UserId: &userIdStr,
-					},
-				}, nil
-			} else {
-				// using CDI < 2.12
-				return epmodels.ResetPasswordUsingTokenResponse{
-					OK: &struct {
-						UserId *string
-					}{},
-				}, nil
-			}
+		if response.Status == "OK" {
+			// UserId is only sent by the core for CDI >= 2.12
+			return epmodels.ResetPasswordUsingTokenResponse{
+				OK: &struct {
+					UserId *string
+				}{
+					UserId: response.UserId,
+				},
+			}, nil
 		} else {
 			return epmodels.ResetPasswordUsingTokenResponse{
 				ResetPasswordInvalidTokenError: &struct{}{},
@@ -159,11 +152,9 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 	}
 
 	updateEmailOrPassword := func(userId string, email, password *string, applyPasswordPolicy *bool, tenantIdForPasswordPolicy string, userContext supertokens.UserContext) (epmodels.UpdateEmailOrPasswordResponse, error) {
-		requestBody := map[string]interface{}{
-			"userId": userId,
-		}
-		if email != nil {
-			requestBody["email"] = email
+		requestBody := supertokens.CoreUpdateEmailOrPasswordRequest{
+			UserId: userId,
+			Email:  email,
 		}
 		if password != nil {
 			if applyPasswordPolicy == nil || *applyPasswordPolicy {
@@ -180,18 +171,20 @@ func MakeRecipeImplementation(querier supertokens.Querier, getEmailPasswordConfi
 					}
 				}
 			}
-			requestBody["password"] = password
+			requestBody.Password = password
 		}
-		response, err := querier.SendPutRequest("/recipe/user", requestBody, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPutRequestTyped("/recipe/user", requestBody, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceEmailPasswordUser, userId)
 		if err != nil {
 			return epmodels.UpdateEmailOrPasswordResponse{}, nil
 		}
 
-		if response["status"].(string) == "OK" {
+		if response.Status == "OK" {
 			return epmodels.UpdateEmailOrPasswordResponse{
 				OK: &struct{}{},
 			}, nil
-		} else if response["status"].(string) == "EMAIL_ALREADY_EXISTS_ERROR" {
+		} else if response.Status == "EMAIL_ALREADY_EXISTS_ERROR" {
 			return epmodels.UpdateEmailOrPasswordResponse{
 				EmailAlreadyExistsError: &struct{}{},
 			}, nil
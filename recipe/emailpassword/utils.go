@@ -16,7 +16,6 @@
 package emailpassword
 
 import (
-	"encoding/json"
 	"reflect"
 	"regexp"
 
@@ -254,16 +253,3 @@ func defaultEmailValidator(value interface{}, tenantId string) *string {
 	}
 	return nil
 }
-
-func parseUser(value interface{}) (*epmodels.User, error) {
-	respJSON, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
-	}
-	var user epmodels.User
-	err = json.Unmarshal(respJSON, &user)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
@@ -30,7 +30,7 @@ func MakeServiceImplementation(settings emaildelivery.SMTPSettings) emaildeliver
 
 	getContent := func(input emaildelivery.EmailType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
 		if input.EmailVerification != nil {
-			return getEmailVerifyEmailContent(*input.EmailVerification)
+			return getEmailVerifyEmailContent(*input.EmailVerification, userContext)
 		} else {
 			return emaildelivery.EmailContent{}, errors.New("should never come here")
 		}
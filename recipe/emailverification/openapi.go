@@ -0,0 +1,67 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package emailverification
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+func generateEmailVerifyTokenAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary:         "Sends an email verification email to the user of the session",
+		Statuses:        []string{"OK", "EMAIL_ALREADY_VERIFIED_ERROR"},
+		SessionRequired: true,
+	}
+}
+
+func verifyEmailAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Verifies an email using the token from the email verification email",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:     "object",
+			Required: []string{"method", "token"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"method": {Type: "string", Enum: []string{"token"}},
+				"token":  {Type: "string"},
+			},
+		},
+		Statuses: []string{"OK", "EMAIL_VERIFICATION_INVALID_TOKEN_ERROR"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"user": {
+					Type: "object",
+					Properties: map[string]*supertokens.OpenAPISchema{
+						"id":    {Type: "string"},
+						"email": {Type: "string"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func isEmailVerifiedAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary:  "Returns whether the email of the user of the session is verified",
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"isVerified": {Type: "boolean"},
+			},
+		},
+		SessionRequired: true,
+	}
+}
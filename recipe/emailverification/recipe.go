@@ -41,8 +41,6 @@ type Recipe struct {
 	AddGetEmailForUserIdFunc func(function evmodels.TypeGetEmailForUserID)
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config evmodels.TypeInput, emailDeliveryIngredient *emaildelivery.Ingredient, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	getEmailForUserIdFuncsFromOtherRecipes := []evmodels.TypeGetEmailForUserID{}
 
@@ -54,12 +52,13 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config e
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
 	recipeImplementation := makeRecipeImplementation(*querierInstance)
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
 	r.RecipeModule = recipeModuleInstance
@@ -106,28 +105,34 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config e
 	return *r, nil
 }
 
-func getRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func getRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
-	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "Initialisation not done. Did you forget to call the init function?"}
 }
 
-func GetRecipeInstance() *Recipe {
-	return singletonInstance
+func GetRecipeInstance(userContext ...supertokens.UserContext) *Recipe {
+	instance, _ := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	return instance
 }
 
 func recipeInit(config evmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, nil, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
 
 			supertokens.AddPostInitCallback(func() error {
-				sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
+				userContext := supertokens.MakeUserContextForAppInfo(appInfo)
+				sessionRecipe, err := session.GetRecipeInstanceOrThrowError(userContext)
 
 				if err != nil {
 					return err
@@ -142,7 +147,7 @@ func recipeInit(config evmodels.TypeInput) supertokens.Recipe {
 				}
 				return nil
 			})
-			return &singletonInstance.RecipeModule, nil
+			return &recipe.RecipeModule, nil
 		}
 		return nil, errors.New("Emailverification recipe has already been initialised. Please check your code for bugs.")
 	}
@@ -165,16 +170,19 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: generateEmailVerifyTokenAPINormalised,
 		ID:                     generateEmailVerifyTokenAPI,
 		Disabled:               r.APIImpl.GenerateEmailVerifyTokenPOST == nil,
+		Spec:                   generateEmailVerifyTokenAPISpec(),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: emailVerifyAPINormalised,
 		ID:                     emailVerifyAPI,
 		Disabled:               r.APIImpl.VerifyEmailPOST == nil,
+		Spec:                   verifyEmailAPISpec(),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: emailVerifyAPINormalised,
 		ID:                     emailVerifyAPI,
 		Disabled:               r.APIImpl.IsEmailVerifiedGET == nil,
+		Spec:                   isEmailVerifiedAPISpec(),
 	}}, nil
 }
 
@@ -206,7 +214,6 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 }
 
 func resetForTest() {
-	singletonInstance = nil
 	EmailVerificationEmailSentForTest = false
 	EmailVerificationDataForTest = struct {
 		User                    evmodels.User
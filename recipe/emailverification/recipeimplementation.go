@@ -22,17 +22,17 @@ import (
 
 func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterface {
 	createEmailVerificationToken := func(userID, email string, tenantId string, userContext supertokens.UserContext) (evmodels.CreateEmailVerificationTokenResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/user/email/verify/token", map[string]interface{}{
-			"userId": userID,
-			"email":  email,
-		}, userContext)
+		var response supertokens.CoreCreateEmailVerificationTokenResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/user/email/verify/token", supertokens.CoreCreateEmailVerificationTokenRequest{
+			UserId: userID,
+			Email:  email,
+		}, &response, userContext)
 		if err != nil {
 			return evmodels.CreateEmailVerificationTokenResponse{}, err
 		}
-		status, ok := response["status"]
-		if ok && status == "OK" {
+		if response.Status == "OK" {
 			return evmodels.CreateEmailVerificationTokenResponse{
-				OK: &struct{ Token string }{Token: response["token"].(string)},
+				OK: &struct{ Token string }{Token: response.Token},
 			}, nil
 		}
 
@@ -42,19 +42,19 @@ func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterf
 	}
 
 	verifyEmailUsingToken := func(token string, tenantId string, userContext supertokens.UserContext) (evmodels.VerifyEmailUsingTokenResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/user/email/verify", map[string]interface{}{
-			"method": "token",
-			"token":  token,
-		}, userContext)
+		var response supertokens.CoreVerifyEmailUsingTokenResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/user/email/verify", supertokens.CoreVerifyEmailUsingTokenRequest{
+			Method: "token",
+			Token:  token,
+		}, &response, userContext)
 		if err != nil {
 			return evmodels.VerifyEmailUsingTokenResponse{}, err
 		}
-		status, ok := response["status"]
-		if ok && status == "OK" {
+		if response.Status == "OK" {
 			return evmodels.VerifyEmailUsingTokenResponse{
 				OK: &struct{ User evmodels.User }{User: evmodels.User{
-					ID:    response["userId"].(string),
-					Email: response["email"].(string),
+					ID:    response.UserId,
+					Email: response.Email,
 				}},
 			}, nil
 		}
@@ -64,21 +64,23 @@ func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterf
 	}
 
 	isEmailVerified := func(userID, email string, userContext supertokens.UserContext) (bool, error) {
-		response, err := querier.SendGetRequest("/recipe/user/email/verify", map[string]string{
+		var response supertokens.CoreIsEmailVerifiedResponse
+		err := querier.SendGetRequestTyped("/recipe/user/email/verify", map[string]string{
 			"userId": userID,
 			"email":  email,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return false, err
 		}
-		return response["isVerified"].(bool), nil
+		return response.IsVerified, nil
 	}
 
 	revokeEmailVerificationTokens := func(userId string, email string, tenantId string, userContext supertokens.UserContext) (evmodels.RevokeEmailVerificationTokensResponse, error) {
-		_, err := querier.SendPostRequest(tenantId+"/recipe/user/email/verify/token/remove", map[string]interface{}{
-			"userId": userId,
-			"email":  email,
-		}, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/user/email/verify/token/remove", supertokens.CoreUserEmailRequest{
+			UserId: userId,
+			Email:  email,
+		}, &response, userContext)
 		if err != nil {
 			return evmodels.RevokeEmailVerificationTokensResponse{}, err
 		}
@@ -88,10 +90,11 @@ func makeRecipeImplementation(querier supertokens.Querier) evmodels.RecipeInterf
 	}
 
 	unverifyEmail := func(userId string, email string, userContext supertokens.UserContext) (evmodels.UnverifyEmailResponse, error) {
-		_, err := querier.SendPostRequest("/recipe/user/email/verify/remove", map[string]interface{}{
-			"userId": userId,
-			"email":  email,
-		}, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPostRequestTyped("/recipe/user/email/verify/remove", supertokens.CoreUserEmailRequest{
+			UserId: userId,
+			Email:  email,
+		}, &response, userContext)
 		if err != nil {
 			return evmodels.UnverifyEmailResponse{}, err
 		}
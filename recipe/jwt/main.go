@@ -25,7 +25,7 @@ func Init(config *jwtmodels.TypeInput) supertokens.Recipe {
 }
 
 func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, useStaticSigningKey *bool, userContext ...supertokens.UserContext) (jwtmodels.CreateJWTResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return jwtmodels.CreateJWTResponse{}, err
 	}
@@ -36,7 +36,7 @@ func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, u
 }
 
 func GetJWKS(userContext ...supertokens.UserContext) (jwtmodels.GetJWKSResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return jwtmodels.GetJWKSResponse{}, err
 	}
@@ -33,20 +33,19 @@ type Recipe struct {
 	APIImpl      jwtmodels.APIInterface
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *jwtmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 	verifiedConfig := validateAndNormaliseUserInput(appInfo, config)
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
 	recipeImplementation := makeRecipeImplementation(*querierInstance, verifiedConfig, appInfo)
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
 	r.RecipeModule = recipeModuleInstance
@@ -56,22 +55,26 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	return *r, nil
 }
 
-func getRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func getRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
-	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "Initialisation not done. Did you forget to call the init function?"}
 }
 
 func recipeInit(config *jwtmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, errors.New("JWT recipe has already been initialised. Please check your code for bugs.")
 	}
@@ -90,6 +93,7 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: getJWKSAPINormalised,
 		ID:                     GetJWKSAPI,
 		Disabled:               r.APIImpl.GetJWKSGET == nil,
+		Spec:                   getJWKSAPISpec(),
 	}}, nil
 }
 
@@ -113,6 +117,6 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 	return false, nil
 }
 
-func ResetForTest() {
-	singletonInstance = nil
-}
+// ResetForTest does nothing since recipe instances are reset along with the SuperTokens
+// instance that they belong to. It is kept for the tests that call it.
+func ResetForTest() {}
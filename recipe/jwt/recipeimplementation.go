@@ -40,22 +40,22 @@ func makeRecipeImplementation(querier supertokens.Querier, config jwtmodels.Type
 			shouldUseStaticSigningKey = *useStaticSigningKey
 		}
 
-		response, err := querier.SendPostRequest("/recipe/jwt", map[string]interface{}{
-			"payload":             payload,
-			"validity":            validitySeconds,
-			"algorithm":           "RS256",
-			"jwksDomain":          appInfo.APIDomain.GetAsStringDangerous(),
-			"useStaticSigningKey": shouldUseStaticSigningKey,
-		}, userContext)
+		var response supertokens.CoreCreateJWTResponse
+		err := querier.SendPostRequestTyped("/recipe/jwt", supertokens.CoreCreateJWTRequest{
+			Payload:             payload,
+			Validity:            validitySeconds,
+			Algorithm:           "RS256",
+			JwksDomain:          appInfo.APIDomain.GetAsStringDangerous(),
+			UseStaticSigningKey: shouldUseStaticSigningKey,
+		}, &response, userContext)
 		if err != nil {
 			return jwtmodels.CreateJWTResponse{}, err
 		}
 
-		status, ok := response["status"]
-		if ok && status == "OK" {
+		if response.Status == "OK" {
 			return jwtmodels.CreateJWTResponse{
 				OK: &struct{ Jwt string }{
-					Jwt: response["jwt"].(string),
+					Jwt: response.Jwt,
 				},
 			}, nil
 		} else {
@@ -65,22 +65,14 @@ func makeRecipeImplementation(querier supertokens.Querier, config jwtmodels.Type
 		}
 	}
 	getJWKS := func(userContext supertokens.UserContext) (jwtmodels.GetJWKSResponse, error) {
-		response, headers, err := querier.SendGetRequestWithResponseHeaders("/.well-known/jwks.json", map[string]string{}, userContext)
+		var response supertokens.CoreGetJWKSResponse
+		headers, err := querier.SendGetRequestWithResponseHeadersTyped("/.well-known/jwks.json", map[string]string{}, &response, userContext)
 		if err != nil {
 			return jwtmodels.GetJWKSResponse{}, err
 		}
-
 		keys := []jwtmodels.JsonWebKeys{}
-
-		for _, v := range response["keys"].([]interface{}) {
-			keys = append(keys, jwtmodels.JsonWebKeys{
-				Kty: (v.(map[string]interface{}))["kty"].(string),
-				Kid: (v.(map[string]interface{}))["kid"].(string),
-				N:   (v.(map[string]interface{}))["n"].(string),
-				E:   (v.(map[string]interface{}))["e"].(string),
-				Alg: (v.(map[string]interface{}))["alg"].(string),
-				Use: (v.(map[string]interface{}))["use"].(string),
-			})
+		for _, key := range response.Keys {
+			keys = append(keys, jwtmodels.JsonWebKeys(key))
 		}
 
 		validityInSeconds := defaultJWKSMaxAge
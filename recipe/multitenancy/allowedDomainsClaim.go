@@ -7,7 +7,7 @@ import (
 
 func NewAllowedDomainsClaim() (*claims.TypeSessionClaim, claims.PrimitiveArrayClaimValidators) {
 	fetchDomains := func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
-		instance, err := GetRecipeInstanceOrThrowError()
+		instance, err := GetRecipeInstanceOrThrowError(userContext)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,109 @@
+package multitenancy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/multitenancy/multitenancymodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newCORSTestInstance(t *testing.T, cors *supertokens.CORSConfig) *supertokens.Instance {
+	core := coreemulator.Start(nil)
+	t.Cleanup(core.Close)
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "https://supertokens.io",
+			APIDomain:     "https://api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&multitenancymodels.TypeInput{
+				GetAllowedDomainsForTenantId: func(tenantId string, userContext supertokens.UserContext) ([]string, error) {
+					return []string{fmt.Sprintf("%s.example.com", tenantId), "localhost:3000"}, nil
+				},
+			}),
+			session.Init(nil),
+		},
+		CORS: cors,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return instance
+}
+
+func TestThatTheAllowedDomainsOfTheTenantAreAllowedByCORS(t *testing.T) {
+	instance := newCORSTestInstance(t, &supertokens.CORSConfig{
+		AllowTenantDomains: true,
+	})
+	handler := instance.Middleware(nil)
+
+	preflight := func(path string, origin string) string {
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		return res.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal(t, "https://t1.example.com", preflight("/auth/t1/loginmethods", "https://t1.example.com"))
+	assert.Empty(t, preflight("/auth/t2/loginmethods", "https://t1.example.com"))
+	assert.Equal(t, "http://localhost:3000", preflight("/auth/t2/loginmethods", "http://localhost:3000"))
+	// the scheme and port have to match as well, and domains without a scheme use https
+	assert.Empty(t, preflight("/auth/t1/loginmethods", "http://t1.example.com"))
+	assert.Empty(t, preflight("/auth/t1/loginmethods", "https://t1.example.com:8443"))
+	assert.Empty(t, preflight("/auth/t2/loginmethods", "http://localhost:3001"))
+	// requests that are not for a tenant use the public tenant
+	assert.Equal(t, "https://public.example.com", preflight("/auth/loginmethods", "https://public.example.com"))
+	assert.Equal(t, "https://public.example.com", preflight("/sessioninfo", "https://public.example.com"))
+	assert.Empty(t, preflight("/sessioninfo", "https://t1.example.com"))
+	// the website is always allowed
+	assert.Equal(t, "https://supertokens.io", preflight("/auth/t1/loginmethods", "https://supertokens.io"))
+}
+
+func TestThatCORSOnlyAllowsCredentialsForTheDomainsOfTrustedTenants(t *testing.T) {
+	call := func(instance *supertokens.Instance, path string, origin string) *httptest.ResponseRecorder {
+		sessionContainer, err := session.CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, supertokens.SetInstanceInUserContext(nil, instance))
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Origin", origin)
+		req.AddCookie(&http.Cookie{Name: "sAccessToken", Value: sessionContainer.GetAccessToken()})
+		res := httptest.NewRecorder()
+		instance.Middleware(http.NotFoundHandler()).ServeHTTP(res, req)
+		return res
+	}
+
+	// the session is of the public tenant, but the path names t1, whose domain is the origin
+	instance := newCORSTestInstance(t, &supertokens.CORSConfig{
+		AllowTenantDomains: true,
+	})
+	res := call(instance, "/auth/t1/loginmethods", "https://t1.example.com")
+	assert.Equal(t, "https://t1.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Credentials"))
+	res = call(instance, "/auth/t1/loginmethods", "https://supertokens.io")
+	assert.Equal(t, "true", res.Header().Get("Access-Control-Allow-Credentials"))
+
+	// the tenant returned by GetTenantId is trusted, regardless of the path
+	instance = newCORSTestInstance(t, &supertokens.CORSConfig{
+		AllowTenantDomains: true,
+		GetTenantId: func(req *http.Request, userContext supertokens.UserContext) (string, error) {
+			return "public", nil
+		},
+	})
+	res = call(instance, "/auth/t1/loginmethods", "https://t1.example.com")
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	res = call(instance, "/auth/t1/loginmethods", "https://public.example.com")
+	assert.Equal(t, "https://public.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", res.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -1,5 +1,19 @@
 package mterrors
 
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+// The error codes of the multitenancy errors (see supertokens.GetErrorCode)
+const (
+	ErrorCodeTenantDoesNotExist      supertokens.ErrorCode = "TENANT_DOES_NOT_EXIST"
+	ErrorCodeRecipeDisabledForTenant supertokens.ErrorCode = "RECIPE_DISABLED_FOR_TENANT"
+)
+
+// Sentinel errors for the multitenancy errors, to be used with errors.Is
+var (
+	ErrTenantDoesNotExist      = supertokens.NewSentinelError(ErrorCodeTenantDoesNotExist)
+	ErrRecipeDisabledForTenant = supertokens.NewSentinelError(ErrorCodeRecipeDisabledForTenant)
+)
+
 type TenantDoesNotExistError struct {
 	Msg string
 }
@@ -8,6 +22,14 @@ func (err TenantDoesNotExistError) Error() string {
 	return err.Msg
 }
 
+func (err TenantDoesNotExistError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeTenantDoesNotExist
+}
+
+func (err TenantDoesNotExistError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
+
 type RecipeDisabledForTenantError struct {
 	Msg string
 }
@@ -15,3 +37,11 @@ type RecipeDisabledForTenantError struct {
 func (err RecipeDisabledForTenantError) Error() string {
 	return err.Msg
 }
+
+func (err RecipeDisabledForTenantError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeRecipeDisabledForTenant
+}
+
+func (err RecipeDisabledForTenantError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
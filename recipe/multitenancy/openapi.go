@@ -0,0 +1,59 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package multitenancy
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+func loginMethodsAPISpec() *supertokens.APISpec {
+	enabledSchema := &supertokens.OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*supertokens.OpenAPISchema{
+			"enabled": {Type: "boolean"},
+		},
+	}
+	return &supertokens.APISpec{
+		Summary: "Returns the login methods that are enabled for the tenant",
+		QueryParameters: []supertokens.OpenAPIParameter{{
+			Name:   "clientType",
+			In:     "query",
+			Schema: &supertokens.OpenAPISchema{Type: "string"},
+		}},
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"emailPassword": enabledSchema,
+				"passwordless":  enabledSchema,
+				"thirdParty": {
+					Type: "object",
+					Properties: map[string]*supertokens.OpenAPISchema{
+						"enabled": {Type: "boolean"},
+						"providers": {
+							Type: "array",
+							Items: &supertokens.OpenAPISchema{
+								Type: "object",
+								Properties: map[string]*supertokens.OpenAPISchema{
+									"id":   {Type: "string"},
+									"name": {Type: "string"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
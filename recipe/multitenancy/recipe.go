@@ -39,19 +39,18 @@ type Recipe struct {
 	GetAllowedDomainsForTenantId func(tenantId string, userContext supertokens.UserContext) ([]string, error)
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *multitenancymodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*Recipe, error) {
 	r := &Recipe{}
 	verifiedConfig := validateAndNormaliseUserInput(config)
 	r.Config = verifiedConfig
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return nil, err
 	}
 	recipeImplementation := makeRecipeImplementation(*querierInstance, verifiedConfig, appInfo)
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
@@ -62,26 +61,26 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 
 	r.GetAllowedDomainsForTenantId = verifiedConfig.GetAllowedDomainsForTenantId
 
-	r.RecipeModule.ResetForTest = resetForTest
-
 	return r, nil
 }
 
-func GetRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func GetRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
 
-	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "Initialisation not done. Did you forget to call the init function?"}
 }
 
-func GetRecipeInstance() *Recipe {
-	return singletonInstance
+func GetRecipeInstance(userContext ...supertokens.UserContext) *Recipe {
+	instance, _ := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	return instance
 }
 
 func recipeInit(config *multitenancymodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
@@ -89,7 +88,8 @@ func recipeInit(config *multitenancymodels.TypeInput) supertokens.Recipe {
 
 			if recipe.GetAllowedDomainsForTenantId != nil {
 				supertokens.AddPostInitCallback(func() error {
-					sessionRecipe, err := session.GetRecipeInstanceOrThrowError()
+					userContext := supertokens.MakeUserContextForAppInfo(appInfo)
+					sessionRecipe, err := session.GetRecipeInstanceOrThrowError(userContext)
 
 					if err != nil {
 						return nil // skip adding claims if session recipe is not initialised
@@ -101,8 +101,11 @@ func recipeInit(config *multitenancymodels.TypeInput) supertokens.Recipe {
 				})
 			}
 
-			singletonInstance = recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, errors.New("Multitenancy recipe has already been initialised. Please check your code for bugs.")
 	}
@@ -122,6 +125,7 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 			PathWithoutAPIBasePath: loginMethodsAPI,
 			ID:                     LoginMethodsAPI,
 			Disabled:               r.APIImpl.LoginMethodsGET == nil,
+			Spec:                   loginMethodsAPISpec(),
 		},
 	}, nil
 }
@@ -150,10 +154,6 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 	return false, nil
 }
 
-func resetForTest() {
-	singletonInstance = nil
-}
-
 func (r *Recipe) SetStaticThirdPartyProviders(providers []tpmodels.ProviderInput) {
 	// the `staticThirdPartyProviders` is always overwritten with the provider list from
 	// the last thirdparty recipe that was initialised. In case of multitenancy, the
@@ -172,7 +172,15 @@ func init() {
 	multitenancyclaims.AllowedDomainsClaim, multitenancyclaims.AllowedDomainsClaimValidators = NewAllowedDomainsClaim()
 
 	supertokens.GetTenantIdFuncFromUsingMultitenancyRecipe = func(tenantIdFromFrontend string, userContext supertokens.UserContext) (string, error) {
-		mtRecipe := GetRecipeInstance()
+		mtRecipe := GetRecipeInstance(userContext)
 		return (*mtRecipe.RecipeImpl.GetTenantId)(tenantIdFromFrontend, userContext)
 	}
+
+	supertokens.GetAllowedDomainsForTenantIdFuncFromUsingMultitenancyRecipe = func(tenantId string, userContext supertokens.UserContext) ([]string, error) {
+		mtRecipe := GetRecipeInstance(userContext)
+		if mtRecipe == nil || mtRecipe.GetAllowedDomainsForTenantId == nil {
+			return nil, nil
+		}
+		return mtRecipe.GetAllowedDomainsForTenantId(tenantId, userContext)
+	}
 }
@@ -30,31 +30,24 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 	}
 
 	createOrUpdateTenant := func(tenantId string, config multitenancymodels.TenantConfig, userContext supertokens.UserContext) (multitenancymodels.CreateOrUpdateTenantResponse, error) {
-		requestBody := map[string]interface{}{
-			"tenantId": tenantId,
-		}
-		if config.EmailPasswordEnabled != nil {
-			requestBody["emailPasswordEnabled"] = *config.EmailPasswordEnabled
-		}
-		if config.PasswordlessEnabled != nil {
-			requestBody["passwordlessEnabled"] = *config.PasswordlessEnabled
-		}
-		if config.ThirdPartyEnabled != nil {
-			requestBody["thirdPartyEnabled"] = *config.ThirdPartyEnabled
-		}
-		if config.CoreConfig != nil {
-			requestBody["coreConfig"] = config.CoreConfig
-		}
-		createOrUpdateResponse, err := querier.SendPutRequest("/recipe/multitenancy/tenant", requestBody, userContext)
+		requestBody := supertokens.CoreCreateOrUpdateTenantRequest{
+			TenantId:             tenantId,
+			EmailPasswordEnabled: config.EmailPasswordEnabled,
+			PasswordlessEnabled:  config.PasswordlessEnabled,
+			ThirdPartyEnabled:    config.ThirdPartyEnabled,
+			CoreConfig:           config.CoreConfig,
+		}
+		var createOrUpdateResponse supertokens.CoreCreateOrUpdateTenantResponse
+		err := querier.SendPutRequestTyped("/recipe/multitenancy/tenant", requestBody, &createOrUpdateResponse, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceTenant, tenantId)
 		if err != nil {
 			return multitenancymodels.CreateOrUpdateTenantResponse{}, err
 		}
 
-		_, ok := createOrUpdateResponse["status"].(string)
-		if ok {
+		if createOrUpdateResponse.Status != "" {
 			return multitenancymodels.CreateOrUpdateTenantResponse{
 				OK: &struct{ CreatedNew bool }{
-					CreatedNew: createOrUpdateResponse["createdNew"].(bool),
+					CreatedNew: createOrUpdateResponse.CreatedNew,
 				},
 			}, nil
 		}
@@ -63,17 +56,18 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 	}
 
 	deleteTenant := func(tenantId string, userContext supertokens.UserContext) (multitenancymodels.DeleteTenantResponse, error) {
-		deleteTenantResponse, err := querier.SendPostRequest("/recipe/multitenancy/tenant/remove", map[string]interface{}{
-			"tenantId": tenantId,
-		}, userContext)
+		var deleteTenantResponse supertokens.CoreDeleteTenantResponse
+		err := querier.SendPostRequestTyped("/recipe/multitenancy/tenant/remove", supertokens.CoreDeleteTenantRequest{
+			TenantId: tenantId,
+		}, &deleteTenantResponse, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceTenant, tenantId)
 		if err != nil {
 			return multitenancymodels.DeleteTenantResponse{}, err
 		}
-		_, ok := deleteTenantResponse["status"].(string)
-		if ok {
+		if deleteTenantResponse.Status != "" {
 			return multitenancymodels.DeleteTenantResponse{
 				OK: &struct{ DidExist bool }{
-					DidExist: deleteTenantResponse["didExist"].(bool),
+					DidExist: deleteTenantResponse.DidExist,
 				},
 			}, nil
 		}
@@ -82,43 +76,51 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 	}
 
 	getTenant := func(tenantId string, userContext supertokens.UserContext) (*multitenancymodels.Tenant, error) {
-		tenantResponse, err := querier.SendGetRequest(fmt.Sprintf("/%s/recipe/multitenancy/tenant", tenantId), map[string]string{}, userContext)
+		cachedTenant := &multitenancymodels.Tenant{}
+		if querier.GetFromCache(supertokens.CacheNamespaceTenant, tenantId, cachedTenant) {
+			return cachedTenant, nil
+		}
+		path := fmt.Sprintf("/%s/recipe/multitenancy/tenant", tenantId)
+		var tenantResponse supertokens.CoreGetTenantResponse
+		err := querier.SendGetRequestTyped(path, map[string]string{}, &tenantResponse, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status, ok := tenantResponse["status"].(string)
-		if ok {
-			if status == "TENANT_NOT_FOUND_ERROR" {
-				return nil, nil
-			}
-
-			result := &multitenancymodels.Tenant{}
-			err = supertokens.MapToStruct(tenantResponse, result)
+		if tenantResponse.Status == "TENANT_NOT_FOUND_ERROR" {
+			return nil, nil
+		}
+		if tenantResponse.Status == "OK" {
+			result, err := tenantFromCoreTenant(path, tenantResponse.CoreTenant)
 			if err != nil {
 				return nil, err
 			}
-
-			if status == "OK" {
-				return result, nil
-			}
+			querier.SetInCache(supertokens.CacheNamespaceTenant, tenantId, &result)
+			return &result, nil
 		}
 
 		return nil, errors.New("should not come here")
 	}
 
 	listAllTenants := func(userContext supertokens.UserContext) (multitenancymodels.ListAllTenantsResponse, error) {
-		tenantsResponse, err := querier.SendGetRequest("/recipe/multitenancy/tenant/list", map[string]string{}, userContext)
+		path := "/recipe/multitenancy/tenant/list"
+		var response supertokens.CoreListAllTenantsResponse
+		err := querier.SendGetRequestTyped(path, map[string]string{}, &response, userContext)
 		if err != nil {
 			return multitenancymodels.ListAllTenantsResponse{}, err
 		}
 		result := multitenancymodels.ListAllTenantsResponse{
 			OK: &struct {
 				Tenants []multitenancymodels.Tenant `json:"tenants"`
-			}{},
+			}{
+				Tenants: []multitenancymodels.Tenant{},
+			},
 		}
-		err = supertokens.MapToStruct(tenantsResponse, result.OK)
-		if err != nil {
-			return multitenancymodels.ListAllTenantsResponse{}, err
+		for _, coreTenant := range response.Tenants {
+			tenant, err := tenantFromCoreTenant(path, coreTenant)
+			if err != nil {
+				return multitenancymodels.ListAllTenantsResponse{}, err
+			}
+			result.OK.Tenants = append(result.OK.Tenants, tenant)
 		}
 		return result, nil
 	}
@@ -129,13 +131,13 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 			return multitenancymodels.CreateOrUpdateThirdPartyConfigResponse{}, err
 		}
 
-		requestBody := map[string]interface{}{
-			"config": configMap,
-		}
-		if skipValidation != nil {
-			requestBody["skipValidation"] = *skipValidation
+		requestBody := supertokens.CoreCreateOrUpdateThirdPartyConfigRequest{
+			Config:         configMap,
+			SkipValidation: skipValidation,
 		}
-		response, err := querier.SendPutRequest(fmt.Sprintf("/%s/recipe/multitenancy/config/thirdparty", tenantId), requestBody, userContext)
+		var response supertokens.CoreCreateOrUpdateThirdPartyConfigResponse
+		err = querier.SendPutRequestTyped(fmt.Sprintf("/%s/recipe/multitenancy/config/thirdparty", tenantId), requestBody, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceTenant, tenantId)
 		if err != nil {
 			return multitenancymodels.CreateOrUpdateThirdPartyConfigResponse{}, err
 		}
@@ -143,50 +145,57 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 			OK: &struct {
 				CreatedNew bool
 			}{
-				CreatedNew: response["createdNew"].(bool),
+				CreatedNew: response.CreatedNew,
 			},
 		}, nil
 	}
 
 	deleteThirdPartyConfig := func(tenantId string, thirdPartyId string, userContext supertokens.UserContext) (multitenancymodels.DeleteThirdPartyConfigResponse, error) {
-		response, err := querier.SendPostRequest(fmt.Sprintf("/%s/recipe/multitenancy/config/thirdparty/remove", tenantId), map[string]interface{}{
-			"thirdPartyId": thirdPartyId,
-		}, userContext)
+		var response supertokens.CoreDeleteThirdPartyConfigResponse
+		err := querier.SendPostRequestTyped(fmt.Sprintf("/%s/recipe/multitenancy/config/thirdparty/remove", tenantId), supertokens.CoreDeleteThirdPartyConfigRequest{
+			ThirdPartyId: thirdPartyId,
+		}, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceTenant, tenantId)
 		if err != nil {
 			return multitenancymodels.DeleteThirdPartyConfigResponse{}, err
 		}
 
 		return multitenancymodels.DeleteThirdPartyConfigResponse{
 			OK: &struct{ DidConfigExist bool }{
-				DidConfigExist: response["didConfigExist"].(bool),
+				DidConfigExist: response.DidConfigExist,
 			},
 		}, nil
 	}
 
 	associateUserToTenant := func(tenantId string, userId string, userContext supertokens.UserContext) (multitenancymodels.AssociateUserToTenantResponse, error) {
-		response, err := querier.SendPostRequest(fmt.Sprintf("/%s/recipe/multitenancy/tenant/user", tenantId), map[string]interface{}{
-			"userId": userId,
-		}, userContext)
+		var response supertokens.CoreAssociateUserToTenantResponse
+		err := querier.SendPostRequestTyped(fmt.Sprintf("/%s/recipe/multitenancy/tenant/user", tenantId), supertokens.CoreTenantUserRequest{
+			UserId: userId,
+		}, &response, userContext)
+		// the tenants of the user are part of the cached user objects
+		querier.InvalidateCacheForUser(userId)
 		if err != nil {
 			return multitenancymodels.AssociateUserToTenantResponse{}, err
 		}
 		return multitenancymodels.AssociateUserToTenantResponse{
 			OK: &struct{ WasAlreadyAssociated bool }{
-				WasAlreadyAssociated: response["wasAlreadyAssociated"].(bool),
+				WasAlreadyAssociated: response.WasAlreadyAssociated,
 			},
 		}, nil
 	}
 
 	disassociateUserFromTenant := func(tenantId string, userId string, userContext supertokens.UserContext) (multitenancymodels.DisassociateUserFromTenantResponse, error) {
-		response, err := querier.SendPostRequest(fmt.Sprintf("/%s/recipe/multitenancy/tenant/user/remove", tenantId), map[string]interface{}{
-			"userId": userId,
-		}, userContext)
+		var response supertokens.CoreDisassociateUserFromTenantResponse
+		err := querier.SendPostRequestTyped(fmt.Sprintf("/%s/recipe/multitenancy/tenant/user/remove", tenantId), supertokens.CoreTenantUserRequest{
+			UserId: userId,
+		}, &response, userContext)
+		querier.InvalidateCacheForUser(userId)
 		if err != nil {
 			return multitenancymodels.DisassociateUserFromTenantResponse{}, err
 		}
 		return multitenancymodels.DisassociateUserFromTenantResponse{
 			OK: &struct{ WasAssociated bool }{
-				WasAssociated: response["wasAssociated"].(bool),
+				WasAssociated: response.WasAssociated,
 			},
 		}, nil
 	}
@@ -206,3 +215,24 @@ func makeRecipeImplementation(querier supertokens.Querier, config multitenancymo
 		DisassociateUserFromTenant: &disassociateUserFromTenant,
 	}
 }
+
+// tenantFromCoreTenant converts a tenant returned by the core, decoding its thirdparty providers
+func tenantFromCoreTenant(path string, coreTenant supertokens.CoreTenant) (multitenancymodels.Tenant, error) {
+	tenant := multitenancymodels.Tenant{
+		TenantId:   coreTenant.TenantId,
+		CoreConfig: coreTenant.CoreConfig,
+	}
+	tenant.EmailPassword.Enabled = coreTenant.EmailPassword.Enabled
+	tenant.Passwordless.Enabled = coreTenant.Passwordless.Enabled
+	tenant.ThirdParty.Enabled = coreTenant.ThirdParty.Enabled
+	tenant.ThirdParty.Providers = []tpmodels.ProviderConfig{}
+	for _, providerJSON := range coreTenant.ThirdParty.Providers {
+		var provider tpmodels.ProviderConfig
+		err := supertokens.DecodeCoreResponse(path, providerJSON, &provider)
+		if err != nil {
+			return multitenancymodels.Tenant{}, err
+		}
+		tenant.ThirdParty.Providers = append(tenant.ThirdParty.Providers, provider)
+	}
+	return tenant, nil
+}
@@ -26,7 +26,7 @@ func Init(config *openidmodels.TypeInput) supertokens.Recipe {
 }
 
 func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, useStaticSigningKey *bool, userContext ...supertokens.UserContext) (jwtmodels.CreateJWTResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return jwtmodels.CreateJWTResponse{}, err
 	}
@@ -37,7 +37,7 @@ func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, u
 }
 
 func GetJWKS(userContext ...supertokens.UserContext) (jwtmodels.GetJWKSResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return jwtmodels.GetJWKSResponse{}, err
 	}
@@ -48,7 +48,7 @@ func GetJWKS(userContext ...supertokens.UserContext) (jwtmodels.GetJWKSResponse,
 }
 
 func GetOpenIdDiscoveryConfiguration(userContext ...supertokens.UserContext) (openidmodels.GetOpenIdDiscoveryConfigurationResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return openidmodels.GetOpenIdDiscoveryConfigurationResponse{}, err
 	}
@@ -36,8 +36,6 @@ type Recipe struct {
 
 const RECIPE_ID = "openid"
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *openidmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 
@@ -58,6 +56,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 		return Recipe{}, err
 	}
 	r.RecipeImpl = verifiedConfig.Override.Functions(makeRecipeImplementation(verifiedConfig, jwtRecipe.RecipeImpl))
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 	r.JwtRecipe = jwtRecipe
 
 	r.RecipeModule.ResetForTest = ResetForTest
@@ -65,22 +64,26 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	return *r, nil
 }
 
-func getRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func getRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
 	return nil, defaultErrors.New("Initialisation not done. Did you forget to call the init function?")
 }
 
 func recipeInit(config *openidmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, defaultErrors.New("OpenID recipe has already been initialised. Please check your code for bugs.")
 	}
@@ -98,6 +101,7 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: normalisedPath,
 		ID:                     GetDiscoveryConfigUrl,
 		Disabled:               r.APIImpl.GetOpenIdDiscoveryConfigurationGET == nil,
+		Spec:                   getDiscoveryConfigurationAPISpec(),
 	}}
 
 	jwtAPIs, err := r.JwtRecipe.RecipeModule.GetAPIsHandled()
@@ -134,6 +138,5 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 }
 
 func ResetForTest() {
-	singletonInstance = nil
 	jwt.ResetForTest()
 }
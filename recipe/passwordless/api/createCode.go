@@ -74,9 +74,7 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 			validateErr = options.Config.ContactMethodEmailOrPhone.ValidateEmailAddress(email, tenantId)
 		}
 		if validateErr != nil {
-			return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(supertokens.GeneralErrorResponse{
-				Message: *validateErr,
-			}))
+			return sendValidationErrorResponse(options, *validateErr, tenantId, userContext)
 		}
 	}
 
@@ -88,9 +86,7 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 			validateErr = options.Config.ContactMethodEmailOrPhone.ValidatePhoneNumber(phoneNumber, tenantId)
 		}
 		if validateErr != nil {
-			return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(supertokens.GeneralErrorResponse{
-				Message: *validateErr,
-			}))
+			return sendValidationErrorResponse(options, *validateErr, tenantId, userContext)
 		}
 
 		parsedPhoneNumber, err := phonenumbers.Parse(phoneNumber.(string), "")
@@ -136,3 +132,16 @@ func CreateCode(apiImplementation plessmodels.APIInterface, tenantId string, opt
 
 	return supertokens.Send200Response(options.Res, result)
 }
+
+// sendValidationErrorResponse sends the localised message of a failed validation of the email or phone number
+// (see supertokens.LocaliseMessage) as a GENERAL_ERROR
+func sendValidationErrorResponse(options plessmodels.APIOptions, message string, tenantId string, userContext supertokens.UserContext) error {
+	key, localisedMessage := supertokens.LocaliseMessage(message, tenantId, userContext)
+	response := supertokens.ConvertGeneralErrorToJsonResponse(supertokens.GeneralErrorResponse{
+		Message: localisedMessage,
+	})
+	if key != "" {
+		response["messageKey"] = key
+	}
+	return supertokens.Send200Response(options.Res, response)
+}
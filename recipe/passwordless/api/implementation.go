@@ -46,7 +46,7 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 		user := response.OK.User
 
 		if user.Email != nil {
-			evInstance := emailverification.GetRecipeInstance()
+			evInstance := emailverification.GetRecipeInstance(userContext)
 			if evInstance != nil {
 				tokenResponse, err := (*evInstance.RecipeImpl.CreateEmailVerificationToken)(user.ID, *user.Email, tenantId, userContext)
 				if err != nil {
@@ -61,6 +61,7 @@ func MakeAPIImplementation() plessmodels.APIInterface {
 			}
 		}
 
+		userContext = session.SetAuthenticationMethodInUserContext(userContext, options.RecipeID)
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, user.ID, map[string]interface{}{}, map[string]interface{}{}, userContext)
 		if err != nil {
 			return plessmodels.ConsumeCodePOSTResponse{}, err
@@ -2836,8 +2836,8 @@ const otpLoginTemplate = `<!doctype html>
 
 </html>`
 
-func getPasswordlessLoginEmailContent(input emaildelivery.PasswordlessLoginType) (emaildelivery.EmailContent, error) {
-	stInstance, err := supertokens.GetInstanceOrThrowError()
+func getPasswordlessLoginEmailContent(input emaildelivery.PasswordlessLoginType, userContext supertokens.UserContext) (emaildelivery.EmailContent, error) {
+	stInstance, err := supertokens.GetInstanceOrThrowError(userContext)
 	if err != nil {
 		panic("Please call supertokens.Init function before using the Middleware")
 	}
@@ -0,0 +1,124 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package passwordless
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func createCodeAPISpec(config plessmodels.TypeNormalisedInput) *supertokens.APISpec {
+	properties := map[string]*supertokens.OpenAPISchema{}
+	description := ""
+	if config.ContactMethodEmail.Enabled || config.ContactMethodEmailOrPhone.Enabled {
+		properties["email"] = &supertokens.OpenAPISchema{Type: "string"}
+		description = "email is required"
+	}
+	if config.ContactMethodPhone.Enabled || config.ContactMethodEmailOrPhone.Enabled {
+		properties["phoneNumber"] = &supertokens.OpenAPISchema{Type: "string"}
+		description = "phoneNumber is required"
+	}
+	if len(properties) == 2 {
+		description = "One of email or phoneNumber is required"
+	}
+	return &supertokens.APISpec{
+		Summary: "Sends a code and magic link to the email or phone number of the user",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:        "object",
+			Description: description,
+			Properties:  properties,
+		},
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"deviceId":         {Type: "string"},
+				"preAuthSessionId": {Type: "string"},
+				"flowType":         {Type: "string", Enum: []string{"USER_INPUT_CODE", "MAGIC_LINK", "USER_INPUT_CODE_AND_MAGIC_LINK"}},
+			},
+		},
+	}
+}
+
+func resendCodeAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Sends a new code to the email or phone number of the user",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:     "object",
+			Required: []string{"deviceId", "preAuthSessionId"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"deviceId":         {Type: "string"},
+				"preAuthSessionId": {Type: "string"},
+			},
+		},
+		Statuses: []string{"OK", "RESTART_FLOW_ERROR"},
+	}
+}
+
+func consumeCodeAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Signs in or signs up a user with the code or magic link that they received, and creates a session",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:        "object",
+			Description: "Either linkCode, or deviceId and userInputCode are required",
+			Required:    []string{"preAuthSessionId"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"preAuthSessionId": {Type: "string"},
+				"linkCode":         {Type: "string"},
+				"deviceId":         {Type: "string"},
+				"userInputCode":    {Type: "string"},
+			},
+		},
+		Statuses: []string{"OK", "INCORRECT_USER_INPUT_CODE_ERROR", "EXPIRED_USER_INPUT_CODE_ERROR", "RESTART_FLOW_ERROR"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"createdNewUser": {Type: "boolean"},
+				"user": {
+					Type: "object",
+					Properties: map[string]*supertokens.OpenAPISchema{
+						"id":          {Type: "string"},
+						"email":       {Type: "string"},
+						"phoneNumber": {Type: "string"},
+						"timeJoined":  {Type: "integer"},
+						"tenantIds":   {Type: "array", Items: &supertokens.OpenAPISchema{Type: "string"}},
+					},
+				},
+				"failedCodeInputAttemptCount": {Type: "integer", Description: "Set if the code is incorrect or expired"},
+				"maximumCodeInputAttempts":    {Type: "integer", Description: "Set if the code is incorrect or expired"},
+			},
+		},
+	}
+}
+
+func existsAPISpec(summary string, queryParameter string) *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: summary,
+		QueryParameters: []supertokens.OpenAPIParameter{{
+			Name:     queryParameter,
+			In:       "query",
+			Required: true,
+			Schema:   &supertokens.OpenAPISchema{Type: "string"},
+		}},
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"exists": {Type: "boolean"},
+			},
+		},
+	}
+}
@@ -39,8 +39,6 @@ type Recipe struct {
 	SmsDelivery   smsdelivery.Ingredient
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config plessmodels.TypeInput, emailDeliveryIngredient *emaildelivery.Ingredient, smsDeliveryIngredient *smsdelivery.Ingredient, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 	verifiedConfig := validateAndNormaliseUserInput(appInfo, config)
@@ -48,12 +46,13 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config p
 
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
 	recipeImplementation := MakeRecipeImplementation(*querierInstance)
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 
 	recipeModuleInstance := supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
 	r.RecipeModule = recipeModuleInstance
@@ -71,7 +70,8 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config p
 	}
 
 	supertokens.AddPostInitCallback(func() error {
-		emailVerificationRecipe := emailverification.GetRecipeInstance()
+		userContext := supertokens.MakeUserContextForAppInfo(appInfo)
+		emailVerificationRecipe := emailverification.GetRecipeInstance(userContext)
 		if emailVerificationRecipe != nil {
 			emailVerificationRecipe.AddGetEmailForUserIdFunc(r.getEmailForUserId)
 		}
@@ -104,26 +104,31 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config p
 	return *r, nil
 }
 
-func GetRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func GetRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
-	return nil, errors.New("initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "initialisation not done. Did you forget to call the init function?"}
 }
 
-func GetRecipeInstance() *Recipe {
-	return singletonInstance
+func GetRecipeInstance(userContext ...supertokens.UserContext) *Recipe {
+	instance, _ := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	return instance
 }
 
 func recipeInit(config plessmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, nil, nil, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, errors.New("passwordless recipe has already been initialised. Please check your code for bugs")
 	}
@@ -166,36 +171,43 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: consumeCodeAPINormalised,
 		ID:                     consumeCodeAPI,
 		Disabled:               r.APIImpl.ConsumeCodePOST == nil,
+		Spec:                   consumeCodeAPISpec(),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: createCodeAPINormalised,
 		ID:                     createCodeAPI,
 		Disabled:               r.APIImpl.CreateCodePOST == nil,
+		Spec:                   createCodeAPISpec(r.Config),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: doesEmailExistsAPINormalisedOld,
 		ID:                     doesEmailExistAPIOld,
 		Disabled:               r.APIImpl.EmailExistsGET == nil,
+		Spec:                   existsAPISpec("Checks whether a passwordless user with the email exists", "email"),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: doesEmailExistsAPINormalised,
 		ID:                     doesEmailExistAPI,
 		Disabled:               r.APIImpl.EmailExistsGET == nil,
+		Spec:                   existsAPISpec("Checks whether a passwordless user with the email exists", "email"),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: doesPhoneNumberExistsAPINormalisedOld,
 		ID:                     doesPhoneNumberExistAPIOld,
 		Disabled:               r.APIImpl.PhoneNumberExistsGET == nil,
+		Spec:                   existsAPISpec("Checks whether a passwordless user with the phone number exists", "phoneNumber"),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: doesPhoneNumberExistsAPINormalised,
 		ID:                     doesPhoneNumberExistAPI,
 		Disabled:               r.APIImpl.PhoneNumberExistsGET == nil,
+		Spec:                   existsAPISpec("Checks whether a passwordless user with the phone number exists", "phoneNumber"),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: resendCodeAPINormalised,
 		ID:                     resendCodeAPI,
 		Disabled:               r.APIImpl.ResendCodePOST == nil,
+		Spec:                   resendCodeAPISpec(),
 	}}, nil
 }
 
@@ -233,7 +245,7 @@ func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWrit
 }
 
 func (r *Recipe) CreateMagicLink(email *string, phoneNumber *string, tenantId string, userContext supertokens.UserContext) (string, error) {
-	stInstance, err := supertokens.GetInstanceOrThrowError()
+	stInstance, err := supertokens.GetInstanceOrThrowError(userContext)
 	if err != nil {
 		return "", err
 	}
@@ -335,7 +347,6 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 }
 
 func resetForTest() {
-	singletonInstance = nil
 	PasswordlessLoginEmailSentForTest = false
 	PasswordlessLoginEmailDataForTest = struct {
 		Email            string
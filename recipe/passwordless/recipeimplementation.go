@@ -24,55 +24,49 @@ import (
 
 func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInterface {
 	createCode := func(email *string, phoneNumber *string, userInputCode *string, tenantId string, userContext supertokens.UserContext) (plessmodels.CreateCodeResponse, error) {
-		body := map[string]interface{}{}
+		body := supertokens.CoreCreatePasswordlessCodeRequest{
+			UserInputCode: userInputCode,
+		}
 		if email != nil {
-			body["email"] = *email
+			body.Email = email
 		} else if phoneNumber != nil {
-			body["phoneNumber"] = *phoneNumber
-		}
-		if userInputCode != nil {
-			body["userInputCode"] = *userInputCode
+			body.PhoneNumber = phoneNumber
 		}
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup/code", body, userContext)
+		var response supertokens.CorePasswordlessCodeResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup/code", body, &response, userContext)
 		if err != nil {
 			return plessmodels.CreateCodeResponse{}, err
 		}
 		return plessmodels.CreateCodeResponse{
-			OK: &plessmodels.NewCode{
-				PreAuthSessionID: response["preAuthSessionId"].(string),
-				CodeID:           response["codeId"].(string),
-				DeviceID:         response["deviceId"].(string),
-				UserInputCode:    response["userInputCode"].(string),
-				LinkCode:         response["linkCode"].(string),
-				CodeLifetime:     uint64(response["codeLifetime"].(float64)),
-				TimeCreated:      uint64(response["timeCreated"].(float64)),
-			},
+			OK: newCodeFromCoreResponse(response),
 		}, nil
 	}
 
 	consumeCode := func(userInput *plessmodels.UserInputCodeWithDeviceID, linkCode *string, preAuthSessionID string, tenantId string, userContext supertokens.UserContext) (plessmodels.ConsumeCodeResponse, error) {
-		body := map[string]interface{}{
-			"preAuthSessionId": preAuthSessionID,
+		body := supertokens.CoreConsumePasswordlessCodeRequest{
+			PreAuthSessionId: preAuthSessionID,
 		}
 		if userInput != nil {
-			body["userInputCode"] = userInput.Code
-			body["deviceId"] = userInput.DeviceID
+			body.UserInputCode = &userInput.Code
+			body.DeviceId = &userInput.DeviceID
 		} else if linkCode != nil {
-			body["linkCode"] = *linkCode
+			body.LinkCode = linkCode
 		}
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup/code/consume", body, userContext)
+		var response supertokens.CoreConsumePasswordlessCodeResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup/code/consume", body, &response, userContext)
 		if err != nil {
 			return plessmodels.ConsumeCodeResponse{}, err
 		}
-		status := response["status"].(string)
+		status := response.Status
 		if status == "OK" {
+			supertokens.RecordSignInUpMetric(RECIPE_ID, tenantId, response.CreatedNewUser)
 			return plessmodels.ConsumeCodeResponse{
 				OK: &struct {
 					CreatedNewUser bool
 					User           plessmodels.User
 				}{
-					CreatedNewUser: response["createdNewUser"].(bool),
-					User:           getUserFromJSONResponse(response["user"].(map[string]interface{})),
+					CreatedNewUser: response.CreatedNewUser,
+					User:           plessmodels.User(response.User),
 				},
 			}, nil
 		} else if status == "INCORRECT_USER_INPUT_CODE_ERROR" {
@@ -81,8 +75,8 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 					FailedCodeInputAttemptCount int
 					MaximumCodeInputAttempts    int
 				}{
-					FailedCodeInputAttemptCount: int(response["failedCodeInputAttemptCount"].(float64)),
-					MaximumCodeInputAttempts:    int(response["maximumCodeInputAttempts"].(float64)),
+					FailedCodeInputAttemptCount: response.FailedCodeInputAttemptCount,
+					MaximumCodeInputAttempts:    response.MaximumCodeInputAttempts,
 				},
 			}, nil
 
@@ -92,8 +86,8 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 					FailedCodeInputAttemptCount int
 					MaximumCodeInputAttempts    int
 				}{
-					FailedCodeInputAttemptCount: int(response["failedCodeInputAttemptCount"].(float64)),
-					MaximumCodeInputAttempts:    int(response["maximumCodeInputAttempts"].(float64)),
+					FailedCodeInputAttemptCount: response.FailedCodeInputAttemptCount,
+					MaximumCodeInputAttempts:    response.MaximumCodeInputAttempts,
 				},
 			}, nil
 		} else {
@@ -104,32 +98,22 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	createNewCodeForDevice := func(deviceID string, userInputCode *string, tenantId string, userContext supertokens.UserContext) (plessmodels.ResendCodeResponse, error) {
-		body := map[string]interface{}{
-			"deviceId": deviceID,
+		body := supertokens.CoreCreateNewPasswordlessCodeForDeviceRequest{
+			DeviceId:      deviceID,
+			UserInputCode: userInputCode,
 		}
 
-		if userInputCode != nil {
-			body["userInputCode"] = *userInputCode
-		}
-
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup/code", body, userContext)
+		var response supertokens.CorePasswordlessCodeResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup/code", body, &response, userContext)
 		if err != nil {
 			return plessmodels.ResendCodeResponse{}, err
 		}
 
-		status := response["status"].(string)
+		status := response.Status
 
 		if status == "OK" {
 			return plessmodels.ResendCodeResponse{
-				OK: &plessmodels.NewCode{
-					PreAuthSessionID: response["preAuthSessionId"].(string),
-					CodeID:           response["codeId"].(string),
-					DeviceID:         response["deviceId"].(string),
-					UserInputCode:    response["userInputCode"].(string),
-					LinkCode:         response["linkCode"].(string),
-					CodeLifetime:     uint64(response["codeLifetime"].(float64)),
-					TimeCreated:      uint64(response["timeCreated"].(float64)),
-				},
+				OK: newCodeFromCoreResponse(response),
 			}, nil
 		} else if status == "USER_INPUT_CODE_ALREADY_USED_ERROR" {
 			return plessmodels.ResendCodeResponse{
@@ -143,63 +127,61 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	getUserByEmail := func(email string, tenantId string, userContext supertokens.UserContext) (*plessmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
+		var response supertokens.CorePasswordlessUserResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/user", map[string]string{
 			"email": email,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status := response["status"].(string)
 
-		if status == "OK" {
-			user := getUserFromJSONResponse(response["user"].(map[string]interface{}))
-			return &user, nil
+		if response.Status == "OK" {
+			return (*plessmodels.User)(response.User), nil
 		}
 		return nil, nil
 	}
 
 	getUserByID := func(userID string, userContext supertokens.UserContext) (*plessmodels.User, error) {
-		response, err := querier.SendGetRequest("/recipe/user", map[string]string{
+		var response supertokens.CorePasswordlessUserResponse
+		err := querier.SendGetRequestTyped("/recipe/user", map[string]string{
 			"userId": userID,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status := response["status"].(string)
 
-		if status == "OK" {
-			user := getUserFromJSONResponse(response["user"].(map[string]interface{}))
-			return &user, nil
+		if response.Status == "OK" {
+			return (*plessmodels.User)(response.User), nil
 		}
 		return nil, nil
 	}
 
 	getUserByPhoneNumber := func(phoneNumber string, tenantId string, userContext supertokens.UserContext) (*plessmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
+		var response supertokens.CorePasswordlessUserResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/user", map[string]string{
 			"phoneNumber": phoneNumber,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		status := response["status"].(string)
 
-		if status == "OK" {
-			user := getUserFromJSONResponse(response["user"].(map[string]interface{}))
-			return &user, nil
+		if response.Status == "OK" {
+			return (*plessmodels.User)(response.User), nil
 		}
 		return nil, nil
 	}
 
 	listCodesByDeviceID := func(deviceID string, tenantId string, userContext supertokens.UserContext) (*plessmodels.DeviceType, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/signinup/codes", map[string]string{
+		var response supertokens.CoreListPasswordlessCodesResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/signinup/codes", map[string]string{
 			"deviceId": deviceID,
-		}, userContext)
+		}, &response, userContext)
 
 		if err != nil {
 			return nil, err
 		}
 
-		devices := getDevicesFromResponse(response["devices"].([]interface{}))
+		devices := devicesFromCoreResponse(response)
 
 		if len(devices) == 1 {
 			return &devices[0], nil
@@ -209,39 +191,42 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	listCodesByEmail := func(email string, tenantId string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/signinup/codes", map[string]string{
+		var response supertokens.CoreListPasswordlessCodesResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/signinup/codes", map[string]string{
 			"email": email,
-		}, userContext)
+		}, &response, userContext)
 
 		if err != nil {
 			return nil, err
 		}
 
-		return getDevicesFromResponse(response["devices"].([]interface{})), nil
+		return devicesFromCoreResponse(response), nil
 	}
 
 	listCodesByPhoneNumber := func(phoneNumber string, tenantId string, userContext supertokens.UserContext) ([]plessmodels.DeviceType, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/signinup/codes", map[string]string{
+		var response supertokens.CoreListPasswordlessCodesResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/signinup/codes", map[string]string{
 			"phoneNumber": phoneNumber,
-		}, userContext)
+		}, &response, userContext)
 
 		if err != nil {
 			return nil, err
 		}
 
-		return getDevicesFromResponse(response["devices"].([]interface{})), nil
+		return devicesFromCoreResponse(response), nil
 	}
 
 	listCodesByPreAuthSessionID := func(preAuthSessionID string, tenantId string, userContext supertokens.UserContext) (*plessmodels.DeviceType, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/signinup/codes", map[string]string{
+		var response supertokens.CoreListPasswordlessCodesResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/signinup/codes", map[string]string{
 			"preAuthSessionId": preAuthSessionID,
-		}, userContext)
+		}, &response, userContext)
 
 		if err != nil {
 			return nil, err
 		}
 
-		devices := getDevicesFromResponse(response["devices"].([]interface{}))
+		devices := devicesFromCoreResponse(response)
 
 		if len(devices) == 1 {
 			return &devices[0], nil
@@ -251,13 +236,14 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	revokeAllCodes := func(email *string, phoneNumber *string, tenantId string, userContext supertokens.UserContext) error {
-		body := map[string]interface{}{}
+		body := supertokens.CoreRevokePasswordlessCodesRequest{}
 		if email != nil {
-			body["email"] = *email
+			body.Email = email
 		} else if phoneNumber != nil {
-			body["phoneNumber"] = *phoneNumber
+			body.PhoneNumber = phoneNumber
 		}
-		_, err := querier.SendPostRequest(tenantId+"/recipe/signinup/codes/remove", body, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup/codes/remove", body, &response, userContext)
 		if err != nil {
 			return err
 		}
@@ -265,10 +251,11 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	revokeCode := func(codeID string, tenantId string, userContext supertokens.UserContext) error {
-		body := map[string]interface{}{
-			"codeId": codeID,
+		body := supertokens.CoreRevokePasswordlessCodeRequest{
+			CodeId: codeID,
 		}
-		_, err := querier.SendPostRequest(tenantId+"/recipe/signinup/code/remove", body, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup/code/remove", body, &response, userContext)
 		if err != nil {
 			return err
 		}
@@ -276,22 +263,19 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	updateUser := func(userID string, email *string, phoneNumber *string, userContext supertokens.UserContext) (plessmodels.UpdateUserResponse, error) {
-		body := map[string]interface{}{
-			"userId": userID,
-		}
-		if email != nil {
-			body["email"] = *email
-		}
-		if phoneNumber != nil {
-			body["phoneNumber"] = *phoneNumber
+		body := supertokens.CoreUpdatePasswordlessUserRequest{
+			UserId:      userID,
+			Email:       email,
+			PhoneNumber: phoneNumber,
 		}
 
-		response, err := querier.SendPutRequest("/recipe/user", body, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPutRequestTyped("/recipe/user", body, &response, userContext)
 		if err != nil {
 			return plessmodels.UpdateUserResponse{}, err
 		}
 
-		status := response["status"].(string)
+		status := response.Status
 
 		if status == "OK" {
 			return plessmodels.UpdateUserResponse{
@@ -313,17 +297,17 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	deleteEmailForUser := func(userID string, userContext supertokens.UserContext) (plessmodels.DeleteUserResponse, error) {
-		body := map[string]interface{}{
-			"userId": userID,
-			"email":  nil,
+		body := supertokens.CoreDeletePasswordlessUserEmailRequest{
+			UserId: userID,
 		}
 
-		response, err := querier.SendPutRequest("/recipe/user", body, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPutRequestTyped("/recipe/user", body, &response, userContext)
 		if err != nil {
 			return plessmodels.DeleteUserResponse{}, err
 		}
 
-		status := response["status"].(string)
+		status := response.Status
 
 		if status == "OK" {
 			return plessmodels.DeleteUserResponse{
@@ -339,17 +323,17 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 
 	deletePhoneNumberForUser := func(userID string, userContext supertokens.UserContext) (plessmodels.DeleteUserResponse, error) {
-		body := map[string]interface{}{
-			"userId":      userID,
-			"phoneNumber": nil,
+		body := supertokens.CoreDeletePasswordlessUserPhoneNumberRequest{
+			UserId: userID,
 		}
 
-		response, err := querier.SendPutRequest("/recipe/user", body, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPutRequestTyped("/recipe/user", body, &response, userContext)
 		if err != nil {
 			return plessmodels.DeleteUserResponse{}, err
 		}
 
-		status := response["status"].(string)
+		status := response.Status
 
 		if status == "OK" {
 			return plessmodels.DeleteUserResponse{
@@ -383,74 +367,32 @@ func MakeRecipeImplementation(querier supertokens.Querier) plessmodels.RecipeInt
 	}
 }
 
-func getDevicesFromResponse(devicesJSON []interface{}) []plessmodels.DeviceType {
+func newCodeFromCoreResponse(response supertokens.CorePasswordlessCodeResponse) *plessmodels.NewCode {
+	return &plessmodels.NewCode{
+		PreAuthSessionID: response.PreAuthSessionID,
+		CodeID:           response.CodeID,
+		DeviceID:         response.DeviceID,
+		UserInputCode:    response.UserInputCode,
+		LinkCode:         response.LinkCode,
+		CodeLifetime:     response.CodeLifetime,
+		TimeCreated:      response.TimeCreated,
+	}
+}
+
+func devicesFromCoreResponse(response supertokens.CoreListPasswordlessCodesResponse) []plessmodels.DeviceType {
 	result := []plessmodels.DeviceType{}
-	for _, deviceJSON := range devicesJSON {
+	for _, deviceJSON := range response.Devices {
 		device := plessmodels.DeviceType{
-			PreAuthSessionID:            (deviceJSON.(map[string]interface{}))["preAuthSessionId"].(string),
-			FailedCodeInputAttemptCount: int((deviceJSON.(map[string]interface{}))["failedCodeInputAttemptCount"].(float64)),
-			Codes:                       getCodesFromDevicesResponse((deviceJSON.(map[string]interface{}))["codes"].([]interface{})),
-		}
-		{
-			email, ok := (deviceJSON.(map[string]interface{}))["email"]
-			if ok {
-				emailStr := email.(string)
-				device.Email = &emailStr
-			}
+			PreAuthSessionID:            deviceJSON.PreAuthSessionID,
+			FailedCodeInputAttemptCount: deviceJSON.FailedCodeInputAttemptCount,
+			Email:                       deviceJSON.Email,
+			PhoneNumber:                 deviceJSON.PhoneNumber,
+			Codes:                       []plessmodels.Code{},
 		}
-		{
-			phoneNumber, ok := (deviceJSON.(map[string]interface{}))["phoneNumber"]
-			if ok {
-				phoneNumberStr := phoneNumber.(string)
-				device.PhoneNumber = &phoneNumberStr
-			}
+		for _, codeJSON := range deviceJSON.Codes {
+			device.Codes = append(device.Codes, plessmodels.Code(codeJSON))
 		}
-
 		result = append(result, device)
 	}
 	return result
 }
-
-func getCodesFromDevicesResponse(codesJSON []interface{}) []plessmodels.Code {
-	result := []plessmodels.Code{}
-	for _, codeJSON := range codesJSON {
-		code := plessmodels.Code{
-			CodeID:       codeJSON.(map[string]interface{})["codeId"].(string),
-			TimeCreated:  uint64(codeJSON.(map[string]interface{})["timeCreated"].(float64)),
-			CodeLifetime: uint64(codeJSON.(map[string]interface{})["codeLifetime"].(float64)),
-		}
-		result = append(result, code)
-	}
-	return result
-}
-
-func getUserFromJSONResponse(userJSON map[string]interface{}) plessmodels.User {
-	user := plessmodels.User{
-		ID:         userJSON["id"].(string),
-		TimeJoined: uint64(userJSON["timeJoined"].(float64)),
-	}
-	{
-		email, ok := userJSON["email"]
-		if ok {
-			emailStr := email.(string)
-			user.Email = &emailStr
-		}
-	}
-	{
-		phoneNumber, ok := userJSON["phoneNumber"]
-		if ok {
-			phoneNumberStr := phoneNumber.(string)
-			user.PhoneNumber = &phoneNumberStr
-		}
-	}
-	{
-		tenantIds, ok := userJSON["tenantIds"].([]interface{})
-		if ok {
-			user.TenantIds = make([]string, len(tenantIds))
-			for i, tenantId := range tenantIds {
-				user.TenantIds[i] = tenantId.(string)
-			}
-		}
-	}
-	return user
-}
@@ -32,7 +32,7 @@ const SUPERTOKENS_SMS_SERVICE_URL = "https://api.supertokens.com/0/services/sms"
 
 func MakeSupertokensSMSService(apiKey string) *smsdelivery.SmsDeliveryInterface {
 	sendPasswordlessLoginSms := func(input smsdelivery.PasswordlessLoginType, userContext supertokens.UserContext) error {
-		instance, err := supertokens.GetInstanceOrThrowError()
+		instance, err := supertokens.GetInstanceOrThrowError(userContext)
 		if err != nil {
 			return err
 		}
@@ -68,26 +68,24 @@ func MakeSupertokensSMSService(apiKey string) *smsdelivery.SmsDeliveryInterface
 		resp, err := client.Do(req)
 
 		if err == nil && resp.StatusCode < 300 {
-			supertokens.LogDebugMessage(fmt.Sprintf("Passwordless login SMS sent to %s", input.PhoneNumber))
+			supertokens.LogDebug(userContext, "Passwordless login SMS sent", "phoneNumber", input.PhoneNumber)
 			return nil
 		}
 
 		if err != nil {
-			supertokens.LogDebugMessage(fmt.Sprintf("Error: %s", err.Error()))
+			supertokens.LogDebug(userContext, "Error sending passwordless login SMS", "error", err)
 		} else {
-			supertokens.LogDebugMessage(fmt.Sprintf("Error status: %d", resp.StatusCode))
-			body, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				supertokens.LogDebugMessage(fmt.Sprintf("Error: %s", err.Error()))
+			body, readErr := ioutil.ReadAll(resp.Body)
+			if readErr != nil {
+				supertokens.LogDebug(userContext, "Error sending passwordless login SMS", "status", resp.StatusCode, "error", readErr)
 			} else {
-				supertokens.LogDebugMessage(fmt.Sprintf("Error response: %s", string(body)))
+				supertokens.LogDebug(userContext, "Error sending passwordless login SMS", "status", resp.StatusCode, "response", string(body))
 			}
 
 			err = errors.New(fmt.Sprintf("Error sending SMS. API returned %d status.", resp.StatusCode))
 		}
 
-		supertokens.LogDebugMessage("Logging the input below:")
-		supertokens.LogDebugMessage(string(jsonData))
+		supertokens.LogDebug(userContext, "Passwordless login SMS input", "input", data)
 		return err
 	}
 
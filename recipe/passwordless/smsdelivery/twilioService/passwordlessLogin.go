@@ -35,8 +35,8 @@ Or click ${magicLink} to login.
 
 This is valid for ${time}.`
 
-func getPasswordlessLoginSmsContent(input smsdelivery.PasswordlessLoginType) smsdelivery.SMSContent {
-	stInstance, err := supertokens.GetInstanceOrThrowError()
+func getPasswordlessLoginSmsContent(input smsdelivery.PasswordlessLoginType, userContext supertokens.UserContext) smsdelivery.SMSContent {
+	stInstance, err := supertokens.GetInstanceOrThrowError(userContext)
 	if err != nil {
 		panic("Please call supertokens.Init function before using the Middleware")
 	}
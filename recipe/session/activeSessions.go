@@ -0,0 +1,144 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// getSessionForActiveSessionsAPI returns the session of the request. Like for the sign out API, the global
+// claim validators are not run, so that users can always revoke their sessions. Impersonation sessions are
+// rejected, since the sessions of the user are not for the admin to see or revoke.
+func getSessionForActiveSessionsAPI(options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	sessionRequired := true
+	return GetSessionFromRequest(options.Req, options.Res, options.Config, &sessmodels.VerifySessionOptions{
+		SessionRequired: &sessionRequired,
+		OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+			return []claims.SessionClaimValidator{sessionclaims.ImpersonationClaimValidators.IsNotImpersonated()}, nil
+		},
+	}, options.RecipeImplementation, userContext)
+}
+
+func ActiveSessionsAPI(apiImplementation sessmodels.APIInterface, options sessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.ActiveSessionsGET == nil || (*apiImplementation.ActiveSessionsGET == nil) {
+		options.OtherHandler.ServeHTTP(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := getSessionForActiveSessionsAPI(options, userContext)
+	if err != nil {
+		return err
+	}
+
+	resp, err := (*apiImplementation.ActiveSessionsGET)(sessionContainer, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if resp.OK != nil {
+		sessions := resp.OK.Sessions
+		if sessions == nil {
+			sessions = []sessmodels.ActiveSession{}
+		}
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":   "OK",
+			"sessions": sessions,
+		})
+	} else if resp.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*resp.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
+
+func RevokeSessionAPI(apiImplementation sessmodels.APIInterface, options sessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.RevokeSessionPOST == nil || (*apiImplementation.RevokeSessionPOST == nil) {
+		options.OtherHandler.ServeHTTP(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := getSessionForActiveSessionsAPI(options, userContext)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return supertokens.BadInputError{Msg: "Please provide the session handle"}
+	}
+	sessionHandle, ok := readBody["sessionHandle"].(string)
+	if !ok || sessionHandle == "" {
+		return supertokens.BadInputError{Msg: "Please provide the session handle"}
+	}
+
+	resp, err := (*apiImplementation.RevokeSessionPOST)(sessionHandle, sessionContainer, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if resp.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+		})
+	} else if resp.SessionNotFoundError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "SESSION_NOT_FOUND_ERROR",
+		})
+	} else if resp.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*resp.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
+
+func RevokeOtherSessionsAPI(apiImplementation sessmodels.APIInterface, options sessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.RevokeOtherSessionsPOST == nil || (*apiImplementation.RevokeOtherSessionsPOST == nil) {
+		options.OtherHandler.ServeHTTP(options.Res, options.Req)
+		return nil
+	}
+
+	sessionContainer, err := getSessionForActiveSessionsAPI(options, userContext)
+	if err != nil {
+		return err
+	}
+
+	resp, err := (*apiImplementation.RevokeOtherSessionsPOST)(sessionContainer, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if resp.OK != nil {
+		revokedSessionHandles := resp.OK.RevokedSessionHandles
+		if revokedSessionHandles == nil {
+			revokedSessionHandles = []string{}
+		}
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status":                "OK",
+			"revokedSessionHandles": revokedSessionHandles,
+		})
+	} else if resp.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*resp.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
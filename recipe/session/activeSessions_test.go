@@ -0,0 +1,93 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func newActiveSessionsTestInstance(t *testing.T, config *sessmodels.TypeInput) (http.Handler, supertokens.UserContext) {
+	userContext := newSessionTestUserContext(t, nil, config)
+	return getInstanceForTest(t, userContext).Middleware(http.NotFoundHandler()), userContext
+}
+
+func callActiveSessionsAPI(handler http.Handler, method string, path string, accessToken string, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	var result map[string]interface{}
+	json.Unmarshal(res.Body.Bytes(), &result)
+	return res.Code, result
+}
+
+func TestThatUsersCanListAndRevokeTheirSessions(t *testing.T) {
+	exposeActiveSessionsAPIs := &sessmodels.TypeInput{ExposeActiveSessionsAPIs: true}
+	handler, userContext := newActiveSessionsTestInstance(t, exposeActiveSessionsAPIs)
+
+	current, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, map[string]interface{}{
+		"st-device": map[string]interface{}{"userAgent": "Firefox"},
+	}, nil, userContext)
+	assert.NoError(t, err)
+	other, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	third, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	otherUser, err := CreateNewSessionWithoutRequestResponse("public", "otherUserId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	status, body := callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", current.GetAccessToken(), "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "OK", body["status"])
+	sessions := body["sessions"].([]interface{})
+	assert.Len(t, sessions, 3)
+	for _, listed := range sessions {
+		listed := listed.(map[string]interface{})
+		assert.Equal(t, listed["sessionHandle"] == current.GetHandle(), listed["current"])
+		if listed["sessionHandle"] == current.GetHandle() {
+			assert.Equal(t, map[string]interface{}{"userAgent": "Firefox"}, listed["deviceInfo"])
+		} else {
+			assert.NotContains(t, listed, "deviceInfo")
+		}
+	}
+
+	// sessions of other users can not be revoked
+	_, body = callActiveSessionsAPI(handler, http.MethodPost, "/auth/sessions/revoke", current.GetAccessToken(), `{"sessionHandle":"`+otherUser.GetHandle()+`"}`)
+	assert.Equal(t, "SESSION_NOT_FOUND_ERROR", body["status"])
+	information, err := GetSessionInformation(otherUser.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, information)
+
+	_, body = callActiveSessionsAPI(handler, http.MethodPost, "/auth/sessions/revoke", current.GetAccessToken(), `{"sessionHandle":"`+other.GetHandle()+`"}`)
+	assert.Equal(t, "OK", body["status"])
+	information, err = GetSessionInformation(other.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, information)
+
+	_, body = callActiveSessionsAPI(handler, http.MethodPost, "/auth/sessions/revoke/others", current.GetAccessToken(), "")
+	assert.Equal(t, "OK", body["status"])
+	assert.Equal(t, []interface{}{third.GetHandle()}, body["revokedSessionHandles"])
+	handles, err := GetAllSessionHandlesForUser("userId", nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{current.GetHandle()}, handles)
+
+	status, _ = callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestThatTheActiveSessionsAPIsAreNotExposedByDefault(t *testing.T) {
+	handler, userContext := newActiveSessionsTestInstance(t, nil)
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	status, _ := callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", sessionContainer.GetAccessToken(), "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = callActiveSessionsAPI(handler, http.MethodPost, "/auth/sessions/revoke/others", sessionContainer.GetAccessToken(), "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
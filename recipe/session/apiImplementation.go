@@ -80,9 +80,144 @@ func MakeAPIImplementation() sessmodels.APIInterface {
 		}, nil
 	}
 
+	activeSessionsGET := func(sessionContainer sessmodels.SessionContainer, options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.ActiveSessionsGETResponse, error) {
+		tenantId := sessionContainer.GetTenantIdWithContext(userContext)
+		fetchAcrossAllTenants := false
+		sessionHandles, err := (*options.RecipeImplementation.GetAllSessionHandlesForUser)(sessionContainer.GetUserIDWithContext(userContext), tenantId, &fetchAcrossAllTenants, userContext)
+		if err != nil {
+			return sessmodels.ActiveSessionsGETResponse{}, err
+		}
+
+		sessions := []sessmodels.ActiveSession{}
+		for _, sessionHandle := range sessionHandles {
+			sessionInformation, err := (*options.RecipeImplementation.GetSessionInformation)(sessionHandle, userContext)
+			if err != nil {
+				return sessmodels.ActiveSessionsGETResponse{}, err
+			}
+			if sessionInformation == nil {
+				// the session expired or was revoked in the meantime
+				continue
+			}
+			sessions = append(sessions, sessmodels.ActiveSession{
+				SessionHandle: sessionInformation.SessionHandle,
+				TenantId:      sessionInformation.TenantId,
+				TimeCreated:   sessionInformation.TimeCreated,
+				Expiry:        sessionInformation.Expiry,
+				Current:       sessionInformation.SessionHandle == sessionContainer.GetHandleWithContext(userContext),
+				DeviceInfo:    sessionInformation.DeviceInfo,
+			})
+		}
+
+		return sessmodels.ActiveSessionsGETResponse{
+			OK: &struct{ Sessions []sessmodels.ActiveSession }{
+				Sessions: sessions,
+			},
+		}, nil
+	}
+
+	revokeSessionPOST := func(sessionHandle string, sessionContainer sessmodels.SessionContainer, options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.RevokeSessionPOSTResponse, error) {
+		if sessionHandle == sessionContainer.GetHandleWithContext(userContext) {
+			// this also clears the tokens of the current session
+			err := sessionContainer.RevokeSessionWithContext(userContext)
+			if err != nil {
+				return sessmodels.RevokeSessionPOSTResponse{}, err
+			}
+			return sessmodels.RevokeSessionPOSTResponse{
+				OK: &struct{}{},
+			}, nil
+		}
+
+		sessionInformation, err := (*options.RecipeImplementation.GetSessionInformation)(sessionHandle, userContext)
+		if err != nil {
+			return sessmodels.RevokeSessionPOSTResponse{}, err
+		}
+		// we do not tell the user whether a session of another user exists
+		if sessionInformation == nil || sessionInformation.UserId != sessionContainer.GetUserIDWithContext(userContext) || sessionInformation.TenantId != sessionContainer.GetTenantIdWithContext(userContext) {
+			return sessmodels.RevokeSessionPOSTResponse{
+				SessionNotFoundError: &struct{}{},
+			}, nil
+		}
+
+		_, err = (*options.RecipeImplementation.RevokeSession)(sessionHandle, userContext)
+		if err != nil {
+			return sessmodels.RevokeSessionPOSTResponse{}, err
+		}
+		return sessmodels.RevokeSessionPOSTResponse{
+			OK: &struct{}{},
+		}, nil
+	}
+
+	revokeOtherSessionsPOST := func(sessionContainer sessmodels.SessionContainer, options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.RevokeOtherSessionsPOSTResponse, error) {
+		fetchAcrossAllTenants := false
+		sessionHandles, err := (*options.RecipeImplementation.GetAllSessionHandlesForUser)(sessionContainer.GetUserIDWithContext(userContext), sessionContainer.GetTenantIdWithContext(userContext), &fetchAcrossAllTenants, userContext)
+		if err != nil {
+			return sessmodels.RevokeOtherSessionsPOSTResponse{}, err
+		}
+
+		otherSessionHandles := []string{}
+		for _, sessionHandle := range sessionHandles {
+			if sessionHandle != sessionContainer.GetHandleWithContext(userContext) {
+				otherSessionHandles = append(otherSessionHandles, sessionHandle)
+			}
+		}
+
+		revokedSessionHandles := []string{}
+		if len(otherSessionHandles) > 0 {
+			revokedSessionHandles, err = (*options.RecipeImplementation.RevokeMultipleSessions)(otherSessionHandles, userContext)
+			if err != nil {
+				return sessmodels.RevokeOtherSessionsPOSTResponse{}, err
+			}
+		}
+
+		return sessmodels.RevokeOtherSessionsPOSTResponse{
+			OK: &struct{ RevokedSessionHandles []string }{
+				RevokedSessionHandles: revokedSessionHandles,
+			},
+		}, nil
+	}
+
+	impersonatePOST := func(targetUserID string, tenantId string, adminSession sessmodels.SessionContainer, options sessmodels.APIOptions, userContext supertokens.UserContext) (sessmodels.ImpersonatePOSTResponse, error) {
+		// impersonation sessions can not be used to impersonate other users
+		if adminSession.IsImpersonatedWithContext(userContext) || targetUserID == adminSession.GetUserIDWithContext(userContext) {
+			return sessmodels.ImpersonatePOSTResponse{
+				ImpersonationNotAllowedError: &struct{}{},
+			}, nil
+		}
+		canImpersonate, err := options.Config.Impersonation.CanImpersonate(adminSession, targetUserID, tenantId, userContext)
+		if err != nil {
+			return sessmodels.ImpersonatePOSTResponse{}, err
+		}
+		if !canImpersonate {
+			return sessmodels.ImpersonatePOSTResponse{
+				ImpersonationNotAllowedError: &struct{}{},
+			}, nil
+		}
+
+		instance, err := getRecipeInstanceOrThrowError(userContext)
+		if err != nil {
+			return sessmodels.ImpersonatePOSTResponse{}, err
+		}
+		sessionContainer, err := createImpersonationSessionInRequest(options.Req, options.Res, instance, tenantId, targetUserID, adminSession.GetUserIDWithContext(userContext), userContext)
+		if err != nil {
+			return sessmodels.ImpersonatePOSTResponse{}, err
+		}
+		return sessmodels.ImpersonatePOSTResponse{
+			OK: &struct {
+				Session sessmodels.SessionContainer
+			}{
+				Session: sessionContainer,
+			},
+		}, nil
+	}
+
 	return sessmodels.APIInterface{
 		RefreshPOST:   &refreshPOST,
 		VerifySession: &verifySession,
 		SignOutPOST:   &signOutPOST,
+
+		ActiveSessionsGET:       &activeSessionsGET,
+		RevokeSessionPOST:       &revokeSessionPOST,
+		RevokeOtherSessionsPOST: &revokeOtherSessionsPOST,
+		ImpersonatePOST:         &impersonatePOST,
 	}
 }
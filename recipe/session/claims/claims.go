@@ -2,12 +2,13 @@ package claims
 
 import (
 	"github.com/supertokens/supertokens-golang/supertokens"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func SessionClaim(key string, fetchValue FetchValueFunc) *TypeSessionClaim {
 	sessionClaim := &TypeSessionClaim{
 		Key:        key,
-		FetchValue: fetchValue,
+		FetchValue: withFetchValueSpan(key, fetchValue),
 	}
 
 	sessionClaim.Build = func(userId string, tenantId string, payloadToUpdate map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
@@ -31,6 +32,18 @@ func SessionClaim(key string, fetchValue FetchValueFunc) *TypeSessionClaim {
 	return sessionClaim
 }
 
+func withFetchValueSpan(key string, fetchValue FetchValueFunc) FetchValueFunc {
+	if fetchValue == nil {
+		return nil
+	}
+	return func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		span := supertokens.StartSpan(userContext, "claims.FetchValue", attribute.String("supertokens.claim", key), attribute.String("supertokens.tenant_id", tenantId))
+		value, err := fetchValue(userId, tenantId, userContext)
+		span.End(err)
+		return value, err
+	}
+}
+
 type FetchValueFunc func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error)
 
 type TypeSessionClaim struct {
@@ -54,9 +67,13 @@ type SessionClaimValidator struct {
 type ClaimValidationResult struct {
 	IsValid bool
 	Reason  interface{} // This can be nil, add checks when used
+	// MessageKey is the key of the message in the reason, with which the message is translated if I18n is
+	// configured. Messages without a key are not translated.
+	MessageKey supertokens.MessageKey
 }
 
 type ClaimValidationError struct {
-	ID     string      `json:"id"`
-	Reason interface{} `json:"reason,omitempty"` // This can be nil, add checks when used
+	ID         string                 `json:"id"`
+	Reason     interface{}            `json:"reason,omitempty"` // This can be nil, add checks when used
+	MessageKey supertokens.MessageKey `json:"-"`
 }
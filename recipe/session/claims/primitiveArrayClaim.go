@@ -37,7 +37,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if claimVal == nil {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
 							Reason: map[string]interface{}{
 								"message":           "value does not exist",
 								"expectedToInclude": val,
@@ -48,7 +49,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
 					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
 							Reason: map[string]interface{}{
 								"message":         "expired",
 								"ageInSeconds":    ageInSeconds,
@@ -58,7 +60,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					}
 					if !includes(claimVal, val) {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
 							Reason: map[string]interface{}{
 								"message":           "wrong value",
 								"expectedToInclude": val,
@@ -98,7 +101,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if claimVal == nil {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
 							Reason: map[string]interface{}{
 								"message":           "value does not exist",
 								"expectedToInclude": val,
@@ -109,7 +113,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
 					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
 							Reason: map[string]interface{}{
 								"message":         "expired",
 								"ageInSeconds":    ageInSeconds,
@@ -119,7 +124,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					}
 					if includes(claimVal, val) {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
 							Reason: map[string]interface{}{
 								"message":           "wrong value",
 								"expectedToExclude": val,
@@ -159,7 +165,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if claimVal == nil {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
 							Reason: map[string]interface{}{
 								"message":           "value does not exist",
 								"expectedToInclude": vals,
@@ -170,7 +177,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
 					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
 							Reason: map[string]interface{}{
 								"message":         "expired",
 								"ageInSeconds":    ageInSeconds,
@@ -181,7 +189,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if !includesAll(claimVal, vals) {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
 							Reason: map[string]interface{}{
 								"message":           "wrong value",
 								"expectedToInclude": vals,
@@ -221,7 +230,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if claimVal == nil {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
 							Reason: map[string]interface{}{
 								"message":           "value does not exist",
 								"expectedToInclude": vals,
@@ -232,7 +242,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
 					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
 							Reason: map[string]interface{}{
 								"message":         "expired",
 								"ageInSeconds":    ageInSeconds,
@@ -243,7 +254,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if excludesAll(claimVal, vals) {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
 							Reason: map[string]interface{}{
 								"message":                       "wrong value",
 								"expectedToIncludeAtLeastOneOf": vals,
@@ -283,7 +295,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if claimVal == nil {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
 							Reason: map[string]interface{}{
 								"message":              "value does not exist",
 								"expectedToNotInclude": vals,
@@ -294,7 +307,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
 					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
 							Reason: map[string]interface{}{
 								"message":         "expired",
 								"ageInSeconds":    ageInSeconds,
@@ -305,7 +319,8 @@ func PrimitiveArrayClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInS
 
 					if !excludesAll(claimVal, vals) {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
 							Reason: map[string]interface{}{
 								"message":              "wrong value",
 								"expectedToNotInclude": vals,
@@ -72,7 +72,8 @@ func PrimitiveClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInSecond
 
 					if claimVal == nil {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
 							Reason: map[string]interface{}{
 								"message":       "value does not exist",
 								"expectedValue": val,
@@ -83,7 +84,8 @@ func PrimitiveClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInSecond
 					ageInSeconds := (time.Now().UnixNano()/1000000 - *sessionClaim.GetLastRefetchTime(payload, userContext)) / 1000
 					if maxAgeInSeconds != nil && ageInSeconds > *maxAgeInSeconds {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
 							Reason: map[string]interface{}{
 								"message":         "expired",
 								"ageInSeconds":    ageInSeconds,
@@ -93,7 +95,8 @@ func PrimitiveClaim(key string, fetchValue FetchValueFunc, defaultMaxAgeInSecond
 					}
 					if claimVal != val {
 						return ClaimValidationResult{
-							IsValid: false,
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
 							Reason: map[string]interface{}{
 								"message":       "wrong value",
 								"expectedValue": val,
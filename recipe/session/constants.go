@@ -23,6 +23,12 @@ const (
 	RefreshAPIPath = "/session/refresh"
 	SignoutAPIPath = "/signout"
 
+	ActiveSessionsAPIPath      = "/sessions"
+	RevokeSessionAPIPath       = "/sessions/revoke"
+	RevokeOtherSessionsAPIPath = "/sessions/revoke/others"
+
+	ImpersonateAPIPath = "/session/impersonate"
+
 	AntiCSRF_VIA_TOKEN         = "VIA_TOKEN"
 	AntiCSRF_VIA_CUSTOM_HEADER = "VIA_CUSTOM_HEADER"
 	AntiCSRF_NONE              = "NONE"
@@ -0,0 +1,253 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// deviceInfoKeyInSessionData is the reserved key in the session data in the database under which the device
+// of the session is stored. It is removed from the session data that the app reads, and kept when the app
+// updates it.
+const deviceInfoKeyInSessionData = "st-device"
+
+func normaliseDeviceInfoConfig(config *sessmodels.DeviceInfoConfig) (*sessmodels.NormalisedDeviceInfoConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	trustedProxies := []*net.IPNet{}
+	for _, trustedProxy := range config.TrustedProxies {
+		if !strings.Contains(trustedProxy, "/") {
+			ip := net.ParseIP(trustedProxy)
+			if ip == nil {
+				return nil, supertokens.BadInputError{Msg: "Invalid trusted proxy: " + trustedProxy}
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+			trustedProxies = append(trustedProxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(trustedProxy)
+		if err != nil {
+			return nil, supertokens.BadInputError{Msg: "Invalid trusted proxy: " + trustedProxy}
+		}
+		trustedProxies = append(trustedProxies, ipNet)
+	}
+	return &sessmodels.NormalisedDeviceInfoConfig{
+		TrustedProxies: trustedProxies,
+		GetGeolocation: config.GetGeolocation,
+	}, nil
+}
+
+// addDeviceInfoToSessionData returns a copy of the session data with the device of the request added to it
+func addDeviceInfoToSessionData(req *http.Request, config *sessmodels.NormalisedDeviceInfoConfig, sessionDataInDatabase map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
+	deviceInfo := getDeviceInfoFromRequest(req, config, userContext)
+	deviceInfoMap, err := deviceInfoToMap(deviceInfo)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	for key, value := range sessionDataInDatabase {
+		result[key] = value
+	}
+	result[deviceInfoKeyInSessionData] = deviceInfoMap
+	return result, nil
+}
+
+func getDeviceInfoFromRequest(req *http.Request, config *sessmodels.NormalisedDeviceInfoConfig, userContext supertokens.UserContext) sessmodels.DeviceInfo {
+	userAgent := req.Header.Get("User-Agent")
+	deviceType, browser, os := parseUserAgent(userAgent)
+	deviceInfo := sessmodels.DeviceInfo{
+		IPAddress:  getClientIPAddress(req, config.TrustedProxies),
+		UserAgent:  userAgent,
+		DeviceType: deviceType,
+		Browser:    browser,
+		OS:         os,
+	}
+	if config.GetGeolocation != nil && deviceInfo.IPAddress != "" {
+		geolocation, err := config.GetGeolocation(deviceInfo.IPAddress, userContext)
+		if err != nil {
+			// the location is only informational, so we do not fail creating the session because of it
+			supertokens.LogWarn(userContext, "getting the location of the device failed", "error", err.Error())
+		} else {
+			deviceInfo.Geolocation = geolocation
+		}
+	}
+	return deviceInfo
+}
+
+// getClientIPAddress returns the IP address of the client. If the request comes from a trusted proxy, this
+// is the last address in the X-Forwarded-For header that is not one of a trusted proxy.
+func getClientIPAddress(req *http.Request, trustedProxies []*net.IPNet) string {
+	remoteAddress := req.RemoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddress); err == nil {
+		remoteAddress = host
+	}
+	if !isTrustedProxy(remoteAddress, trustedProxies) {
+		return remoteAddress
+	}
+
+	forwardedFor := []string{}
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		forwardedFor = append(forwardedFor, strings.Split(header, ",")...)
+	}
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		address := strings.TrimSpace(forwardedFor[i])
+		if net.ParseIP(address) == nil {
+			// we can not trust anything that was added before an invalid address
+			break
+		}
+		if !isTrustedProxy(address, trustedProxies) {
+			return address
+		}
+		remoteAddress = address
+	}
+	return remoteAddress
+}
+
+func isTrustedProxy(address string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, trustedProxy := range trustedProxies {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUserAgent returns the device type, browser and operating system of a User-Agent header. Values
+// that are not recognised are empty.
+func parseUserAgent(userAgent string) (deviceType string, browser string, os string) {
+	if userAgent == "" {
+		return "", "", ""
+	}
+	ua := strings.ToLower(userAgent)
+
+	switch {
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		os = "iOS"
+	case strings.Contains(ua, "cros"):
+		os = "Chrome OS"
+	case strings.Contains(ua, "mac os x") || strings.Contains(ua, "macintosh"):
+		os = "macOS"
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	// the order matters, since most browsers also claim to be the ones that they are based on
+	switch {
+	case strings.Contains(ua, "edg/") || strings.Contains(ua, "edga/") || strings.Contains(ua, "edgios/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "samsungbrowser/"):
+		browser = "Samsung Internet"
+	case strings.Contains(ua, "firefox/") || strings.Contains(ua, "fxios/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	}
+
+	switch {
+	case strings.Contains(ua, "bot") || strings.Contains(ua, "crawler") || strings.Contains(ua, "spider"):
+		deviceType = "bot"
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") || (strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")):
+		deviceType = "tablet"
+	case strings.Contains(ua, "mobile") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		deviceType = "mobile"
+	case os != "":
+		deviceType = "desktop"
+	}
+	return deviceType, browser, os
+}
+
+func deviceInfoToMap(deviceInfo sessmodels.DeviceInfo) (map[string]interface{}, error) {
+	serialised, err := json.Marshal(deviceInfo)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	err = json.Unmarshal(serialised, &result)
+	return result, err
+}
+
+// removeDeviceInfoFromSessionData returns a copy of the session data without the device of the session
+func removeDeviceInfoFromSessionData(sessionDataInDatabase map[string]interface{}) map[string]interface{} {
+	if _, ok := sessionDataInDatabase[deviceInfoKeyInSessionData]; !ok {
+		return sessionDataInDatabase
+	}
+	result := map[string]interface{}{}
+	for key, value := range sessionDataInDatabase {
+		if key != deviceInfoKeyInSessionData {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// keepDeviceInfoInSessionData returns a copy of the new session data with the recorded device of the session
+// added to it, since updating the session data replaces all of it
+func keepDeviceInfoInSessionData(sessionInformation sessmodels.SessionInformation, newSessionData map[string]interface{}) (map[string]interface{}, error) {
+	result := removeDeviceInfoFromSessionData(newSessionData)
+	if sessionInformation.DeviceInfo == nil {
+		return result, nil
+	}
+	deviceInfoMap, err := deviceInfoToMap(*sessionInformation.DeviceInfo)
+	if err != nil {
+		return nil, err
+	}
+	withDeviceInfo := map[string]interface{}{}
+	for key, value := range result {
+		withDeviceInfo[key] = value
+	}
+	withDeviceInfo[deviceInfoKeyInSessionData] = deviceInfoMap
+	return withDeviceInfo, nil
+}
+
+// getDeviceInfoFromSessionData returns the recorded device of a session, or nil if there is none
+func getDeviceInfoFromSessionData(sessionDataInDatabase map[string]interface{}) *sessmodels.DeviceInfo {
+	deviceInfoMap, ok := sessionDataInDatabase[deviceInfoKeyInSessionData].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	serialised, err := json.Marshal(deviceInfoMap)
+	if err != nil {
+		return nil
+	}
+	var deviceInfo sessmodels.DeviceInfo
+	if json.Unmarshal(serialised, &deviceInfo) != nil {
+		return nil
+	}
+	return &deviceInfo
+}
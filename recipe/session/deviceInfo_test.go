@@ -0,0 +1,138 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestThatTheDeviceIsRecordedWhenASessionIsCreated(t *testing.T) {
+	handler, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		ExposeActiveSessionsAPIs: true,
+		DeviceInfo: &sessmodels.DeviceInfoConfig{
+			TrustedProxies: []string{"10.0.0.0/8"},
+			GetGeolocation: func(ipAddress string, userContext supertokens.UserContext) (*sessmodels.Geolocation, error) {
+				return &sessmodels.Geolocation{Country: "DE", City: "Berlin"}, nil
+			},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.RemoteAddr = "10.0.0.1:4321"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.2")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	instance, err := supertokens.GetInstanceOrThrowError(userContext)
+	assert.NoError(t, err)
+	req = supertokens.SetInstanceInRequest(req, instance)
+	sessionContainer, err := CreateNewSession(req, httptest.NewRecorder(), "public", "userId", nil, map[string]interface{}{"theme": "dark"})
+	assert.NoError(t, err)
+
+	sessionInformation, err := GetSessionInformation(sessionContainer.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "dark", sessionInformation.SessionDataInDatabase["theme"])
+	assert.Equal(t, &sessmodels.DeviceInfo{
+		IPAddress:   "203.0.113.7",
+		UserAgent:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		DeviceType:  "desktop",
+		Browser:     "Chrome",
+		OS:          "Windows",
+		Geolocation: &sessmodels.Geolocation{Country: "DE", City: "Berlin"},
+	}, sessionInformation.DeviceInfo)
+
+	_, body := callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", sessionContainer.GetAccessToken(), "")
+	listed := body["sessions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Chrome", listed["deviceInfo"].(map[string]interface{})["browser"])
+
+	// sessions created without a request do not have a device
+	sessionContainer, err = CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	sessionInformation, err = GetSessionInformation(sessionContainer.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, sessionInformation.DeviceInfo)
+}
+
+func TestThatTheDeviceIsKeptWhenTheSessionDataIsUpdated(t *testing.T) {
+	handler, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		ExposeActiveSessionsAPIs: true,
+		DeviceInfo:               &sessmodels.DeviceInfoConfig{},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0")
+	instance, err := supertokens.GetInstanceOrThrowError(userContext)
+	assert.NoError(t, err)
+	req = supertokens.SetInstanceInRequest(req, instance)
+	// the app can use any key in the session data
+	sessionContainer, err := CreateNewSession(req, httptest.NewRecorder(), "public", "userId", nil, map[string]interface{}{"deviceInfo": "from the app"})
+	assert.NoError(t, err)
+
+	sessionData, err := sessionContainer.GetSessionDataInDatabase()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"deviceInfo": "from the app"}, sessionData)
+
+	assert.NoError(t, sessionContainer.UpdateSessionDataInDatabase(map[string]interface{}{"theme": "dark"}))
+	sessionData, err = sessionContainer.GetSessionDataInDatabase()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"theme": "dark"}, sessionData)
+
+	_, body := callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", sessionContainer.GetAccessToken(), "")
+	listed := body["sessions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "Firefox", listed["deviceInfo"].(map[string]interface{})["browser"])
+
+	updated, err := UpdateSessionDataInDatabase("unknownHandle", map[string]interface{}{}, userContext)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func TestThatTheIPAddressIsOnlyTakenFromTheHeadersOfTrustedProxies(t *testing.T) {
+	config, err := normaliseDeviceInfoConfig(&sessmodels.DeviceInfoConfig{
+		TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1", "::1"},
+	})
+	assert.NoError(t, err)
+
+	getIPAddress := func(remoteAddress string, forwardedFor string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddress
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		return getClientIPAddress(req, config.TrustedProxies)
+	}
+
+	assert.Equal(t, "203.0.113.7", getIPAddress("203.0.113.7:1234", ""))
+	assert.Equal(t, "203.0.113.7", getIPAddress("203.0.113.7:1234", "198.51.100.1"))
+	assert.Equal(t, "198.51.100.1", getIPAddress("192.168.1.1:1234", "198.51.100.1"))
+	assert.Equal(t, "198.51.100.1", getIPAddress("[::1]:1234", "1.2.3.4, 198.51.100.1, 10.1.2.3"))
+	assert.Equal(t, "10.1.2.3", getIPAddress("10.0.0.1:1234", "not-an-ip, 10.1.2.3"))
+	assert.Equal(t, "10.0.0.1", getIPAddress("10.0.0.1:1234", ""))
+
+	_, err = normaliseDeviceInfoConfig(&sessmodels.DeviceInfoConfig{TrustedProxies: []string{"proxy.example.com"}})
+	assert.Error(t, err)
+}
+
+func TestThatUserAgentsAreParsed(t *testing.T) {
+	testCases := []struct {
+		userAgent  string
+		deviceType string
+		browser    string
+		os         string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_2) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15", "desktop", "Safari", "macOS"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1", "mobile", "Chrome", "iOS"},
+		{"Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Safari/537.36", "tablet", "Samsung Internet", "Android"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "desktop", "Firefox", "Linux"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91", "desktop", "Edge", "Windows"},
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "bot", "", ""},
+		{"curl/8.4.0", "", "", ""},
+	}
+	for _, testCase := range testCases {
+		deviceType, browser, os := parseUserAgent(testCase.userAgent)
+		assert.Equal(t, testCase.deviceType, deviceType, testCase.userAgent)
+		assert.Equal(t, testCase.browser, browser, testCase.userAgent)
+		assert.Equal(t, testCase.os, os, testCase.userAgent)
+	}
+}
@@ -15,7 +15,10 @@
 
 package errors
 
-import "github.com/supertokens/supertokens-golang/recipe/session/claims"
+import (
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
 
 const (
 	UnauthorizedErrorStr                 = "UNAUTHORISED"
@@ -23,6 +26,28 @@ const (
 	TokenTheftDetectedErrorStr           = "TOKEN_THEFT_DETECTED"
 	InvalidClaimsErrorStr                = "INVALID_CLAIMS"
 	ClearDuplicateSessionCookiesErrorStr = "CLEAR_DUPLICATE_SESSION_COOKIES"
+	SessionLimitReachedErrorStr          = "SESSION_LIMIT_REACHED"
+)
+
+// The error codes of the session errors (see supertokens.GetErrorCode)
+const (
+	ErrorCodeUnauthorised                 supertokens.ErrorCode = UnauthorizedErrorStr
+	ErrorCodeTryRefreshToken              supertokens.ErrorCode = TryRefreshTokenErrorStr
+	ErrorCodeTokenTheftDetected           supertokens.ErrorCode = TokenTheftDetectedErrorStr
+	ErrorCodeInvalidClaims                supertokens.ErrorCode = InvalidClaimsErrorStr
+	ErrorCodeClearDuplicateSessionCookies supertokens.ErrorCode = ClearDuplicateSessionCookiesErrorStr
+	ErrorCodeSessionLimitReached          supertokens.ErrorCode = SessionLimitReachedErrorStr
+)
+
+// Sentinel errors for the session errors, to be used with errors.Is. For example, errors.Is(err, ErrTryRefreshToken)
+// is true if err is (or wraps) a TryRefreshTokenError.
+var (
+	ErrUnauthorised                 = supertokens.NewSentinelError(ErrorCodeUnauthorised)
+	ErrTryRefreshToken              = supertokens.NewSentinelError(ErrorCodeTryRefreshToken)
+	ErrTokenTheftDetected           = supertokens.NewSentinelError(ErrorCodeTokenTheftDetected)
+	ErrInvalidClaims                = supertokens.NewSentinelError(ErrorCodeInvalidClaims)
+	ErrClearDuplicateSessionCookies = supertokens.NewSentinelError(ErrorCodeClearDuplicateSessionCookies)
+	ErrSessionLimitReached          = supertokens.NewSentinelError(ErrorCodeSessionLimitReached)
 )
 
 // TryRefreshTokenError used for when the refresh API needs to be called
@@ -34,6 +59,14 @@ func (err TryRefreshTokenError) Error() string {
 	return err.Msg
 }
 
+func (err TryRefreshTokenError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeTryRefreshToken
+}
+
+func (err TryRefreshTokenError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
+
 // TokenTheftDetectedError used for when token theft has happened for a session
 type TokenTheftDetectedError struct {
 	Msg     string
@@ -49,25 +82,63 @@ func (err TokenTheftDetectedError) Error() string {
 	return err.Msg
 }
 
+func (err TokenTheftDetectedError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeTokenTheftDetected
+}
+
+func (err TokenTheftDetectedError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
+
+// The reasons of an UnauthorizedError for sessions that expired because of the session lifetime policy, or
+// because they are impersonation sessions that reached their lifetime
+const (
+	UnauthorisedReasonIdleTimeout              = "IDLE_TIMEOUT"
+	UnauthorisedReasonAbsoluteLifetimeExceeded = "ABSOLUTE_LIFETIME_EXCEEDED"
+	UnauthorisedReasonImpersonationExpired     = "IMPERSONATION_EXPIRED"
+)
+
 // UnauthorizedError used for when the user has been logged out
 type UnauthorizedError struct {
 	Msg         string
 	ClearTokens *bool
+	// Reason is set if the session expired because of the session lifetime policy or of the lifetime of
+	// impersonation sessions (see the UnauthorisedReason constants). The error is then handled by
+	// OnSessionExpired instead of OnUnauthorised.
+	Reason string
 }
 
 func (err UnauthorizedError) Error() string {
 	return err.Msg
 }
 
+func (err UnauthorizedError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeUnauthorised
+}
+
+func (err UnauthorizedError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
+
 type InvalidClaimError struct {
 	Msg           string
 	InvalidClaims []claims.ClaimValidationError
+	// TenantId is the tenant of the session, for which the messages of the claim validation errors are translated
+	TenantId string
 }
 
 func (err InvalidClaimError) Error() string {
 	return err.Msg
 }
 
+func (err InvalidClaimError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeInvalidClaims
+}
+
+func (err InvalidClaimError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
+
 type ClearDuplicateSessionCookiesError struct {
 	Msg string
 }
@@ -75,3 +146,32 @@ type ClearDuplicateSessionCookiesError struct {
 func (err ClearDuplicateSessionCookiesError) Error() string {
 	return err.Msg
 }
+
+func (err ClearDuplicateSessionCookiesError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeClearDuplicateSessionCookies
+}
+
+func (err ClearDuplicateSessionCookiesError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
+
+// SessionLimitReachedError is returned when creating a session for a user that already has the maximum number
+// of sessions (see SessionLimit in the config)
+type SessionLimitReachedError struct {
+	Msg         string
+	UserId      string
+	TenantId    string
+	MaxSessions int
+}
+
+func (err SessionLimitReachedError) Error() string {
+	return err.Msg
+}
+
+func (err SessionLimitReachedError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeSessionLimitReached
+}
+
+func (err SessionLimitReachedError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, err.ErrorCode())
+}
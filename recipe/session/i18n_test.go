@@ -0,0 +1,114 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func TestThatClaimValidationErrorsAreLocalisedForTheTenantOfTheSessionUsingTheirKeys(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	req, err := http.NewRequest(http.MethodPut, core.URL+"/recipe/multitenancy/tenant", strings.NewReader(`{"tenantId":"paris"}`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tenantRes, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tenantRes.Body.Close()
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		I18n: &supertokens.I18nConfig{
+			Translations: map[string]map[supertokens.MessageKey]string{
+				"fr": {
+					supertokens.MessageKeyInvalidClaim:           "revendication invalide",
+					supertokens.MessageKeyClaimValueDoesNotExist: "la valeur n'existe pas",
+					"MFA_REQUIRED": "authentification à deux facteurs requise",
+				},
+			},
+			GetLocaleForTenant: func(tenantId string, userContext supertokens.UserContext) (*string, error) {
+				if tenantId == "paris" {
+					locale := "fr"
+					return &locale, nil
+				}
+				return nil, nil
+			},
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer instance.Close()
+	userContext := supertokens.SetInstanceInUserContext(nil, instance)
+
+	nilClaim, nilClaimValidators := NilClaim()
+	mfaValidator := claims.SessionClaimValidator{
+		ID: "st-mfa",
+		Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+			return claims.ClaimValidationResult{
+				IsValid:    false,
+				MessageKey: "MFA_REQUIRED",
+				Reason:     map[string]interface{}{"message": "Please complete the second factor"},
+			}
+		},
+	}
+	handler := instance.Middleware(VerifySession(&sessmodels.VerifySessionOptions{
+		OverrideGlobalClaimValidators: func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error) {
+			return []claims.SessionClaimValidator{nilClaimValidators.HasValue(true, nil, nil), mfaValidator}, nil
+		},
+	}, func(rw http.ResponseWriter, r *http.Request) {}))
+
+	call := func(tenantId string) map[string]interface{} {
+		sessionContainer, err := CreateNewSessionWithoutRequestResponse(tenantId, "userId", nil, nil, nil, userContext)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		// the claim of the validator is never set
+		assert.Nil(t, sessionContainer.GetClaimValue(nilClaim))
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+sessionContainer.GetAccessToken())
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, req)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		var result map[string]interface{}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &result))
+		return result
+	}
+	reasonOf := func(result map[string]interface{}, i int) map[string]interface{} {
+		return result["claimValidationErrors"].([]interface{})[i].(map[string]interface{})["reason"].(map[string]interface{})
+	}
+
+	result := call("paris")
+	assert.Equal(t, "revendication invalide", result["message"])
+	assert.Equal(t, "la valeur n'existe pas", reasonOf(result, 0)["message"])
+	assert.Equal(t, "CLAIM_VALUE_DOES_NOT_EXIST", reasonOf(result, 0)["messageKey"])
+	assert.Equal(t, "authentification à deux facteurs requise", reasonOf(result, 1)["message"])
+	assert.Equal(t, "MFA_REQUIRED", reasonOf(result, 1)["messageKey"])
+
+	// messages of custom keys that are not translated to the locale are sent as they are
+	result = call("public")
+	assert.Equal(t, "invalid claim", result["message"])
+	assert.Equal(t, "value does not exist", reasonOf(result, 0)["message"])
+	assert.Equal(t, "Please complete the second factor", reasonOf(result, 1)["message"])
+	assert.Equal(t, "MFA_REQUIRED", reasonOf(result, 1)["messageKey"])
+}
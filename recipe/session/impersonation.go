@@ -0,0 +1,324 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"encoding/json"
+	defaultErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// actorPayloadKey is the key of the RFC 8693 actor claim, which holds the user ID of the admin as "sub"
+const actorPayloadKey = "act"
+
+// impersonationExpiryPayloadKey is the key in the access token payload under which the time at which an
+// impersonation session expires is stored, in milliseconds
+const impersonationExpiryPayloadKey = "st-imp-exp"
+
+const defaultImpersonationSessionLifetime = time.Hour
+
+func normaliseImpersonationConfig(config *sessmodels.ImpersonationConfig) (*sessmodels.NormalisedImpersonationConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	sessionLifetime := defaultImpersonationSessionLifetime
+	if config.SessionLifetime != nil {
+		if *config.SessionLifetime <= 0 {
+			return nil, defaultErrors.New("SessionLifetime of the impersonation config must be positive")
+		}
+		sessionLifetime = *config.SessionLifetime
+	}
+	restrictedClaimValidatorIDs := []string{}
+	if config.RestrictedClaimValidatorIDs != nil {
+		restrictedClaimValidatorIDs = config.RestrictedClaimValidatorIDs
+	}
+	return &sessmodels.NormalisedImpersonationConfig{
+		CanImpersonate:                config.CanImpersonate,
+		SessionLifetime:               sessionLifetime,
+		RestrictedClaimValidatorIDs:   restrictedClaimValidatorIDs,
+		OnImpersonationSessionCreated: config.OnImpersonationSessionCreated,
+	}, nil
+}
+
+func NewImpersonationClaim() (*claims.TypeSessionClaim, sessionclaims.TypeImpersonationClaimValidators) {
+	// The claim is only set when an impersonation session is created, so it can not be fetched
+	impersonationClaim := claims.SessionClaim(actorPayloadKey, func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		return nil, nil
+	})
+
+	impersonationClaim.AddToPayload_internal = func(payload map[string]interface{}, value interface{}, userContext supertokens.UserContext) map[string]interface{} {
+		payload[impersonationClaim.Key] = map[string]interface{}{
+			"sub": value,
+		}
+		return payload
+	}
+	impersonationClaim.RemoveFromPayloadByMerge_internal = func(payload map[string]interface{}, userContext supertokens.UserContext) map[string]interface{} {
+		payload[impersonationClaim.Key] = nil
+		return payload
+	}
+	impersonationClaim.RemoveFromPayload = func(payload map[string]interface{}, userContext supertokens.UserContext) map[string]interface{} {
+		delete(payload, impersonationClaim.Key)
+		return payload
+	}
+	impersonationClaim.GetValueFromPayload = func(payload map[string]interface{}, userContext supertokens.UserContext) interface{} {
+		impersonatorUserID := getImpersonatorUserIDFromPayload(payload)
+		if impersonatorUserID == nil {
+			return nil
+		}
+		return *impersonatorUserID
+	}
+	impersonationClaim.GetLastRefetchTime = func(payload map[string]interface{}, userContext supertokens.UserContext) *int64 {
+		return nil
+	}
+
+	validators := sessionclaims.TypeImpersonationClaimValidators{
+		IsNotImpersonated: func() claims.SessionClaimValidator {
+			return claims.SessionClaimValidator{
+				ID:    impersonationClaim.Key,
+				Claim: impersonationClaim,
+				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
+					return false
+				},
+				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+					if getImpersonatorUserIDFromPayload(payload) != nil {
+						return claims.ClaimValidationResult{
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeySessionImpersonated,
+							Reason: map[string]interface{}{
+								"message": "session is impersonated",
+							},
+						}
+					}
+					return claims.ClaimValidationResult{
+						IsValid: true,
+					}
+				},
+			}
+		},
+	}
+	return impersonationClaim, validators
+}
+
+func init() {
+	// this function is called automatically when the package is imported
+	sessionclaims.ImpersonationClaim, sessionclaims.ImpersonationClaimValidators = NewImpersonationClaim()
+}
+
+func getImpersonatorUserIDFromPayload(accessTokenPayload map[string]interface{}) *string {
+	actor, ok := accessTokenPayload[actorPayloadKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	impersonatorUserID, ok := actor["sub"].(string)
+	if !ok {
+		return nil
+	}
+	return &impersonatorUserID
+}
+
+// makeImpersonationUserContext returns a copy of the user context that makes createNewSession create an
+// impersonation session. It is a copy so that the sessions that are later created with the user context of
+// the request are not impersonated.
+func makeImpersonationUserContext(userContext supertokens.UserContext, adminUserID string) supertokens.UserContext {
+	result := map[string]interface{}{}
+	defaultObj := map[string]interface{}{}
+	if userContext != nil {
+		for key, value := range *userContext {
+			result[key] = value
+		}
+		if existingDefaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			for key, value := range existingDefaultObj {
+				defaultObj[key] = value
+			}
+		}
+	}
+	defaultObj["impersonatorUserId"] = adminUserID
+	result["_default"] = defaultObj
+	return &result
+}
+
+func getImpersonatorUserIDFromUserContext(userContext supertokens.UserContext) *string {
+	if userContext != nil {
+		if defaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			if impersonatorUserID, ok := defaultObj["impersonatorUserId"].(string); ok {
+				return &impersonatorUserID
+			}
+		}
+	}
+	return nil
+}
+
+// addImpersonationToAccessTokenPayload returns a copy of the payload of a new impersonation session with the
+// actor claim and the expiry of the session added to it
+func addImpersonationToAccessTokenPayload(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) map[string]interface{} {
+	impersonatorUserID := getImpersonatorUserIDFromUserContext(userContext)
+	if impersonatorUserID == nil || config.Impersonation == nil {
+		return accessTokenPayload
+	}
+	result := map[string]interface{}{}
+	for key, value := range accessTokenPayload {
+		result[key] = value
+	}
+	result = sessionclaims.ImpersonationClaim.AddToPayload_internal(result, *impersonatorUserID, userContext)
+	result[impersonationExpiryPayloadKey] = float64(time.Now().Add(config.Impersonation.SessionLifetime).UnixMilli())
+	return result
+}
+
+// restrictClaimValidatorsForImpersonation replaces the validators in RestrictedClaimValidatorIDs with ones that
+// always fail if the payload is the one of an impersonation session
+func restrictClaimValidatorsForImpersonation(config sessmodels.TypeNormalisedInput, claimValidators []claims.SessionClaimValidator, accessTokenPayload map[string]interface{}) []claims.SessionClaimValidator {
+	if config.Impersonation == nil || len(config.Impersonation.RestrictedClaimValidatorIDs) == 0 || getImpersonatorUserIDFromPayload(accessTokenPayload) == nil {
+		return claimValidators
+	}
+	result := make([]claims.SessionClaimValidator, len(claimValidators))
+	for i, validator := range claimValidators {
+		result[i] = validator
+		if !supertokens.DoesSliceContainString(validator.ID, config.Impersonation.RestrictedClaimValidatorIDs) {
+			continue
+		}
+		result[i].Validate = func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+			return claims.ClaimValidationResult{
+				IsValid:    false,
+				MessageKey: supertokens.MessageKeySessionImpersonated,
+				Reason: map[string]interface{}{
+					"message": "session is impersonated",
+				},
+			}
+		}
+	}
+	return result
+}
+
+// enforceImpersonationExpiry revokes impersonation sessions that reached their lifetime. It does not depend on
+// the config, so that impersonation sessions also expire if impersonation is disabled afterwards.
+func enforceImpersonationExpiry(recipeImpl sessmodels.RecipeInterface, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) error {
+	if sessionContainer == nil || !sessionContainer.IsImpersonatedWithContext(userContext) {
+		return nil
+	}
+	expiry := sanitizeNumberInputAsUint64(sessionContainer.GetAccessTokenPayloadWithContext(userContext)[impersonationExpiryPayloadKey])
+	if expiry != nil && time.Now().Before(time.UnixMilli(int64(*expiry))) {
+		return nil
+	}
+	supertokens.LogDebugMessage("enforceImpersonationExpiry: Returning UNAUTHORISED because the impersonation session expired")
+	return expireSession(recipeImpl, sessionContainer, errors.UnauthorisedReasonImpersonationExpired, "impersonation session expired", userContext)
+}
+
+func createImpersonationSession(instance *Recipe, tenantId string, targetUserID string, adminUserID string, userContext supertokens.UserContext, createNewSession func(userContext supertokens.UserContext) (sessmodels.SessionContainer, error)) (sessmodels.SessionContainer, error) {
+	config := instance.Config.Impersonation
+	if config == nil {
+		return nil, defaultErrors.New("impersonation is not enabled. Please set Impersonation in the config of the session recipe")
+	}
+	if targetUserID == adminUserID {
+		return nil, defaultErrors.New("users can not impersonate themselves")
+	}
+
+	sessionContainer, err := createNewSession(makeImpersonationUserContext(userContext, adminUserID))
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := time.Now().Add(config.SessionLifetime)
+	if expiryInPayload := sanitizeNumberInputAsUint64(sessionContainer.GetAccessTokenPayloadWithContext(userContext)[impersonationExpiryPayloadKey]); expiryInPayload != nil {
+		expiry = time.UnixMilli(int64(*expiryInPayload))
+	}
+	supertokens.LogDebugMessage("createImpersonationSession: Created an impersonation session for user " + targetUserID + " on behalf of " + adminUserID)
+	if config.OnImpersonationSessionCreated != nil {
+		err = config.OnImpersonationSessionCreated(sessmodels.ImpersonationAuditEvent{
+			AdminUserID:   adminUserID,
+			TargetUserID:  targetUserID,
+			TenantId:      tenantId,
+			SessionHandle: sessionContainer.GetHandleWithContext(userContext),
+			TimeCreated:   time.Now(),
+			Expiry:        expiry,
+		}, userContext)
+		if err != nil {
+			// impersonation sessions must not exist without an entry in the audit trail
+			_, revokeErr := (*instance.RecipeImpl.RevokeSession)(sessionContainer.GetHandleWithContext(userContext), userContext)
+			if revokeErr != nil {
+				return nil, revokeErr
+			}
+			return nil, err
+		}
+	}
+	return sessionContainer, nil
+}
+
+// createImpersonationSessionInRequest is used by the impersonation API
+func createImpersonationSessionInRequest(req *http.Request, res http.ResponseWriter, instance *Recipe, tenantId string, targetUserID string, adminUserID string, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	return createImpersonationSession(instance, tenantId, targetUserID, adminUserID, userContext, func(userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+		return CreateNewSessionInRequest(req, res, tenantId, instance.Config, instance.RecipeModule.GetAppInfo(), *instance, instance.RecipeImpl, targetUserID, nil, nil, userContext)
+	})
+}
+
+func ImpersonateAPI(apiImplementation sessmodels.APIInterface, options sessmodels.APIOptions, userContext supertokens.UserContext) error {
+	if apiImplementation.ImpersonatePOST == nil || (*apiImplementation.ImpersonatePOST == nil) {
+		options.OtherHandler.ServeHTTP(options.Res, options.Req)
+		return nil
+	}
+
+	// Unlike for the active sessions APIs, the claim validators of the admin session are run
+	sessionRequired := true
+	adminSession, err := GetSessionFromRequest(options.Req, options.Res, options.Config, &sessmodels.VerifySessionOptions{
+		SessionRequired: &sessionRequired,
+	}, options.RecipeImplementation, userContext)
+	if err != nil {
+		return err
+	}
+
+	body, err := supertokens.ReadFromRequest(options.Req)
+	if err != nil {
+		return err
+	}
+	var readBody map[string]interface{}
+	err = json.Unmarshal(body, &readBody)
+	if err != nil {
+		return supertokens.BadInputError{Msg: "Please provide the user ID of the user to impersonate"}
+	}
+	targetUserID, ok := readBody["userId"].(string)
+	if !ok || targetUserID == "" {
+		return supertokens.BadInputError{Msg: "Please provide the user ID of the user to impersonate"}
+	}
+	tenantId, ok := readBody["tenantId"].(string)
+	if !ok || tenantId == "" {
+		tenantId = adminSession.GetTenantIdWithContext(userContext)
+	}
+
+	resp, err := (*apiImplementation.ImpersonatePOST)(targetUserID, tenantId, adminSession, options, userContext)
+	if err != nil {
+		return err
+	}
+
+	if resp.OK != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "OK",
+			"userId": resp.OK.Session.GetUserIDWithContext(userContext),
+		})
+	} else if resp.ImpersonationNotAllowedError != nil {
+		return supertokens.Send200Response(options.Res, map[string]interface{}{
+			"status": "IMPERSONATION_NOT_ALLOWED_ERROR",
+		})
+	} else if resp.GeneralError != nil {
+		return supertokens.Send200Response(options.Res, supertokens.ConvertGeneralErrorToJsonResponse(*resp.GeneralError))
+	}
+	return supertokens.ErrorIfNoResponse(options.Res)
+}
@@ -0,0 +1,226 @@
+package session
+
+import (
+	defaultErrors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestThatImpersonationSessionsHaveAnActorClaimAndAreAudited(t *testing.T) {
+	events := []sessmodels.ImpersonationAuditEvent{}
+	_, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		Impersonation: &sessmodels.ImpersonationConfig{
+			OnImpersonationSessionCreated: func(event sessmodels.ImpersonationAuditEvent, userContext supertokens.UserContext) error {
+				events = append(events, event)
+				return nil
+			},
+		},
+	})
+
+	impersonated, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "customer", impersonated.GetUserID())
+	assert.True(t, impersonated.IsImpersonated())
+	assert.Equal(t, "admin", *impersonated.GetImpersonatorUserID())
+	assert.Equal(t, map[string]interface{}{"sub": "admin"}, impersonated.GetAccessTokenPayload()["act"])
+	assert.Equal(t, "admin", impersonated.GetClaimValue(sessionclaims.ImpersonationClaim))
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "admin", events[0].AdminUserID)
+	assert.Equal(t, "customer", events[0].TargetUserID)
+	assert.Equal(t, "public", events[0].TenantId)
+	assert.Equal(t, impersonated.GetHandle(), events[0].SessionHandle)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), events[0].Expiry, time.Minute)
+
+	// the user context of the caller is not changed, so sessions that are created with it are not impersonated
+	normal, err := CreateNewSessionWithoutRequestResponse("public", "customer", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.False(t, normal.IsImpersonated())
+	assert.Nil(t, normal.GetImpersonatorUserID())
+
+	isNotImpersonated := []claims.SessionClaimValidator{sessionclaims.ImpersonationClaimValidators.IsNotImpersonated()}
+	assert.NoError(t, normal.AssertClaims(isNotImpersonated))
+	err = impersonated.AssertClaims(isNotImpersonated)
+	assert.ErrorIs(t, err, errors.ErrInvalidClaims)
+
+	// the session is still impersonated after it is refreshed
+	refreshed, err := RefreshSessionWithoutRequestResponse(*impersonated.GetAllSessionTokensDangerously().RefreshToken, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "admin", *refreshed.GetImpersonatorUserID())
+}
+
+func TestThatRecentAuthWithinRejectsImpersonationSessions(t *testing.T) {
+	_, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		RecordRecentAuth: true,
+		Impersonation:    &sessmodels.ImpersonationConfig{},
+	})
+	// the admin authenticated right before impersonating the user
+	userContext = SetAuthenticationMethodInUserContext(userContext, "emailpassword")
+
+	impersonated, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, impersonated.GetClaimValue(sessionclaims.RecentAuthClaim))
+
+	recentAuthWithin := sessionclaims.RecentAuthClaimValidators.RecentAuthWithin(300)
+	err = impersonated.AssertClaims([]claims.SessionClaimValidator{recentAuthWithin})
+	assert.ErrorIs(t, err, errors.ErrInvalidClaims)
+
+	// even if the claim is set on the session
+	assert.NoError(t, RecordReauthentication(impersonated, "emailpassword", userContext))
+	result := recentAuthWithin.Validate(impersonated.GetAccessTokenPayload(), userContext)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "session is impersonated", result.Reason.(map[string]interface{})["message"])
+
+	normal, err := CreateNewSessionWithoutRequestResponse("public", "customer", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.NoError(t, normal.AssertClaims([]claims.SessionClaimValidator{recentAuthWithin}))
+}
+
+func TestThatRestrictedClaimValidatorsFailForImpersonationSessions(t *testing.T) {
+	trueClaim, trueClaimValidators := TrueClaim()
+	_, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		Impersonation: &sessmodels.ImpersonationConfig{
+			RestrictedClaimValidatorIDs: []string{trueClaim.Key},
+		},
+	})
+
+	impersonated, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.NoError(t, err)
+	assert.NoError(t, impersonated.SetClaimValue(trueClaim, true))
+	err = impersonated.AssertClaims([]claims.SessionClaimValidator{trueClaimValidators.IsTrue(nil, nil)})
+	assert.ErrorIs(t, err, errors.ErrInvalidClaims)
+	invalidClaims := err.(errors.InvalidClaimError).InvalidClaims
+	assert.Len(t, invalidClaims, 1)
+	assert.Equal(t, trueClaim.Key, invalidClaims[0].ID)
+	assert.Equal(t, "session is impersonated", invalidClaims[0].Reason.(map[string]interface{})["message"])
+
+	// other validators are not restricted
+	nilClaim, nilClaimValidators := NilClaim()
+	assert.NoError(t, impersonated.SetClaimValue(nilClaim, "value"))
+	assert.NoError(t, impersonated.AssertClaims([]claims.SessionClaimValidator{nilClaimValidators.HasValue("value", nil, nil)}))
+
+	normal, err := CreateNewSessionWithoutRequestResponse("public", "customer", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.NoError(t, normal.SetClaimValue(trueClaim, true))
+	assert.NoError(t, normal.AssertClaims([]claims.SessionClaimValidator{trueClaimValidators.IsTrue(nil, nil)}))
+}
+
+func TestThatImpersonationSessionsCanNotUseTheActiveSessionsAPIs(t *testing.T) {
+	handler, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		ExposeActiveSessionsAPIs: true,
+		Impersonation:            &sessmodels.ImpersonationConfig{},
+	})
+
+	userSession, err := CreateNewSessionWithoutRequestResponse("public", "customer", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	impersonated, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.NoError(t, err)
+
+	status, _ := callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", impersonated.GetAccessToken(), "")
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = callActiveSessionsAPI(handler, http.MethodPost, "/auth/sessions/revoke", impersonated.GetAccessToken(), `{"sessionHandle":"`+userSession.GetHandle()+`"}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = callActiveSessionsAPI(handler, http.MethodPost, "/auth/sessions/revoke/others", impersonated.GetAccessToken(), "")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	sessionHandles, err := GetAllSessionHandlesForUser("customer", nil, userContext)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{userSession.GetHandle(), impersonated.GetHandle()}, sessionHandles)
+
+	// the user can still use them
+	status, _ = callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", userSession.GetAccessToken(), "")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestThatImpersonationSessionsExpire(t *testing.T) {
+	sessionLifetime := 100 * time.Millisecond
+	_, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		Impersonation: &sessmodels.ImpersonationConfig{SessionLifetime: &sessionLifetime},
+	})
+
+	impersonated, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.NoError(t, err)
+	_, err = GetSessionWithoutRequestResponse(impersonated.GetAccessToken(), nil, nil, userContext)
+	assert.NoError(t, err)
+
+	time.Sleep(2 * sessionLifetime)
+	_, err = GetSessionWithoutRequestResponse(impersonated.GetAccessToken(), nil, nil, userContext)
+	assertSessionExpired(t, err, errors.UnauthorisedReasonImpersonationExpired)
+	sessionInformation, err := GetSessionInformation(impersonated.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, sessionInformation)
+}
+
+func TestThatImpersonationSessionsAreRevokedIfTheyCanNotBeAudited(t *testing.T) {
+	_, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		Impersonation: &sessmodels.ImpersonationConfig{
+			OnImpersonationSessionCreated: func(event sessmodels.ImpersonationAuditEvent, userContext supertokens.UserContext) error {
+				return defaultErrors.New("audit trail is unavailable")
+			},
+		},
+	})
+
+	_, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.EqualError(t, err, "audit trail is unavailable")
+	sessionHandles, err := GetAllSessionHandlesForUser("customer", nil, userContext)
+	assert.NoError(t, err)
+	assert.Empty(t, sessionHandles)
+}
+
+func TestThatImpersonationMustBeEnabled(t *testing.T) {
+	handler, userContext := newActiveSessionsTestInstance(t, nil)
+
+	_, err := CreateImpersonationSessionWithoutRequestResponse("public", "customer", "admin", userContext)
+	assert.Error(t, err)
+
+	admin, err := CreateNewSessionWithoutRequestResponse("public", "admin", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	status, _ := callActiveSessionsAPI(handler, http.MethodPost, "/auth/session/impersonate", admin.GetAccessToken(), `{"userId":"customer"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestThatAdminsCanImpersonateUsersUsingTheImpersonationAPI(t *testing.T) {
+	handler, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		Impersonation: &sessmodels.ImpersonationConfig{
+			CanImpersonate: func(adminSession sessmodels.SessionContainer, targetUserID string, tenantId string, userContext supertokens.UserContext) (bool, error) {
+				return adminSession.GetUserID() == "admin", nil
+			},
+		},
+	})
+
+	admin, err := CreateNewSessionWithoutRequestResponse("public", "admin", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	status, body := callActiveSessionsAPI(handler, http.MethodPost, "/auth/session/impersonate", admin.GetAccessToken(), `{"userId":"customer"}`)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "OK", body["status"])
+	assert.Equal(t, "customer", body["userId"])
+
+	sessionHandles, err := GetAllSessionHandlesForUser("customer", nil, userContext)
+	assert.NoError(t, err)
+	assert.Len(t, sessionHandles, 1)
+	sessionInformation, err := GetSessionInformation(sessionHandles[0], userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"sub": "admin"}, sessionInformation.CustomClaimsInAccessTokenPayload["act"])
+
+	notAdmin, err := CreateNewSessionWithoutRequestResponse("public", "otherUser", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	_, body = callActiveSessionsAPI(handler, http.MethodPost, "/auth/session/impersonate", notAdmin.GetAccessToken(), `{"userId":"customer"}`)
+	assert.Equal(t, "IMPERSONATION_NOT_ALLOWED_ERROR", body["status"])
+
+	// impersonation sessions can not impersonate other users
+	impersonated, err := CreateImpersonationSessionWithoutRequestResponse("public", "otherAdmin", "admin", userContext)
+	assert.NoError(t, err)
+	_, body = callActiveSessionsAPI(handler, http.MethodPost, "/auth/session/impersonate", impersonated.GetAccessToken(), `{"userId":"customer"}`)
+	assert.Equal(t, "IMPERSONATION_NOT_ALLOWED_ERROR", body["status"])
+
+	status, _ = callActiveSessionsAPI(handler, http.MethodPost, "/auth/session/impersonate", admin.GetAccessToken(), `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
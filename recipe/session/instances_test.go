@@ -0,0 +1,35 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatSeparateInstancesUseTheirOwnSessionRecipe(t *testing.T) {
+	userContextA := newSessionTestUserContext(t, nil, nil)
+	userContextB := newSessionTestUserContext(t, nil, nil)
+
+	// neither instance is the default one
+	_, err := GetRecipeInstanceOrThrowError()
+	assert.Error(t, err)
+
+	recipeA, err := GetRecipeInstanceOrThrowError(userContextA)
+	assert.NoError(t, err)
+	recipeB, err := GetRecipeInstanceOrThrowError(userContextB)
+	assert.NoError(t, err)
+	assert.NotSame(t, recipeA, recipeB)
+
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "user", nil, nil, nil, userContextA)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// the session only exists in the core of the first instance
+	revoked, err := RevokeSession(sessionContainer.GetHandle(), userContextB)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+	revoked, err = RevokeSession(sessionContainer.GetHandle(), userContextA)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
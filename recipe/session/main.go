@@ -22,6 +22,7 @@ import (
 	"github.com/supertokens/supertokens-golang/recipe/jwt/jwtmodels"
 	"github.com/supertokens/supertokens-golang/recipe/openid/openidmodels"
 	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
 	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -31,13 +32,13 @@ func Init(config *sessmodels.TypeInput) supertokens.Recipe {
 }
 
 func CreateNewSession(req *http.Request, res http.ResponseWriter, tenantId string, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	if len(userContext) == 0 {
+		userContext = append(userContext, supertokens.MakeDefaultUserContextFromAPI(req))
+	}
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
-	if len(userContext) == 0 {
-		userContext = append(userContext, &map[string]interface{}{})
-	}
 	config := instance.Config
 	appInfo := instance.RecipeModule.GetAppInfo()
 
@@ -45,7 +46,7 @@ func CreateNewSession(req *http.Request, res http.ResponseWriter, tenantId strin
 }
 
 func CreateNewSessionWithoutRequestResponse(tenantId string, userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCSRF *bool, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -85,20 +86,20 @@ func CreateNewSessionWithoutRequestResponse(tenantId string, userID string, acce
 }
 
 func GetSession(req *http.Request, res http.ResponseWriter, options *sessmodels.VerifySessionOptions, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	if len(userContext) == 0 {
+		userContext = append(userContext, supertokens.MakeDefaultUserContextFromAPI(req))
+	}
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
-	if len(userContext) == 0 {
-		userContext = append(userContext, &map[string]interface{}{})
-	}
 	config := instance.Config
 
 	return GetSessionFromRequest(req, res, config, options, instance.RecipeImpl, userContext[0])
 }
 
 func GetSessionWithoutRequestResponse(accessToken string, antiCSRFToken *string, options *sessmodels.VerifySessionOptions, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +140,7 @@ func GetSessionWithoutRequestResponse(accessToken string, antiCSRFToken *string,
 }
 
 func GetSessionInformation(sessionHandle string, userContext ...supertokens.UserContext) (*sessmodels.SessionInformation, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -150,18 +151,18 @@ func GetSessionInformation(sessionHandle string, userContext ...supertokens.User
 }
 
 func RefreshSession(req *http.Request, res http.ResponseWriter, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	if len(userContext) == 0 {
+		userContext = append(userContext, supertokens.MakeDefaultUserContextFromAPI(req))
+	}
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
-	if len(userContext) == 0 {
-		userContext = append(userContext, &map[string]interface{}{})
-	}
 	return RefreshSessionInRequest(req, res, instance.Config, instance.RecipeImpl, userContext[0])
 }
 
 func RefreshSessionWithoutRequestResponse(refreshToken string, disableAntiCSRF *bool, antiCSRFToken *string, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -179,7 +180,7 @@ func RefreshSessionWithoutRequestResponse(refreshToken string, disableAntiCSRF *
 }
 
 func RevokeAllSessionsForUser(userID string, tenantId *string, userContext ...supertokens.UserContext) ([]string, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -198,7 +199,7 @@ func RevokeAllSessionsForUser(userID string, tenantId *string, userContext ...su
 }
 
 func GetAllSessionHandlesForUser(userID string, tenantId *string, userContext ...supertokens.UserContext) ([]string, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +219,7 @@ func GetAllSessionHandlesForUser(userID string, tenantId *string, userContext ..
 }
 
 func RevokeSession(sessionHandle string, userContext ...supertokens.UserContext) (bool, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return false, err
 	}
@@ -229,7 +230,7 @@ func RevokeSession(sessionHandle string, userContext ...supertokens.UserContext)
 }
 
 func RevokeMultipleSessions(sessionHandles []string, userContext ...supertokens.UserContext) ([]string, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +241,7 @@ func RevokeMultipleSessions(sessionHandles []string, userContext ...supertokens.
 }
 
 func UpdateSessionDataInDatabase(sessionHandle string, newSessionData map[string]interface{}, userContext ...supertokens.UserContext) (bool, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return false, err
 	}
@@ -251,7 +252,7 @@ func UpdateSessionDataInDatabase(sessionHandle string, newSessionData map[string
 }
 
 func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, useStaticSigningKey *bool, userContext ...supertokens.UserContext) (jwtmodels.CreateJWTResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return jwtmodels.CreateJWTResponse{}, err
 	}
@@ -262,7 +263,7 @@ func CreateJWT(payload map[string]interface{}, validitySecondsPointer *uint64, u
 }
 
 func GetJWKS(userContext ...supertokens.UserContext) (jwtmodels.GetJWKSResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return jwtmodels.GetJWKSResponse{}, err
 	}
@@ -273,7 +274,7 @@ func GetJWKS(userContext ...supertokens.UserContext) (jwtmodels.GetJWKSResponse,
 }
 
 func GetOpenIdDiscoveryConfiguration(userContext ...supertokens.UserContext) (openidmodels.GetOpenIdDiscoveryConfigurationResponse, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return openidmodels.GetOpenIdDiscoveryConfigurationResponse{}, err
 	}
@@ -289,7 +290,7 @@ func ValidateClaimsForSessionHandle(
 	userContext ...supertokens.UserContext,
 ) (sessmodels.ValidateClaimsResponse, error) {
 
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return sessmodels.ValidateClaimsResponse{}, err
 	}
@@ -350,7 +351,7 @@ func ValidateClaimsInJWTPayload(
 	userContext ...supertokens.UserContext,
 ) ([]claims.ClaimValidationError, error) {
 
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -376,7 +377,7 @@ func ValidateClaimsInJWTPayload(
 }
 
 func MergeIntoAccessTokenPayload(sessionHandle string, accessTokenPayloadUpdate map[string]interface{}, userContext ...supertokens.UserContext) (bool, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return false, err
 	}
@@ -387,7 +388,7 @@ func MergeIntoAccessTokenPayload(sessionHandle string, accessTokenPayloadUpdate
 }
 
 func FetchAndSetClaim(sessionHandle string, claim *claims.TypeSessionClaim, userContext ...supertokens.UserContext) (bool, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return false, err
 	}
@@ -398,7 +399,7 @@ func FetchAndSetClaim(sessionHandle string, claim *claims.TypeSessionClaim, user
 }
 
 func SetClaimValue(sessionHandle string, claim *claims.TypeSessionClaim, value interface{}, userContext ...supertokens.UserContext) (bool, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return false, err
 	}
@@ -409,7 +410,7 @@ func SetClaimValue(sessionHandle string, claim *claims.TypeSessionClaim, value i
 }
 
 func GetClaimValue(sessionHandle string, claim *claims.TypeSessionClaim, userContext ...supertokens.UserContext) (sessmodels.GetClaimValueResult, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return sessmodels.GetClaimValueResult{}, err
 	}
@@ -420,7 +421,7 @@ func GetClaimValue(sessionHandle string, claim *claims.TypeSessionClaim, userCon
 }
 
 func RemoveClaim(sessionHandle string, claim *claims.TypeSessionClaim, userContext ...supertokens.UserContext) (bool, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return false, err
 	}
@@ -430,12 +431,52 @@ func RemoveClaim(sessionHandle string, claim *claims.TypeSessionClaim, userConte
 	return (*instance.RecipeImpl.RemoveClaim)(sessionHandle, claim, userContext[0])
 }
 
-func VerifySession(options *sessmodels.VerifySessionOptions, otherHandler http.HandlerFunc) http.HandlerFunc {
-	instance, err := getRecipeInstanceOrThrowError()
+// CreateImpersonationSession creates a session for the target user on behalf of the admin, for example so
+// that support staff can reproduce an issue of a customer. Impersonation must be enabled in the config.
+func CreateImpersonationSession(req *http.Request, res http.ResponseWriter, tenantId string, targetUserID string, adminUserID string, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, supertokens.MakeDefaultUserContextFromAPI(req))
+	}
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
-		panic("can't fetch supertokens instance. You should call the supertokens.Init function before using the VerifySession function.")
+		return nil, err
 	}
-	return VerifySessionHelper(*instance, options, otherHandler)
+	return createImpersonationSessionInRequest(req, res, instance, tenantId, targetUserID, adminUserID, userContext[0])
+}
+
+func CreateImpersonationSessionWithoutRequestResponse(tenantId string, targetUserID string, adminUserID string, userContext ...supertokens.UserContext) (sessmodels.SessionContainer, error) {
+	instance, err := getRecipeInstanceOrThrowError(userContext...)
+	if err != nil {
+		return nil, err
+	}
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return createImpersonationSession(instance, tenantId, targetUserID, adminUserID, userContext[0], func(impersonationUserContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
+		return CreateNewSessionWithoutRequestResponse(tenantId, targetUserID, nil, nil, nil, impersonationUserContext)
+	})
+}
+
+// RecordReauthentication updates the RecentAuthClaim of the session after the user authenticated again with
+// the method (e.g. "emailpassword"), without creating a new session
+func RecordReauthentication(sessionContainer sessmodels.SessionContainer, method string, userContext ...supertokens.UserContext) error {
+	if len(userContext) == 0 {
+		userContext = append(userContext, &map[string]interface{}{})
+	}
+	return sessionContainer.SetClaimValueWithContext(sessionclaims.RecentAuthClaim, method, userContext[0])
+}
+
+// VerifySession uses the session recipe of the SuperTokens instance whose middleware the request went
+// through, or the one of the instance created by supertokens.Init otherwise.
+func VerifySession(options *sessmodels.VerifySessionOptions, otherHandler http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		instance, err := getRecipeInstanceOrThrowError(supertokens.MakeDefaultUserContextFromAPI(r))
+		if err != nil {
+			http.Error(w, "can't fetch supertokens instance. You should call the supertokens.Init function before using the VerifySession function.", http.StatusInternalServerError)
+			return
+		}
+		VerifySessionHelper(*instance, options, otherHandler).ServeHTTP(w, r)
+	})
 }
 
 func GetSessionFromRequestContext(ctx context.Context) sessmodels.SessionContainer {
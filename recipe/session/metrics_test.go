@@ -0,0 +1,52 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func TestThatMetricsAreRecordedForRevokedSessions(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	registry := supertokens.NewInMemoryMetricsRegistry()
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+		Metrics: &supertokens.MetricsConfig{
+			Registry: registry,
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer supertokens.ResetForTest()
+	defer instance.Close()
+	userContext := supertokens.SetInstanceInUserContext(nil, instance)
+
+	sessionHandles := []string{}
+	for i := 0; i < 2; i++ {
+		sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "user", nil, nil, nil, userContext)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		sessionHandles = append(sessionHandles, sessionContainer.GetHandle())
+	}
+
+	revoked, err := RevokeMultipleSessions(sessionHandles, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(revoked))
+
+	assert.Equal(t, float64(2), registry.GetCounterValue(supertokens.MetricSessionsRevoked, nil))
+}
@@ -0,0 +1,114 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+func refreshAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Refreshes the session using the refresh token, and sends new tokens",
+		OtherResponses: map[string]string{
+			"200": "The session was refreshed",
+			"401": "The refresh token is missing or invalid, or token theft was detected",
+		},
+	}
+}
+
+func signOutAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary:         "Revokes the session and clears the tokens",
+		Statuses:        []string{"OK"},
+		SessionRequired: true,
+	}
+}
+
+func activeSessionsAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary:  "Lists the sessions of the user of the session",
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"sessions": {
+					Type: "array",
+					Items: &supertokens.OpenAPISchema{
+						Type: "object",
+						Properties: map[string]*supertokens.OpenAPISchema{
+							"sessionHandle": {Type: "string"},
+							"tenantId":      {Type: "string"},
+							"timeCreated":   {Type: "integer"},
+							"expiry":        {Type: "integer"},
+							"current":       {Type: "boolean"},
+							"deviceInfo":    {Type: "object"},
+						},
+					},
+				},
+			},
+		},
+		SessionRequired: true,
+	}
+}
+
+func revokeSessionAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Revokes a session of the user of the session",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:     "object",
+			Required: []string{"sessionHandle"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"sessionHandle": {Type: "string"},
+			},
+		},
+		Statuses:        []string{"OK", "SESSION_NOT_FOUND_ERROR"},
+		SessionRequired: true,
+	}
+}
+
+func revokeOtherSessionsAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary:  "Revokes all sessions of the user of the session, except for the session itself",
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"revokedSessionHandles": {Type: "array", Items: &supertokens.OpenAPISchema{Type: "string"}},
+			},
+		},
+		SessionRequired: true,
+	}
+}
+
+func impersonateAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Creates a session for the target user on behalf of the admin user of the session",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:     "object",
+			Required: []string{"userId"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"userId":   {Type: "string"},
+				"tenantId": {Type: "string", Description: "The tenant of the target user. Defaults to the tenant of the session"},
+			},
+		},
+		Statuses: []string{"OK", "IMPERSONATION_NOT_ALLOWED_ERROR"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"userId": {Type: "string"},
+			},
+		},
+		SessionRequired: true,
+	}
+}
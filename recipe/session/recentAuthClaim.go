@@ -0,0 +1,192 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// defaultAuthenticationMethod is recorded for sessions that are created without an authentication method
+// in the user context (see SetAuthenticationMethodInUserContext)
+const defaultAuthenticationMethod = "unknown"
+
+func NewRecentAuthClaim() (*claims.TypeSessionClaim, sessionclaims.TypeRecentAuthClaimValidators) {
+	// The value can not be fetched, since it can only be known when the user authenticates. This means that
+	// FetchAndSetClaim does not change the claim, and that validators never refetch it.
+	fetchValue := func(userId string, tenantId string, userContext supertokens.UserContext) (interface{}, error) {
+		return nil, nil
+	}
+
+	// The value of the claim is the authentication method, and the time at which it was set is the time of
+	// the authentication
+	recentAuthClaim, _ := claims.PrimitiveClaim("st-auth", fetchValue, nil)
+
+	validators := sessionclaims.TypeRecentAuthClaimValidators{
+		RecentAuthWithin: func(maxAgeInSeconds int64, methods ...string) claims.SessionClaimValidator {
+			return claims.SessionClaimValidator{
+				ID:    recentAuthClaim.Key,
+				Claim: recentAuthClaim,
+				ShouldRefetch: func(payload map[string]interface{}, userContext supertokens.UserContext) bool {
+					return false
+				},
+				Validate: func(payload map[string]interface{}, userContext supertokens.UserContext) claims.ClaimValidationResult {
+					method, ok := recentAuthClaim.GetValueFromPayload(payload, userContext).(string)
+					authenticatedAt := recentAuthClaim.GetLastRefetchTime(payload, userContext)
+					// the admin of an impersonation session did not authenticate as the user
+					if getImpersonatorUserIDFromPayload(payload) != nil {
+						return claims.ClaimValidationResult{
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeySessionImpersonated,
+							Reason: map[string]interface{}{
+								"message": "session is impersonated",
+							},
+						}
+					}
+					if !ok || authenticatedAt == nil {
+						return claims.ClaimValidationResult{
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueDoesNotExist,
+							Reason: map[string]interface{}{
+								"message": "value does not exist",
+							},
+						}
+					}
+					ageInSeconds := (time.Now().UnixNano()/1000000 - *authenticatedAt) / 1000
+					if ageInSeconds > maxAgeInSeconds {
+						return claims.ClaimValidationResult{
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimValueExpired,
+							Reason: map[string]interface{}{
+								"message":         "expired",
+								"ageInSeconds":    ageInSeconds,
+								"maxAgeInSeconds": maxAgeInSeconds,
+							},
+						}
+					}
+					if len(methods) > 0 && !isOneOfAuthenticationMethods(method, methods) {
+						return claims.ClaimValidationResult{
+							IsValid:    false,
+							MessageKey: supertokens.MessageKeyClaimWrongValue,
+							Reason: map[string]interface{}{
+								"message":       "wrong value",
+								"expectedValue": methods,
+								"actualValue":   method,
+							},
+						}
+					}
+					return claims.ClaimValidationResult{
+						IsValid: true,
+					}
+				},
+			}
+		},
+	}
+	return recentAuthClaim, validators
+}
+
+func isOneOfAuthenticationMethods(method string, methods []string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	// this function is called automatically when the package is imported
+	sessionclaims.RecentAuthClaim, sessionclaims.RecentAuthClaimValidators = NewRecentAuthClaim()
+}
+
+// SetAuthenticationMethodInUserContext returns a copy of the user context with the method with which the user
+// authenticated (e.g. "thirdparty"), which is recorded in the RecentAuthClaim of the sessions that are created
+// with it. The given user context is not changed, so that the method is not recorded for the other sessions
+// that are created with it.
+func SetAuthenticationMethodInUserContext(userContext supertokens.UserContext, method string) supertokens.UserContext {
+	return copyUserContextWithDefaultValue(userContext, "authenticationMethod", method)
+}
+
+// SetReauthenticationInUserContext returns a copy of the user context that marks the calls made with it as part
+// of the re-authentication of the user of a session, so that recipes check the credentials of the user without
+// counting it as a sign in. Overrides of the sign in functions of the recipes can check it using
+// IsReauthenticationInUserContext.
+func SetReauthenticationInUserContext(userContext supertokens.UserContext) supertokens.UserContext {
+	return copyUserContextWithDefaultValue(userContext, "reauthentication", true)
+}
+
+// IsReauthenticationInUserContext returns whether the user context is the one of a re-authentication (see
+// SetReauthenticationInUserContext)
+func IsReauthenticationInUserContext(userContext supertokens.UserContext) bool {
+	if userContext != nil {
+		if defaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			if reauthentication, ok := defaultObj["reauthentication"].(bool); ok {
+				return reauthentication
+			}
+		}
+	}
+	return false
+}
+
+// copyUserContextWithDefaultValue returns a copy of the user context with the value in its "_default" object. The
+// values of the user context are not copied themselves.
+func copyUserContextWithDefaultValue(userContext supertokens.UserContext, key string, value interface{}) supertokens.UserContext {
+	_userContext := map[string]interface{}{}
+	if userContext != nil {
+		for k, v := range *userContext {
+			_userContext[k] = v
+		}
+	}
+
+	defaultObj := map[string]interface{}{}
+	if existingDefaultObj, ok := _userContext["_default"].(map[string]interface{}); ok {
+		for k, v := range existingDefaultObj {
+			defaultObj[k] = v
+		}
+	}
+	defaultObj[key] = value
+	_userContext["_default"] = defaultObj
+
+	return &_userContext
+}
+
+func getAuthenticationMethodFromUserContext(userContext supertokens.UserContext) string {
+	if userContext != nil {
+		if defaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			if method, ok := defaultObj["authenticationMethod"].(string); ok && method != "" {
+				return method
+			}
+		}
+	}
+	return defaultAuthenticationMethod
+}
+
+// addRecentAuthToAccessTokenPayload returns a copy of the payload of a new session with the RecentAuthClaim
+// added to it. It is not added to impersonation sessions.
+func addRecentAuthToAccessTokenPayload(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}, userContext supertokens.UserContext) map[string]interface{} {
+	if !config.RecordRecentAuth || getImpersonatorUserIDFromUserContext(userContext) != nil {
+		return accessTokenPayload
+	}
+	result := map[string]interface{}{}
+	for key, value := range accessTokenPayload {
+		result[key] = value
+	}
+	return sessionclaims.RecentAuthClaim.AddToPayload_internal(result, getAuthenticationMethodFromUserContext(userContext), userContext)
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessionclaims"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func TestThatRecentAuthWithinChecksTheAgeAndMethodOfTheAuthentication(t *testing.T) {
+	makePayload := func(method string, age time.Duration) map[string]interface{} {
+		return map[string]interface{}{
+			"st-auth": map[string]interface{}{
+				"v": method,
+				"t": float64(time.Now().Add(-age).UnixMilli()),
+			},
+		}
+	}
+
+	validator := sessionclaims.RecentAuthClaimValidators.RecentAuthWithin(300)
+	assert.Equal(t, "st-auth", validator.ID)
+	assert.False(t, validator.ShouldRefetch(map[string]interface{}{}, nil))
+	assert.True(t, validator.Validate(makePayload("emailpassword", time.Minute), nil).IsValid)
+
+	result := validator.Validate(makePayload("emailpassword", 10*time.Minute), nil)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "expired", result.Reason.(map[string]interface{})["message"])
+
+	result = validator.Validate(map[string]interface{}{}, nil)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "value does not exist", result.Reason.(map[string]interface{})["message"])
+
+	validator = sessionclaims.RecentAuthClaimValidators.RecentAuthWithin(300, "emailpassword", "passwordless")
+	assert.True(t, validator.Validate(makePayload("passwordless", time.Minute), nil).IsValid)
+	result = validator.Validate(makePayload("thirdparty", time.Minute), nil)
+	assert.False(t, result.IsValid)
+	assert.Equal(t, "wrong value", result.Reason.(map[string]interface{})["message"])
+}
+
+func TestThatTheRecentAuthClaimIsOnlyAddedToSessionsIfEnabled(t *testing.T) {
+	newInstance := func(recordRecentAuth bool) supertokens.UserContext {
+		return newSessionTestUserContext(t, nil, &sessmodels.TypeInput{RecordRecentAuth: recordRecentAuth})
+	}
+
+	userContext := SetAuthenticationMethodInUserContext(newInstance(true), "thirdparty")
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "thirdparty", sessionContainer.GetClaimValue(sessionclaims.RecentAuthClaim))
+
+	sessionInformation, err := GetSessionInformation(sessionContainer.GetHandle(), userContext)
+	assert.NoError(t, err)
+	authenticatedAt := sessionclaims.RecentAuthClaim.GetLastRefetchTime(sessionInformation.CustomClaimsInAccessTokenPayload, userContext)
+	assert.NotNil(t, authenticatedAt)
+
+	// the claim can not be refreshed without authenticating
+	time.Sleep(5 * time.Millisecond)
+	_, err = FetchAndSetClaim(sessionContainer.GetHandle(), sessionclaims.RecentAuthClaim, userContext)
+	assert.NoError(t, err)
+	sessionInformation, err = GetSessionInformation(sessionContainer.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "thirdparty", sessionclaims.RecentAuthClaim.GetValueFromPayload(sessionInformation.CustomClaimsInAccessTokenPayload, userContext))
+	assert.Equal(t, authenticatedAt, sessionclaims.RecentAuthClaim.GetLastRefetchTime(sessionInformation.CustomClaimsInAccessTokenPayload, userContext))
+
+	assert.NoError(t, RecordReauthentication(sessionContainer, "emailpassword", userContext))
+	assert.Equal(t, "emailpassword", sessionContainer.GetClaimValue(sessionclaims.RecentAuthClaim))
+
+	sessionContainer, err = CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, newInstance(false))
+	assert.NoError(t, err)
+	assert.Nil(t, sessionContainer.GetClaimValue(sessionclaims.RecentAuthClaim))
+}
+
+func TestThatSettingTheAuthenticationMethodForRecentAuthDoesNotChangeTheUserContext(t *testing.T) {
+	userContext := newSessionTestUserContext(t, nil, &sessmodels.TypeInput{RecordRecentAuth: true})
+
+	thirdPartyUserContext := SetAuthenticationMethodInUserContext(userContext, "thirdparty")
+	assert.Equal(t, defaultAuthenticationMethod, getAuthenticationMethodFromUserContext(userContext))
+	assert.Equal(t, "thirdparty", getAuthenticationMethodFromUserContext(thirdPartyUserContext))
+
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultAuthenticationMethod, sessionContainer.GetClaimValue(sessionclaims.RecentAuthClaim))
+	sessionContainer, err = CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, thirdPartyUserContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "thirdparty", sessionContainer.GetClaimValue(sessionclaims.RecentAuthClaim))
+
+	reauthenticationUserContext := SetReauthenticationInUserContext(thirdPartyUserContext)
+	assert.False(t, IsReauthenticationInUserContext(thirdPartyUserContext))
+	assert.True(t, IsReauthenticationInUserContext(reauthenticationUserContext))
+	assert.Equal(t, "thirdparty", getAuthenticationMethodFromUserContext(reauthenticationUserContext))
+}
@@ -41,8 +41,6 @@ type Recipe struct {
 
 const RECIPE_ID = "session"
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *sessmodels.TypeInput, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{
 		claimsAddedByOtherRecipes:          []*claims.TypeSessionClaim{},
@@ -77,10 +75,17 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	supertokens.LogDebugMessage("session init: RefreshTokenPath: " + verifiedConfig.RefreshTokenPath.GetAsStringDangerous())
 	supertokens.LogDebugMessage("session init: SessionExpiredStatusCode: " + strconv.Itoa(verifiedConfig.SessionExpiredStatusCode))
 
+	if verifiedConfig.RevocationList != nil {
+		err := verifiedConfig.RevocationList.Subscribe()
+		if err != nil {
+			return Recipe{}, err
+		}
+	}
+
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(MakeAPIImplementation())
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
@@ -95,6 +100,7 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	}
 
 	r.RecipeImpl = verifiedConfig.Override.Functions(recipeImplementation)
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 	r.OpenIdRecipe = openIdRecipe
 
 	r.RecipeModule.ResetForTest = ResetForTest
@@ -102,26 +108,30 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	return *r, nil
 }
 
-func getRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func getRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
-	return nil, defaultErrors.New("Initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "Initialisation not done. Did you forget to call the init function?"}
 }
 
-func GetRecipeInstanceOrThrowError() (*Recipe, error) {
-	return getRecipeInstanceOrThrowError()
+func GetRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	return getRecipeInstanceOrThrowError(userContext...)
 }
 
 func recipeInit(config *sessmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, defaultErrors.New("Session recipe has already been initialised. Please check your code for bugs.")
 	}
@@ -138,16 +148,58 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 	if err != nil {
 		return nil, err
 	}
+	activeSessionsAPIPathNormalised, err := supertokens.NewNormalisedURLPath(ActiveSessionsAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	revokeSessionAPIPathNormalised, err := supertokens.NewNormalisedURLPath(RevokeSessionAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	revokeOtherSessionsAPIPathNormalised, err := supertokens.NewNormalisedURLPath(RevokeOtherSessionsAPIPath)
+	if err != nil {
+		return nil, err
+	}
+	impersonateAPIPathNormalised, err := supertokens.NewNormalisedURLPath(ImpersonateAPIPath)
+	if err != nil {
+		return nil, err
+	}
 	resp := []supertokens.APIHandled{{
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: refreshAPIPathNormalised,
 		ID:                     RefreshAPIPath,
 		Disabled:               r.APIImpl.RefreshPOST == nil,
+		Spec:                   refreshAPISpec(),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: signoutAPIPathNormalised,
 		ID:                     SignoutAPIPath,
 		Disabled:               r.APIImpl.SignOutPOST == nil,
+		Spec:                   signOutAPISpec(),
+	}, {
+		Method:                 http.MethodGet,
+		PathWithoutAPIBasePath: activeSessionsAPIPathNormalised,
+		ID:                     ActiveSessionsAPIPath,
+		Disabled:               !r.Config.ExposeActiveSessionsAPIs || r.APIImpl.ActiveSessionsGET == nil,
+		Spec:                   activeSessionsAPISpec(),
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: revokeSessionAPIPathNormalised,
+		ID:                     RevokeSessionAPIPath,
+		Disabled:               !r.Config.ExposeActiveSessionsAPIs || r.APIImpl.RevokeSessionPOST == nil,
+		Spec:                   revokeSessionAPISpec(),
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: revokeOtherSessionsAPIPathNormalised,
+		ID:                     RevokeOtherSessionsAPIPath,
+		Disabled:               !r.Config.ExposeActiveSessionsAPIs || r.APIImpl.RevokeOtherSessionsPOST == nil,
+		Spec:                   revokeOtherSessionsAPISpec(),
+	}, {
+		Method:                 http.MethodPost,
+		PathWithoutAPIBasePath: impersonateAPIPathNormalised,
+		ID:                     ImpersonateAPIPath,
+		Disabled:               r.Config.Impersonation == nil || r.Config.Impersonation.CanImpersonate == nil || r.APIImpl.ImpersonatePOST == nil,
+		Spec:                   impersonateAPISpec(),
 	}}
 
 	jwtAPIs, err := r.OpenIdRecipe.RecipeModule.GetAPIsHandled()
@@ -174,6 +226,14 @@ func (r *Recipe) handleAPIRequest(id string, tenantId string, req *http.Request,
 		return HandleRefreshAPI(r.APIImpl, options, userContext)
 	} else if id == SignoutAPIPath {
 		return SignOutAPI(r.APIImpl, options, userContext)
+	} else if id == ActiveSessionsAPIPath {
+		return ActiveSessionsAPI(r.APIImpl, options, userContext)
+	} else if id == RevokeSessionAPIPath {
+		return RevokeSessionAPI(r.APIImpl, options, userContext)
+	} else if id == RevokeOtherSessionsAPIPath {
+		return RevokeOtherSessionsAPI(r.APIImpl, options, userContext)
+	} else if id == ImpersonateAPIPath {
+		return ImpersonateAPI(r.APIImpl, options, userContext)
 	} else {
 		return r.OpenIdRecipe.RecipeModule.HandleAPIRequest(id, tenantId, req, res, theirhandler, path, method, userContext)
 	}
@@ -186,26 +246,33 @@ func (r *Recipe) getAllCORSHeaders() []string {
 }
 
 func (r *Recipe) handleError(err error, req *http.Request, res http.ResponseWriter, userContext supertokens.UserContext) (bool, error) {
-	if defaultErrors.As(err, &errors.UnauthorizedError{}) {
+	var unauthErr errors.UnauthorizedError
+	var tokenTheftErr errors.TokenTheftDetectedError
+	var invalidClaimErr errors.InvalidClaimError
+	var sessionLimitReachedErr errors.SessionLimitReachedError
+	if defaultErrors.As(err, &unauthErr) {
 		supertokens.LogDebugMessage("errorHandler: returning UNAUTHORISED")
-		unauthErr := err.(errors.UnauthorizedError)
 		if unauthErr.ClearTokens == nil || *unauthErr.ClearTokens {
 			supertokens.LogDebugMessage("errorHandler: Clearing tokens because of UNAUTHORISED response")
 			ClearSessionFromAllTokenTransferMethods(r.Config, req, res, userContext)
 		}
+		if unauthErr.Reason != "" {
+			return true, r.Config.ErrorHandlers.OnSessionExpired(unauthErr.Reason, req, res)
+		}
 		return true, r.Config.ErrorHandlers.OnUnauthorised(err.Error(), req, res)
 	} else if defaultErrors.As(err, &errors.TryRefreshTokenError{}) {
 		supertokens.LogDebugMessage("errorHandler: returning TRY_REFRESH_TOKEN")
 		return true, r.Config.ErrorHandlers.OnTryRefreshToken(err.Error(), req, res)
-	} else if defaultErrors.As(err, &errors.TokenTheftDetectedError{}) {
+	} else if defaultErrors.As(err, &tokenTheftErr) {
 		supertokens.LogDebugMessage("errorHandler: clearing tokens because of TOKEN_THEFT_DETECTED response")
 		ClearSessionFromAllTokenTransferMethods(r.Config, req, res, userContext)
-		errs := err.(errors.TokenTheftDetectedError)
-		return true, r.Config.ErrorHandlers.OnTokenTheftDetected(errs.Payload.SessionHandle, errs.Payload.UserID, req, res)
-	} else if defaultErrors.As(err, &errors.InvalidClaimError{}) {
+		return true, r.Config.ErrorHandlers.OnTokenTheftDetected(tokenTheftErr.Payload.SessionHandle, tokenTheftErr.Payload.UserID, req, res)
+	} else if defaultErrors.As(err, &invalidClaimErr) {
 		supertokens.LogDebugMessage("errorHandler: returning INVALID_CLAIMS")
-		errs := err.(errors.InvalidClaimError)
-		return true, r.Config.ErrorHandlers.OnInvalidClaim(errs.InvalidClaims, req, res)
+		return true, r.Config.ErrorHandlers.OnInvalidClaim(invalidClaimErr.InvalidClaims, setTenantIdInRequest(req, invalidClaimErr.TenantId), res)
+	} else if defaultErrors.As(err, &sessionLimitReachedErr) {
+		supertokens.LogDebugMessage("errorHandler: returning SESSION_LIMIT_REACHED")
+		return true, r.Config.ErrorHandlers.OnSessionLimitReached(sessionLimitReachedErr.Msg, sessionLimitReachedErr.TenantId, req, res)
 	} else if defaultErrors.As(err, &errors.ClearDuplicateSessionCookiesError{}) {
 		supertokens.LogDebugMessage("errorHandler: returning CLEAR_DUPLICATE_SESSION_COOKIES")
 		// This error occurs in the `refreshPOST` API when multiple session
@@ -243,6 +310,5 @@ func (r *Recipe) getClaimValidatorsAddedByOtherRecipes() []claims.SessionClaimVa
 }
 
 func ResetForTest() {
-	singletonInstance = nil
 	openid.ResetForTest()
 }
@@ -21,6 +21,7 @@ import (
 	defaultErrors "errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,13 +33,45 @@ import (
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
 
+const jwksPath = "/.well-known/jwks.json"
+
+// jwksCache holds the keys of the cores of the SuperTokens instance created by supertokens.Init, and
+// jwksCacheForOtherCores the ones of instances created by supertokens.New that use other cores, keyed
+// by the URLs of their cores.
 var jwksCache *sessmodels.GetJWKSResult = nil
+var jwksCacheForOtherCores = map[string]*sessmodels.GetJWKSResult{}
 var mutex sync.RWMutex
 
-func getJWKSFromCacheIfPresent() *sessmodels.GetJWKSResult {
+func getJWKSCacheKey(corePaths []string) string {
+	key := strings.Join(corePaths, ";")
+	if key == strings.Join(supertokens.GetAllCoreUrlsForPath(jwksPath), ";") {
+		return ""
+	}
+	return key
+}
+
+// this must be called with mutex held
+func getJWKSCacheEntry(cacheKey string) *sessmodels.GetJWKSResult {
+	if cacheKey == "" {
+		return jwksCache
+	}
+	return jwksCacheForOtherCores[cacheKey]
+}
+
+// this must be called with mutex held for writing
+func setJWKSCacheEntry(cacheKey string, result *sessmodels.GetJWKSResult) {
+	if cacheKey == "" {
+		jwksCache = result
+	} else {
+		jwksCacheForOtherCores[cacheKey] = result
+	}
+}
+
+func getJWKSFromCacheIfPresent(cacheKey string) *sessmodels.GetJWKSResult {
 	mutex.RLock()
 	defer mutex.RUnlock()
-	if jwksCache != nil {
+	cachedResult := getJWKSCacheEntry(cacheKey)
+	if cachedResult != nil {
 		// This means that we have valid JWKs for the given core path
 		// We check if we need to refresh before returning
 		currentTime := time.Now().UnixNano() / int64(time.Millisecond)
@@ -48,7 +81,7 @@ func getJWKSFromCacheIfPresent() *sessmodels.GetJWKSResult {
 		// Note that this also means that the SDK will not try to query any other Core (if there are multiple)
 		// if it has a valid cache entry from one of the core URLs. It will only attempt to fetch
 		// from the cores again after the entry in the cache is expired
-		if (currentTime - jwksCache.LastFetched) < JWKCacheMaxAgeInMs {
+		if (currentTime - cachedResult.LastFetched) < JWKCacheMaxAgeInMs {
 			if supertokens.IsRunningInTestMode() {
 				if len(returnedFromCache) == cap(returnedFromCache) { // need to clear the channel if full because it's not being consumed in the test
 					close(returnedFromCache)
@@ -57,21 +90,22 @@ func getJWKSFromCacheIfPresent() *sessmodels.GetJWKSResult {
 				returnedFromCache <- true
 			}
 
-			return jwksCache
+			return cachedResult
 		}
 	}
 
 	return nil
 }
 
-func getJWKS() (*keyfunc.JWKS, error) {
-	corePaths := supertokens.GetAllCoreUrlsForPath("/.well-known/jwks.json")
+func getJWKS(userContext ...supertokens.UserContext) (*keyfunc.JWKS, error) {
+	corePaths := supertokens.GetAllCoreUrlsForPath(jwksPath, userContext...)
 
 	if len(corePaths) == 0 {
 		return nil, defaultErrors.New("No SuperTokens core available to query. Please pass supertokens > connectionURI to the init function, or override all the functions of the recipe you are using.")
 	}
 
-	resultFromCache := getJWKSFromCacheIfPresent()
+	cacheKey := getJWKSCacheKey(corePaths)
+	resultFromCache := getJWKSFromCacheIfPresent(cacheKey)
 
 	if resultFromCache != nil {
 		return resultFromCache.JWKS, nil
@@ -89,6 +123,7 @@ func getJWKS() (*keyfunc.JWKS, error) {
 		// RefreshUnknownKID - Fetch JWKS again if the kid in the header of the JWT does not match any in
 		// the keyfunc library's cache
 		jwks, jwksError := keyfunc.Get(path, keyfunc.Options{
+			Client:            supertokens.GetQuerierHTTPClient(userContext...),
 			RefreshUnknownKID: true,
 		})
 
@@ -104,7 +139,7 @@ func getJWKS() (*keyfunc.JWKS, error) {
 			// This also has the added benefit where if initially the request failed because the core
 			// was down and then it comes back up, the next time it will try to request that core again
 			// after the cache has expired
-			jwksCache = &jwksResult
+			setJWKSCacheEntry(cacheKey, &jwksResult)
 
 			if supertokens.IsRunningInTestMode() {
 				if len(returnedFromCache) == cap(returnedFromCache) { // need to clear the channel if full because it's not being consumed in the test
@@ -132,12 +167,12 @@ error resistant.
 Every core instance a backend is connected to is expected to connect to the same database and use the same key set for
 token verification. Otherwise, the result of session verification would depend on which core is currently available.
 */
-func GetCombinedJWKS() (*keyfunc.JWKS, error) {
+func GetCombinedJWKS(userContext ...supertokens.UserContext) (*keyfunc.JWKS, error) {
 	if supertokens.IsRunningInTestMode() {
 		urlsAttemptedForJWKSFetch = []string{}
 	}
 
-	jwksResult, err := getJWKS()
+	jwksResult, err := getJWKS(userContext...)
 
 	if err != nil {
 		return nil, err
@@ -146,12 +181,36 @@ func GetCombinedJWKS() (*keyfunc.JWKS, error) {
 	return jwksResult, nil
 }
 
+// addToRevocationList adds revoked sessions to the revocation list, if it is enabled. Publishing them is best
+// effort, since the sessions were already revoked in the core.
+func addToRevocationList(config sessmodels.TypeNormalisedInput, sessionHandles []string, userContext supertokens.UserContext) {
+	if config.RevocationList == nil {
+		return
+	}
+	err := config.RevocationList.Add(sessionHandles)
+	if err != nil {
+		supertokens.LogWarn(userContext, "publishing revoked sessions failed", "error", err.Error())
+	}
+}
+
 func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo) sessmodels.RecipeInterface {
 	var result sessmodels.RecipeInterface
 
 	createNewSession := func(userID string, accessTokenPayload map[string]interface{}, sessionDataInDatabase map[string]interface{}, disableAntiCsrf *bool, tenantId string, userContext supertokens.UserContext) (sessmodels.SessionContainer, error) {
 		supertokens.LogDebugMessage("createNewSession: Started")
 
+		// impersonation sessions do not count towards the session limit, so that they do not evict the sessions
+		// of the user
+		if getImpersonatorUserIDFromUserContext(userContext) == nil {
+			err := enforceSessionLimit(config, result, userID, tenantId, userContext)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		accessTokenPayload = addSessionLifetimeToAccessTokenPayload(config, accessTokenPayload)
+		accessTokenPayload = addRecentAuthToAccessTokenPayload(config, accessTokenPayload, userContext)
+		accessTokenPayload = addImpersonationToAccessTokenPayload(config, accessTokenPayload, userContext)
 		sessionResponse, err := createNewSessionHelper(
 			config, querier, userID, disableAntiCsrf != nil && *disableAntiCsrf == true, accessTokenPayload, sessionDataInDatabase, tenantId, userContext,
 		)
@@ -160,6 +219,9 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 		}
 
 		supertokens.LogDebugMessage("createNewSession: Finished")
+		supertokens.IncrementCounterMetric(supertokens.MetricSessionsCreated, map[string]string{
+			"tenant_id": tenantId,
+		})
 
 		parsedJWT, parseErr := ParseJWTWithoutSignatureVerification(sessionResponse.AccessToken.Token)
 		if parseErr != nil {
@@ -280,6 +342,15 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 		sessionContainerInput := makeSessionContainerInput(accessTokenStringForSession, session.Handle, session.UserID, session.TenantId, payload, result, frontToken, antiCsrfToken, nil, nil, !accessTokenNil)
 		sessionContainer := newSessionContainer(config, &sessionContainerInput)
 
+		err = enforceSessionLifetime(config, result, sessionContainer, false, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = enforceImpersonationExpiry(result, sessionContainer, userContext)
+		if err != nil {
+			return nil, err
+		}
+
 		return sessionContainer, nil
 	}
 
@@ -299,6 +370,9 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			return nil, err
 		}
 		supertokens.LogDebugMessage("refreshSession: Success!")
+		supertokens.IncrementCounterMetric(supertokens.MetricSessionsRefreshed, map[string]string{
+			"tenant_id": response.Session.TenantId,
+		})
 
 		responseToken, parseErr := ParseJWTWithoutSignatureVerification(response.AccessToken.Token)
 		if parseErr != nil {
@@ -311,11 +385,23 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 		sessionContainerInput := makeSessionContainerInput(response.AccessToken.Token, session.Handle, session.UserID, session.TenantId, responseToken.Payload, result, frontToken, response.AntiCsrfToken, nil, &response.RefreshToken, true)
 		sessionContainer := newSessionContainer(config, &sessionContainerInput)
 
+		err = enforceSessionLifetime(config, result, sessionContainer, true, userContext)
+		if err != nil {
+			return nil, err
+		}
+		err = enforceImpersonationExpiry(result, sessionContainer, userContext)
+		if err != nil {
+			return nil, err
+		}
+
 		return sessionContainer, nil
 	}
 
 	revokeAllSessionsForUser := func(userID string, tenantId string, revokeAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
-		return revokeAllSessionsForUserHelper(querier, userID, tenantId, revokeAcrossAllTenants, userContext)
+		revokedSessionHandles, err := revokeAllSessionsForUserHelper(querier, userID, tenantId, revokeAcrossAllTenants, userContext)
+		supertokens.AddToCounterMetric(supertokens.MetricSessionsRevoked, nil, float64(len(revokedSessionHandles)))
+		addToRevocationList(config, revokedSessionHandles, userContext)
+		return revokedSessionHandles, err
 	}
 
 	getAllSessionHandlesForUser := func(userID string, tenantId string, fetchAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
@@ -323,14 +409,35 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 	}
 
 	revokeSession := func(sessionHandle string, userContext supertokens.UserContext) (bool, error) {
-		return revokeSessionHelper(querier, sessionHandle, userContext)
+		revoked, err := revokeSessionHelper(querier, sessionHandle, userContext)
+		if revoked {
+			supertokens.IncrementCounterMetric(supertokens.MetricSessionsRevoked, nil)
+			addToRevocationList(config, []string{sessionHandle}, userContext)
+		}
+		return revoked, err
 	}
 
 	revokeMultipleSessions := func(sessionHandles []string, userContext supertokens.UserContext) ([]string, error) {
-		return revokeMultipleSessionsHelper(querier, sessionHandles, userContext)
+		revokedSessionHandles, err := revokeMultipleSessionsHelper(querier, sessionHandles, userContext)
+		supertokens.AddToCounterMetric(supertokens.MetricSessionsRevoked, nil, float64(len(revokedSessionHandles)))
+		addToRevocationList(config, revokedSessionHandles, userContext)
+		return revokedSessionHandles, err
 	}
 
 	updateSessionDataInDatabase := func(sessionHandle string, newSessionData map[string]interface{}, userContext supertokens.UserContext) (bool, error) {
+		if config.DeviceInfo != nil {
+			sessionInformation, err := (*result.GetSessionInformation)(sessionHandle, userContext)
+			if err != nil {
+				return false, err
+			}
+			if sessionInformation == nil {
+				return false, nil
+			}
+			newSessionData, err = keepDeviceInfoInSessionData(*sessionInformation, newSessionData)
+			if err != nil {
+				return false, err
+			}
+		}
 		return updateSessionDataInDatabaseHelper(querier, sessionHandle, newSessionData, userContext)
 	}
 
@@ -409,6 +516,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 			accessTokenPayloadUpdate = accessTokenPayload
 		}
 
+		claimValidators = restrictClaimValidatorsForImpersonation(config, claimValidators, accessTokenPayload)
 		invalidClaims := ValidateClaimsInPayload(claimValidators, accessTokenPayload, userContext)
 
 		if len(accessTokenPayloadUpdate) == 0 {
@@ -422,6 +530,7 @@ func MakeRecipeImplementation(querier supertokens.Querier, config sessmodels.Typ
 	}
 
 	validateClaimsInJWTPayload := func(userId string, jwtPayload map[string]interface{}, claimValidators []claims.SessionClaimValidator, userContext supertokens.UserContext) ([]claims.ClaimValidationError, error) {
+		claimValidators = restrictClaimValidatorsForImpersonation(config, claimValidators, jwtPayload)
 		invalidClaims := ValidateClaimsInPayload(claimValidators, jwtPayload, userContext)
 		return invalidClaims, nil
 	}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+type testRevocationPubSub struct {
+	lock        sync.Mutex
+	subscribers []func(sessionHandles []string)
+}
+
+func (p *testRevocationPubSub) Publish(sessionHandles []string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, subscriber := range p.subscribers {
+		subscriber(sessionHandles)
+	}
+	return nil
+}
+
+func (p *testRevocationPubSub) Subscribe(onRevoked func(sessionHandles []string)) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.subscribers = append(p.subscribers, onRevoked)
+	return nil
+}
+
+func newRevocationListTestInstance(t *testing.T, core *coreemulator.Core, revocationList *sessmodels.RevocationListConfig) supertokens.UserContext {
+	return newSessionTestUserContext(t, core, &sessmodels.TypeInput{RevocationList: revocationList})
+}
+
+func TestThatTheRevocationListRejectsRevokedSessionsWithoutQueryingTheCore(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	pubSub := &testRevocationPubSub{}
+	first := newRevocationListTestInstance(t, core, &sessmodels.RevocationListConfig{PubSub: pubSub})
+	second := newRevocationListTestInstance(t, core, &sessmodels.RevocationListConfig{PubSub: pubSub})
+	withoutRevocationList := newRevocationListTestInstance(t, core, nil)
+
+	revoked, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, first)
+	assert.NoError(t, err)
+	other, err := CreateNewSessionWithoutRequestResponse("public", "otherUserId", nil, nil, nil, first)
+	assert.NoError(t, err)
+
+	_, err = RevokeAllSessionsForUser("userId", nil, first)
+	assert.NoError(t, err)
+
+	for _, userContext := range []supertokens.UserContext{first, second} {
+		didGetSessionCallCore = false
+		_, err = GetSessionWithoutRequestResponse(revoked.GetAccessToken(), nil, nil, userContext)
+		assert.ErrorIs(t, err, errors.ErrUnauthorised)
+		assert.False(t, didGetSessionCallCore)
+
+		sessionContainer, err := GetSessionWithoutRequestResponse(other.GetAccessToken(), nil, nil, userContext)
+		assert.NoError(t, err)
+		assert.Equal(t, "otherUserId", sessionContainer.GetUserID())
+	}
+
+	// without the revocation list, the access token can be used until it expires
+	sessionContainer, err := GetSessionWithoutRequestResponse(revoked.GetAccessToken(), nil, nil, withoutRevocationList)
+	assert.NoError(t, err)
+	assert.Equal(t, "userId", sessionContainer.GetUserID())
+}
+
+func TestThatRevokedSessionsAreRemovedFromTheRevocationListAfterTheRetention(t *testing.T) {
+	retention := 50 * time.Millisecond
+	revocationList := sessmodels.NewRevocationList(sessmodels.RevocationListConfig{Retention: &retention})
+
+	assert.NoError(t, revocationList.Add([]string{"handle"}))
+	assert.True(t, revocationList.IsRevoked("handle"))
+	assert.False(t, revocationList.IsRevoked("otherHandle"))
+
+	time.Sleep(2 * retention)
+	assert.False(t, revocationList.IsRevoked("handle"))
+}
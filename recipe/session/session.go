@@ -251,6 +251,7 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 			return errors.InvalidClaimError{
 				Msg:           "invalid claims",
 				InvalidClaims: validateClaimResponse.InvalidClaims,
+				TenantId:      sessionContainer.GetTenantIdWithContext(userContext),
 			}
 		}
 
@@ -305,6 +306,13 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 		return nil
 	}
 
+	sessionContainer.IsImpersonatedWithContext = func(userContext supertokens.UserContext) bool {
+		return sessionContainer.GetImpersonatorUserIDWithContext(userContext) != nil
+	}
+	sessionContainer.GetImpersonatorUserIDWithContext = func(userContext supertokens.UserContext) *string {
+		return getImpersonatorUserIDFromPayload(sessionContainer.GetAccessTokenPayloadWithContext(userContext))
+	}
+
 	sessionContainer.RevokeSession = func() error {
 		return sessionContainer.RevokeSessionWithContext(&map[string]interface{}{})
 	}
@@ -376,5 +384,12 @@ func newSessionContainer(config sessmodels.TypeNormalisedInput, session *Session
 		return sessionContainer.AttachToRequestResponseWithContext(info, &map[string]interface{}{})
 	}
 
+	sessionContainer.IsImpersonated = func() bool {
+		return sessionContainer.IsImpersonatedWithContext(&map[string]interface{}{})
+	}
+	sessionContainer.GetImpersonatorUserID = func() *string {
+		return sessionContainer.GetImpersonatorUserIDWithContext(&map[string]interface{}{})
+	}
+
 	return sessionContainer
 }
@@ -16,7 +16,6 @@
 package session
 
 import (
-	"encoding/json"
 	defaultErrors "errors"
 	"fmt"
 	"strings"
@@ -33,36 +32,32 @@ func createNewSessionHelper(config sessmodels.TypeNormalisedInput, querier super
 	if sessionDataInDatabase == nil {
 		sessionDataInDatabase = map[string]interface{}{}
 	}
-	requestBody := map[string]interface{}{
-		"userId":               userID,
-		"userDataInJWT":        AccessTokenPayload,
-		"userDataInDatabase":   sessionDataInDatabase,
-		"enableAntiCsrf":       !disableAntiCsrf && config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN,
-		"useDynamicSigningKey": config.UseDynamicAccessTokenSigningKey,
+	requestBody := supertokens.CoreCreateSessionRequest{
+		UserId:               userID,
+		UserDataInJWT:        AccessTokenPayload,
+		UserDataInDatabase:   sessionDataInDatabase,
+		EnableAntiCsrf:       !disableAntiCsrf && config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN,
+		UseDynamicSigningKey: config.UseDynamicAccessTokenSigningKey,
 	}
 
-	response, err := querier.SendPostRequest(tenantId+"/recipe/session", requestBody, userContext)
+	var response supertokens.CoreCreateSessionResponse
+	err := querier.SendPostRequestTyped(tenantId+"/recipe/session", requestBody, &response, userContext)
 	if err != nil {
 		return sessmodels.CreateOrRefreshAPIResponse{}, err
 	}
 
-	responseByte, err := json.Marshal(response)
-	if err != nil {
-		return sessmodels.CreateOrRefreshAPIResponse{}, err
-	}
-	var resp sessmodels.CreateOrRefreshAPIResponse
-	err = json.Unmarshal(responseByte, &resp)
-	if err != nil {
-		return sessmodels.CreateOrRefreshAPIResponse{}, err
-	}
-
-	return resp, nil
+	return sessmodels.CreateOrRefreshAPIResponse{
+		Session:       sessmodels.SessionStruct(response.Session),
+		AccessToken:   sessmodels.CreateOrRefreshAPIResponseToken(response.AccessToken),
+		RefreshToken:  sessmodels.CreateOrRefreshAPIResponseToken(response.RefreshToken),
+		AntiCsrfToken: response.AntiCsrfToken,
+	}, nil
 }
 
 func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, parsedAccessToken sessmodels.ParsedJWTInfo, antiCsrfToken *string, doAntiCsrfCheck, alwaysCheckCore bool, userContext supertokens.UserContext) (sessmodels.GetSessionResponse, error) {
 	var accessTokenInfo *AccessTokenInfoStruct = nil
 	var err error = nil
-	combinedJwks, jwksError := GetCombinedJWKS()
+	combinedJwks, jwksError := GetCombinedJWKS(userContext)
 	if jwksError != nil {
 		supertokens.LogDebugMessage(fmt.Sprintf("getSessionHelper: Returning TryRefreshTokenError because there was an error fetching JWKs - %s", jwksError))
 		if !defaultErrors.As(jwksError, &errors.TryRefreshTokenError{}) {
@@ -105,6 +100,11 @@ func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens
 		}
 	}
 
+	if accessTokenInfo != nil && config.RevocationList != nil && config.RevocationList.IsRevoked(accessTokenInfo.SessionHandle) {
+		supertokens.LogDebugMessage("getSession: Returning UNAUTHORISED because the session is in the revocation list")
+		return sessmodels.GetSessionResponse{}, errors.UnauthorizedError{Msg: "Session has been revoked"}
+	}
+
 	if parsedAccessToken.Version >= 3 {
 		tokenUsesDynamicKey := false
 		kid := parsedAccessToken.KID
@@ -149,36 +149,27 @@ func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens
 			},
 		}, nil
 	}
-	requestBody := map[string]interface{}{
-		"accessToken":     parsedAccessToken.RawTokenString,
-		"doAntiCsrfCheck": doAntiCsrfCheck,
-		"enableAntiCsrf":  config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN,
-		"checkDatabase":   alwaysCheckCore,
-	}
-	if antiCsrfToken != nil {
-		requestBody["antiCsrfToken"] = *antiCsrfToken
+	requestBody := supertokens.CoreVerifySessionRequest{
+		AccessToken:     parsedAccessToken.RawTokenString,
+		AntiCsrfToken:   antiCsrfToken,
+		DoAntiCsrfCheck: doAntiCsrfCheck,
+		EnableAntiCsrf:  config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN,
+		CheckDatabase:   alwaysCheckCore,
 	}
 
 	if supertokens.IsRunningInTestMode() {
 		didGetSessionCallCore = true
 	}
-	response, err := querier.SendPostRequest("/recipe/session/verify", requestBody, userContext)
+	var response supertokens.CoreVerifySessionResponse
+	err = querier.SendPostRequestTyped("/recipe/session/verify", requestBody, &response, userContext)
 	if err != nil {
 		return sessmodels.GetSessionResponse{}, err
 	}
 
-	status := response["status"]
-	if status.(string) == "OK" {
-		delete(response, "status")
-
-		responseByte, err := json.Marshal(response)
-		if err != nil {
-			return sessmodels.GetSessionResponse{}, err
-		}
-		var result sessmodels.GetSessionResponse
-		err = json.Unmarshal(responseByte, &result)
-		if err != nil {
-			return sessmodels.GetSessionResponse{}, err
+	if response.Status == "OK" {
+		result := sessmodels.GetSessionResponse{
+			Session:     sessmodels.SessionStruct(response.Session),
+			AccessToken: sessmodels.CreateOrRefreshAPIResponseToken(response.AccessToken),
 		}
 
 		var expiryToSet uint64
@@ -193,77 +184,76 @@ func getSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens
 
 		result.Session.ExpiryTime = expiryToSet
 		return result, nil
-	} else if response["status"].(string) == errors.UnauthorizedErrorStr {
+	} else if response.Status == errors.UnauthorizedErrorStr {
 		supertokens.LogDebugMessage("getSession: Returning UNAUTHORISED because of core response")
-		return sessmodels.GetSessionResponse{}, errors.UnauthorizedError{Msg: response["message"].(string)}
+		return sessmodels.GetSessionResponse{}, errors.UnauthorizedError{Msg: response.Message}
 	} else {
 		supertokens.LogDebugMessage("getSession: Returning TRY_REFRESH_TOKEN because of core response")
-		return sessmodels.GetSessionResponse{}, errors.TryRefreshTokenError{Msg: response["message"].(string)}
+		return sessmodels.GetSessionResponse{}, errors.TryRefreshTokenError{Msg: response.Message}
 	}
 }
 
 func getSessionInformationHelper(querier supertokens.Querier, sessionHandle string, userContext supertokens.UserContext) (*sessmodels.SessionInformation, error) {
-	response, err := querier.SendGetRequest("/recipe/session",
+	var response supertokens.CoreGetSessionInformationResponse
+	err := querier.SendGetRequestTyped("/recipe/session",
 		map[string]string{
 			"sessionHandle": sessionHandle,
-		}, userContext)
+		}, &response, userContext)
 	if err != nil {
 		return nil, err
 	}
-	if response["status"] == "OK" {
+	if response.Status == "OK" {
 		return &sessmodels.SessionInformation{
-			SessionHandle:                    response["sessionHandle"].(string),
-			UserId:                           response["userId"].(string),
-			SessionDataInDatabase:            response["userDataInDatabase"].(map[string]interface{}),
-			Expiry:                           uint64(response["expiry"].(float64)),
-			TimeCreated:                      uint64(response["timeCreated"].(float64)),
-			CustomClaimsInAccessTokenPayload: response["userDataInJWT"].(map[string]interface{}),
-			TenantId:                         response["tenantId"].(string),
+			SessionHandle:                    response.SessionHandle,
+			UserId:                           response.UserId,
+			SessionDataInDatabase:            removeDeviceInfoFromSessionData(response.UserDataInDatabase),
+			Expiry:                           response.Expiry,
+			TimeCreated:                      response.TimeCreated,
+			CustomClaimsInAccessTokenPayload: response.UserDataInJWT,
+			TenantId:                         response.TenantId,
+			DeviceInfo:                       getDeviceInfoFromSessionData(response.UserDataInDatabase),
 		}, nil
 	}
 	return nil, nil
 }
 
 func refreshSessionHelper(config sessmodels.TypeNormalisedInput, querier supertokens.Querier, refreshToken string, antiCsrfToken *string, disableAntiCsrf bool, useDynamicAccessTokenSigningKey bool, userContext supertokens.UserContext) (sessmodels.CreateOrRefreshAPIResponse, error) {
-	requestBody := map[string]interface{}{
-		"refreshToken":         refreshToken,
-		"enableAntiCsrf":       !disableAntiCsrf && config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN,
-		"useDynamicSigningKey": useDynamicAccessTokenSigningKey,
-	}
-	if antiCsrfToken != nil {
-		requestBody["antiCsrfToken"] = *antiCsrfToken
+	requestBody := supertokens.CoreRefreshSessionRequest{
+		RefreshToken:         refreshToken,
+		AntiCsrfToken:        antiCsrfToken,
+		EnableAntiCsrf:       !disableAntiCsrf && config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_TOKEN,
+		UseDynamicSigningKey: useDynamicAccessTokenSigningKey,
 	}
 
 	if config.AntiCsrfFunctionOrString.FunctionValue == nil && config.AntiCsrfFunctionOrString.StrValue == AntiCSRF_VIA_CUSTOM_HEADER && !disableAntiCsrf {
 		return sessmodels.CreateOrRefreshAPIResponse{}, defaultErrors.New("Please either use VIA_TOKEN, NONE or call with doAntiCsrfCheck false")
 	}
 
-	response, err := querier.SendPostRequest("/recipe/session/refresh", requestBody, userContext)
+	var response supertokens.CoreRefreshSessionResponse
+	err := querier.SendPostRequestTyped("/recipe/session/refresh", requestBody, &response, userContext)
 	if err != nil {
 		return sessmodels.CreateOrRefreshAPIResponse{}, err
 	}
-	if response["status"] == "OK" {
-		delete(response, "status")
-		responseByte, err := json.Marshal(response)
-		if err != nil {
-			return sessmodels.CreateOrRefreshAPIResponse{}, err
-		}
-		var result sessmodels.CreateOrRefreshAPIResponse
-		err = json.Unmarshal(responseByte, &result)
-		if err != nil {
-			return sessmodels.CreateOrRefreshAPIResponse{}, err
-		}
-		return result, nil
-	} else if response["status"].(string) == errors.UnauthorizedErrorStr {
+	if response.Status == "OK" {
+		return sessmodels.CreateOrRefreshAPIResponse{
+			Session:       sessmodels.SessionStruct(response.Session),
+			AccessToken:   sessmodels.CreateOrRefreshAPIResponseToken(response.AccessToken),
+			RefreshToken:  sessmodels.CreateOrRefreshAPIResponseToken(response.RefreshToken),
+			AntiCsrfToken: response.AntiCsrfToken,
+		}, nil
+	} else if response.Status == errors.UnauthorizedErrorStr {
 		supertokens.LogDebugMessage("refreshSession: Returning UNAUTHORISED because of core response")
-		return sessmodels.CreateOrRefreshAPIResponse{}, errors.UnauthorizedError{Msg: response["message"].(string)}
+		return sessmodels.CreateOrRefreshAPIResponse{}, errors.UnauthorizedError{Msg: response.Message}
 	} else {
 		sessionInfo := errors.TokenTheftDetectedErrorPayload{
-			SessionHandle: (response["session"].(map[string]interface{}))["handle"].(string),
-			UserID:        (response["session"].(map[string]interface{}))["userId"].(string),
+			SessionHandle: response.Session.Handle,
+			UserID:        response.Session.UserID,
 		}
 
-		supertokens.LogDebugMessage("refreshSession: Returning TOKEN_THEFT_DETECTED because of core response")
+		supertokens.LogWarn(userContext, "refreshSession: Returning TOKEN_THEFT_DETECTED because of core response", "sessionHandle", response.Session.Handle, "userId", response.Session.UserID)
+		supertokens.IncrementCounterMetric(supertokens.MetricTokenTheftDetected, map[string]string{
+			"tenant_id": response.Session.TenantId,
+		})
 		return sessmodels.CreateOrRefreshAPIResponse{}, errors.TokenTheftDetectedError{
 			Msg:     "Token theft detected",
 			Payload: sessionInfo,
@@ -272,30 +262,20 @@ func refreshSessionHelper(config sessmodels.TypeNormalisedInput, querier superto
 }
 
 func revokeAllSessionsForUserHelper(querier supertokens.Querier, userID string, tenantId string, revokeAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
-	requestBody := map[string]interface{}{
-		"userId": userID,
+	requestBody := supertokens.CoreRevokeAllSessionsForUserRequest{
+		UserId:                 userID,
+		RevokeAcrossAllTenants: revokeAcrossAllTenants,
 	}
-	if revokeAcrossAllTenants != nil {
-		requestBody["revokeAcrossAllTenants"] = *revokeAcrossAllTenants
-
-		if *revokeAcrossAllTenants {
-			tenantId = "" // so that we don't pass the tenantId in the url
-		}
+	if revokeAcrossAllTenants != nil && *revokeAcrossAllTenants {
+		tenantId = "" // so that we don't pass the tenantId in the url
 	}
-	response, err := querier.SendPostRequest(tenantId+"/recipe/session/remove", requestBody, userContext)
+	var response supertokens.CoreRevokeSessionsResponse
+	err := querier.SendPostRequestTyped(tenantId+"/recipe/session/remove", requestBody, &response, userContext)
 	if err != nil {
 		return nil, err
 	}
 
-	revokedSessionHandlesAsSliceOfInterfaces := response["sessionHandlesRevoked"].([]interface{})
-
-	var result []string
-
-	for _, val := range revokedSessionHandlesAsSliceOfInterfaces {
-		result = append(result, val.(string))
-	}
-
-	return result, nil
+	return response.SessionHandlesRevoked, nil
 }
 
 func getAllSessionHandlesForUserHelper(querier supertokens.Querier, userID string, tenantId string, fetchAcrossAllTenants *bool, userContext supertokens.UserContext) ([]string, error) {
@@ -309,65 +289,53 @@ func getAllSessionHandlesForUserHelper(querier supertokens.Querier, userID strin
 			tenantId = "" // so that we don't pass the tenantId in the url
 		}
 	}
-	response, err := querier.SendGetRequest(tenantId+"/recipe/session/user", queryParams, userContext)
+	var response supertokens.CoreGetAllSessionHandlesForUserResponse
+	err := querier.SendGetRequestTyped(tenantId+"/recipe/session/user", queryParams, &response, userContext)
 	if err != nil {
 		return nil, err
 	}
 
-	sessionHandlesAsSliceOfInterfaces := response["sessionHandles"].([]interface{})
-
-	var result []string
-
-	for _, val := range sessionHandlesAsSliceOfInterfaces {
-		result = append(result, val.(string))
-	}
-
-	return result, nil
+	return response.SessionHandles, nil
 }
 
 func revokeSessionHelper(querier supertokens.Querier, sessionHandle string, userContext supertokens.UserContext) (bool, error) {
-	response, err := querier.SendPostRequest("/recipe/session/remove",
-		map[string]interface{}{
-			"sessionHandles": [1]string{sessionHandle},
-		}, userContext)
+	var response supertokens.CoreRevokeSessionsResponse
+	err := querier.SendPostRequestTyped("/recipe/session/remove",
+		supertokens.CoreRevokeSessionsRequest{
+			SessionHandles: []string{sessionHandle},
+		}, &response, userContext)
 	if err != nil {
 		return false, err
 	}
-	return len(response["sessionHandlesRevoked"].([]interface{})) == 1, nil
+	return len(response.SessionHandlesRevoked) == 1, nil
 }
 
 func revokeMultipleSessionsHelper(querier supertokens.Querier, sessionHandles []string, userContext supertokens.UserContext) ([]string, error) {
-	response, err := querier.SendPostRequest("/recipe/session/remove",
-		map[string]interface{}{
-			"sessionHandles": sessionHandles,
-		}, userContext)
+	var response supertokens.CoreRevokeSessionsResponse
+	err := querier.SendPostRequestTyped("/recipe/session/remove",
+		supertokens.CoreRevokeSessionsRequest{
+			SessionHandles: sessionHandles,
+		}, &response, userContext)
 	if err != nil {
 		return nil, err
 	}
-	revokedSessionHandlesAsSliceOfInterfaces := response["sessionHandlesRevoked"].([]interface{})
-
-	var result []string
-
-	for _, val := range revokedSessionHandlesAsSliceOfInterfaces {
-		result = append(result, val.(string))
-	}
-
-	return result, nil
+	return response.SessionHandlesRevoked, nil
 }
 
 func updateSessionDataInDatabaseHelper(querier supertokens.Querier, sessionHandle string, newSessionData map[string]interface{}, userContext supertokens.UserContext) (bool, error) {
 	if newSessionData == nil {
 		newSessionData = map[string]interface{}{}
 	}
-	response, err := querier.SendPutRequest("/recipe/session/data",
-		map[string]interface{}{
-			"sessionHandle":      sessionHandle,
-			"userDataInDatabase": newSessionData,
-		}, userContext)
+	var response supertokens.CoreStatusResponse
+	err := querier.SendPutRequestTyped("/recipe/session/data",
+		supertokens.CoreUpdateSessionDataInDatabaseRequest{
+			SessionHandle:      sessionHandle,
+			UserDataInDatabase: newSessionData,
+		}, &response, userContext)
 	if err != nil {
 		return false, err
 	}
-	if response["status"].(string) == errors.UnauthorizedErrorStr {
+	if response.Status == errors.UnauthorizedErrorStr {
 		return false, nil
 	}
 	return true, nil
@@ -377,14 +345,15 @@ func updateAccessTokenPayloadHelper(querier supertokens.Querier, sessionHandle s
 	if newAccessTokenPayload == nil {
 		newAccessTokenPayload = map[string]interface{}{}
 	}
-	response, err := querier.SendPutRequest("/recipe/jwt/data", map[string]interface{}{
-		"sessionHandle": sessionHandle,
-		"userDataInJWT": newAccessTokenPayload,
-	}, userContext)
+	var response supertokens.CoreStatusResponse
+	err := querier.SendPutRequestTyped("/recipe/jwt/data", supertokens.CoreUpdateAccessTokenPayloadRequest{
+		SessionHandle: sessionHandle,
+		UserDataInJWT: newAccessTokenPayload,
+	}, &response, userContext)
 	if err != nil {
 		return false, err
 	}
-	if response["status"].(string) == errors.UnauthorizedErrorStr {
+	if response.Status == errors.UnauthorizedErrorStr {
 		return false, nil
 	}
 	return true, nil
@@ -394,24 +363,20 @@ func regenerateAccessTokenHelper(querier supertokens.Querier, newAccessTokenPayl
 	if newAccessTokenPayload == nil {
 		newAccessTokenPayload = &map[string]interface{}{}
 	}
-	response, err := querier.SendPostRequest("/recipe/session/regenerate", map[string]interface{}{
-		"accessToken":   accessToken,
-		"userDataInJWT": newAccessTokenPayload,
-	}, userContext)
+	var resp supertokens.CoreRegenerateAccessTokenResponse
+	err := querier.SendPostRequestTyped("/recipe/session/regenerate", supertokens.CoreRegenerateAccessTokenRequest{
+		AccessToken:   accessToken,
+		UserDataInJWT: *newAccessTokenPayload,
+	}, &resp, userContext)
 	if err != nil {
 		return nil, err
 	}
-	if response["status"].(string) == errors.UnauthorizedErrorStr {
+	if resp.Status == errors.UnauthorizedErrorStr {
 		return nil, nil
 	}
-	responseByte, err := json.Marshal(response)
-	if err != nil {
-		return nil, err
-	}
-	var resp sessmodels.RegenerateAccessTokenResponse
-	err = json.Unmarshal(responseByte, &resp)
-	if err != nil {
-		return nil, err
-	}
-	return &resp, nil
+	return &sessmodels.RegenerateAccessTokenResponse{
+		Status:      resp.Status,
+		Session:     sessmodels.SessionStruct(resp.Session),
+		AccessToken: sessmodels.CreateOrRefreshAPIResponseToken(resp.AccessToken),
+	}, nil
 }
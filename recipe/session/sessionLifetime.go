@@ -0,0 +1,182 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// sessionLifetimePayloadKey is the key in the access token payload under which the time the session was
+// created ("c") and the time it was last used ("a") are stored, in milliseconds
+const sessionLifetimePayloadKey = "st-lt"
+
+const defaultActivityUpdateInterval = time.Minute
+
+type sessionLifetimeTimestamps struct {
+	created    time.Time
+	lastActive time.Time
+}
+
+func normaliseSessionLifetimeConfig(config *sessmodels.SessionLifetimeConfig) *sessmodels.NormalisedSessionLifetimeConfig {
+	if config == nil {
+		return nil
+	}
+	return &sessmodels.NormalisedSessionLifetimeConfig{
+		Policy:                 config.Policy,
+		GetPolicyForTenant:     config.GetPolicyForTenant,
+		ActivityUpdateInterval: config.ActivityUpdateInterval,
+	}
+}
+
+func makeSessionLifetimePayload(timestamps sessionLifetimeTimestamps) map[string]interface{} {
+	return map[string]interface{}{
+		"c": float64(timestamps.created.UnixMilli()),
+		"a": float64(timestamps.lastActive.UnixMilli()),
+	}
+}
+
+func getSessionLifetimeTimestamps(accessTokenPayload map[string]interface{}) (sessionLifetimeTimestamps, bool) {
+	payload, ok := accessTokenPayload[sessionLifetimePayloadKey].(map[string]interface{})
+	if !ok {
+		return sessionLifetimeTimestamps{}, false
+	}
+	created := sanitizeNumberInputAsUint64(payload["c"])
+	lastActive := sanitizeNumberInputAsUint64(payload["a"])
+	if created == nil || lastActive == nil {
+		return sessionLifetimeTimestamps{}, false
+	}
+	return sessionLifetimeTimestamps{
+		created:    time.UnixMilli(int64(*created)),
+		lastActive: time.UnixMilli(int64(*lastActive)),
+	}, true
+}
+
+// addSessionLifetimeToAccessTokenPayload returns a copy of the payload of a new session with the timestamps
+// of the session lifetime policy added to it
+func addSessionLifetimeToAccessTokenPayload(config sessmodels.TypeNormalisedInput, accessTokenPayload map[string]interface{}) map[string]interface{} {
+	if config.SessionLifetime == nil {
+		return accessTokenPayload
+	}
+	result := map[string]interface{}{}
+	for key, value := range accessTokenPayload {
+		result[key] = value
+	}
+	now := time.Now()
+	result[sessionLifetimePayloadKey] = makeSessionLifetimePayload(sessionLifetimeTimestamps{created: now, lastActive: now})
+	return result
+}
+
+func getSessionLifetimePolicy(config *sessmodels.NormalisedSessionLifetimeConfig, tenantId string, userContext supertokens.UserContext) (sessmodels.SessionLifetimePolicy, error) {
+	policy := config.Policy
+	if config.GetPolicyForTenant == nil {
+		return policy, nil
+	}
+	tenantPolicy, err := config.GetPolicyForTenant(tenantId, userContext)
+	if err != nil {
+		return sessmodels.SessionLifetimePolicy{}, err
+	}
+	if tenantPolicy != nil {
+		if tenantPolicy.IdleTimeout != nil {
+			policy.IdleTimeout = tenantPolicy.IdleTimeout
+		}
+		if tenantPolicy.AbsoluteLifetime != nil {
+			policy.AbsoluteLifetime = tenantPolicy.AbsoluteLifetime
+		}
+	}
+	return policy, nil
+}
+
+func getActivityUpdateInterval(config *sessmodels.NormalisedSessionLifetimeConfig, policy sessmodels.SessionLifetimePolicy) time.Duration {
+	if config.ActivityUpdateInterval != nil {
+		return *config.ActivityUpdateInterval
+	}
+	if policy.IdleTimeout != nil && *policy.IdleTimeout > 0 && *policy.IdleTimeout/10 < defaultActivityUpdateInterval {
+		return *policy.IdleTimeout / 10
+	}
+	return defaultActivityUpdateInterval
+}
+
+// enforceSessionLifetime revokes the session and returns an UnauthorizedError if it expired because of the
+// session lifetime policy. Otherwise, the time the session was last used is updated in its access token if
+// it was recorded more than the activity update interval ago.
+func enforceSessionLifetime(config sessmodels.TypeNormalisedInput, recipeImpl sessmodels.RecipeInterface, sessionContainer sessmodels.SessionContainer, isRefresh bool, userContext supertokens.UserContext) error {
+	if config.SessionLifetime == nil || sessionContainer == nil {
+		return nil
+	}
+	policy, err := getSessionLifetimePolicy(config.SessionLifetime, sessionContainer.GetTenantIdWithContext(userContext), userContext)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	timestamps, ok := getSessionLifetimeTimestamps(sessionContainer.GetAccessTokenPayloadWithContext(userContext))
+	if !ok {
+		// the session was created before the policy was enabled. We start tracking it once it is refreshed,
+		// so that this does not cost a query to the core for every request.
+		if !isRefresh {
+			return nil
+		}
+		timeCreated, err := sessionContainer.GetTimeCreatedWithContext(userContext)
+		if err != nil {
+			return err
+		}
+		timestamps = sessionLifetimeTimestamps{created: time.UnixMilli(int64(timeCreated)), lastActive: now}
+	}
+
+	if policy.AbsoluteLifetime != nil && *policy.AbsoluteLifetime > 0 && now.Sub(timestamps.created) > *policy.AbsoluteLifetime {
+		supertokens.LogDebugMessage("enforceSessionLifetime: Returning UNAUTHORISED because the session exceeded its absolute lifetime")
+		return expireSession(recipeImpl, sessionContainer, errors.UnauthorisedReasonAbsoluteLifetimeExceeded, "session exceeded its maximum lifetime", userContext)
+	}
+
+	if policy.IdleTimeout != nil && *policy.IdleTimeout > 0 && now.Sub(timestamps.lastActive) > *policy.IdleTimeout {
+		// the activity may have been recorded in an access token that was issued for another request
+		sessionInformation, err := (*recipeImpl.GetSessionInformation)(sessionContainer.GetHandleWithContext(userContext), userContext)
+		if err != nil {
+			return err
+		}
+		if sessionInformation == nil {
+			return errors.UnauthorizedError{Msg: "session does not exist anymore"}
+		}
+		latestTimestamps, ok := getSessionLifetimeTimestamps(sessionInformation.CustomClaimsInAccessTokenPayload)
+		if ok && latestTimestamps.lastActive.After(timestamps.lastActive) {
+			timestamps.lastActive = latestTimestamps.lastActive
+		}
+		if now.Sub(timestamps.lastActive) > *policy.IdleTimeout {
+			supertokens.LogDebugMessage("enforceSessionLifetime: Returning UNAUTHORISED because the session was idle for too long")
+			return expireSession(recipeImpl, sessionContainer, errors.UnauthorisedReasonIdleTimeout, "session expired due to inactivity", userContext)
+		}
+	}
+
+	if ok && now.Sub(timestamps.lastActive) < getActivityUpdateInterval(config.SessionLifetime, policy) {
+		return nil
+	}
+	timestamps.lastActive = now
+	return sessionContainer.MergeIntoAccessTokenPayloadWithContext(map[string]interface{}{
+		sessionLifetimePayloadKey: makeSessionLifetimePayload(timestamps),
+	}, userContext)
+}
+
+func expireSession(recipeImpl sessmodels.RecipeInterface, sessionContainer sessmodels.SessionContainer, reason string, message string, userContext supertokens.UserContext) error {
+	_, err := (*recipeImpl.RevokeSession)(sessionContainer.GetHandleWithContext(userContext), userContext)
+	if err != nil {
+		return err
+	}
+	return errors.UnauthorizedError{Msg: message, Reason: reason}
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	defaultErrors "errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func assertSessionExpired(t *testing.T, err error, reason string) {
+	var unauthErr errors.UnauthorizedError
+	if assert.True(t, defaultErrors.As(err, &unauthErr), "expected an UnauthorizedError, got %v", err) {
+		assert.Equal(t, reason, unauthErr.Reason)
+	}
+}
+
+func TestThatIdleSessionsExpire(t *testing.T) {
+	idleTimeout := 300 * time.Millisecond
+	activityUpdateInterval := 50 * time.Millisecond
+	handler, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		ExposeActiveSessionsAPIs: true,
+		SessionLifetime: &sessmodels.SessionLifetimeConfig{
+			Policy:                 sessmodels.SessionLifetimePolicy{IdleTimeout: &idleTimeout},
+			ActivityUpdateInterval: &activityUpdateInterval,
+		},
+	})
+
+	active, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	idle, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	// the session stays valid while it is in use, for longer than the idle timeout
+	accessToken := active.GetAccessToken()
+	for i := 0; i < 4; i++ {
+		time.Sleep(100 * time.Millisecond)
+		sessionContainer, err := GetSessionWithoutRequestResponse(accessToken, nil, nil, userContext)
+		assert.NoError(t, err)
+		accessToken = sessionContainer.GetAccessToken()
+	}
+
+	_, err = GetSessionWithoutRequestResponse(idle.GetAccessToken(), nil, nil, userContext)
+	assertSessionExpired(t, err, errors.UnauthorisedReasonIdleTimeout)
+	sessionInformation, err := GetSessionInformation(idle.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, sessionInformation)
+
+	// the reason is sent to the frontend
+	time.Sleep(idleTimeout + 50*time.Millisecond)
+	status, body := callActiveSessionsAPI(handler, http.MethodGet, "/auth/sessions", accessToken, "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, errors.UnauthorisedReasonIdleTimeout, body["reason"])
+}
+
+func TestThatSessionsExpireAfterTheirAbsoluteLifetime(t *testing.T) {
+	absoluteLifetime := time.Hour
+	absoluteLifetimeOfTenant := 300 * time.Millisecond
+	_, userContext := newActiveSessionsTestInstance(t, &sessmodels.TypeInput{
+		SessionLifetime: &sessmodels.SessionLifetimeConfig{
+			Policy: sessmodels.SessionLifetimePolicy{AbsoluteLifetime: &absoluteLifetime},
+			GetPolicyForTenant: func(tenantId string, userContext supertokens.UserContext) (*sessmodels.SessionLifetimePolicy, error) {
+				if tenantId == "public" {
+					return &sessmodels.SessionLifetimePolicy{AbsoluteLifetime: &absoluteLifetimeOfTenant}, nil
+				}
+				return nil, nil
+			},
+		},
+	})
+
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	_, err = GetSessionWithoutRequestResponse(sessionContainer.GetAccessToken(), nil, nil, userContext)
+	assert.NoError(t, err)
+
+	time.Sleep(absoluteLifetimeOfTenant + 50*time.Millisecond)
+	_, err = RefreshSessionWithoutRequestResponse(*sessionContainer.GetAllSessionTokensDangerously().RefreshToken, nil, nil, userContext)
+	assertSessionExpired(t, err, errors.UnauthorisedReasonAbsoluteLifetimeExceeded)
+	_, err = GetSessionWithoutRequestResponse(sessionContainer.GetAccessToken(), nil, nil, userContext)
+	assert.ErrorIs(t, err, errors.ErrUnauthorised)
+}
+
+func TestThatTheLimitsOfTheTenantPolicyDefaultToTheLimitsOfThePolicy(t *testing.T) {
+	idleTimeout := time.Minute
+	absoluteLifetime := time.Hour
+	disabled := time.Duration(0)
+	config := normaliseSessionLifetimeConfig(&sessmodels.SessionLifetimeConfig{
+		Policy: sessmodels.SessionLifetimePolicy{IdleTimeout: &idleTimeout, AbsoluteLifetime: &absoluteLifetime},
+		GetPolicyForTenant: func(tenantId string, userContext supertokens.UserContext) (*sessmodels.SessionLifetimePolicy, error) {
+			if tenantId == "t1" {
+				return &sessmodels.SessionLifetimePolicy{AbsoluteLifetime: &disabled}, nil
+			}
+			return nil, nil
+		},
+	})
+
+	policy, err := getSessionLifetimePolicy(config, "t1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, idleTimeout, *policy.IdleTimeout)
+	assert.Equal(t, disabled, *policy.AbsoluteLifetime)
+	assert.Equal(t, 6*time.Second, getActivityUpdateInterval(config, policy))
+
+	policy, err = getSessionLifetimePolicy(config, "public", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, absoluteLifetime, *policy.AbsoluteLifetime)
+}
@@ -0,0 +1,131 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"fmt"
+	"sort"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+func normaliseSessionLimitConfig(config *sessmodels.SessionLimitConfig) (*sessmodels.SessionLimitConfig, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.MaxSessions < 1 {
+		return nil, defaultErrors.New("MaxSessions of the session limit must be at least 1")
+	}
+	result := *config
+	if result.Policy == "" {
+		result.Policy = sessmodels.SessionLimitPolicyEvictOldest
+	}
+	if result.Policy != sessmodels.SessionLimitPolicyEvictOldest && result.Policy != sessmodels.SessionLimitPolicyReject {
+		return nil, defaultErrors.New("the policy of the session limit must be one of 'EVICT_OLDEST' or 'REJECT'")
+	}
+	if result.GetSessionsToEvict == nil {
+		result.GetSessionsToEvict = getOldestSessionsToEvict
+	}
+	return &result, nil
+}
+
+func getOldestSessionsToEvict(sessions []sessmodels.SessionInformation, numberOfSessionsToEvict int, userContext supertokens.UserContext) ([]string, error) {
+	sessionHandles := []string{}
+	for i := 0; i < numberOfSessionsToEvict && i < len(sessions); i++ {
+		sessionHandles = append(sessionHandles, sessions[i].SessionHandle)
+	}
+	return sessionHandles, nil
+}
+
+// filterSessionHandlesToEvict removes the handles that are not of the given sessions, so that
+// GetSessionsToEvict can not revoke the sessions of other users, and the handles that are repeated
+func filterSessionHandlesToEvict(sessions []sessmodels.SessionInformation, sessionHandles []string) []string {
+	isSessionOfUser := map[string]bool{}
+	for _, sessionInformation := range sessions {
+		isSessionOfUser[sessionInformation.SessionHandle] = true
+	}
+	result := []string{}
+	for _, sessionHandle := range sessionHandles {
+		if isSessionOfUser[sessionHandle] {
+			result = append(result, sessionHandle)
+			// so that repeated handles are skipped
+			isSessionOfUser[sessionHandle] = false
+		}
+	}
+	return result
+}
+
+// enforceSessionLimit makes room for a new session of the user, or returns a SessionLimitReachedError
+func enforceSessionLimit(config sessmodels.TypeNormalisedInput, recipeImpl sessmodels.RecipeInterface, userID string, tenantId string, userContext supertokens.UserContext) error {
+	sessionLimit := config.SessionLimit
+	if sessionLimit == nil {
+		return nil
+	}
+	fetchAcrossAllTenants := sessionLimit.AcrossAllTenants
+	sessionHandles, err := (*recipeImpl.GetAllSessionHandlesForUser)(userID, tenantId, &fetchAcrossAllTenants, userContext)
+	if err != nil {
+		return err
+	}
+	numberOfSessionsToEvict := len(sessionHandles) + 1 - sessionLimit.MaxSessions
+	if numberOfSessionsToEvict <= 0 {
+		return nil
+	}
+	limitReachedErr := errors.SessionLimitReachedError{
+		Msg:         fmt.Sprintf("The user already has the maximum number of sessions (%d)", sessionLimit.MaxSessions),
+		UserId:      userID,
+		TenantId:    tenantId,
+		MaxSessions: sessionLimit.MaxSessions,
+	}
+	if sessionLimit.Policy == sessmodels.SessionLimitPolicyReject {
+		supertokens.LogDebugMessage("createNewSession: Returning SESSION_LIMIT_REACHED because the user has the maximum number of sessions")
+		return limitReachedErr
+	}
+
+	sessions := []sessmodels.SessionInformation{}
+	for _, sessionHandle := range sessionHandles {
+		sessionInformation, err := (*recipeImpl.GetSessionInformation)(sessionHandle, userContext)
+		if err != nil {
+			return err
+		}
+		if sessionInformation != nil {
+			sessions = append(sessions, *sessionInformation)
+		}
+	}
+	// sessions may have expired in the meantime
+	numberOfSessionsToEvict = len(sessions) + 1 - sessionLimit.MaxSessions
+	if numberOfSessionsToEvict <= 0 {
+		return nil
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].TimeCreated < sessions[j].TimeCreated
+	})
+
+	chosenSessionHandles, err := sessionLimit.GetSessionsToEvict(sessions, numberOfSessionsToEvict, userContext)
+	if err != nil {
+		return err
+	}
+	sessionHandlesToEvict := filterSessionHandlesToEvict(sessions, chosenSessionHandles)
+	if len(sessionHandlesToEvict) < numberOfSessionsToEvict {
+		supertokens.LogDebugMessage("createNewSession: Returning SESSION_LIMIT_REACHED because not enough sessions were chosen to be evicted")
+		return limitReachedErr
+	}
+	supertokens.LogDebugMessage(fmt.Sprintf("createNewSession: Evicting %d sessions because the user has the maximum number of sessions", len(sessionHandlesToEvict)))
+	_, err = (*recipeImpl.RevokeMultipleSessions)(sessionHandlesToEvict, userContext)
+	return err
+}
@@ -0,0 +1,195 @@
+package session
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newSessionLimitTestInstance(t *testing.T, sessionLimit *sessmodels.SessionLimitConfig) supertokens.UserContext {
+	return newSessionTestUserContext(t, nil, &sessmodels.TypeInput{SessionLimit: sessionLimit})
+}
+
+func createSessionsForSessionLimitTest(t *testing.T, userContext supertokens.UserContext, count int) []string {
+	sessionHandles := []string{}
+	for i := 0; i < count; i++ {
+		sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+		assert.NoError(t, err)
+		sessionHandles = append(sessionHandles, sessionContainer.GetHandle())
+		// so that the sessions have different creation times
+		time.Sleep(5 * time.Millisecond)
+	}
+	return sessionHandles
+}
+
+func TestThatTheOldestSessionsAreEvictedWhenTheSessionLimitIsReached(t *testing.T) {
+	userContext := newSessionLimitTestInstance(t, &sessmodels.SessionLimitConfig{MaxSessions: 2})
+
+	sessionHandles := createSessionsForSessionLimitTest(t, userContext, 4)
+
+	remaining, err := GetAllSessionHandlesForUser("userId", nil, userContext)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, sessionHandles[2:], remaining)
+}
+
+func TestThatNewSessionsAreRejectedWhenTheSessionLimitIsReached(t *testing.T) {
+	userContext := newSessionLimitTestInstance(t, &sessmodels.SessionLimitConfig{
+		MaxSessions: 2,
+		Policy:      sessmodels.SessionLimitPolicyReject,
+	})
+
+	sessionHandles := createSessionsForSessionLimitTest(t, userContext, 2)
+
+	_, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.ErrorIs(t, err, errors.ErrSessionLimitReached)
+	assert.Equal(t, 2, err.(errors.SessionLimitReachedError).MaxSessions)
+
+	// the limit is per user
+	_, err = CreateNewSessionWithoutRequestResponse("public", "otherUserId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	remaining, err := GetAllSessionHandlesForUser("userId", nil, userContext)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, sessionHandles, remaining)
+}
+
+func TestThatTheSessionsToEvictOnTheSessionLimitCanBeChosen(t *testing.T) {
+	evictNone := false
+	userContext := newSessionLimitTestInstance(t, &sessmodels.SessionLimitConfig{
+		MaxSessions: 2,
+		GetSessionsToEvict: func(sessions []sessmodels.SessionInformation, numberOfSessionsToEvict int, userContext supertokens.UserContext) ([]string, error) {
+			if evictNone {
+				return []string{}, nil
+			}
+			// evict the newest sessions instead of the oldest ones
+			sessionHandles := []string{}
+			for i := 0; i < numberOfSessionsToEvict; i++ {
+				sessionHandles = append(sessionHandles, sessions[len(sessions)-1-i].SessionHandle)
+			}
+			return sessionHandles, nil
+		},
+	})
+
+	sessionHandles := createSessionsForSessionLimitTest(t, userContext, 3)
+
+	remaining, err := GetAllSessionHandlesForUser("userId", nil, userContext)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{sessionHandles[0], sessionHandles[2]}, remaining)
+
+	evictNone = true
+	_, err = CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.ErrorIs(t, err, errors.ErrSessionLimitReached)
+}
+
+func TestThatOnlyTheSessionsOfTheUserAreEvictedWhenTheSessionLimitIsReached(t *testing.T) {
+	var sessionHandlesToEvict []string
+	userContext := newSessionLimitTestInstance(t, &sessmodels.SessionLimitConfig{
+		MaxSessions: 2,
+		GetSessionsToEvict: func(sessions []sessmodels.SessionInformation, numberOfSessionsToEvict int, userContext supertokens.UserContext) ([]string, error) {
+			return sessionHandlesToEvict, nil
+		},
+	})
+
+	otherSession, err := CreateNewSessionWithoutRequestResponse("public", "otherUserId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	sessionHandles := createSessionsForSessionLimitTest(t, userContext, 2)
+
+	// the session of the other user does not count as an evicted session
+	sessionHandlesToEvict = []string{otherSession.GetHandle()}
+	_, err = CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.ErrorIs(t, err, errors.ErrSessionLimitReached)
+
+	sessionHandlesToEvict = []string{otherSession.GetHandle(), sessionHandles[1], sessionHandles[1]}
+	newSession, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	remaining, err := GetAllSessionHandlesForUser("userId", nil, userContext)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{sessionHandles[0], newSession.GetHandle()}, remaining)
+	remaining, err = GetAllSessionHandlesForUser("otherUserId", nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{otherSession.GetHandle()}, remaining)
+}
+
+func TestThatRepeatedSessionHandlesToEvictOnTheSessionLimitAreOnlyCountedOnce(t *testing.T) {
+	sessions := []sessmodels.SessionInformation{{SessionHandle: "handle1"}, {SessionHandle: "handle2"}}
+
+	assert.Equal(t, []string{"handle2"}, filterSessionHandlesToEvict(sessions, []string{"handle2", "handle2", "unknown"}))
+	assert.Equal(t, []string{"handle2", "handle1"}, filterSessionHandlesToEvict(sessions, []string{"handle2", "handle1", "handle2"}))
+}
+
+func TestThatTheSessionLimitConfigIsValidated(t *testing.T) {
+	_, err := normaliseSessionLimitConfig(&sessmodels.SessionLimitConfig{MaxSessions: 0})
+	assert.Error(t, err)
+	_, err = normaliseSessionLimitConfig(&sessmodels.SessionLimitConfig{MaxSessions: 1, Policy: "UNKNOWN"})
+	assert.Error(t, err)
+
+	sessionLimit, err := normaliseSessionLimitConfig(&sessmodels.SessionLimitConfig{MaxSessions: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, sessmodels.SessionLimitPolicyEvictOldest, sessionLimit.Policy)
+}
+
+func TestThatTheSessionLimitReachedMessageIsTranslatedForTheTenantOfTheSession(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		I18n: &supertokens.I18nConfig{
+			Translations: map[string]map[supertokens.MessageKey]string{
+				"fr": {supertokens.MessageKeySessionLimitReached: "Vous avez atteint le nombre maximum de sessions."},
+			},
+			GetLocaleForTenant: func(tenantId string, userContext supertokens.UserContext) (*string, error) {
+				if tenantId == "paris" {
+					locale := "fr"
+					return &locale, nil
+				}
+				return nil, nil
+			},
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(&sessmodels.TypeInput{SessionLimit: &sessmodels.SessionLimitConfig{MaxSessions: 1}}),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer instance.Close()
+	recipeInstance, err := getRecipeInstanceOrThrowError(supertokens.SetInstanceInUserContext(nil, instance))
+	assert.NoError(t, err)
+
+	for tenantId, message := range map[string]string{
+		"public": "You have reached the maximum number of sessions. Please sign out on another device first.",
+		"paris":  "Vous avez atteint le nombre maximum de sessions.",
+	} {
+		req := supertokens.SetInstanceInRequest(httptest.NewRequest(http.MethodPost, "/auth/signin", nil), instance)
+		res := httptest.NewRecorder()
+		handled, err := recipeInstance.handleError(errors.SessionLimitReachedError{
+			Msg:         "The user already has the maximum number of sessions (1)",
+			UserId:      "userId",
+			TenantId:    tenantId,
+			MaxSessions: 1,
+		}, req, res, nil)
+		assert.NoError(t, err)
+		assert.True(t, handled)
+
+		var result map[string]interface{}
+		assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &result))
+		assert.Equal(t, "GENERAL_ERROR", result["status"])
+		assert.Equal(t, message, result["message"])
+	}
+}
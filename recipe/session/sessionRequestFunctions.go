@@ -102,6 +102,13 @@ func CreateNewSessionInRequest(req *http.Request, res http.ResponseWriter, tenan
 
 	disableAntiCSRF := outputTokenTransferMethod == sessmodels.HeaderTransferMethod
 
+	if config.DeviceInfo != nil {
+		sessionDataInDatabase, err = addDeviceInfoToSessionData(req, config.DeviceInfo, sessionDataInDatabase, userContext)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	sessionResponse, err := (*recipeImpl.CreateNewSession)(userID, finalAccessTokenPayload, sessionDataInDatabase, &disableAntiCSRF, tenantId, userContext)
 
 	if err != nil {
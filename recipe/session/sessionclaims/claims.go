@@ -0,0 +1,29 @@
+package sessionclaims
+
+import (
+	"github.com/supertokens/supertokens-golang/recipe/session/claims"
+)
+
+type TypeRecentAuthClaimValidators struct {
+	// RecentAuthWithin checks that the user signed in or re-authenticated at most maxAgeInSeconds ago. If
+	// methods are given, the user must have authenticated with one of them (e.g. "emailpassword").
+	RecentAuthWithin func(maxAgeInSeconds int64, methods ...string) claims.SessionClaimValidator
+}
+
+// RecentAuthClaim holds the method with which the user last authenticated, and the time at which they did.
+// It is only added to sessions if RecordRecentAuth is set in the config of the session recipe.
+var RecentAuthClaim *claims.TypeSessionClaim
+
+var RecentAuthClaimValidators TypeRecentAuthClaimValidators
+
+type TypeImpersonationClaimValidators struct {
+	// IsNotImpersonated rejects sessions that an admin created on behalf of their user, for operations that
+	// only the user should be able to do (e.g. changing their password)
+	IsNotImpersonated func() claims.SessionClaimValidator
+}
+
+// ImpersonationClaim is the "act" claim of impersonation sessions. Its value is the user ID of the admin that
+// created the session.
+var ImpersonationClaim *claims.TypeSessionClaim
+
+var ImpersonationClaimValidators TypeImpersonationClaimValidators
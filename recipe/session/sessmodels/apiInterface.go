@@ -26,9 +26,59 @@ type APIInterface struct {
 	RefreshPOST   *func(options APIOptions, userContext supertokens.UserContext) (SessionContainer, error)
 	SignOutPOST   *func(sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (SignOutPOSTResponse, error)
 	VerifySession *func(verifySessionOptions *VerifySessionOptions, options APIOptions, userContext supertokens.UserContext) (SessionContainer, error)
+
+	// The active sessions APIs are only exposed if ExposeActiveSessionsAPIs is set in the config
+	ActiveSessionsGET       *func(sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (ActiveSessionsGETResponse, error)
+	RevokeSessionPOST       *func(sessionHandle string, sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (RevokeSessionPOSTResponse, error)
+	RevokeOtherSessionsPOST *func(sessionContainer SessionContainer, options APIOptions, userContext supertokens.UserContext) (RevokeOtherSessionsPOSTResponse, error)
+
+	// The impersonation API is only exposed if CanImpersonate is set in the Impersonation config
+	ImpersonatePOST *func(targetUserID string, tenantId string, adminSession SessionContainer, options APIOptions, userContext supertokens.UserContext) (ImpersonatePOSTResponse, error)
 }
 
 type SignOutPOSTResponse struct {
 	OK           *struct{}
 	GeneralError *supertokens.GeneralErrorResponse
 }
+
+// ActiveSession is a session of the user, as listed by the active sessions API
+type ActiveSession struct {
+	SessionHandle string `json:"sessionHandle"`
+	TenantId      string `json:"tenantId"`
+	TimeCreated   uint64 `json:"timeCreated"`
+	Expiry        uint64 `json:"expiry"`
+	// Current is true for the session that the request was made with
+	Current bool `json:"current"`
+	// DeviceInfo is nil if the device was not recorded when the session was created
+	DeviceInfo *DeviceInfo `json:"deviceInfo,omitempty"`
+}
+
+type ActiveSessionsGETResponse struct {
+	OK *struct {
+		Sessions []ActiveSession
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type RevokeSessionPOSTResponse struct {
+	OK *struct{}
+	// The session does not exist, or it is not a session of the user
+	SessionNotFoundError *struct{}
+	GeneralError         *supertokens.GeneralErrorResponse
+}
+
+type RevokeOtherSessionsPOSTResponse struct {
+	OK *struct {
+		RevokedSessionHandles []string
+	}
+	GeneralError *supertokens.GeneralErrorResponse
+}
+
+type ImpersonatePOSTResponse struct {
+	OK *struct {
+		Session SessionContainer
+	}
+	// The admin is not allowed to impersonate the target user
+	ImpersonationNotAllowedError *struct{}
+	GeneralError                 *supertokens.GeneralErrorResponse
+}
@@ -16,6 +16,7 @@
 package sessmodels
 
 import (
+	"net"
 	"net/http"
 	"time"
 
@@ -111,6 +112,27 @@ type TypeInput struct {
 	GetTokenTransferMethod                       func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) TokenTransferMethod
 	ExposeAccessTokenToFrontendInCookieBasedAuth bool
 	UseDynamicAccessTokenSigningKey              *bool
+	// ExposeActiveSessionsAPIs exposes the APIs with which users list their sessions, and revoke one
+	// or all others of them
+	ExposeActiveSessionsAPIs bool
+	// DeviceInfo records the device that sessions are created from in their session data in the
+	// database. Disabled if nil.
+	DeviceInfo *DeviceInfoConfig
+	// RevocationList rejects the access tokens of recently revoked sessions without querying the core.
+	// Disabled if nil.
+	RevocationList *RevocationListConfig
+	// SessionLifetime expires sessions that are idle or too old, in addition to the validity of the refresh
+	// token in the core. Disabled if nil.
+	SessionLifetime *SessionLifetimeConfig
+	// SessionLimit caps the number of sessions that a user can have at the same time. Disabled if nil.
+	SessionLimit *SessionLimitConfig
+	// RecordRecentAuth adds the RecentAuthClaim of the sessionclaims package to new sessions, which records
+	// when and how the user authenticated. Sensitive APIs can then require a recent authentication using
+	// its RecentAuthWithin validator.
+	RecordRecentAuth bool
+	// Impersonation allows admins to create sessions on behalf of other users (see CreateImpersonationSession).
+	// Disabled if nil.
+	Impersonation *ImpersonationConfig
 }
 
 type OverrideStruct struct {
@@ -125,6 +147,12 @@ type ErrorHandlers struct {
 	OnTokenTheftDetected           func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error
 	OnInvalidClaim                 func(validationErrors []claims.ClaimValidationError, req *http.Request, res http.ResponseWriter) error
 	OnClearDuplicateSessionCookies func(message string, req *http.Request, res http.ResponseWriter) error
+	// OnSessionExpired is called if the session expired because of the session lifetime policy, with the
+	// reason as one of the UnauthorisedReason constants of the errors package
+	OnSessionExpired func(reason string, req *http.Request, res http.ResponseWriter) error
+	// OnSessionLimitReached is called if a session could not be created because the user has the maximum
+	// number of sessions (see SessionLimit), with the tenant in which the session was created
+	OnSessionLimitReached func(message string, tenantId string, req *http.Request, res http.ResponseWriter) error
 }
 
 type TypeNormalisedInput struct {
@@ -141,6 +169,13 @@ type TypeNormalisedInput struct {
 	GetTokenTransferMethod                       func(req *http.Request, forCreateNewSession bool, userContext supertokens.UserContext) TokenTransferMethod
 	ExposeAccessTokenToFrontendInCookieBasedAuth bool
 	UseDynamicAccessTokenSigningKey              bool
+	ExposeActiveSessionsAPIs                     bool
+	DeviceInfo                                   *NormalisedDeviceInfoConfig
+	RevocationList                               *RevocationList
+	SessionLifetime                              *NormalisedSessionLifetimeConfig
+	SessionLimit                                 *SessionLimitConfig
+	RecordRecentAuth                             bool
+	Impersonation                                *NormalisedImpersonationConfig
 }
 
 type AntiCsrfFunctionOrString struct {
@@ -161,6 +196,139 @@ type VerifySessionOptions struct {
 	OverrideGlobalClaimValidators func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error)
 }
 
+// WebSocketOptions configures how a session is bound to a WebSocket connection
+type WebSocketOptions struct {
+	// Used to verify the session when the connection is upgraded, and when the client sends a new
+	// access token
+	VerifySessionOptions *VerifySessionOptions
+
+	// How often the core is asked whether the session still exists. Defaults to 1 minute. Disabled if
+	// it's zero or negative.
+	RevocationCheckInterval *time.Duration
+}
+
+// DeviceInfoConfig configures how the device is recorded when a session is created
+type DeviceInfoConfig struct {
+	// TrustedProxies are the IP addresses or CIDR ranges (e.g. "10.0.0.0/8") of the proxies in front of
+	// the backend. The IP address of the client is only taken from the X-Forwarded-For header of requests
+	// that come from one of them.
+	TrustedProxies []string
+	// GetGeolocation returns the approximate location of an IP address, for example using a GeoIP
+	// database. The location is not recorded if nil.
+	GetGeolocation func(ipAddress string, userContext supertokens.UserContext) (*Geolocation, error)
+}
+
+type NormalisedDeviceInfoConfig struct {
+	TrustedProxies []*net.IPNet
+	GetGeolocation func(ipAddress string, userContext supertokens.UserContext) (*Geolocation, error)
+}
+
+// DeviceInfo is the device that a session was created from. It is stored as "deviceInfo" in the session
+// data in the database.
+type DeviceInfo struct {
+	IPAddress string `json:"ipAddress,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
+	// DeviceType is "desktop", "mobile", "tablet" or "bot"
+	DeviceType  string       `json:"deviceType,omitempty"`
+	Browser     string       `json:"browser,omitempty"`
+	OS          string       `json:"os,omitempty"`
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+}
+
+type Geolocation struct {
+	Country string `json:"country,omitempty"`
+	Region  string `json:"region,omitempty"`
+	City    string `json:"city,omitempty"`
+}
+
+// SessionLifetimePolicy is when sessions expire. A zero duration disables a limit.
+type SessionLifetimePolicy struct {
+	// IdleTimeout expires sessions that have not been used for this long
+	IdleTimeout *time.Duration
+	// AbsoluteLifetime expires sessions this long after they were created, even if they are in use
+	AbsoluteLifetime *time.Duration
+}
+
+// SessionLifetimeConfig configures when sessions expire because of inactivity or their age. Sessions that
+// expire are revoked, and the request is answered using the OnSessionExpired error handler.
+type SessionLifetimeConfig struct {
+	// Policy is used for all tenants, unless GetPolicyForTenant returns another one
+	Policy SessionLifetimePolicy
+	// GetPolicyForTenant returns the policy of a tenant, or nil to use Policy. Limits that are nil in the
+	// returned policy are taken from Policy.
+	GetPolicyForTenant func(tenantId string, userContext supertokens.UserContext) (*SessionLifetimePolicy, error)
+	// ActivityUpdateInterval is how often the last activity of a session that is in use is recorded in its
+	// access token, which requires a query to the core. Defaults to 1 minute, or a tenth of the idle
+	// timeout if that is shorter.
+	ActivityUpdateInterval *time.Duration
+}
+
+type NormalisedSessionLifetimeConfig struct {
+	Policy                 SessionLifetimePolicy
+	GetPolicyForTenant     func(tenantId string, userContext supertokens.UserContext) (*SessionLifetimePolicy, error)
+	ActivityUpdateInterval *time.Duration
+}
+
+type SessionLimitPolicy string
+
+const (
+	// SessionLimitPolicyEvictOldest revokes the oldest sessions of the user to make room for the new one
+	SessionLimitPolicyEvictOldest SessionLimitPolicy = "EVICT_OLDEST"
+	// SessionLimitPolicyReject rejects the new session with a SessionLimitReachedError
+	SessionLimitPolicyReject SessionLimitPolicy = "REJECT"
+)
+
+// SessionLimitConfig configures how many sessions a user can have at the same time. The sessions of the user
+// are counted when a new session is created, so concurrent sign ins can exceed the limit.
+type SessionLimitConfig struct {
+	// MaxSessions is the maximum number of sessions of a user, including the new one
+	MaxSessions int
+	// AcrossAllTenants counts the sessions of the user in all tenants, instead of only the ones in the
+	// tenant of the new session
+	AcrossAllTenants bool
+	// Policy defaults to SessionLimitPolicyEvictOldest
+	Policy SessionLimitPolicy
+	// GetSessionsToEvict returns the handles of the sessions to revoke for SessionLimitPolicyEvictOldest.
+	// sessions are sorted from the oldest to the newest. Handles that are not of the given sessions and
+	// repeated handles are ignored, and if fewer handles than numberOfSessionsToEvict remain, the new
+	// session is rejected. Defaults to the oldest sessions.
+	GetSessionsToEvict func(sessions []SessionInformation, numberOfSessionsToEvict int, userContext supertokens.UserContext) ([]string, error)
+}
+
+// ImpersonationConfig configures the sessions that admins create on behalf of other users, for example to
+// reproduce an issue of a customer. The access tokens of these sessions contain an "act" claim with the user
+// ID of the admin (see RFC 8693).
+type ImpersonationConfig struct {
+	// CanImpersonate returns whether the user of the admin session can impersonate the target user. The
+	// impersonation API is only exposed if it is set.
+	CanImpersonate func(adminSession SessionContainer, targetUserID string, tenantId string, userContext supertokens.UserContext) (bool, error)
+	// SessionLifetime is the time after which impersonation sessions expire. Defaults to 1 hour.
+	SessionLifetime *time.Duration
+	// RestrictedClaimValidatorIDs are the IDs of the claim validators that always fail for impersonation
+	// sessions, for example to protect sensitive routes. The RecentAuthClaim validators never accept
+	// impersonation sessions, whether or not they are in the list.
+	RestrictedClaimValidatorIDs []string
+	// OnImpersonationSessionCreated is called for every impersonation session that is created, to record it
+	// in an audit trail
+	OnImpersonationSessionCreated func(event ImpersonationAuditEvent, userContext supertokens.UserContext) error
+}
+
+type NormalisedImpersonationConfig struct {
+	CanImpersonate                func(adminSession SessionContainer, targetUserID string, tenantId string, userContext supertokens.UserContext) (bool, error)
+	SessionLifetime               time.Duration
+	RestrictedClaimValidatorIDs   []string
+	OnImpersonationSessionCreated func(event ImpersonationAuditEvent, userContext supertokens.UserContext) error
+}
+
+type ImpersonationAuditEvent struct {
+	AdminUserID   string
+	TargetUserID  string
+	TenantId      string
+	SessionHandle string
+	TimeCreated   time.Time
+	Expiry        time.Time
+}
+
 type APIOptions struct {
 	RecipeImplementation RecipeInterface
 	Config               TypeNormalisedInput
@@ -178,6 +346,12 @@ type NormalisedErrorHandlers struct {
 	OnTokenTheftDetected           func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error
 	OnInvalidClaim                 func(validationErrors []claims.ClaimValidationError, req *http.Request, res http.ResponseWriter) error
 	OnClearDuplicateSessionCookies func(message string, req *http.Request, res http.ResponseWriter) error
+	// OnSessionExpired is called if the session expired because of the session lifetime policy, with the
+	// reason as one of the UnauthorisedReason constants of the errors package
+	OnSessionExpired func(reason string, req *http.Request, res http.ResponseWriter) error
+	// OnSessionLimitReached is called if a session could not be created because the user has the maximum
+	// number of sessions (see SessionLimit), with the tenant in which the session was created
+	OnSessionLimitReached func(message string, tenantId string, req *http.Request, res http.ResponseWriter) error
 }
 
 type SessionTokens struct {
@@ -226,6 +400,8 @@ type TypeSessionContainer struct {
 	GetClaimValueWithContext           func(claim *claims.TypeSessionClaim, userContext supertokens.UserContext) interface{}
 	RemoveClaimWithContext             func(claim *claims.TypeSessionClaim, userContext supertokens.UserContext) error
 	AttachToRequestResponseWithContext func(info RequestResponseInfo, userContext supertokens.UserContext) error
+	IsImpersonatedWithContext          func(userContext supertokens.UserContext) bool
+	GetImpersonatorUserIDWithContext   func(userContext supertokens.UserContext) *string
 
 	MergeIntoAccessTokenPayload func(accessTokenPayloadUpdate map[string]interface{}) error
 
@@ -235,6 +411,13 @@ type TypeSessionContainer struct {
 	GetClaimValue           func(claim *claims.TypeSessionClaim) interface{}
 	RemoveClaim             func(claim *claims.TypeSessionClaim) error
 	AttachToRequestResponse func(info RequestResponseInfo) error
+
+	// IsImpersonated returns whether the session was created by an admin on behalf of its user (see
+	// CreateImpersonationSession)
+	IsImpersonated func() bool
+	// GetImpersonatorUserID returns the user ID of the admin that created the session, or nil if it is not
+	// impersonated
+	GetImpersonatorUserID func() *string
 }
 
 type SessionContainer = *TypeSessionContainer
@@ -247,6 +430,8 @@ type SessionInformation struct {
 	CustomClaimsInAccessTokenPayload map[string]interface{}
 	TimeCreated                      uint64
 	TenantId                         string
+	// DeviceInfo is nil if the device was not recorded when the session was created
+	DeviceInfo *DeviceInfo
 }
 
 type ParsedJWTInfo struct {
@@ -0,0 +1,105 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package sessmodels
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationPubSub shares the handles of revoked sessions between the instances of the backend, for
+// example using redis pub/sub. Handles that an instance publishes may also be delivered back to it.
+type RevocationPubSub interface {
+	// Publish is called with the handles of the sessions that were revoked by this instance
+	Publish(sessionHandles []string) error
+	// Subscribe is called once when the session recipe is initialised. onRevoked must be called with the
+	// handles of the sessions that were revoked by any instance.
+	Subscribe(onRevoked func(sessionHandles []string)) error
+}
+
+// RevocationListConfig configures the list of recently revoked sessions, which is used to reject the
+// access tokens of revoked sessions without querying the core for every request
+type RevocationListConfig struct {
+	// PubSub shares revocations with the other instances of the backend. If nil, only the sessions
+	// revoked by this instance are rejected.
+	PubSub RevocationPubSub
+	// Retention is how long a revoked session is kept in the list. It must be at least the access token
+	// validity configured in the core. Defaults to 1 hour, which is the default validity.
+	Retention *time.Duration
+}
+
+// RevocationList is the list of recently revoked sessions of a session recipe instance
+type RevocationList struct {
+	lock      sync.Mutex
+	revokedAt map[string]time.Time
+	retention time.Duration
+	pubSub    RevocationPubSub
+}
+
+func NewRevocationList(config RevocationListConfig) *RevocationList {
+	retention := time.Hour
+	if config.Retention != nil {
+		retention = *config.Retention
+	}
+	return &RevocationList{
+		revokedAt: map[string]time.Time{},
+		retention: retention,
+		pubSub:    config.PubSub,
+	}
+}
+
+// Add adds the handles of sessions that were revoked by this instance to the list, and publishes them
+func (l *RevocationList) Add(sessionHandles []string) error {
+	if len(sessionHandles) == 0 {
+		return nil
+	}
+	l.AddFromPubSub(sessionHandles)
+	if l.pubSub == nil {
+		return nil
+	}
+	return l.pubSub.Publish(sessionHandles)
+}
+
+// AddFromPubSub adds the handles of sessions that were revoked by any instance to the list
+func (l *RevocationList) AddFromPubSub(sessionHandles []string) {
+	now := time.Now()
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	// revocations are rare compared to requests, so expired entries are only removed here
+	for sessionHandle, revokedAt := range l.revokedAt {
+		if now.Sub(revokedAt) > l.retention {
+			delete(l.revokedAt, sessionHandle)
+		}
+	}
+	for _, sessionHandle := range sessionHandles {
+		l.revokedAt[sessionHandle] = now
+	}
+}
+
+func (l *RevocationList) IsRevoked(sessionHandle string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	revokedAt, ok := l.revokedAt[sessionHandle]
+	return ok && time.Since(revokedAt) <= l.retention
+}
+
+// Subscribe subscribes to the revocations of the other instances, if there is a pub/sub
+func (l *RevocationList) Subscribe() error {
+	if l.pubSub == nil {
+		return nil
+	}
+	return l.pubSub.Subscribe(l.AddFromPubSub)
+}
@@ -18,6 +18,7 @@ package session
 import (
 	"net/http"
 
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 	"github.com/supertokens/supertokens-golang/test/unittesting"
 )
@@ -33,6 +34,7 @@ func resetAll() {
 	returnedFromCache = make(chan bool, 1000)
 	urlsAttemptedForJWKSFetch = []string{}
 	jwksCache = nil
+	jwksCacheForOtherCores = map[string]*sessmodels.GetJWKSResult{}
 }
 
 func BeforeEach() {
@@ -0,0 +1,29 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+// newSessionTestUserContext creates an instance with only the session recipe and returns a user context
+// that uses it. If core is nil, a new core is started for the instance.
+func newSessionTestUserContext(t *testing.T, core *coreemulator.Core, config *sessmodels.TypeInput) supertokens.UserContext {
+	var instance *supertokens.Instance
+	if core == nil {
+		instance = coreemulator.NewTestInstance(t, Init(config))
+	} else {
+		instance = coreemulator.NewTestInstanceForCore(t, core, Init(config))
+	}
+	return supertokens.SetInstanceInUserContext(nil, instance)
+}
+
+func getInstanceForTest(t *testing.T, userContext supertokens.UserContext) *supertokens.Instance {
+	instance, err := supertokens.GetInstanceOrThrowError(userContext)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return instance
+}
@@ -0,0 +1,57 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestThatTracingCreatesSpansForRecipeFunctions(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	exporter := tracetest.NewInMemoryExporter()
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			Init(nil),
+		},
+		Tracing: &supertokens.TracingConfig{
+			TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer supertokens.ResetForTest()
+	defer instance.Close()
+	userContext := supertokens.SetInstanceInUserContext(nil, instance)
+
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "user", nil, nil, nil, userContext)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	exporter.Reset()
+
+	info, err := GetSessionInformation(sessionContainer.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "user", info.UserId)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	coreSpan := spans[0]
+	recipeSpan := spans[1]
+	assert.Equal(t, "supertokens.core GET /recipe/session", coreSpan.Name)
+	assert.Equal(t, "session.GetSessionInformation", recipeSpan.Name)
+	assert.Equal(t, recipeSpan.SpanContext.SpanID(), coreSpan.Parent.SpanID())
+}
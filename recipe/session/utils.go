@@ -1,6 +1,7 @@
 package session
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -26,7 +27,7 @@ func GetRequiredClaimValidators(
 	overrideGlobalClaimValidators func(globalClaimValidators []claims.SessionClaimValidator, sessionContainer sessmodels.SessionContainer, userContext supertokens.UserContext) ([]claims.SessionClaimValidator, error),
 	userContext supertokens.UserContext,
 ) ([]claims.SessionClaimValidator, error) {
-	instance, err := getRecipeInstanceOrThrowError()
+	instance, err := getRecipeInstanceOrThrowError(userContext)
 	if err != nil {
 		return nil, err
 	}
@@ -153,36 +154,46 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 
 	errorHandlers := sessmodels.NormalisedErrorHandlers{
 		OnTokenTheftDetected: func(sessionHandle string, userID string, req *http.Request, res http.ResponseWriter) error {
-			recipeInstance, err := getRecipeInstanceOrThrowError()
+			recipeInstance, err := getRecipeInstanceOrThrowError(supertokens.MakeDefaultUserContextFromAPI(req))
 			if err != nil {
 				return err
 			}
 			return sendTokenTheftDetectedResponse(*recipeInstance, sessionHandle, userID, req, res)
 		},
 		OnTryRefreshToken: func(message string, req *http.Request, res http.ResponseWriter) error {
-			recipeInstance, err := getRecipeInstanceOrThrowError()
+			recipeInstance, err := getRecipeInstanceOrThrowError(supertokens.MakeDefaultUserContextFromAPI(req))
 			if err != nil {
 				return err
 			}
 			return sendTryRefreshTokenResponse(*recipeInstance, message, req, res)
 		},
 		OnUnauthorised: func(message string, req *http.Request, res http.ResponseWriter) error {
-			recipeInstance, err := getRecipeInstanceOrThrowError()
+			recipeInstance, err := getRecipeInstanceOrThrowError(supertokens.MakeDefaultUserContextFromAPI(req))
 			if err != nil {
 				return err
 			}
 			return sendUnauthorisedResponse(*recipeInstance, message, req, res)
 		},
 		OnInvalidClaim: func(validationErrors []claims.ClaimValidationError, req *http.Request, res http.ResponseWriter) error {
-			recipeInstance, err := getRecipeInstanceOrThrowError()
+			recipeInstance, err := getRecipeInstanceOrThrowError(supertokens.MakeDefaultUserContextFromAPI(req))
 			if err != nil {
 				return err
 			}
-			return sendInvalidClaimResponse(*recipeInstance, validationErrors, req, res)
+			return sendInvalidClaimResponse(*recipeInstance, validationErrors, getTenantIdFromRequest(req), req, res)
 		},
 		OnClearDuplicateSessionCookies: func(message string, req *http.Request, res http.ResponseWriter) error {
 			return supertokens.Send200Response(res, message)
 		},
+		OnSessionExpired: func(reason string, req *http.Request, res http.ResponseWriter) error {
+			recipeInstance, err := getRecipeInstanceOrThrowError(supertokens.MakeDefaultUserContextFromAPI(req))
+			if err != nil {
+				return err
+			}
+			return sendSessionExpiredResponse(*recipeInstance, reason, req, res)
+		},
+		OnSessionLimitReached: func(message string, tenantId string, req *http.Request, res http.ResponseWriter) error {
+			return sendSessionLimitReachedResponse(tenantId, req, res)
+		},
 	}
 
 	if config != nil && config.ErrorHandlers != nil {
@@ -201,6 +212,12 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		if config.ErrorHandlers.OnClearDuplicateSessionCookies != nil {
 			errorHandlers.OnClearDuplicateSessionCookies = config.ErrorHandlers.OnClearDuplicateSessionCookies
 		}
+		if config.ErrorHandlers.OnSessionExpired != nil {
+			errorHandlers.OnSessionExpired = config.ErrorHandlers.OnSessionExpired
+		}
+		if config.ErrorHandlers.OnSessionLimitReached != nil {
+			errorHandlers.OnSessionLimitReached = config.ErrorHandlers.OnSessionLimitReached
+		}
 	}
 
 	refreshAPIPath, err := supertokens.NewNormalisedURLPath(RefreshAPIPath)
@@ -222,6 +239,26 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		useDynamicSigningKey = *config.UseDynamicAccessTokenSigningKey
 	}
 
+	deviceInfo, err := normaliseDeviceInfoConfig(config.DeviceInfo)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	sessionLimit, err := normaliseSessionLimitConfig(config.SessionLimit)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	impersonation, err := normaliseImpersonationConfig(config.Impersonation)
+	if err != nil {
+		return sessmodels.TypeNormalisedInput{}, err
+	}
+
+	var revocationList *sessmodels.RevocationList
+	if config.RevocationList != nil {
+		revocationList = sessmodels.NewRevocationList(*config.RevocationList)
+	}
+
 	typeNormalisedInput := sessmodels.TypeNormalisedInput{
 		RefreshTokenPath:         appInfo.APIBasePath.AppendPath(refreshAPIPath),
 		CookieDomain:             cookieDomain,
@@ -233,6 +270,13 @@ func ValidateAndNormaliseUserInput(appInfo supertokens.NormalisedAppinfo, config
 		AntiCsrfFunctionOrString: antiCsrfFunctionOrString,
 		ExposeAccessTokenToFrontendInCookieBasedAuth: config.ExposeAccessTokenToFrontendInCookieBasedAuth,
 		UseDynamicAccessTokenSigningKey:              useDynamicSigningKey,
+		ExposeActiveSessionsAPIs:                     config.ExposeActiveSessionsAPIs,
+		DeviceInfo:                                   deviceInfo,
+		RevocationList:                               revocationList,
+		SessionLifetime:                              normaliseSessionLifetimeConfig(config.SessionLifetime),
+		SessionLimit:                                 sessionLimit,
+		RecordRecentAuth:                             config.RecordRecentAuth,
+		Impersonation:                                impersonation,
 		ErrorHandlers:                                errorHandlers,
 		GetTokenTransferMethod:                       config.GetTokenTransferMethod,
 		Override: sessmodels.OverrideStruct{
@@ -349,13 +393,83 @@ func sendUnauthorisedResponse(recipeInstance Recipe, _ string, _ *http.Request,
 	return supertokens.SendNon200ResponseWithMessage(response, "unauthorised", recipeInstance.Config.SessionExpiredStatusCode)
 }
 
-func sendInvalidClaimResponse(recipeInstance Recipe, claimValidationErrors []claims.ClaimValidationError, _ *http.Request, response http.ResponseWriter) error {
+func sendSessionExpiredResponse(recipeInstance Recipe, reason string, _ *http.Request, response http.ResponseWriter) error {
+	return supertokens.SendNon200Response(response, recipeInstance.Config.SessionExpiredStatusCode, map[string]interface{}{
+		"message": "session expired",
+		"reason":  reason,
+	})
+}
+
+// sendSessionLimitReachedResponse answers the sign in request like a GENERAL_ERROR response, so that the
+// message is shown by the frontend. The message is translated for the tenant in which the session was created.
+func sendSessionLimitReachedResponse(tenantId string, request *http.Request, response http.ResponseWriter) error {
+	userContext := supertokens.MakeDefaultUserContextFromAPI(request)
+	return supertokens.Send200Response(response, map[string]interface{}{
+		"status":     "GENERAL_ERROR",
+		"message":    supertokens.Translate(supertokens.MessageKeySessionLimitReached, tenantId, userContext),
+		"messageKey": supertokens.MessageKeySessionLimitReached,
+	})
+}
+
+type tenantIdContextKey struct{}
+
+// setTenantIdInRequest returns a copy of the request with the tenant of the session in its context, so that the
+// default error handlers can translate their messages for it without changing the signature of the handlers
+func setTenantIdInRequest(req *http.Request, tenantId string) *http.Request {
+	if tenantId == "" {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), tenantIdContextKey{}, tenantId))
+}
+
+// getTenantIdFromRequest returns the tenant set using setTenantIdInRequest, or the default tenant
+func getTenantIdFromRequest(req *http.Request) string {
+	if tenantId, ok := req.Context().Value(tenantIdContextKey{}).(string); ok {
+		return tenantId
+	}
+	return supertokens.DefaultTenantId
+}
+
+// sendInvalidClaimResponse sends the claim validation errors of a session, with the messages translated for
+// the tenant of the session
+func sendInvalidClaimResponse(recipeInstance Recipe, claimValidationErrors []claims.ClaimValidationError, tenantId string, request *http.Request, response http.ResponseWriter) error {
+	userContext := supertokens.MakeDefaultUserContextFromAPI(request)
 	return supertokens.SendNon200Response(response, recipeInstance.Config.InvalidClaimStatusCode, map[string]interface{}{
-		"message":               "invalid claim",
-		"claimValidationErrors": claimValidationErrors,
+		"message":               supertokens.Translate(supertokens.MessageKeyInvalidClaim, tenantId, userContext),
+		"messageKey":            supertokens.MessageKeyInvalidClaim,
+		"claimValidationErrors": localiseClaimValidationErrors(claimValidationErrors, tenantId, userContext),
 	})
 }
 
+// localiseClaimValidationErrors translates the messages in the reasons of the claim validation errors using
+// their MessageKey, and adds the key as messageKey. The reasons are left as they are if translations are not
+// configured, and for errors without a key.
+func localiseClaimValidationErrors(claimValidationErrors []claims.ClaimValidationError, tenantId string, userContext supertokens.UserContext) []claims.ClaimValidationError {
+	if !supertokens.IsI18nEnabled(userContext) {
+		return claimValidationErrors
+	}
+	result := make([]claims.ClaimValidationError, len(claimValidationErrors))
+	for i, claimValidationError := range claimValidationErrors {
+		result[i] = claimValidationError
+		reason, ok := claimValidationError.Reason.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if claimValidationError.MessageKey == "" {
+			continue
+		}
+		localisedReason := map[string]interface{}{}
+		for k, v := range reason {
+			localisedReason[k] = v
+		}
+		message, _ := reason["message"].(string)
+		localisedReason["message"] = supertokens.TranslateWithFallback(claimValidationError.MessageKey, message, tenantId, userContext)
+		localisedReason["messageKey"] = claimValidationError.MessageKey
+		result[i].Reason = localisedReason
+	}
+	return result
+}
+
 func sendTokenTheftDetectedResponse(recipeInstance Recipe, sessionHandle string, _ string, _ *http.Request, response http.ResponseWriter) error {
 	_, err := (*recipeInstance.RecipeImpl.RevokeSession)(sessionHandle, &map[string]interface{}{})
 	if err != nil {
@@ -371,9 +485,13 @@ func ValidateClaimsInPayload(claimValidators []claims.SessionClaimValidator, new
 		claimValidationResult := validator.Validate(newAccessTokenPayload, userContext)
 		supertokens.LogDebugMessage(fmt.Sprint("validateClaimsInPayload ", validator.ID, " validation res ", claimValidationResult))
 		if !claimValidationResult.IsValid {
+			supertokens.IncrementCounterMetric(supertokens.MetricClaimValidationFailures, map[string]string{
+				"claim": validator.ID,
+			})
 			validationErrors = append(validationErrors, claims.ClaimValidationError{
-				ID:     validator.ID,
-				Reason: claimValidationResult.Reason,
+				ID:         validator.ID,
+				Reason:     claimValidationResult.Reason,
+				MessageKey: claimValidationResult.MessageKey,
 			})
 		}
 	}
@@ -0,0 +1,251 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package session
+
+import (
+	defaultErrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/supertokens/supertokens-golang/recipe/session/errors"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// The close codes that a WebSocket connection is closed with by a WebSocketSession. They are in the
+// range that is reserved for applications.
+const (
+	// The access token (or the session) expired without a new access token being sent
+	WebSocketCloseSessionExpired = 4001
+	// The session was revoked
+	WebSocketCloseSessionRevoked = 4002
+)
+
+const defaultWebSocketRevocationCheckInterval = time.Minute
+
+// WebSocketCloser closes a WebSocket connection with a close code and reason. It should be implemented
+// using the WebSocket library in use, for example by sending a close message and closing the connection.
+type WebSocketCloser func(code int, reason string) error
+
+// WebSocketSession is a session that is bound to a WebSocket connection. The connection is closed
+// (using the WebSocketCloser given to Bind) when the access token expires without the client sending a
+// new one (see UpdateAccessToken), when the session expires, or when the session is revoked.
+type WebSocketSession struct {
+	lock                    sync.Mutex
+	sessionContainer        sessmodels.SessionContainer
+	verifySessionOptions    sessmodels.VerifySessionOptions
+	revocationCheckInterval time.Duration
+	userContext             supertokens.UserContext
+	accessTokenExpiry       time.Time
+	sessionExpiry           time.Time
+	closer                  WebSocketCloser
+	expiryTimer             *time.Timer
+	done                    chan struct{}
+	closed                  bool
+}
+
+// VerifyWebSocketUpgrade verifies the session of a request that will be upgraded to a WebSocket
+// connection. It must be called before the upgrade, and the returned session must then be bound to the
+// connection using Bind. Like GetSession, it returns nil if there is no session and SessionRequired is
+// false in the options.
+func VerifyWebSocketUpgrade(req *http.Request, res http.ResponseWriter, options *sessmodels.WebSocketOptions, userContext ...supertokens.UserContext) (*WebSocketSession, error) {
+	if len(userContext) == 0 {
+		userContext = append(userContext, supertokens.MakeDefaultUserContextFromAPI(req))
+	}
+	if options == nil {
+		options = &sessmodels.WebSocketOptions{}
+	}
+	sessionContainer, err := GetSession(req, res, options.VerifySessionOptions, userContext...)
+	if err != nil || sessionContainer == nil {
+		return nil, err
+	}
+
+	// the user context of the request can't be used once the request is done
+	instance, err := supertokens.GetInstanceOrThrowError(userContext...)
+	if err != nil {
+		return nil, err
+	}
+	webSocketSession := &WebSocketSession{
+		sessionContainer:        sessionContainer,
+		revocationCheckInterval: defaultWebSocketRevocationCheckInterval,
+		userContext:             supertokens.SetInstanceInUserContext(nil, instance),
+		accessTokenExpiry:       getAccessTokenExpiry(sessionContainer),
+		done:                    make(chan struct{}),
+	}
+	if options.VerifySessionOptions != nil {
+		webSocketSession.verifySessionOptions = *options.VerifySessionOptions
+	}
+	// the access token is sent over the connection, and not as a cookie
+	False := false
+	True := true
+	webSocketSession.verifySessionOptions.AntiCsrfCheck = &False
+	webSocketSession.verifySessionOptions.SessionRequired = &True
+	if options.RevocationCheckInterval != nil {
+		webSocketSession.revocationCheckInterval = *options.RevocationCheckInterval
+	}
+
+	sessionExpiry, err := sessionContainer.GetExpiryWithContext(webSocketSession.userContext)
+	if err != nil {
+		return nil, err
+	}
+	webSocketSession.sessionExpiry = time.UnixMilli(int64(sessionExpiry))
+
+	return webSocketSession, nil
+}
+
+// Bind binds the session to the WebSocket connection. closer is called (at most once) to close the
+// connection when the session expires or is revoked.
+func (s *WebSocketSession) Bind(closer WebSocketCloser) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed || s.closer != nil {
+		return
+	}
+	s.closer = closer
+	s.expiryTimer = time.AfterFunc(s.timeUntilExpiry(), s.closeIfExpired)
+	if s.revocationCheckInterval > 0 {
+		go s.checkRevocation()
+	}
+}
+
+// GetSession returns the session, which is the one of the last access token sent by the client.
+func (s *WebSocketSession) GetSession() sessmodels.SessionContainer {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sessionContainer
+}
+
+// UpdateAccessToken verifies an access token that the client sent over the connection after refreshing
+// the session, and uses its expiry from then on. The access token must belong to the same session. If
+// an error is returned, the connection stays open until the previous access token expires. The first
+// use of a refreshed access token creates a new one, which should be sent back to the client (see
+// GetSession).
+func (s *WebSocketSession) UpdateAccessToken(accessToken string) error {
+	options := s.verifySessionOptions
+	sessionContainer, err := GetSessionWithoutRequestResponse(accessToken, nil, &options, s.userContext)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if sessionContainer.GetHandleWithContext(s.userContext) != s.sessionContainer.GetHandleWithContext(s.userContext) {
+		return errors.UnauthorizedError{Msg: "the access token belongs to a different session"}
+	}
+	s.sessionContainer = sessionContainer
+	s.accessTokenExpiry = getAccessTokenExpiry(sessionContainer)
+	if s.expiryTimer != nil {
+		s.expiryTimer.Reset(s.timeUntilExpiry())
+	}
+	return nil
+}
+
+// Done returns a channel that is closed once the session is no longer bound to the connection.
+func (s *WebSocketSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unbinds the session from the connection without closing the connection. It should be called
+// when the connection is closed for other reasons.
+func (s *WebSocketSession) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.unbind()
+}
+
+func (s *WebSocketSession) unbind() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.expiryTimer != nil {
+		s.expiryTimer.Stop()
+	}
+	close(s.done)
+}
+
+func (s *WebSocketSession) closeConnection(code int, reason string) {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.unbind()
+	closer := s.closer
+	s.lock.Unlock()
+
+	supertokens.LogDebug(s.userContext, "closing WebSocket connection", "code", code, "reason", reason)
+	if err := closer(code, reason); err != nil {
+		supertokens.LogWarn(s.userContext, "closing WebSocket connection failed", "error", err.Error())
+	}
+}
+
+// timeUntilExpiry must be called with the lock held
+func (s *WebSocketSession) timeUntilExpiry() time.Duration {
+	expiry := s.accessTokenExpiry
+	if expiry.IsZero() || s.sessionExpiry.Before(expiry) {
+		expiry = s.sessionExpiry
+	}
+	return time.Until(expiry)
+}
+
+func (s *WebSocketSession) closeIfExpired() {
+	s.lock.Lock()
+	// the timer may have fired just before a new access token was sent
+	expired := s.timeUntilExpiry() <= 0
+	s.lock.Unlock()
+	if expired {
+		s.closeConnection(WebSocketCloseSessionExpired, "session expired")
+	}
+}
+
+func (s *WebSocketSession) checkRevocation() {
+	ticker := time.NewTicker(s.revocationCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+		sessionExpiry, err := s.GetSession().GetExpiryWithContext(s.userContext)
+		if err != nil {
+			if defaultErrors.Is(err, errors.ErrUnauthorised) {
+				s.closeConnection(WebSocketCloseSessionRevoked, "session revoked")
+				return
+			}
+			supertokens.LogWarn(s.userContext, "checking whether the session of a WebSocket connection was revoked failed", "error", err.Error())
+			continue
+		}
+		s.lock.Lock()
+		s.sessionExpiry = time.UnixMilli(int64(sessionExpiry))
+		if !s.closed {
+			s.expiryTimer.Reset(s.timeUntilExpiry())
+		}
+		s.lock.Unlock()
+	}
+}
+
+// getAccessTokenExpiry returns the expiry of the access token of the session from its exp claim, or the
+// zero time if it has none
+func getAccessTokenExpiry(sessionContainer sessmodels.SessionContainer) time.Time {
+	exp, ok := sessionContainer.GetAccessTokenPayload()["exp"].(float64)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(int64(exp), 0)
+}
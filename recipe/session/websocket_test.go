@@ -0,0 +1,109 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/session/sessmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newWebSocketTestInstance(t *testing.T, config *coreemulator.Config) (*supertokens.Instance, supertokens.UserContext) {
+	core := coreemulator.Start(config)
+	t.Cleanup(core.Close)
+	userContext := newSessionTestUserContext(t, core, nil)
+	return getInstanceForTest(t, userContext), userContext
+}
+
+type closeResult struct {
+	code   int
+	reason string
+}
+
+func bindWebSocketSession(t *testing.T, instance *supertokens.Instance, accessToken string, options *sessmodels.WebSocketOptions) (*WebSocketSession, chan closeResult) {
+	req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req = supertokens.SetInstanceInRequest(req, instance)
+	webSocketSession, err := VerifyWebSocketUpgrade(req, httptest.NewRecorder(), options)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	closed := make(chan closeResult, 1)
+	webSocketSession.Bind(func(code int, reason string) error {
+		closed <- closeResult{code: code, reason: reason}
+		return nil
+	})
+	t.Cleanup(webSocketSession.Close)
+	return webSocketSession, closed
+}
+
+func TestThatWebSocketConnectionsAreClosedWhenTheAccessTokenExpires(t *testing.T) {
+	instance, userContext := newWebSocketTestInstance(t, &coreemulator.Config{AccessTokenValidity: time.Second})
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	webSocketSession, closed := bindWebSocketSession(t, instance, sessionContainer.GetAccessToken(), nil)
+	assert.Equal(t, "userId", webSocketSession.GetSession().GetUserID())
+
+	select {
+	case result := <-closed:
+		assert.Equal(t, WebSocketCloseSessionExpired, result.code)
+	case <-time.After(3 * time.Second):
+		t.Fatal("the connection was not closed")
+	}
+	<-webSocketSession.Done()
+}
+
+func TestThatWebSocketConnectionsAreClosedWhenTheSessionIsRevoked(t *testing.T) {
+	instance, userContext := newWebSocketTestInstance(t, nil)
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+
+	interval := 10 * time.Millisecond
+	_, closed := bindWebSocketSession(t, instance, sessionContainer.GetAccessToken(), &sessmodels.WebSocketOptions{
+		RevocationCheckInterval: &interval,
+	})
+
+	_, err = RevokeSession(sessionContainer.GetHandle(), userContext)
+	assert.NoError(t, err)
+
+	select {
+	case result := <-closed:
+		assert.Equal(t, WebSocketCloseSessionRevoked, result.code)
+	case <-time.After(time.Second):
+		t.Fatal("the connection was not closed")
+	}
+}
+
+func TestThatWebSocketSessionsAcceptRefreshedAccessTokens(t *testing.T) {
+	instance, userContext := newWebSocketTestInstance(t, nil)
+	sessionContainer, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	webSocketSession, closed := bindWebSocketSession(t, instance, sessionContainer.GetAccessToken(), nil)
+
+	refreshToken := sessionContainer.GetAllSessionTokensDangerously().RefreshToken
+	refreshedSession, err := RefreshSessionWithoutRequestResponse(*refreshToken, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.NoError(t, webSocketSession.UpdateAccessToken(refreshedSession.GetAccessToken()))
+	// the first use of a refreshed access token gives a new one
+	updatedAccessToken := webSocketSession.GetSession().GetAccessToken()
+	assert.NotEqual(t, sessionContainer.GetAccessToken(), updatedAccessToken)
+	assert.Equal(t, sessionContainer.GetHandle(), webSocketSession.GetSession().GetHandle())
+
+	// access tokens of other sessions are rejected
+	otherSession, err := CreateNewSessionWithoutRequestResponse("public", "userId", nil, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.Error(t, webSocketSession.UpdateAccessToken(otherSession.GetAccessToken()))
+	assert.Error(t, webSocketSession.UpdateAccessToken("invalid"))
+	assert.Equal(t, updatedAccessToken, webSocketSession.GetSession().GetAccessToken())
+
+	select {
+	case <-closed:
+		t.Fatal("the connection was closed")
+	default:
+	}
+}
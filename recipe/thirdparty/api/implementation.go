@@ -80,7 +80,7 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 		}
 
 		if emailInfo.IsVerified {
-			evInstance := emailverification.GetRecipeInstance()
+			evInstance := emailverification.GetRecipeInstance(userContext)
 			if evInstance != nil {
 				tokenResponse, err := (*evInstance.RecipeImpl.CreateEmailVerificationToken)(response.OK.User.ID, response.OK.User.Email, tenantId, userContext)
 				if err != nil {
@@ -95,6 +95,7 @@ func MakeAPIImplementation() tpmodels.APIInterface {
 			}
 		}
 
+		userContext = session.SetAuthenticationMethodInUserContext(userContext, options.RecipeID)
 		session, err := session.CreateNewSession(options.Req, options.Res, tenantId, response.OK.User.ID, nil, nil, userContext)
 		if err != nil {
 			return tpmodels.SignInUpPOSTResponse{}, err
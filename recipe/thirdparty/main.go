@@ -25,7 +25,7 @@ func Init(config *tpmodels.TypeInput) supertokens.Recipe {
 }
 
 func ManuallyCreateOrUpdateUser(tenantId string, thirdPartyID string, thirdPartyUserID string, email string, userContext ...supertokens.UserContext) (tpmodels.ManuallyCreateOrUpdateUserResponse, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return tpmodels.ManuallyCreateOrUpdateUserResponse{}, err
 	}
@@ -36,7 +36,7 @@ func ManuallyCreateOrUpdateUser(tenantId string, thirdPartyID string, thirdParty
 }
 
 func GetUserByID(userID string, userContext ...supertokens.UserContext) (*tpmodels.User, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +47,7 @@ func GetUserByID(userID string, userContext ...supertokens.UserContext) (*tpmode
 }
 
 func GetUsersByEmail(tenantId string, email string, userContext ...supertokens.UserContext) ([]tpmodels.User, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return []tpmodels.User{}, err
 	}
@@ -58,7 +58,7 @@ func GetUsersByEmail(tenantId string, email string, userContext ...supertokens.U
 }
 
 func GetUserByThirdPartyInfo(tenantId string, thirdPartyID, thirdPartyUserID string, userContext ...supertokens.UserContext) (*tpmodels.User, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +69,7 @@ func GetUserByThirdPartyInfo(tenantId string, thirdPartyID, thirdPartyUserID str
 }
 
 func GetProvider(tenantId string, thirdPartyID string, clientType *string, userContext ...supertokens.UserContext) (*tpmodels.TypeProvider, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,112 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package thirdparty
+
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+func signInUpAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Signs in or signs up a user with a third party provider, and creates a session",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type:        "object",
+			Description: "One of redirectURIInfo or oAuthTokens is required",
+			Required:    []string{"thirdPartyId"},
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"thirdPartyId": {Type: "string"},
+				"clientType":   {Type: "string"},
+				"redirectURIInfo": {
+					Type:     "object",
+					Required: []string{"redirectURIOnProviderDashboard"},
+					Properties: map[string]*supertokens.OpenAPISchema{
+						"redirectURIOnProviderDashboard": {Type: "string"},
+						"redirectURIQueryParams":         {Type: "object"},
+						"pkceCodeVerifier":               {Type: "string"},
+					},
+				},
+				"oAuthTokens": {Type: "object"},
+			},
+		},
+		Statuses: []string{"OK", "NO_EMAIL_GIVEN_BY_PROVIDER"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"createdNewUser": {Type: "boolean"},
+				"user": {
+					Type: "object",
+					Properties: map[string]*supertokens.OpenAPISchema{
+						"id":         {Type: "string"},
+						"email":      {Type: "string"},
+						"timeJoined": {Type: "integer"},
+						"thirdParty": {
+							Type: "object",
+							Properties: map[string]*supertokens.OpenAPISchema{
+								"id":     {Type: "string"},
+								"userId": {Type: "string"},
+							},
+						},
+						"tenantIds": {Type: "array", Items: &supertokens.OpenAPISchema{Type: "string"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func authorisationUrlAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Returns the URL of the third party provider that the user should be redirected to",
+		QueryParameters: []supertokens.OpenAPIParameter{{
+			Name:     "thirdPartyId",
+			In:       "query",
+			Required: true,
+			Schema:   &supertokens.OpenAPISchema{Type: "string"},
+		}, {
+			Name:     "redirectURIOnProviderDashboard",
+			In:       "query",
+			Required: true,
+			Schema:   &supertokens.OpenAPISchema{Type: "string"},
+		}, {
+			Name:   "clientType",
+			In:     "query",
+			Schema: &supertokens.OpenAPISchema{Type: "string"},
+		}},
+		Statuses: []string{"OK"},
+		Response: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"urlWithQueryParams": {Type: "string"},
+				"pkceCodeVerifier":   {Type: "string"},
+			},
+		},
+	}
+}
+
+func appleRedirectHandlerAPISpec() *supertokens.APISpec {
+	return &supertokens.APISpec{
+		Summary: "Receives the form post from Apple, and redirects to the website",
+		RequestBody: &supertokens.OpenAPISchema{
+			Type: "object",
+			Properties: map[string]*supertokens.OpenAPISchema{
+				"code":  {Type: "string"},
+				"state": {Type: "string"},
+			},
+		},
+		RequestBodyContentType: "application/x-www-form-urlencoded",
+		OtherResponses: map[string]string{
+			"303": "Redirects to the website with the code and state",
+		},
+	}
+}
@@ -39,7 +39,7 @@ import (
 
 // Network utils
 func doGetRequest(url string, queryParams map[string]interface{}, headers map[string]string) (interface{}, error) {
-	supertokens.LogDebugMessage(fmt.Sprintf("GET request to %s, with query params %v and headers %v", url, queryParams, headers))
+	supertokens.LogDebug(nil, "GET request to provider", "url", url, "queryParams", queryParams, "headers", headers)
 
 	if queryParams != nil {
 		urlObj, err := urllib.Parse(url)
@@ -77,7 +77,7 @@ func doGetRequest(url string, queryParams map[string]interface{}, headers map[st
 		return nil, err
 	}
 
-	supertokens.LogDebugMessage(fmt.Sprintf("Received response with status %d and body %s", resp.StatusCode, string(body)))
+	logProviderResponse(resp.StatusCode, body)
 
 	var result interface{}
 	err = json.Unmarshal(body, &result)
@@ -90,8 +90,17 @@ func doGetRequest(url string, queryParams map[string]interface{}, headers map[st
 	return result, nil
 }
 
+// logProviderResponse decodes the body if possible, so that the tokens in it are redacted from the logs
+func logProviderResponse(statusCode int, body []byte) {
+	var decodedBody interface{}
+	if json.Unmarshal(body, &decodedBody) != nil {
+		decodedBody = string(body)
+	}
+	supertokens.LogDebug(nil, "Received response from provider", "status", statusCode, "body", decodedBody)
+}
+
 func doPostRequest(url string, params map[string]interface{}, headers map[string]interface{}) (map[string]interface{}, int, error) {
-	supertokens.LogDebugMessage(fmt.Sprintf("POST request to %s, with form fields %v and headers %v", url, params, headers))
+	supertokens.LogDebug(nil, "POST request to provider", "url", url, "formFields", params, "headers", headers)
 
 	postBody, err := qs.Marshal(params)
 	if err != nil {
@@ -119,7 +128,7 @@ func doPostRequest(url string, params map[string]interface{}, headers map[string
 		return nil, resp.StatusCode, err
 	}
 
-	supertokens.LogDebugMessage(fmt.Sprintf("Received response with status %d and body %s", resp.StatusCode, string(body)))
+	logProviderResponse(resp.StatusCode, body)
 
 	var result map[string]interface{}
 	err = json.Unmarshal(body, &result)
@@ -217,7 +226,7 @@ func randomBytes(length int) ([]byte, error) {
 	for {
 		buf := make([]byte, length)
 		if _, err := io.ReadFull(rand.Reader, buf); err != nil {
-			return nil, fmt.Errorf("failed to read random bytes: %v", err)
+			return nil, fmt.Errorf("failed to read random bytes: %w", err)
 		}
 		for _, b := range buf {
 			// Avoid bias by using a value range that's a multiple of 62
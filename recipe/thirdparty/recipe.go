@@ -39,14 +39,12 @@ type Recipe struct {
 	Providers    []tpmodels.ProviderInput
 }
 
-var singletonInstance *Recipe
-
 func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *tpmodels.TypeInput, emailDeliveryIngredient *emaildelivery.Ingredient, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (Recipe, error) {
 	r := &Recipe{}
 
 	r.RecipeModule = supertokens.MakeRecipeModule(recipeId, appInfo, r.handleAPIRequest, r.getAllCORSHeaders, r.getAPIsHandled, nil, r.handleError, onSuperTokensAPIError)
 
-	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId)
+	querierInstance, err := supertokens.GetNewQuerierInstanceOrThrowError(recipeId, supertokens.MakeUserContextForAppInfo(appInfo))
 	if err != nil {
 		return Recipe{}, err
 	}
@@ -57,15 +55,17 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 	r.Config = verifiedConfig
 	r.APIImpl = verifiedConfig.Override.APIs(api.MakeAPIImplementation())
 	r.RecipeImpl = verifiedConfig.Override.Functions(MakeRecipeImplementation(*querierInstance, verifiedConfig.SignInAndUpFeature.Providers))
+	supertokens.InstrumentRecipeInterface(recipeId, &r.RecipeImpl)
 	r.Providers = verifiedConfig.SignInAndUpFeature.Providers
 
 	supertokens.AddPostInitCallback(func() error {
-		evRecipe := emailverification.GetRecipeInstance()
+		userContext := supertokens.MakeUserContextForAppInfo(appInfo)
+		evRecipe := emailverification.GetRecipeInstance(userContext)
 		if evRecipe != nil {
 			evRecipe.AddGetEmailForUserIdFunc(r.getEmailForUserId)
 		}
 
-		mtRecipe := multitenancy.GetRecipeInstance()
+		mtRecipe := multitenancy.GetRecipeInstance(userContext)
 		if mtRecipe != nil {
 			mtRecipe.SetStaticThirdPartyProviders(verifiedConfig.SignInAndUpFeature.Providers)
 		}
@@ -80,23 +80,27 @@ func MakeRecipe(recipeId string, appInfo supertokens.NormalisedAppinfo, config *
 
 func recipeInit(config *tpmodels.TypeInput) supertokens.Recipe {
 	return func(appInfo supertokens.NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*supertokens.RecipeModule, error) {
-		if singletonInstance == nil {
+		if supertokens.GetRecipeInstance(RECIPE_ID, supertokens.MakeUserContextForAppInfo(appInfo)) == nil {
 			recipe, err := MakeRecipe(RECIPE_ID, appInfo, config, nil, onSuperTokensAPIError)
 			if err != nil {
 				return nil, err
 			}
-			singletonInstance = &recipe
-			return &singletonInstance.RecipeModule, nil
+			err = supertokens.RegisterRecipeInstance(appInfo, RECIPE_ID, &recipe)
+			if err != nil {
+				return nil, err
+			}
+			return &recipe.RecipeModule, nil
 		}
 		return nil, errors.New("ThirdParty recipe has already been initialised. Please check your code for bugs.")
 	}
 }
 
-func GetRecipeInstanceOrThrowError() (*Recipe, error) {
-	if singletonInstance != nil {
-		return singletonInstance, nil
+func GetRecipeInstanceOrThrowError(userContext ...supertokens.UserContext) (*Recipe, error) {
+	instance, ok := supertokens.GetRecipeInstance(RECIPE_ID, userContext...).(*Recipe)
+	if ok {
+		return instance, nil
 	}
-	return nil, errors.New("Initialisation not done. Did you forget to call the init function?")
+	return nil, supertokens.NotInitialisedError{Msg: "Initialisation not done. Did you forget to call the init function?"}
 }
 
 // implement RecipeModule
@@ -119,16 +123,19 @@ func (r *Recipe) getAPIsHandled() ([]supertokens.APIHandled, error) {
 		PathWithoutAPIBasePath: signInUpAPI,
 		ID:                     SignInUpAPI,
 		Disabled:               r.APIImpl.SignInUpPOST == nil,
+		Spec:                   signInUpAPISpec(),
 	}, {
 		Method:                 http.MethodGet,
 		PathWithoutAPIBasePath: authorisationAPI,
 		ID:                     AuthorisationAPI,
 		Disabled:               r.APIImpl.AuthorisationUrlGET == nil,
+		Spec:                   authorisationUrlAPISpec(),
 	}, {
 		Method:                 http.MethodPost,
 		PathWithoutAPIBasePath: appleRedirectHandlerAPI,
 		ID:                     AppleRedirectHandlerAPI,
 		Disabled:               r.APIImpl.AppleRedirectHandlerPOST == nil,
+		Spec:                   appleRedirectHandlerAPISpec(),
 	}}), nil
 }
 
@@ -182,6 +189,6 @@ func (r *Recipe) getEmailForUserId(userID string, userContext supertokens.UserCo
 	}, nil
 }
 
-func ResetForTest() {
-	singletonInstance = nil
-}
+// ResetForTest does nothing since recipe instances are reset along with the SuperTokens
+// instance that they belong to. It is kept for the tests that call it.
+func ResetForTest() {}
@@ -47,18 +47,12 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 	}
 
 	signInUp := func(thirdPartyID, thirdPartyUserID string, email string, oAuthTokens tpmodels.TypeOAuthTokens, rawUserInfoFromProvider tpmodels.TypeRawUserInfoFromProvider, tenantId string, userContext supertokens.UserContext) (tpmodels.SignInUpResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup", map[string]interface{}{
-			"thirdPartyId":     thirdPartyID,
-			"thirdPartyUserId": thirdPartyUserID,
-			"email":            map[string]interface{}{"id": email},
-		}, userContext)
-		if err != nil {
-			return tpmodels.SignInUpResponse{}, err
-		}
-		user, err := parseUser(response["user"])
+		var response supertokens.CoreThirdPartySignInUpResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup", signInUpRequest(thirdPartyID, thirdPartyUserID, email), &response, userContext)
 		if err != nil {
 			return tpmodels.SignInUpResponse{}, err
 		}
+		supertokens.RecordSignInUpMetric(RECIPE_ID, tenantId, response.CreatedNewUser)
 		return tpmodels.SignInUpResponse{
 			OK: &struct {
 				CreatedNewUser          bool
@@ -66,8 +60,8 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 				OAuthTokens             tpmodels.TypeOAuthTokens
 				RawUserInfoFromProvider tpmodels.TypeRawUserInfoFromProvider
 			}{
-				CreatedNewUser:          response["createdNewUser"].(bool),
-				User:                    *user,
+				CreatedNewUser:          response.CreatedNewUser,
+				User:                    tpmodels.User(response.User),
 				OAuthTokens:             oAuthTokens,
 				RawUserInfoFromProvider: rawUserInfoFromProvider,
 			},
@@ -75,15 +69,8 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 	}
 
 	manuallyCreateOrUpdateUser := func(thirdPartyID, thirdPartyUserID string, email string, tenantId string, userContext supertokens.UserContext) (tpmodels.ManuallyCreateOrUpdateUserResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/signinup", map[string]interface{}{
-			"thirdPartyId":     thirdPartyID,
-			"thirdPartyUserId": thirdPartyUserID,
-			"email":            map[string]interface{}{"id": email},
-		}, userContext)
-		if err != nil {
-			return tpmodels.ManuallyCreateOrUpdateUserResponse{}, err
-		}
-		user, err := parseUser(response["user"])
+		var response supertokens.CoreThirdPartySignInUpResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/signinup", signInUpRequest(thirdPartyID, thirdPartyUserID, email), &response, userContext)
 		if err != nil {
 			return tpmodels.ManuallyCreateOrUpdateUserResponse{}, err
 		}
@@ -92,57 +79,52 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 				CreatedNewUser bool
 				User           tpmodels.User
 			}{
-				CreatedNewUser: response["createdNewUser"].(bool),
-				User:           *user,
+				CreatedNewUser: response.CreatedNewUser,
+				User:           tpmodels.User(response.User),
 			},
 		}, nil
 	}
 
 	getUserByID := func(userID string, userContext supertokens.UserContext) (*tpmodels.User, error) {
-		response, err := querier.SendGetRequest("/recipe/user", map[string]string{
+		var response supertokens.CoreThirdPartyUserResponse
+		err := querier.SendGetRequestTyped("/recipe/user", map[string]string{
 			"userId": userID,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		if response["status"] == "OK" {
-			user, err := parseUser(response["user"])
-			if err != nil {
-				return nil, err
-			}
-			return user, nil
+		if response.Status == "OK" {
+			return (*tpmodels.User)(response.User), nil
 		}
 		return nil, nil
 	}
 
 	getUserByThirdPartyInfo := func(thirdPartyID, thirdPartyUserID string, tenantId string, userContext supertokens.UserContext) (*tpmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user", map[string]string{
+		var response supertokens.CoreThirdPartyUserResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/user", map[string]string{
 			"thirdPartyId":     thirdPartyID,
 			"thirdPartyUserId": thirdPartyUserID,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return nil, err
 		}
-		if response["status"] == "OK" {
-			user, err := parseUser(response["user"])
-			if err != nil {
-				return nil, err
-			}
-			return user, nil
+		if response.Status == "OK" {
+			return (*tpmodels.User)(response.User), nil
 		}
 		return nil, nil
 	}
 
 	getUsersByEmail := func(email string, tenantId string, userContext supertokens.UserContext) ([]tpmodels.User, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/users/by-email", map[string]string{
+		var response supertokens.CoreThirdPartyUsersByEmailResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/users/by-email", map[string]string{
 			"email": email,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return []tpmodels.User{}, err
 		}
-		users, err := parseUsers(response["users"])
-		if err != nil {
-			return []tpmodels.User{}, err
+		users := []tpmodels.User{}
+		for _, user := range response.Users {
+			users = append(users, tpmodels.User(user))
 		}
 		return users, nil
 	}
@@ -156,3 +138,12 @@ func MakeRecipeImplementation(querier supertokens.Querier, providers []tpmodels.
 		ManuallyCreateOrUpdateUser: &manuallyCreateOrUpdateUser,
 	}
 }
+
+func signInUpRequest(thirdPartyID, thirdPartyUserID string, email string) supertokens.CoreThirdPartySignInUpRequest {
+	request := supertokens.CoreThirdPartySignInUpRequest{
+		ThirdPartyId:     thirdPartyID,
+		ThirdPartyUserId: thirdPartyUserID,
+	}
+	request.Email.ID = email
+	return request
+}
@@ -1,5 +1,17 @@
 package tperrors
 
+import "github.com/supertokens/supertokens-golang/supertokens"
+
+// The error codes of the thirdparty errors (see supertokens.GetErrorCode)
+const (
+	ErrorCodeClientTypeNotFound supertokens.ErrorCode = "CLIENT_TYPE_NOT_FOUND"
+)
+
+// Sentinel errors for the thirdparty errors, to be used with errors.Is
+var (
+	ErrClientTypeNotFound = supertokens.NewSentinelError(ErrorCodeClientTypeNotFound)
+)
+
 type ClientTypeNotFoundError struct {
 	Msg string
 }
@@ -7,3 +19,11 @@ type ClientTypeNotFoundError struct {
 func (e ClientTypeNotFoundError) Error() string {
 	return e.Msg
 }
+
+func (e ClientTypeNotFoundError) ErrorCode() supertokens.ErrorCode {
+	return ErrorCodeClientTypeNotFound
+}
+
+func (e ClientTypeNotFoundError) Is(target error) bool {
+	return supertokens.IsSentinelErrorFor(target, e.ErrorCode())
+}
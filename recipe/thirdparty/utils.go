@@ -16,8 +16,6 @@
 package thirdparty
 
 import (
-	"encoding/json"
-
 	"github.com/supertokens/supertokens-golang/recipe/thirdparty/tpmodels"
 	"github.com/supertokens/supertokens-golang/supertokens"
 )
@@ -78,39 +76,3 @@ func validateAndNormaliseSignInAndUpConfig(config tpmodels.TypeInputSignInAndUp)
 		Providers: providers,
 	}, nil
 }
-
-func parseUser(value interface{}) (*tpmodels.User, error) {
-	respJSON, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
-	}
-	var user tpmodels.User
-	err = json.Unmarshal(respJSON, &user)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-func parseUsers(value interface{}) ([]tpmodels.User, error) {
-	respJSON, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
-	}
-	var users []interface{}
-	err = json.Unmarshal(respJSON, &users)
-
-	usersResult := make([]tpmodels.User, len(users))
-	for i, user := range users {
-		userObj, err := parseUser(user)
-		if err != nil {
-			return nil, err
-		}
-		usersResult[i] = *userObj
-	}
-
-	if err != nil {
-		return nil, err
-	}
-	return usersResult, nil
-}
@@ -25,7 +25,7 @@ func Init(config *usermetadatamodels.TypeInput) supertokens.Recipe {
 }
 
 func GetUserMetadata(userID string, userContext ...supertokens.UserContext) (map[string]interface{}, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return map[string]interface{}{}, err
 	}
@@ -36,7 +36,7 @@ func GetUserMetadata(userID string, userContext ...supertokens.UserContext) (map
 }
 
 func UpdateUserMetadata(userID string, metadataUpdate map[string]interface{}, userContext ...supertokens.UserContext) (map[string]interface{}, error) {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return map[string]interface{}{}, err
 	}
@@ -47,7 +47,7 @@ func UpdateUserMetadata(userID string, metadataUpdate map[string]interface{}, us
 }
 
 func ClearUserMetadata(userID string, userContext ...supertokens.UserContext) error {
-	instance, err := GetRecipeInstanceOrThrowError()
+	instance, err := GetRecipeInstanceOrThrowError(userContext...)
 	if err != nil {
 		return err
 	}
@@ -22,32 +22,42 @@ import (
 
 func makeRecipeImplementation(querier supertokens.Querier, config usermetadatamodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo) usermetadatamodels.RecipeInterface {
 	getUserMetadata := func(userID string, userContext supertokens.UserContext) (map[string]interface{}, error) {
-		response, err := querier.SendGetRequest("/recipe/user/metadata", map[string]string{
+		cachedMetadata := map[string]interface{}{}
+		if querier.GetFromCache(supertokens.CacheNamespaceUserMetadata, userID, &cachedMetadata) {
+			return cachedMetadata, nil
+		}
+		var response supertokens.CoreUserMetadataResponse
+		err := querier.SendGetRequestTyped("/recipe/user/metadata", map[string]string{
 			"userId": userID,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return map[string]interface{}{}, err
 		}
 
-		return response["metadata"].(map[string]interface{}), nil
+		querier.SetInCache(supertokens.CacheNamespaceUserMetadata, userID, response.Metadata)
+		return response.Metadata, nil
 	}
 
 	updateUserMetadata := func(userID string, metadataUpdate map[string]interface{}, userContext supertokens.UserContext) (map[string]interface{}, error) {
-		response, err := querier.SendPutRequest("/recipe/user/metadata", map[string]interface{}{
-			"userId":         userID,
-			"metadataUpdate": metadataUpdate,
-		}, userContext)
+		var response supertokens.CoreUserMetadataResponse
+		err := querier.SendPutRequestTyped("/recipe/user/metadata", supertokens.CoreUpdateUserMetadataRequest{
+			UserId:         userID,
+			MetadataUpdate: metadataUpdate,
+		}, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceUserMetadata, userID)
 		if err != nil {
 			return map[string]interface{}{}, err
 		}
 
-		return response["metadata"].(map[string]interface{}), nil
+		return response.Metadata, nil
 	}
 
 	clearUserMetadata := func(userID string, userContext supertokens.UserContext) error {
-		_, err := querier.SendPostRequest("/recipe/user/metadata/remove", map[string]interface{}{
-			"userId": userID,
-		}, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPostRequestTyped("/recipe/user/metadata/remove", supertokens.CoreClearUserMetadataRequest{
+			UserId: userID,
+		}, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceUserMetadata, userID)
 		return err
 	}
 
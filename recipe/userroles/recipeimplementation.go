@@ -23,18 +23,19 @@ import (
 func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodels.TypeNormalisedInput, appInfo supertokens.NormalisedAppinfo) userrolesmodels.RecipeInterface {
 
 	addRoleToUser := func(userID string, role string, tenantId string, userContext supertokens.UserContext) (userrolesmodels.AddRoleToUserResponse, error) {
-		response, err := querier.SendPutRequest(tenantId+"/recipe/user/role", map[string]interface{}{
-			"userId": userID,
-			"role":   role,
-		}, userContext)
+		var response supertokens.CoreAddRoleToUserResponse
+		err := querier.SendPutRequestTyped(tenantId+"/recipe/user/role", supertokens.CoreUserRoleRequest{
+			UserId: userID,
+			Role:   role,
+		}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.AddRoleToUserResponse{}, err
 		}
 
-		if response["status"] == "OK" {
+		if response.Status == "OK" {
 			return userrolesmodels.AddRoleToUserResponse{
 				OK: &struct{ DidUserAlreadyHaveRole bool }{
-					DidUserAlreadyHaveRole: response["didUserAlreadyHaveRole"].(bool),
+					DidUserAlreadyHaveRole: response.DidUserAlreadyHaveRole,
 				},
 			}, nil
 		}
@@ -45,18 +46,19 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 	}
 
 	removeUserRole := func(userID string, role string, tenantId string, userContext supertokens.UserContext) (userrolesmodels.RemoveUserRoleResponse, error) {
-		response, err := querier.SendPostRequest(tenantId+"/recipe/user/role/remove", map[string]interface{}{
-			"userId": userID,
-			"role":   role,
-		}, userContext)
+		var response supertokens.CoreRemoveUserRoleResponse
+		err := querier.SendPostRequestTyped(tenantId+"/recipe/user/role/remove", supertokens.CoreUserRoleRequest{
+			UserId: userID,
+			Role:   role,
+		}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.RemoveUserRoleResponse{}, err
 		}
 
-		if response["status"] == "OK" {
+		if response.Status == "OK" {
 			return userrolesmodels.RemoveUserRoleResponse{
 				OK: &struct{ DidUserHaveRole bool }{
-					DidUserHaveRole: response["didUserHaveRole"].(bool),
+					DidUserHaveRole: response.DidUserHaveRole,
 				},
 			}, nil
 		}
@@ -67,33 +69,35 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 	}
 
 	getRolesForUser := func(userID string, tenantId string, userContext supertokens.UserContext) (userrolesmodels.GetRolesForUserResponse, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/user/roles", map[string]string{
+		var response supertokens.CoreRolesResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/user/roles", map[string]string{
 			"userId": userID,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.GetRolesForUserResponse{}, err
 		}
 
 		return userrolesmodels.GetRolesForUserResponse{
 			OK: &struct{ Roles []string }{
-				Roles: convertToStringArray(response["roles"].([]interface{})),
+				Roles: response.Roles,
 			},
 		}, nil
 
 	}
 
 	getUsersThatHaveRole := func(role string, tenantId string, userContext supertokens.UserContext) (userrolesmodels.GetUsersThatHaveRoleResponse, error) {
-		response, err := querier.SendGetRequest(tenantId+"/recipe/role/users", map[string]string{
+		var response supertokens.CoreGetUsersThatHaveRoleResponse
+		err := querier.SendGetRequestTyped(tenantId+"/recipe/role/users", map[string]string{
 			"role": role,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.GetUsersThatHaveRoleResponse{}, err
 		}
 
-		if response["status"] == "OK" {
+		if response.Status == "OK" {
 			return userrolesmodels.GetUsersThatHaveRoleResponse{
 				OK: &struct{ Users []string }{
-					Users: convertToStringArray(response["users"].([]interface{})),
+					Users: response.Users,
 				},
 			}, nil
 		}
@@ -104,33 +108,45 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 	}
 
 	createNewRoleOrAddPermissions := func(role string, permissions []string, userContext supertokens.UserContext) (userrolesmodels.CreateNewRoleOrAddPermissionsResponse, error) {
-		response, err := querier.SendPutRequest("/recipe/role", map[string]interface{}{
-			"role":        role,
-			"permissions": permissions,
-		}, userContext)
+		var response supertokens.CoreCreateNewRoleOrAddPermissionsResponse
+		err := querier.SendPutRequestTyped("/recipe/role", supertokens.CoreRolePermissionsRequest{
+			Role:        role,
+			Permissions: permissions,
+		}, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceRolePermissions, role)
 		if err != nil {
 			return userrolesmodels.CreateNewRoleOrAddPermissionsResponse{}, err
 		}
 
 		return userrolesmodels.CreateNewRoleOrAddPermissionsResponse{
 			OK: &struct{ CreatedNewRole bool }{
-				CreatedNewRole: response["createdNewRole"].(bool),
+				CreatedNewRole: response.CreatedNewRole,
 			},
 		}, nil
 	}
 
 	getPermissionsForRole := func(role string, userContext supertokens.UserContext) (userrolesmodels.GetPermissionsForRoleResponse, error) {
-		response, err := querier.SendGetRequest("/recipe/role/permissions", map[string]string{
+		cachedPermissions := []string{}
+		if querier.GetFromCache(supertokens.CacheNamespaceRolePermissions, role, &cachedPermissions) {
+			return userrolesmodels.GetPermissionsForRoleResponse{
+				OK: &struct{ Permissions []string }{
+					Permissions: cachedPermissions,
+				},
+			}, nil
+		}
+		var response supertokens.CoreGetPermissionsForRoleResponse
+		err := querier.SendGetRequestTyped("/recipe/role/permissions", map[string]string{
 			"role": role,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.GetPermissionsForRoleResponse{}, err
 		}
 
-		if response["status"] == "OK" {
+		if response.Status == "OK" {
+			querier.SetInCache(supertokens.CacheNamespaceRolePermissions, role, response.Permissions)
 			return userrolesmodels.GetPermissionsForRoleResponse{
 				OK: &struct{ Permissions []string }{
-					Permissions: convertToStringArray(response["permissions"].([]interface{})),
+					Permissions: response.Permissions,
 				},
 			}, nil
 		}
@@ -141,15 +157,17 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 	}
 
 	removePermissionsFromRole := func(role string, permissions []string, userContext supertokens.UserContext) (userrolesmodels.RemovePermissionsFromRoleResponse, error) {
-		response, err := querier.SendPostRequest("/recipe/role/permissions/remove", map[string]interface{}{
-			"role":        role,
-			"permissions": permissions,
-		}, userContext)
+		var response supertokens.CoreStatusResponse
+		err := querier.SendPostRequestTyped("/recipe/role/permissions/remove", supertokens.CoreRolePermissionsRequest{
+			Role:        role,
+			Permissions: permissions,
+		}, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceRolePermissions, role)
 		if err != nil {
 			return userrolesmodels.RemovePermissionsFromRoleResponse{}, err
 		}
 
-		if response["status"] == "OK" {
+		if response.Status == "OK" {
 			return userrolesmodels.RemovePermissionsFromRoleResponse{
 				OK: &struct{}{},
 			}, nil
@@ -161,44 +179,48 @@ func makeRecipeImplementation(querier supertokens.Querier, config userrolesmodel
 	}
 
 	getRolesThatHavePermission := func(permission string, userContext supertokens.UserContext) (userrolesmodels.GetRolesThatHavePermissionResponse, error) {
-		response, err := querier.SendGetRequest("/recipe/permission/roles", map[string]string{
+		var response supertokens.CoreRolesResponse
+		err := querier.SendGetRequestTyped("/recipe/permission/roles", map[string]string{
 			"permission": permission,
-		}, userContext)
+		}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.GetRolesThatHavePermissionResponse{}, err
 		}
 
 		return userrolesmodels.GetRolesThatHavePermissionResponse{
 			OK: &struct{ Roles []string }{
-				Roles: convertToStringArray(response["roles"].([]interface{})),
+				Roles: response.Roles,
 			},
 		}, nil
 	}
 
 	deleteRole := func(role string, userContext supertokens.UserContext) (userrolesmodels.DeleteRoleResponse, error) {
-		response, err := querier.SendPostRequest("/recipe/role/remove", map[string]interface{}{
-			"role": role,
-		}, userContext)
+		var response supertokens.CoreDeleteRoleResponse
+		err := querier.SendPostRequestTyped("/recipe/role/remove", supertokens.CoreDeleteRoleRequest{
+			Role: role,
+		}, &response, userContext)
+		querier.InvalidateCache(supertokens.CacheNamespaceRolePermissions, role)
 		if err != nil {
 			return userrolesmodels.DeleteRoleResponse{}, err
 		}
 
 		return userrolesmodels.DeleteRoleResponse{
 			OK: &struct{ DidRoleExist bool }{
-				DidRoleExist: response["didRoleExist"].(bool),
+				DidRoleExist: response.DidRoleExist,
 			},
 		}, nil
 	}
 
 	getAllRoles := func(userContext supertokens.UserContext) (userrolesmodels.GetAllRolesResponse, error) {
-		response, err := querier.SendGetRequest("/recipe/roles", map[string]string{}, userContext)
+		var response supertokens.CoreRolesResponse
+		err := querier.SendGetRequestTyped("/recipe/roles", map[string]string{}, &response, userContext)
 		if err != nil {
 			return userrolesmodels.GetAllRolesResponse{}, err
 		}
 
 		return userrolesmodels.GetAllRolesResponse{
 			OK: &struct{ Roles []string }{
-				Roles: convertToStringArray(response["roles"].([]interface{})),
+				Roles: response.Roles,
 			},
 		}, nil
 	}
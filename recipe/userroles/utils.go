@@ -53,11 +53,3 @@ func makeTypeNormalisedInput(appInfo supertokens.NormalisedAppinfo) userrolesmod
 		},
 	}
 }
-
-func convertToStringArray(arr []interface{}) []string {
-	result := make([]string, len(arr))
-	for idx, v := range arr {
-		result[idx] = v.(string)
-	}
-	return result
-}
@@ -0,0 +1,265 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Namespaces of the core lookups that can be cached. These can be used as keys
+// in CacheConfig.TTLForNamespace.
+const (
+	CacheNamespaceEmailPasswordUser = "emailpassword.user"
+	CacheNamespaceTenant            = "multitenancy.tenant"
+	CacheNamespaceRolePermissions   = "userroles.permissions"
+	CacheNamespaceUserMetadata      = "usermetadata.metadata"
+)
+
+const (
+	defaultCacheTTL               = time.Minute
+	defaultCacheMaxEntries        = 10000
+	cacheNamespaceAndKeySeparator = "|"
+)
+
+// userScopedCacheNamespaces are invalidated when a user is deleted or their user ID mapping changes
+var userScopedCacheNamespaces = []string{CacheNamespaceEmailPasswordUser, CacheNamespaceUserMetadata}
+
+// CacheStore is the backend used to cache responses from the core. Values are JSON encoded
+// so that a store shared between multiple instances of the backend (e.g. redis) can be used.
+//
+// Note that the SDK only invalidates entries for writes that it performs itself. If a store
+// is not shared, writes made by other instances are only seen once the TTL expires.
+type CacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(keys ...string)
+}
+
+type CacheConfig struct {
+	// Store defaults to an in-memory LRU store bound by MaxEntries
+	Store CacheStore
+	// MaxEntries is only used for the default store. Defaults to 10000.
+	MaxEntries int
+	// TTL defaults to 1 minute
+	TTL time.Duration
+	// TTLForNamespace overrides TTL for specific lookups. The keys are the CacheNamespace* constants.
+	// A TTL < 0 disables caching for that namespace.
+	TTLForNamespace map[string]time.Duration
+}
+
+type normalisedCacheConfig struct {
+	store           CacheStore
+	ttl             time.Duration
+	ttlForNamespace map[string]time.Duration
+}
+
+func initCache(config *CacheConfig) {
+	defaultCoreConnection.cache = normaliseCacheConfig(config)
+}
+
+func normaliseCacheConfig(config *CacheConfig) *normalisedCacheConfig {
+	if config == nil {
+		return nil
+	}
+	result := &normalisedCacheConfig{
+		store:           config.Store,
+		ttl:             config.TTL,
+		ttlForNamespace: config.TTLForNamespace,
+	}
+	if result.store == nil {
+		maxEntries := config.MaxEntries
+		if maxEntries <= 0 {
+			maxEntries = defaultCacheMaxEntries
+		}
+		result.store = NewInMemoryCacheStore(maxEntries)
+	}
+	if result.ttl <= 0 {
+		result.ttl = defaultCacheTTL
+	}
+	return result
+}
+
+func getCacheKey(namespace string, key string) string {
+	return namespace + cacheNamespaceAndKeySeparator + key
+}
+
+func (c *normalisedCacheConfig) getTTL(namespace string) time.Duration {
+	if ttl, ok := c.ttlForNamespace[namespace]; ok {
+		return ttl
+	}
+	return c.ttl
+}
+
+// GetFromCache decodes the cached value into result and returns true if there was
+// a (non expired) value in the cache of the instance created by Init.
+func GetFromCache(namespace string, key string, result interface{}) bool {
+	return defaultCoreConnection.cache.get(namespace, key, result)
+}
+
+func SetInCache(namespace string, key string, value interface{}) {
+	defaultCoreConnection.cache.set(namespace, key, value)
+}
+
+func InvalidateCache(namespace string, keys ...string) {
+	defaultCoreConnection.cache.invalidate(namespace, keys...)
+}
+
+// InvalidateCacheForUser removes all cached lookups for the given user IDs
+func InvalidateCacheForUser(userIds ...string) {
+	defaultCoreConnection.cache.invalidateForUser(userIds...)
+}
+
+// GetFromCache is like the package level GetFromCache, but uses the cache of the instance that the querier belongs to
+func (q *Querier) GetFromCache(namespace string, key string, result interface{}) bool {
+	return q.getCoreConnection().cache.get(namespace, key, result)
+}
+
+func (q *Querier) SetInCache(namespace string, key string, value interface{}) {
+	q.getCoreConnection().cache.set(namespace, key, value)
+}
+
+func (q *Querier) InvalidateCache(namespace string, keys ...string) {
+	q.getCoreConnection().cache.invalidate(namespace, keys...)
+}
+
+func (q *Querier) InvalidateCacheForUser(userIds ...string) {
+	q.getCoreConnection().cache.invalidateForUser(userIds...)
+}
+
+func (c *normalisedCacheConfig) get(namespace string, key string, result interface{}) bool {
+	if c == nil || c.getTTL(namespace) < 0 {
+		return false
+	}
+	value, ok := c.store.Get(getCacheKey(namespace, key))
+	if !ok {
+		return false
+	}
+	err := json.Unmarshal(value, result)
+	if err != nil {
+		LogDebugMessage("cache: ignoring entry that could not be decoded for key: " + getCacheKey(namespace, key))
+		return false
+	}
+	return true
+}
+
+func (c *normalisedCacheConfig) set(namespace string, key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	ttl := c.getTTL(namespace)
+	if ttl < 0 {
+		return
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.store.Set(getCacheKey(namespace, key), encoded, ttl)
+}
+
+func (c *normalisedCacheConfig) invalidate(namespace string, keys ...string) {
+	if c == nil {
+		return
+	}
+	cacheKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		cacheKeys = append(cacheKeys, getCacheKey(namespace, key))
+	}
+	c.store.Delete(cacheKeys...)
+}
+
+func (c *normalisedCacheConfig) invalidateForUser(userIds ...string) {
+	for _, namespace := range userScopedCacheNamespaces {
+		c.invalidate(namespace, userIds...)
+	}
+}
+
+type inMemoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+type inMemoryCacheStore struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List
+	lock       sync.Mutex
+}
+
+// NewInMemoryCacheStore returns a CacheStore that keeps up to maxEntries values in memory,
+// evicting the least recently used ones first.
+func NewInMemoryCacheStore(maxEntries int) CacheStore {
+	return &inMemoryCacheStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (s *inMemoryCacheStore) Get(key string) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*inMemoryCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		s.order.Remove(element)
+		delete(s.entries, key)
+		return nil, false
+	}
+	s.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (s *inMemoryCacheStore) Set(key string, value []byte, ttl time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	expiresAt := time.Now().Add(ttl)
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*inMemoryCacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		s.order.MoveToFront(element)
+		return
+	}
+	s.entries[key] = s.order.PushFront(&inMemoryCacheEntry{
+		key:       key,
+		value:     value,
+		expiresAt: expiresAt,
+	})
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*inMemoryCacheEntry).key)
+	}
+}
+
+func (s *inMemoryCacheStore) Delete(keys ...string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, key := range keys {
+		if element, ok := s.entries[key]; ok {
+			s.order.Remove(element)
+			delete(s.entries, key)
+		}
+	}
+}
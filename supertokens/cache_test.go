@@ -0,0 +1,78 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryCacheStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewInMemoryCacheStore(2)
+	store.Set("a", []byte("1"), time.Minute)
+	store.Set("b", []byte("2"), time.Minute)
+
+	_, ok := store.Get("a")
+	assert.True(t, ok)
+
+	store.Set("c", []byte("3"), time.Minute)
+
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+	value, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(value))
+	_, ok = store.Get("c")
+	assert.True(t, ok)
+}
+
+func TestInMemoryCacheStoreExpiresEntries(t *testing.T) {
+	store := NewInMemoryCacheStore(10)
+	store.Set("a", []byte("1"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, ok := store.Get("a")
+	assert.False(t, ok)
+}
+
+func TestCacheHelpers(t *testing.T) {
+	defer initCache(nil)
+
+	result := []string{}
+	SetInCache(CacheNamespaceRolePermissions, "admin", []string{"read"})
+	assert.False(t, GetFromCache(CacheNamespaceRolePermissions, "admin", &result))
+
+	initCache(&CacheConfig{
+		TTLForNamespace: map[string]time.Duration{
+			CacheNamespaceTenant: -1,
+		},
+	})
+
+	SetInCache(CacheNamespaceRolePermissions, "admin", []string{"read", "write"})
+	assert.True(t, GetFromCache(CacheNamespaceRolePermissions, "admin", &result))
+	assert.Equal(t, []string{"read", "write"}, result)
+
+	InvalidateCache(CacheNamespaceRolePermissions, "admin")
+	assert.False(t, GetFromCache(CacheNamespaceRolePermissions, "admin", &result))
+
+	SetInCache(CacheNamespaceTenant, "public", map[string]interface{}{"tenantId": "public"})
+	assert.False(t, GetFromCache(CacheNamespaceTenant, "public", &map[string]interface{}{}))
+
+	SetInCache(CacheNamespaceUserMetadata, "user1", map[string]interface{}{"key": "value"})
+	InvalidateCacheForUser("user1")
+	assert.False(t, GetFromCache(CacheNamespaceUserMetadata, "user1", &map[string]interface{}{}))
+}
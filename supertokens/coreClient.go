@@ -0,0 +1,219 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+/*
+The functions below are typed wrappers around the Send*Request functions of the Querier. The
+response of the core is decoded into the given struct, and an error of type CoreResponseShapeError
+is returned if it does not have the expected shape, instead of the SDK panicking later on.
+
+Fields of the response struct that have the tag `cdi:"required"` must be present in the response
+if the status of the response is "OK" (or if the response has no status). For example:
+
+	type getRolesResponse struct {
+		Status string   `json:"status"`
+		Roles  []string `json:"roles" cdi:"required"`
+	}
+*/
+
+const coreStatusOK = "OK"
+
+// CoreResponseShapeError is returned when a response from the core does not have the expected
+// shape. This usually means that the versions of the core and of this SDK are not compatible.
+type CoreResponseShapeError struct {
+	Path   string
+	Field  string
+	Reason string
+}
+
+func (err CoreResponseShapeError) Error() string {
+	return fmt.Sprintf("unexpected response from the SuperTokens core for path: '%s'. Field '%s' %s", err.Path, err.Field, err.Reason)
+}
+
+func (err CoreResponseShapeError) ErrorCode() ErrorCode {
+	return ErrorCodeCoreResponseShape
+}
+
+func (err CoreResponseShapeError) Is(target error) bool {
+	return IsSentinelErrorFor(target, err.ErrorCode())
+}
+
+func (q *Querier) SendGetRequestTyped(path string, params map[string]string, response interface{}, userContext UserContext) error {
+	resp, err := q.SendGetRequest(path, params, userContext)
+	if err != nil {
+		return err
+	}
+	return DecodeCoreResponse(path, resp, response)
+}
+
+// SendGetRequestWithResponseHeadersTyped is like SendGetRequestTyped, but also returns the headers of the response
+func (q *Querier) SendGetRequestWithResponseHeadersTyped(path string, params map[string]string, response interface{}, userContext UserContext) (http.Header, error) {
+	resp, headers, err := q.SendGetRequestWithResponseHeaders(path, params, userContext)
+	if err != nil {
+		return nil, err
+	}
+	return headers, DecodeCoreResponse(path, resp, response)
+}
+
+func (q *Querier) SendPostRequestTyped(path string, request interface{}, response interface{}, userContext UserContext) error {
+	data, err := coreRequestToMap(request)
+	if err != nil {
+		return err
+	}
+	resp, err := q.SendPostRequest(path, data, userContext)
+	if err != nil {
+		return err
+	}
+	return DecodeCoreResponse(path, resp, response)
+}
+
+func (q *Querier) SendPutRequestTyped(path string, request interface{}, response interface{}, userContext UserContext) error {
+	data, err := coreRequestToMap(request)
+	if err != nil {
+		return err
+	}
+	resp, err := q.SendPutRequest(path, data, userContext)
+	if err != nil {
+		return err
+	}
+	return DecodeCoreResponse(path, resp, response)
+}
+
+func (q *Querier) SendDeleteRequestTyped(path string, request interface{}, params map[string]string, response interface{}, userContext UserContext) error {
+	data, err := coreRequestToMap(request)
+	if err != nil {
+		return err
+	}
+	resp, err := q.SendDeleteRequest(path, data, params, userContext)
+	if err != nil {
+		return err
+	}
+	return DecodeCoreResponse(path, resp, response)
+}
+
+func coreRequestToMap(request interface{}) (map[string]interface{}, error) {
+	if request == nil {
+		return map[string]interface{}{}, nil
+	}
+	if data, ok := request.(map[string]interface{}); ok {
+		return data, nil
+	}
+	return StructToMap(request)
+}
+
+// DecodeCoreResponse decodes a response from the core into result, which must be a pointer to a struct.
+// If response is nil, result is left as is.
+func DecodeCoreResponse(path string, response map[string]interface{}, result interface{}) error {
+	if result == nil || response == nil {
+		return nil
+	}
+	resultValue := reflect.ValueOf(result)
+	if resultValue.Kind() != reflect.Ptr || resultValue.IsNil() {
+		return errors.New("DecodeCoreResponse: result must be a non nil pointer")
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(responseJSON, result)
+	if err != nil {
+		var typeError *json.UnmarshalTypeError
+		if errors.As(err, &typeError) {
+			return CoreResponseShapeError{
+				Path:   path,
+				Field:  typeError.Field,
+				Reason: fmt.Sprintf("should be of type %s but is of type %s", typeError.Type.String(), typeError.Value),
+			}
+		}
+		return err
+	}
+
+	status, hasStatus := response["status"].(string)
+	if hasStatus && status != coreStatusOK {
+		return nil
+	}
+
+	return checkRequiredCoreResponseFields(path, "", response, resultValue.Elem())
+}
+
+func checkRequiredCoreResponseFields(path string, prefix string, response map[string]interface{}, value reflect.Value) error {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldValue, present := response[name]
+		if field.Tag.Get("cdi") == "required" && (!present || fieldValue == nil) {
+			return CoreResponseShapeError{
+				Path:   path,
+				Field:  prefix + name,
+				Reason: "is missing",
+			}
+		}
+
+		switch nested := fieldValue.(type) {
+		case map[string]interface{}:
+			err := checkRequiredCoreResponseFields(path, prefix+name+".", nested, value.Field(i))
+			if err != nil {
+				return err
+			}
+		case []interface{}:
+			if value.Field(i).Kind() != reflect.Slice {
+				continue
+			}
+			for j, element := range nested {
+				elementResponse, ok := element.(map[string]interface{})
+				if !ok || j >= value.Field(i).Len() {
+					continue
+				}
+				err := checkRequiredCoreResponseFields(path, fmt.Sprintf("%s%s[%d].", prefix, name, j), elementResponse, value.Field(i).Index(j))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
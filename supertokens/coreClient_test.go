@@ -0,0 +1,214 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCoreCode struct {
+	CodeID      string `json:"codeId" cdi:"required"`
+	TimeCreated uint64 `json:"timeCreated"`
+}
+
+type testCoreResponse struct {
+	Status  string                 `json:"status"`
+	UserId  string                 `json:"userId" cdi:"required"`
+	Count   int                    `json:"count"`
+	Info    *string                `json:"info"`
+	Data    map[string]interface{} `json:"data"`
+	Codes   []testCoreCode         `json:"codes"`
+	Session *struct {
+		Handle string `json:"handle" cdi:"required"`
+	} `json:"session"`
+}
+
+func TestDecodeCoreResponseDecodesAllFields(t *testing.T) {
+	var result testCoreResponse
+	err := DecodeCoreResponse("/test", map[string]interface{}{
+		"status": "OK",
+		"userId": "user1",
+		"count":  float64(3),
+		"info":   "info",
+		"data":   map[string]interface{}{"key": "value"},
+		"codes": []interface{}{
+			map[string]interface{}{"codeId": "code1", "timeCreated": float64(10)},
+		},
+		"session": map[string]interface{}{"handle": "handle1"},
+	}, &result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user1", result.UserId)
+	assert.Equal(t, 3, result.Count)
+	assert.Equal(t, "info", *result.Info)
+	assert.Equal(t, "value", result.Data["key"])
+	assert.Equal(t, []testCoreCode{{CodeID: "code1", TimeCreated: 10}}, result.Codes)
+	assert.Equal(t, "handle1", result.Session.Handle)
+}
+
+func TestDecodeCoreResponseReturnsErrorForMissingRequiredField(t *testing.T) {
+	var result testCoreResponse
+	err := DecodeCoreResponse("/test", map[string]interface{}{
+		"status": "OK",
+	}, &result)
+
+	var shapeError CoreResponseShapeError
+	assert.True(t, errors.As(err, &shapeError))
+	assert.Equal(t, "/test", shapeError.Path)
+	assert.Equal(t, "userId", shapeError.Field)
+	assert.Equal(t, "unexpected response from the SuperTokens core for path: '/test'. Field 'userId' is missing", err.Error())
+}
+
+func TestDecodeCoreResponseReturnsErrorForMissingNestedRequiredField(t *testing.T) {
+	var result testCoreResponse
+	err := DecodeCoreResponse("/test", map[string]interface{}{
+		"status":  "OK",
+		"userId":  "user1",
+		"session": map[string]interface{}{},
+	}, &result)
+
+	var shapeError CoreResponseShapeError
+	assert.True(t, errors.As(err, &shapeError))
+	assert.Equal(t, "session.handle", shapeError.Field)
+
+	err = DecodeCoreResponse("/test", map[string]interface{}{
+		"status": "OK",
+		"userId": "user1",
+		"codes": []interface{}{
+			map[string]interface{}{"codeId": "code1"},
+			map[string]interface{}{"timeCreated": float64(10)},
+		},
+	}, &result)
+
+	assert.True(t, errors.As(err, &shapeError))
+	assert.Equal(t, "codes[1].codeId", shapeError.Field)
+}
+
+func TestDecodeCoreResponseReturnsErrorForWrongType(t *testing.T) {
+	var result testCoreResponse
+	err := DecodeCoreResponse("/test", map[string]interface{}{
+		"status": "OK",
+		"userId": float64(1),
+	}, &result)
+
+	var shapeError CoreResponseShapeError
+	assert.True(t, errors.As(err, &shapeError))
+	assert.Equal(t, "userId", shapeError.Field)
+	assert.Contains(t, shapeError.Reason, "string")
+}
+
+func TestDecodeCoreResponseSkipsRequiredFieldsForNonOKStatus(t *testing.T) {
+	var result testCoreResponse
+	err := DecodeCoreResponse("/test", map[string]interface{}{
+		"status": "UNKNOWN_USER_ID_ERROR",
+	}, &result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "UNKNOWN_USER_ID_ERROR", result.Status)
+}
+
+func TestCoreRequestsOnlyContainTheOptionalFieldsThatAreSet(t *testing.T) {
+	data, err := coreRequestToMap(CoreVerifySessionRequest{
+		AccessToken: "token",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "token", data["accessToken"])
+	_, ok := data["antiCsrfToken"]
+	assert.False(t, ok)
+
+	antiCsrfToken := "antiCsrf"
+	data, err = coreRequestToMap(CoreVerifySessionRequest{
+		AccessToken:   "token",
+		AntiCsrfToken: &antiCsrfToken,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "antiCsrf", data["antiCsrfToken"])
+
+	// the core removes the email of a passwordless user if it is null
+	data, err = coreRequestToMap(CoreDeletePasswordlessUserEmailRequest{
+		UserId: "user1",
+	})
+	assert.NoError(t, err)
+	email, ok := data["email"]
+	assert.True(t, ok)
+	assert.Nil(t, email)
+}
+
+func TestCoreRequestsToRevokePasswordlessCodesOnlyContainTheEmailOrThePhoneNumber(t *testing.T) {
+	email := "test@example.com"
+	data, err := coreRequestToMap(CoreRevokePasswordlessCodesRequest{
+		Email: &email,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"email": "test@example.com"}, data)
+
+	phoneNumber := "+1234567890"
+	data, err = coreRequestToMap(CoreRevokePasswordlessCodesRequest{
+		PhoneNumber: &phoneNumber,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"phoneNumber": "+1234567890"}, data)
+}
+
+func TestDecodeCoreResponseRequiresTheSessionIdOnlyForSuccessfulDashboardSignIns(t *testing.T) {
+	var result CoreDashboardSignInResponse
+	err := DecodeCoreResponse("/recipe/dashboard/signin", map[string]interface{}{
+		"status": "OK",
+	}, &result)
+	var shapeError CoreResponseShapeError
+	assert.True(t, errors.As(err, &shapeError))
+	assert.Equal(t, "sessionId", shapeError.Field)
+
+	result = CoreDashboardSignInResponse{}
+	err = DecodeCoreResponse("/recipe/dashboard/signin", map[string]interface{}{
+		"status":  "USER_SUSPENDED_ERROR",
+		"message": "suspended",
+	}, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, "suspended", result.Message)
+}
+
+func TestThatMalformedCoreResponsesReturnAnError(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"status":"OK","sessionHandle":"handle","userId":1}`))
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	}))
+
+	var response CoreGetSessionInformationResponse
+	err := q.SendGetRequestTyped("/recipe/session", map[string]string{
+		"sessionHandle": "handle",
+	}, &response, nil)
+
+	var shapeError CoreResponseShapeError
+	assert.True(t, errors.As(err, &shapeError))
+	assert.Equal(t, "/recipe/session", shapeError.Path)
+	assert.Equal(t, "userId", shapeError.Field)
+}
@@ -0,0 +1,613 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+/*
+The types below are the request bodies and responses of the core APIs that are called using the
+typed Send*RequestTyped functions. The query parameters of GET requests are passed as a map.
+
+The users, sessions and tenants in the responses have the same fields as the ones in the models of
+the recipes, so that they can be converted to them directly.
+*/
+
+// CoreStatusResponse is the response of the core APIs that only return a status
+type CoreStatusResponse struct {
+	Status string `json:"status"`
+}
+
+// session
+
+type CoreSession struct {
+	Handle                string                 `json:"handle"`
+	UserID                string                 `json:"userId"`
+	UserDataInAccessToken map[string]interface{} `json:"userDataInJWT"`
+	ExpiryTime            uint64                 `json:"expiryTime"`
+	TenantId              string                 `json:"tenantId"`
+}
+
+type CoreSessionToken struct {
+	Token       string `json:"token"`
+	Expiry      uint64 `json:"expiry"`
+	CreatedTime uint64 `json:"createdTime"`
+}
+
+type CoreCreateSessionRequest struct {
+	UserId               string                 `json:"userId"`
+	UserDataInJWT        map[string]interface{} `json:"userDataInJWT"`
+	UserDataInDatabase   map[string]interface{} `json:"userDataInDatabase"`
+	EnableAntiCsrf       bool                   `json:"enableAntiCsrf"`
+	UseDynamicSigningKey bool                   `json:"useDynamicSigningKey"`
+}
+
+type CoreCreateSessionResponse struct {
+	Session       CoreSession      `json:"session" cdi:"required"`
+	AccessToken   CoreSessionToken `json:"accessToken" cdi:"required"`
+	RefreshToken  CoreSessionToken `json:"refreshToken" cdi:"required"`
+	AntiCsrfToken *string          `json:"antiCsrfToken"`
+}
+
+type CoreVerifySessionRequest struct {
+	AccessToken     string  `json:"accessToken"`
+	AntiCsrfToken   *string `json:"antiCsrfToken,omitempty"`
+	DoAntiCsrfCheck bool    `json:"doAntiCsrfCheck"`
+	EnableAntiCsrf  bool    `json:"enableAntiCsrf"`
+	CheckDatabase   bool    `json:"checkDatabase"`
+}
+
+type CoreVerifySessionResponse struct {
+	Status      string           `json:"status"`
+	Message     string           `json:"message"`
+	Session     CoreSession      `json:"session" cdi:"required"`
+	AccessToken CoreSessionToken `json:"accessToken"`
+}
+
+type CoreGetSessionInformationResponse struct {
+	Status             string                 `json:"status"`
+	SessionHandle      string                 `json:"sessionHandle" cdi:"required"`
+	UserId             string                 `json:"userId" cdi:"required"`
+	UserDataInDatabase map[string]interface{} `json:"userDataInDatabase" cdi:"required"`
+	Expiry             uint64                 `json:"expiry" cdi:"required"`
+	TimeCreated        uint64                 `json:"timeCreated" cdi:"required"`
+	UserDataInJWT      map[string]interface{} `json:"userDataInJWT" cdi:"required"`
+	TenantId           string                 `json:"tenantId" cdi:"required"`
+}
+
+type CoreRefreshSessionRequest struct {
+	RefreshToken         string  `json:"refreshToken"`
+	AntiCsrfToken        *string `json:"antiCsrfToken,omitempty"`
+	EnableAntiCsrf       bool    `json:"enableAntiCsrf"`
+	UseDynamicSigningKey bool    `json:"useDynamicSigningKey"`
+}
+
+type CoreRefreshSessionResponse struct {
+	Status        string           `json:"status"`
+	Message       string           `json:"message"`
+	Session       CoreSession      `json:"session" cdi:"required"`
+	AccessToken   CoreSessionToken `json:"accessToken" cdi:"required"`
+	RefreshToken  CoreSessionToken `json:"refreshToken" cdi:"required"`
+	AntiCsrfToken *string          `json:"antiCsrfToken"`
+}
+
+type CoreRevokeAllSessionsForUserRequest struct {
+	UserId                 string `json:"userId"`
+	RevokeAcrossAllTenants *bool  `json:"revokeAcrossAllTenants,omitempty"`
+}
+
+type CoreRevokeSessionsRequest struct {
+	SessionHandles []string `json:"sessionHandles"`
+}
+
+type CoreRevokeSessionsResponse struct {
+	SessionHandlesRevoked []string `json:"sessionHandlesRevoked" cdi:"required"`
+}
+
+type CoreGetAllSessionHandlesForUserResponse struct {
+	SessionHandles []string `json:"sessionHandles" cdi:"required"`
+}
+
+type CoreUpdateSessionDataInDatabaseRequest struct {
+	SessionHandle      string                 `json:"sessionHandle"`
+	UserDataInDatabase map[string]interface{} `json:"userDataInDatabase"`
+}
+
+type CoreUpdateAccessTokenPayloadRequest struct {
+	SessionHandle string                 `json:"sessionHandle"`
+	UserDataInJWT map[string]interface{} `json:"userDataInJWT"`
+}
+
+type CoreRegenerateAccessTokenRequest struct {
+	AccessToken   string                 `json:"accessToken"`
+	UserDataInJWT map[string]interface{} `json:"userDataInJWT"`
+}
+
+type CoreRegenerateAccessTokenResponse struct {
+	Status      string           `json:"status"`
+	Session     CoreSession      `json:"session"`
+	AccessToken CoreSessionToken `json:"accessToken"`
+}
+
+// emailpassword
+
+type CoreEmailPasswordUser struct {
+	ID         string   `json:"id"`
+	Email      string   `json:"email"`
+	TimeJoined uint64   `json:"timeJoined"`
+	TenantIds  []string `json:"tenantIds"`
+}
+
+// CoreEmailPasswordUserResponse is the response of the core APIs that return an emailpassword user
+type CoreEmailPasswordUserResponse struct {
+	Status string                 `json:"status"`
+	User   *CoreEmailPasswordUser `json:"user" cdi:"required"`
+}
+
+type CoreSignUpOrSignInRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type CoreCreateResetPasswordTokenRequest struct {
+	UserId string `json:"userId"`
+}
+
+type CoreCreateResetPasswordTokenResponse struct {
+	Status string `json:"status"`
+	Token  string `json:"token" cdi:"required"`
+}
+
+type CoreResetPasswordUsingTokenRequest struct {
+	Method      string `json:"method"`
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+type CoreResetPasswordUsingTokenResponse struct {
+	Status string `json:"status"`
+	// UserId is only sent by the core for CDI >= 2.12
+	UserId *string `json:"userId"`
+}
+
+type CoreUpdateEmailOrPasswordRequest struct {
+	UserId   string  `json:"userId"`
+	Email    *string `json:"email,omitempty"`
+	Password *string `json:"password,omitempty"`
+}
+
+// emailverification
+
+type CoreCreateEmailVerificationTokenRequest struct {
+	UserId string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+type CoreCreateEmailVerificationTokenResponse struct {
+	Status string `json:"status"`
+	Token  string `json:"token" cdi:"required"`
+}
+
+type CoreVerifyEmailUsingTokenRequest struct {
+	Method string `json:"method"`
+	Token  string `json:"token"`
+}
+
+type CoreVerifyEmailUsingTokenResponse struct {
+	Status string `json:"status"`
+	UserId string `json:"userId" cdi:"required"`
+	Email  string `json:"email" cdi:"required"`
+}
+
+type CoreIsEmailVerifiedResponse struct {
+	IsVerified bool `json:"isVerified" cdi:"required"`
+}
+
+// CoreUserEmailRequest is the request to revoke the email verification tokens of a user, or to unverify their email
+type CoreUserEmailRequest struct {
+	UserId string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+// jwt
+
+type CoreJsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
+
+type CoreCreateJWTRequest struct {
+	Payload             map[string]interface{} `json:"payload"`
+	Validity            uint64                 `json:"validity"`
+	Algorithm           string                 `json:"algorithm"`
+	JwksDomain          string                 `json:"jwksDomain"`
+	UseStaticSigningKey bool                   `json:"useStaticSigningKey"`
+}
+
+type CoreCreateJWTResponse struct {
+	Status string `json:"status"`
+	Jwt    string `json:"jwt" cdi:"required"`
+}
+
+type CoreGetJWKSResponse struct {
+	Keys []CoreJsonWebKey `json:"keys" cdi:"required"`
+}
+
+// multitenancy
+
+type CoreTenant struct {
+	TenantId      string `json:"tenantId"`
+	EmailPassword struct {
+		Enabled bool `json:"enabled"`
+	} `json:"emailPassword"`
+	Passwordless struct {
+		Enabled bool `json:"enabled"`
+	} `json:"passwordless"`
+	ThirdParty struct {
+		Enabled bool `json:"enabled"`
+		// Providers are decoded by the thirdparty recipe, since their shape is defined there
+		Providers []map[string]interface{} `json:"providers"`
+	} `json:"thirdParty"`
+	CoreConfig map[string]interface{} `json:"coreConfig"`
+}
+
+type CoreCreateOrUpdateTenantRequest struct {
+	TenantId             string                 `json:"tenantId"`
+	EmailPasswordEnabled *bool                  `json:"emailPasswordEnabled,omitempty"`
+	PasswordlessEnabled  *bool                  `json:"passwordlessEnabled,omitempty"`
+	ThirdPartyEnabled    *bool                  `json:"thirdPartyEnabled,omitempty"`
+	CoreConfig           map[string]interface{} `json:"coreConfig,omitempty"`
+}
+
+type CoreCreateOrUpdateTenantResponse struct {
+	Status     string `json:"status"`
+	CreatedNew bool   `json:"createdNew" cdi:"required"`
+}
+
+type CoreDeleteTenantRequest struct {
+	TenantId string `json:"tenantId"`
+}
+
+type CoreDeleteTenantResponse struct {
+	Status   string `json:"status"`
+	DidExist bool   `json:"didExist" cdi:"required"`
+}
+
+type CoreGetTenantResponse struct {
+	Status string `json:"status"`
+	CoreTenant
+}
+
+type CoreListAllTenantsResponse struct {
+	Tenants []CoreTenant `json:"tenants"`
+}
+
+type CoreCreateOrUpdateThirdPartyConfigRequest struct {
+	Config         map[string]interface{} `json:"config"`
+	SkipValidation *bool                  `json:"skipValidation,omitempty"`
+}
+
+type CoreCreateOrUpdateThirdPartyConfigResponse struct {
+	CreatedNew bool `json:"createdNew" cdi:"required"`
+}
+
+type CoreDeleteThirdPartyConfigRequest struct {
+	ThirdPartyId string `json:"thirdPartyId"`
+}
+
+type CoreDeleteThirdPartyConfigResponse struct {
+	DidConfigExist bool `json:"didConfigExist" cdi:"required"`
+}
+
+// CoreTenantUserRequest is the request to associate a user with a tenant, or to disassociate it
+type CoreTenantUserRequest struct {
+	UserId string `json:"userId"`
+}
+
+type CoreAssociateUserToTenantResponse struct {
+	WasAlreadyAssociated bool `json:"wasAlreadyAssociated" cdi:"required"`
+}
+
+type CoreDisassociateUserFromTenantResponse struct {
+	WasAssociated bool `json:"wasAssociated" cdi:"required"`
+}
+
+// passwordless
+
+type CorePasswordlessUser struct {
+	ID          string   `json:"id"`
+	Email       *string  `json:"email"`
+	PhoneNumber *string  `json:"phoneNumber"`
+	TimeJoined  uint64   `json:"timeJoined"`
+	TenantIds   []string `json:"tenantIds"`
+}
+
+// CorePasswordlessUserResponse is the response of the core APIs that return a passwordless user
+type CorePasswordlessUserResponse struct {
+	Status string                `json:"status"`
+	User   *CorePasswordlessUser `json:"user" cdi:"required"`
+}
+
+type CorePasswordlessCode struct {
+	CodeID       string `json:"codeId" cdi:"required"`
+	TimeCreated  uint64 `json:"timeCreated" cdi:"required"`
+	CodeLifetime uint64 `json:"codeLifetime" cdi:"required"`
+}
+
+type CorePasswordlessDevice struct {
+	PreAuthSessionID            string                 `json:"preAuthSessionId" cdi:"required"`
+	FailedCodeInputAttemptCount int                    `json:"failedCodeInputAttemptCount" cdi:"required"`
+	Email                       *string                `json:"email"`
+	PhoneNumber                 *string                `json:"phoneNumber"`
+	Codes                       []CorePasswordlessCode `json:"codes" cdi:"required"`
+}
+
+type CoreCreatePasswordlessCodeRequest struct {
+	Email         *string `json:"email,omitempty"`
+	PhoneNumber   *string `json:"phoneNumber,omitempty"`
+	UserInputCode *string `json:"userInputCode,omitempty"`
+}
+
+type CoreCreateNewPasswordlessCodeForDeviceRequest struct {
+	DeviceId      string  `json:"deviceId"`
+	UserInputCode *string `json:"userInputCode,omitempty"`
+}
+
+// CorePasswordlessCodeResponse is the response of the core when creating a new code
+type CorePasswordlessCodeResponse struct {
+	Status           string `json:"status"`
+	PreAuthSessionID string `json:"preAuthSessionId" cdi:"required"`
+	CodeID           string `json:"codeId" cdi:"required"`
+	DeviceID         string `json:"deviceId" cdi:"required"`
+	UserInputCode    string `json:"userInputCode" cdi:"required"`
+	LinkCode         string `json:"linkCode" cdi:"required"`
+	CodeLifetime     uint64 `json:"codeLifetime" cdi:"required"`
+	TimeCreated      uint64 `json:"timeCreated" cdi:"required"`
+}
+
+type CoreConsumePasswordlessCodeRequest struct {
+	PreAuthSessionId string  `json:"preAuthSessionId"`
+	UserInputCode    *string `json:"userInputCode,omitempty"`
+	DeviceId         *string `json:"deviceId,omitempty"`
+	LinkCode         *string `json:"linkCode,omitempty"`
+}
+
+type CoreConsumePasswordlessCodeResponse struct {
+	Status                      string               `json:"status"`
+	CreatedNewUser              bool                 `json:"createdNewUser" cdi:"required"`
+	User                        CorePasswordlessUser `json:"user" cdi:"required"`
+	FailedCodeInputAttemptCount int                  `json:"failedCodeInputAttemptCount"`
+	MaximumCodeInputAttempts    int                  `json:"maximumCodeInputAttempts"`
+}
+
+type CoreListPasswordlessCodesResponse struct {
+	Devices []CorePasswordlessDevice `json:"devices" cdi:"required"`
+}
+
+type CoreUpdatePasswordlessUserRequest struct {
+	UserId      string  `json:"userId"`
+	Email       *string `json:"email,omitempty"`
+	PhoneNumber *string `json:"phoneNumber,omitempty"`
+}
+
+// CoreDeletePasswordlessUserEmailRequest removes the email of a user, which the core does if it is null
+type CoreDeletePasswordlessUserEmailRequest struct {
+	UserId string  `json:"userId"`
+	Email  *string `json:"email"`
+}
+
+// CoreDeletePasswordlessUserPhoneNumberRequest removes the phone number of a user, which the core does if it is null
+type CoreDeletePasswordlessUserPhoneNumberRequest struct {
+	UserId      string  `json:"userId"`
+	PhoneNumber *string `json:"phoneNumber"`
+}
+
+// CoreRevokePasswordlessCodesRequest revokes the codes of the email, or of the phone number if the email is nil
+type CoreRevokePasswordlessCodesRequest struct {
+	Email       *string `json:"email,omitempty"`
+	PhoneNumber *string `json:"phoneNumber,omitempty"`
+}
+
+type CoreRevokePasswordlessCodeRequest struct {
+	CodeId string `json:"codeId"`
+}
+
+// thirdparty
+
+type CoreThirdPartyUser struct {
+	ID         string `json:"id"`
+	TimeJoined uint64 `json:"timeJoined"`
+	Email      string `json:"email"`
+	ThirdParty struct {
+		ID     string `json:"id"`
+		UserID string `json:"userId"`
+	} `json:"thirdParty"`
+	TenantIds []string `json:"tenantIds"`
+}
+
+// CoreThirdPartyUserResponse is the response of the core APIs that return a thirdparty user
+type CoreThirdPartyUserResponse struct {
+	Status string              `json:"status"`
+	User   *CoreThirdPartyUser `json:"user" cdi:"required"`
+}
+
+type CoreThirdPartySignInUpRequest struct {
+	ThirdPartyId     string `json:"thirdPartyId"`
+	ThirdPartyUserId string `json:"thirdPartyUserId"`
+	Email            struct {
+		ID string `json:"id"`
+	} `json:"email"`
+}
+
+type CoreThirdPartySignInUpResponse struct {
+	Status         string             `json:"status"`
+	CreatedNewUser bool               `json:"createdNewUser" cdi:"required"`
+	User           CoreThirdPartyUser `json:"user" cdi:"required"`
+}
+
+type CoreThirdPartyUsersByEmailResponse struct {
+	Users []CoreThirdPartyUser `json:"users" cdi:"required"`
+}
+
+// usermetadata
+
+type CoreUpdateUserMetadataRequest struct {
+	UserId         string                 `json:"userId"`
+	MetadataUpdate map[string]interface{} `json:"metadataUpdate"`
+}
+
+type CoreUserMetadataResponse struct {
+	Metadata map[string]interface{} `json:"metadata" cdi:"required"`
+}
+
+type CoreClearUserMetadataRequest struct {
+	UserId string `json:"userId"`
+}
+
+// userroles
+
+// CoreUserRoleRequest is the request to add a role to a user, or to remove it
+type CoreUserRoleRequest struct {
+	UserId string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+type CoreAddRoleToUserResponse struct {
+	Status                 string `json:"status"`
+	DidUserAlreadyHaveRole bool   `json:"didUserAlreadyHaveRole" cdi:"required"`
+}
+
+type CoreRemoveUserRoleResponse struct {
+	Status          string `json:"status"`
+	DidUserHaveRole bool   `json:"didUserHaveRole" cdi:"required"`
+}
+
+// CoreRolesResponse is the response of the core APIs that return a list of roles
+type CoreRolesResponse struct {
+	Roles []string `json:"roles" cdi:"required"`
+}
+
+type CoreGetUsersThatHaveRoleResponse struct {
+	Status string   `json:"status"`
+	Users  []string `json:"users" cdi:"required"`
+}
+
+// CoreRolePermissionsRequest is the request to create a role or add permissions to it, or to remove permissions from it
+type CoreRolePermissionsRequest struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+type CoreCreateNewRoleOrAddPermissionsResponse struct {
+	CreatedNewRole bool `json:"createdNewRole" cdi:"required"`
+}
+
+type CoreGetPermissionsForRoleResponse struct {
+	Status      string   `json:"status"`
+	Permissions []string `json:"permissions" cdi:"required"`
+}
+
+type CoreDeleteRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type CoreDeleteRoleResponse struct {
+	DidRoleExist bool `json:"didRoleExist" cdi:"required"`
+}
+
+// user id mapping
+
+type CoreCreateUserIdMappingRequest struct {
+	SuperTokensUserId  string  `json:"superTokensUserId"`
+	ExternalUserId     string  `json:"externalUserId"`
+	ExternalUserIdInfo *string `json:"externalUserIdInfo,omitempty"`
+	Force              *bool   `json:"force,omitempty"`
+}
+
+type CoreCreateUserIdMappingResponse struct {
+	Status                     string `json:"status"`
+	DoesSuperTokensUserIdExist bool   `json:"doesSuperTokensUserIdExist"`
+	DoesExternalUserIdExist    bool   `json:"doesExternalUserIdExist"`
+}
+
+type CoreGetUserIdMappingResponse struct {
+	Status             string  `json:"status"`
+	SuperTokensUserId  string  `json:"superTokensUserId" cdi:"required"`
+	ExternalUserId     string  `json:"externalUserId" cdi:"required"`
+	ExternalUserIdInfo *string `json:"externalUserIdInfo"`
+}
+
+type CoreDeleteUserIdMappingRequest struct {
+	UserId     string  `json:"userId"`
+	UserIdType *string `json:"userIdType,omitempty"`
+	Force      *bool   `json:"force,omitempty"`
+}
+
+type CoreDeleteUserIdMappingResponse struct {
+	DidMappingExist bool `json:"didMappingExist" cdi:"required"`
+}
+
+type CoreUpdateOrDeleteUserIdMappingInfoRequest struct {
+	UserId     string  `json:"userId"`
+	UserIdType *string `json:"userIdType,omitempty"`
+	// The info is deleted if ExternalUserIdInfo is nil
+	ExternalUserIdInfo *string `json:"externalUserIdInfo"`
+}
+
+// users
+
+type CoreUserCountResponse struct {
+	Count float64 `json:"count" cdi:"required"`
+}
+
+type CoreDeleteUserRequest struct {
+	UserId string `json:"userId"`
+}
+
+type CoreTelemetryResponse struct {
+	Exists      bool   `json:"exists" cdi:"required"`
+	TelemetryId string `json:"telemetryId"`
+}
+
+// dashboard
+
+// CoreDashboardSessionRequest is the request to verify a session of the dashboard
+type CoreDashboardSessionRequest struct {
+	SessionId string `json:"sessionId"`
+}
+
+type CoreVerifyDashboardSessionResponse struct {
+	Status string `json:"status"`
+	// Email is not required so that the dashboard can still be accessed if there are no admins
+	Email string `json:"email"`
+}
+
+type CoreDashboardSignInRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type CoreDashboardSignInResponse struct {
+	Status    string `json:"status"`
+	SessionId string `json:"sessionId" cdi:"required"`
+	// Message is only sent by the core if the status is USER_SUSPENDED_ERROR
+	Message string `json:"message"`
+}
+
+type CoreSearchTagsResponse struct {
+	Tags []string `json:"tags" cdi:"required"`
+}
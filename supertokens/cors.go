@@ -0,0 +1,198 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// This function is required to be here because calling multitenancy recipe from this module causes cyclic dependency
+// this function is initialized by the init function in multitenancy recipe
+var GetAllowedDomainsForTenantIdFuncFromUsingMultitenancyRecipe func(tenantId string, userContext UserContext) ([]string, error)
+
+var defaultCORSAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions}
+
+// CORSConfig configures the CORS handling of the middleware. The origin of the website (see AppInfo) is
+// always allowed. Credentials are allowed for the website, AllowedOrigins, and the domains of tenants if the
+// tenant is returned by GetTenantId.
+type CORSConfig struct {
+	// AllowedOrigins are allowed in addition to the origin of the website, for example "https://admin.example.com"
+	AllowedOrigins []string
+	// AllowTenantDomains allows origins whose domain is one of the allowed domains of the tenant of the request
+	// (see GetAllowedDomainsForTenantId in the multitenancy recipe)
+	AllowTenantDomains bool
+	// GetTenantId returns the tenant of a request for AllowTenantDomains. By default, this is the tenant in the
+	// path of requests to the APIs of the recipes, and the public tenant for all other requests. Since the path
+	// is chosen by the caller and session cookies are shared by all tenants, credentials are only allowed for
+	// the domains of tenants that are returned by GetTenantId.
+	GetTenantId func(req *http.Request, userContext UserContext) (string, error)
+	// AllowedHeaders are allowed in addition to Content-Type and the headers used by the recipes (see GetAllCORSHeaders)
+	AllowedHeaders []string
+	// AllowedMethods defaults to GET, POST, PUT, PATCH, DELETE and OPTIONS
+	AllowedMethods []string
+	// MaxAge is how long the response to a preflight request can be cached for. Not sent if nil.
+	MaxAge *time.Duration
+}
+
+// handleCORS adds the CORS headers to the response, and answers preflight requests from allowed origins
+// for the APIs of the recipes. Other preflight requests are passed on to the handler of the app. It returns
+// true if the request was answered.
+func (s *Instance) handleCORS(res http.ResponseWriter, req *http.Request, path NormalisedURLPath, userContext UserContext) bool {
+	res.Header().Add("Vary", "Origin")
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	isPreflight := req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != ""
+
+	allowed, allowCredentials, err := s.isCORSOriginAllowed(origin, req, path, isPreflight, userContext)
+	if err != nil {
+		LogWarn(userContext, "checking whether the origin is allowed failed", "origin", origin, "error", err.Error())
+		allowed = false
+	}
+	if !allowed {
+		LogDebugMessage("cors: origin is not allowed: " + origin)
+	} else {
+		res.Header().Set("Access-Control-Allow-Origin", origin)
+		if allowCredentials {
+			res.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
+
+	if !isPreflight || !allowed {
+		return false
+	}
+	methods := s.cors.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSAllowedMethods
+	}
+	res.Header().Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+	res.Header().Set("Access-Control-Allow-Headers", strings.Join(s.getCORSAllowedHeaders(), ", "))
+	if s.cors.MaxAge != nil {
+		res.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(s.cors.MaxAge.Seconds())))
+	}
+	if !strings.HasPrefix(path.GetAsStringDangerous(), s.AppInfo.APIBasePath.GetAsStringDangerous()) {
+		return false
+	}
+	res.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+func (s *Instance) getCORSAllowedHeaders() []string {
+	headers := append([]string{}, s.GetAllCORSHeaders()...)
+	// the order of GetAllCORSHeaders is random
+	sort.Strings(headers)
+	headers = append([]string{"Content-Type"}, headers...)
+	return append(headers, s.cors.AllowedHeaders...)
+}
+
+// isCORSOriginAllowed returns whether the origin is allowed, and whether credentials are allowed for it
+func (s *Instance) isCORSOriginAllowed(origin string, req *http.Request, path NormalisedURLPath, isPreflight bool, userContext UserContext) (bool, bool, error) {
+	normalisedOrigin, err := NewNormalisedURLDomain(origin)
+	if err != nil {
+		// not an origin that we can compare, e.g. "null"
+		return false, false, nil
+	}
+
+	websiteOrigin, err := s.AppInfo.GetOrigin(req, userContext)
+	if err != nil {
+		return false, false, err
+	}
+	if websiteOrigin.GetAsStringDangerous() == normalisedOrigin.GetAsStringDangerous() {
+		return true, true, nil
+	}
+	for _, allowedOrigin := range s.corsAllowedOrigins {
+		if allowedOrigin.GetAsStringDangerous() == normalisedOrigin.GetAsStringDangerous() {
+			return true, true, nil
+		}
+	}
+
+	if !s.cors.AllowTenantDomains || GetAllowedDomainsForTenantIdFuncFromUsingMultitenancyRecipe == nil {
+		return false, false, nil
+	}
+	tenantId, err := s.getTenantIdForCORS(req, path, isPreflight, userContext)
+	if err != nil {
+		return false, false, err
+	}
+	domains, err := GetAllowedDomainsForTenantIdFuncFromUsingMultitenancyRecipe(tenantId, userContext)
+	if err != nil {
+		return false, false, err
+	}
+	for _, domain := range domains {
+		// domains without a scheme use https (or http for localhost and IP addresses), and the scheme
+		// and port have to match the ones of the origin
+		normalisedDomain, err := NewNormalisedURLDomain(strings.TrimSpace(domain))
+		if err != nil {
+			LogDebugMessage("cors: ignoring invalid allowed domain of the tenant: " + domain)
+			continue
+		}
+		if normalisedDomain.GetAsStringDangerous() == normalisedOrigin.GetAsStringDangerous() {
+			// the tenant in the path can be chosen by the caller, so it must not grant access to the session
+			// of a user of another tenant
+			return true, s.cors.GetTenantId != nil, nil
+		}
+	}
+	return false, false, nil
+}
+
+func (s *Instance) getTenantIdForCORS(req *http.Request, path NormalisedURLPath, isPreflight bool, userContext UserContext) (string, error) {
+	if s.cors.GetTenantId != nil {
+		return s.cors.GetTenantId(req, userContext)
+	}
+	if !strings.HasPrefix(path.GetAsStringDangerous(), s.AppInfo.APIBasePath.GetAsStringDangerous()) {
+		return DefaultTenantId, nil
+	}
+	method := req.Method
+	if isPreflight {
+		method = req.Header.Get("Access-Control-Request-Method")
+	}
+	for _, recipeModule := range s.RecipeModules {
+		id, tenantId, err := recipeModule.ReturnAPIIdIfCanHandleRequest(path, method, userContext)
+		if err != nil {
+			return "", err
+		}
+		if id == nil {
+			continue
+		}
+		if GetTenantIdFuncFromUsingMultitenancyRecipe != nil {
+			return GetTenantIdFuncFromUsingMultitenancyRecipe(tenantId, userContext)
+		}
+		return tenantId, nil
+	}
+	return DefaultTenantId, nil
+}
+
+// normaliseCORSAllowedOrigins normalises the AllowedOrigins of the config, so that invalid ones are
+// reported when the instance is created
+func normaliseCORSAllowedOrigins(config *CORSConfig) ([]NormalisedURLDomain, error) {
+	result := []NormalisedURLDomain{}
+	if config == nil {
+		return result, nil
+	}
+	for _, allowedOrigin := range config.AllowedOrigins {
+		normalisedAllowedOrigin, err := NewNormalisedURLDomain(allowedOrigin)
+		if err != nil {
+			return nil, fmt.Errorf("invalid origin in CORS.AllowedOrigins: %s: %w", allowedOrigin, err)
+		}
+		result = append(result, normalisedAllowedOrigin)
+	}
+	return result, nil
+}
@@ -0,0 +1,146 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSTestHandler(t *testing.T, cors *CORSConfig) http.Handler {
+	ResetForTest()
+	instance, err := New(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "http://localhost:3000",
+			APIDomain:     "http://localhost:3001",
+		},
+		RecipeList: []Recipe{openAPITestRecipe},
+		CORS:       cors,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return instance.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+}
+
+func sendCORSTestRequest(handler http.Handler, method string, path string, origin string, preflightMethod string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if preflightMethod != "" {
+		req.Header.Set("Access-Control-Request-Method", preflightMethod)
+	}
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	return res
+}
+
+func TestThatPreflightRequestsFromTheWebsiteAreAnswered(t *testing.T) {
+	maxAge := 10 * time.Minute
+	handler := newCORSTestHandler(t, &CORSConfig{
+		AllowedHeaders: []string{"X-Custom"},
+		MaxAge:         &maxAge,
+	})
+
+	res := sendCORSTestRequest(handler, http.MethodOptions, "/auth/signin", "http://localhost:3000", http.MethodPost)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "http://localhost:3000", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", res.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, OPTIONS", res.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Content-Type, fdi-version, rid, X-Custom", res.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "600", res.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "Origin", res.Header().Get("Vary"))
+
+	// preflight requests for the APIs of the app are passed on to it with the CORS headers
+	res = sendCORSTestRequest(handler, http.MethodOptions, "/sessioninfo", "http://localhost:3000", http.MethodGet)
+	assert.Equal(t, http.StatusTeapot, res.Code)
+	assert.Equal(t, "http://localhost:3000", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST, PUT, PATCH, DELETE, OPTIONS", res.Header().Get("Access-Control-Allow-Methods"))
+}
+
+func TestThatOtherOriginsAreNotAllowedUnlessConfigured(t *testing.T) {
+	handler := newCORSTestHandler(t, &CORSConfig{
+		AllowedOrigins: []string{"https://admin.example.com"},
+	})
+
+	// preflight requests from other origins are not answered
+	res := sendCORSTestRequest(handler, http.MethodOptions, "/auth/signin", "https://evil.example.com", http.MethodPost)
+	assert.NotEqual(t, http.StatusNoContent, res.Code)
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Methods"))
+
+	res = sendCORSTestRequest(handler, http.MethodOptions, "/auth/signin", "https://admin.example.com", http.MethodPost)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "https://admin.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+
+	res = sendCORSTestRequest(handler, http.MethodOptions, "/auth/signin", "http://admin.example.com", http.MethodPost)
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+
+	res = sendCORSTestRequest(handler, http.MethodOptions, "/auth/signin", "null", http.MethodPost)
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestThatCORSHeadersAreAddedToOtherRequests(t *testing.T) {
+	handler := newCORSTestHandler(t, &CORSConfig{})
+
+	res := sendCORSTestRequest(handler, http.MethodGet, "/sessioninfo", "http://localhost:3000", "")
+	assert.Equal(t, http.StatusTeapot, res.Code)
+	assert.Equal(t, "http://localhost:3000", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", res.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Methods"))
+
+	// OPTIONS requests that are not preflight requests are not answered
+	res = sendCORSTestRequest(handler, http.MethodOptions, "/sessioninfo", "http://localhost:3000", "")
+	assert.Equal(t, http.StatusTeapot, res.Code)
+
+	res = sendCORSTestRequest(handler, http.MethodGet, "/sessioninfo", "", "")
+	assert.Equal(t, http.StatusTeapot, res.Code)
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestThatCORSIsNotHandledIfNotEnabled(t *testing.T) {
+	handler := newCORSTestHandler(t, nil)
+
+	res := sendCORSTestRequest(handler, http.MethodOptions, "/sessioninfo", "http://localhost:3000", http.MethodGet)
+	assert.Equal(t, http.StatusTeapot, res.Code)
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestThatInvalidAllowedOriginsAreReportedWhenCreatingTheInstance(t *testing.T) {
+	ResetForTest()
+	_, err := New(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "http://localhost:3000",
+			APIDomain:     "http://localhost:3001",
+		},
+		RecipeList: []Recipe{openAPITestRecipe},
+		CORS: &CORSConfig{
+			AllowedOrigins: []string{"/admin"},
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "CORS.AllowedOrigins")
+}
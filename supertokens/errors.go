@@ -15,6 +15,79 @@
 
 package supertokens
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is a stable identifier of a kind of error returned by the SDK. Unlike error messages, error
+// codes do not change between versions, so they can be used to branch on errors (see GetErrorCode).
+type ErrorCode string
+
+// The error codes of the errors in this package. The recipes define the codes of their own errors.
+const (
+	ErrorCodeBadInput          ErrorCode = "BAD_INPUT"
+	ErrorCodeNotInitialised    ErrorCode = "NOT_INITIALISED"
+	ErrorCodeCore              ErrorCode = "CORE_ERROR"
+	ErrorCodeCoreUnavailable   ErrorCode = "CORE_UNAVAILABLE"
+	ErrorCodeCoreResponseShape ErrorCode = "CORE_RESPONSE_SHAPE_ERROR"
+)
+
+// CodedError is implemented by all the errors of the SDK that have an error code
+type CodedError interface {
+	error
+	ErrorCode() ErrorCode
+}
+
+// Sentinel errors for the error codes of this package, to be used with errors.Is. An error matches the
+// sentinel error of its error code, even if it is wrapped. For example:
+//
+//	if errors.Is(err, supertokens.ErrCoreUnavailable) {
+//		// retry later
+//	}
+var (
+	ErrBadInput          = NewSentinelError(ErrorCodeBadInput)
+	ErrNotInitialised    = NewSentinelError(ErrorCodeNotInitialised)
+	ErrCore              = NewSentinelError(ErrorCodeCore)
+	ErrCoreUnavailable   = NewSentinelError(ErrorCodeCoreUnavailable)
+	ErrCoreResponseShape = NewSentinelError(ErrorCodeCoreResponseShape)
+)
+
+type sentinelError struct {
+	code ErrorCode
+}
+
+func (err *sentinelError) Error() string {
+	return "supertokens: " + string(err.code)
+}
+
+func (err *sentinelError) ErrorCode() ErrorCode {
+	return err.code
+}
+
+// NewSentinelError creates the sentinel error for an error code. It is used by the recipes to define the
+// sentinel errors of their error codes.
+func NewSentinelError(code ErrorCode) error {
+	return &sentinelError{code: code}
+}
+
+// IsSentinelErrorFor returns true if target is the sentinel error for the error code. Errors with an error
+// code use it to implement the Is method that errors.Is calls.
+func IsSentinelErrorFor(target error, code ErrorCode) bool {
+	sentinel, ok := target.(*sentinelError)
+	return ok && sentinel.code == code
+}
+
+// GetErrorCode returns the error code of the first error in the chain of err that has one, or an empty
+// string if there is none
+func GetErrorCode(err error) ErrorCode {
+	var codedError CodedError
+	if errors.As(err, &codedError) {
+		return codedError.ErrorCode()
+	}
+	return ""
+}
+
 // BadInputError used for non specific exceptions
 type BadInputError struct {
 	Msg string
@@ -23,3 +96,76 @@ type BadInputError struct {
 func (err BadInputError) Error() string {
 	return err.Msg
 }
+
+func (err BadInputError) ErrorCode() ErrorCode {
+	return ErrorCodeBadInput
+}
+
+func (err BadInputError) Is(target error) bool {
+	return IsSentinelErrorFor(target, err.ErrorCode())
+}
+
+// NotInitialisedError is returned when the SDK, or a recipe, is used before it is initialised
+type NotInitialisedError struct {
+	Msg string
+}
+
+func (err NotInitialisedError) Error() string {
+	return err.Msg
+}
+
+func (err NotInitialisedError) ErrorCode() ErrorCode {
+	return ErrorCodeNotInitialised
+}
+
+func (err NotInitialisedError) Is(target error) bool {
+	return IsSentinelErrorFor(target, err.ErrorCode())
+}
+
+// CoreError is returned when the core responds to a request with a status code other than 200, after
+// any retries. 5xx responses are only returned if all the hosts in ConnectionURI failed.
+type CoreError struct {
+	StatusCode int
+	// Path is the path of the request to the core, without the base path of the host
+	Path string
+	// Body is the body of the response
+	Body string
+}
+
+func (err CoreError) Error() string {
+	return fmt.Sprintf("SuperTokens core threw an error for a request to path: '%s' with status code: %v and message: %s", err.Path, err.StatusCode, err.Body)
+}
+
+func (err CoreError) ErrorCode() ErrorCode {
+	return ErrorCodeCore
+}
+
+func (err CoreError) Is(target error) bool {
+	return IsSentinelErrorFor(target, err.ErrorCode())
+}
+
+// CoreUnavailableError is returned when no host in ConnectionURI could be reached, for example because of
+// network errors. Err is the error of the last attempt, if any.
+type CoreUnavailableError struct {
+	Path string
+	Err  error
+}
+
+func (err CoreUnavailableError) Error() string {
+	if err.Err == nil {
+		return "no SuperTokens core available to query"
+	}
+	return fmt.Sprintf("SuperTokens core is not reachable for a request to path: '%s': %s", err.Path, err.Err.Error())
+}
+
+func (err CoreUnavailableError) Unwrap() error {
+	return err.Err
+}
+
+func (err CoreUnavailableError) ErrorCode() ErrorCode {
+	return ErrorCodeCoreUnavailable
+}
+
+func (err CoreUnavailableError) Is(target error) bool {
+	return IsSentinelErrorFor(target, err.ErrorCode())
+}
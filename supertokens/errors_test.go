@@ -0,0 +1,98 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatWrappedErrorsMatchTheSentinelErrorOfTheirCode(t *testing.T) {
+	err := fmt.Errorf("creating the user failed: %w", BadInputError{Msg: "invalid email"})
+
+	assert.True(t, errors.Is(err, ErrBadInput))
+	assert.False(t, errors.Is(err, ErrCore))
+	assert.Equal(t, ErrorCodeBadInput, GetErrorCode(err))
+	var badInputError BadInputError
+	assert.True(t, errors.As(err, &badInputError))
+	assert.Equal(t, "invalid email", badInputError.Msg)
+
+	assert.True(t, errors.Is(CoreResponseShapeError{}, ErrCoreResponseShape))
+	assert.True(t, errors.Is(NotInitialisedError{}, ErrNotInitialised))
+	assert.Equal(t, ErrorCode(""), GetErrorCode(errors.New("some error")))
+	assert.Equal(t, ErrorCode(""), GetErrorCode(nil))
+}
+
+func TestThatCoreUnavailableErrorsWrapTheCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := CoreUnavailableError{Path: "/recipe/session", Err: cause}
+
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, errors.Is(err, ErrCoreUnavailable))
+	assert.Equal(t, "SuperTokens core is not reachable for a request to path: '/recipe/session': connection refused", err.Error())
+	assert.Equal(t, "no SuperTokens core available to query", CoreUnavailableError{}.Error())
+}
+
+func TestThatTheErrorHandlerHandlesWrappedBadInputErrors(t *testing.T) {
+	instance := newOpenAPITestInstance(t, nil)
+	res := httptest.NewRecorder()
+
+	err := instance.ErrorHandler(fmt.Errorf("signing in failed: %w", BadInputError{Msg: "invalid email"}), httptest.NewRequest(http.MethodPost, "/auth/signin", nil), res, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "signing in failed: invalid email")
+}
+
+func TestThatCoreErrorsCanBeMatchedWithErrorsIsAndAs(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(400)
+		rw.Write([]byte("invalid session handle"))
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	}))
+
+	_, err := q.SendGetRequest("/recipe/session", map[string]string{}, nil)
+	assert.True(t, errors.Is(err, ErrCore))
+	assert.Equal(t, ErrorCodeCore, GetErrorCode(err))
+	var coreError CoreError
+	assert.True(t, errors.As(err, &coreError))
+	assert.Equal(t, 400, coreError.StatusCode)
+	assert.Equal(t, "/recipe/session", coreError.Path)
+	assert.Equal(t, "invalid session handle", coreError.Body)
+	assert.Equal(t, "SuperTokens core threw an error for a request to path: '/recipe/session' with status code: 400 and message: invalid session handle", err.Error())
+
+	// the core is not reachable once it is closed
+	testServer.Close()
+	_, err = q.SendGetRequest("/recipe/session", map[string]string{}, nil)
+	assert.True(t, errors.Is(err, ErrCoreUnavailable))
+	assert.False(t, errors.Is(err, ErrCore))
+	var coreUnavailableError CoreUnavailableError
+	assert.True(t, errors.As(err, &coreUnavailableError))
+	assert.Equal(t, "/recipe/session", coreUnavailableError.Path)
+	assert.Error(t, errors.Unwrap(err))
+}
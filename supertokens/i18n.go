@@ -0,0 +1,383 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MessageKey is a stable identifier of a user facing message. It is sent along with the message in the
+// responses of the APIs (for example as "errorKey" in form field errors), so that frontends can show their
+// own text for it.
+type MessageKey string
+
+// The keys of the messages of the recipes
+const (
+	MessageKeyFieldNotOptional        MessageKey = "FIELD_NOT_OPTIONAL"
+	MessageKeyEmailInvalid            MessageKey = "EMAIL_INVALID"
+	MessageKeyPhoneNumberInvalid      MessageKey = "PHONE_NUMBER_INVALID"
+	MessageKeyPasswordTooShort        MessageKey = "PASSWORD_TOO_SHORT"
+	MessageKeyPasswordTooLong         MessageKey = "PASSWORD_TOO_LONG"
+	MessageKeyPasswordMissingAlphabet MessageKey = "PASSWORD_MISSING_ALPHABET"
+	MessageKeyPasswordMissingNumber   MessageKey = "PASSWORD_MISSING_NUMBER"
+	MessageKeyWrongCredentials        MessageKey = "WRONG_CREDENTIALS"
+	MessageKeyInvalidClaim            MessageKey = "INVALID_CLAIM"
+	MessageKeyClaimValueDoesNotExist  MessageKey = "CLAIM_VALUE_DOES_NOT_EXIST"
+	MessageKeyClaimValueExpired       MessageKey = "CLAIM_VALUE_EXPIRED"
+	MessageKeyClaimWrongValue         MessageKey = "CLAIM_WRONG_VALUE"
+	MessageKeySessionLimitReached     MessageKey = "SESSION_LIMIT_REACHED"
+	MessageKeySessionImpersonated     MessageKey = "SESSION_IMPERSONATED"
+)
+
+// DefaultLocale is the locale of the messages of the SDK
+const DefaultLocale = "en"
+
+// defaultMessages are the messages of the SDK in the default locale. Messages returned by the default
+// validators are in this catalog, which is how their keys are found (see LocaliseMessage).
+var defaultMessages = map[MessageKey]string{
+	MessageKeyFieldNotOptional:        "Field is not optional",
+	MessageKeyEmailInvalid:            "Email is invalid",
+	MessageKeyPhoneNumberInvalid:      "Phone number is invalid",
+	MessageKeyPasswordTooShort:        "Password must contain at least 8 characters, including a number",
+	MessageKeyPasswordTooLong:         "Password's length must be lesser than 100 characters",
+	MessageKeyPasswordMissingAlphabet: "Password must contain at least one alphabet",
+	MessageKeyPasswordMissingNumber:   "Password must contain at least one number",
+	MessageKeyWrongCredentials:        "Incorrect email and password combination",
+	MessageKeyInvalidClaim:            "invalid claim",
+	MessageKeyClaimValueDoesNotExist:  "value does not exist",
+	MessageKeyClaimValueExpired:       "expired",
+	MessageKeyClaimWrongValue:         "wrong value",
+	MessageKeySessionLimitReached:     "You have reached the maximum number of sessions. Please sign out on another device first.",
+	MessageKeySessionImpersonated:     "session is impersonated",
+}
+
+var defaultMessageKeys = func() map[string]MessageKey {
+	keys := map[string]MessageKey{}
+	for key, message := range defaultMessages {
+		keys[message] = key
+	}
+	return keys
+}()
+
+// GetDefaultMessage returns the message for the key in the default locale, or an empty string if the key
+// is not one of the SDK
+func GetDefaultMessage(key MessageKey) string {
+	return defaultMessages[key]
+}
+
+// I18nConfig configures the translations of the messages that the APIs send to the frontend
+type I18nConfig struct {
+	// Translations are the messages by locale (e.g. "de" or "pt-BR") and message key. They can contain the
+	// keys of the SDK (see MessageKey) and custom keys, which can be returned by custom form field validators
+	// instead of a message. Messages that are missing from a translation are sent in the default locale.
+	Translations map[string]map[MessageKey]string
+	// DefaultLocale is used if the locale can not be resolved from the request. Defaults to "en".
+	DefaultLocale *string
+	// GetLocaleForTenant returns the locale that is used for all requests of a tenant, or nil to use the
+	// Accept-Language header of the request
+	GetLocaleForTenant func(tenantId string, userContext UserContext) (*string, error)
+}
+
+type normalisedI18nConfig struct {
+	translations       map[string]map[MessageKey]string
+	defaultLocale      string
+	getLocaleForTenant func(tenantId string, userContext UserContext) (*string, error)
+}
+
+func normaliseI18nConfig(config *I18nConfig) *normalisedI18nConfig {
+	normalised := &normalisedI18nConfig{
+		translations:  map[string]map[MessageKey]string{},
+		defaultLocale: DefaultLocale,
+	}
+	if config == nil {
+		return normalised
+	}
+	for locale, messages := range config.Translations {
+		normalised.translations[normaliseLocale(locale)] = messages
+	}
+	if config.DefaultLocale != nil {
+		normalised.defaultLocale = normaliseLocale(*config.DefaultLocale)
+	}
+	normalised.getLocaleForTenant = config.GetLocaleForTenant
+	return normalised
+}
+
+func normaliseLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// SetLocaleInUserContext sets the locale of the messages that are sent for the request that the user context
+// belongs to, instead of resolving it from the tenant or the Accept-Language header
+func SetLocaleInUserContext(userContext UserContext, locale string) UserContext {
+	var _userContext map[string]interface{}
+
+	if userContext == nil {
+		_userContext = map[string]interface{}{}
+	} else {
+		_userContext = *userContext
+	}
+
+	defaultObj, ok := _userContext["_default"].(map[string]interface{})
+
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		_userContext["_default"] = defaultObj
+	}
+
+	defaultObj["locale"] = locale
+
+	return &_userContext
+}
+
+// GetLocale returns the locale of the messages for the request that the user context belongs to. It is the
+// one set using SetLocaleInUserContext, followed by the locale of the tenant (see GetLocaleForTenant in
+// I18nConfig), the best match for the Accept-Language header of the request among the translations, and
+// the default locale.
+func GetLocale(tenantId string, userContext ...UserContext) (string, error) {
+	instance, err := GetInstanceOrThrowError(userContext...)
+	if err != nil {
+		return "", err
+	}
+	var _userContext UserContext
+	if len(userContext) > 0 {
+		_userContext = userContext[0]
+	}
+	return instance.getLocale(tenantId, _userContext)
+}
+
+func (s *Instance) getLocale(tenantId string, userContext UserContext) (string, error) {
+	i18n := s.getI18nConfig()
+	if userContext != nil {
+		if defaultObj, ok := (*userContext)["_default"].(map[string]interface{}); ok {
+			if locale, ok := defaultObj["locale"].(string); ok && locale != "" {
+				return normaliseLocale(locale), nil
+			}
+		}
+	}
+	if i18n.getLocaleForTenant != nil {
+		locale, err := i18n.getLocaleForTenant(tenantId, userContext)
+		if err != nil {
+			return "", err
+		}
+		if locale != nil {
+			return normaliseLocale(*locale), nil
+		}
+	}
+	if request := getRequestFromUserContext(userContext); request != nil {
+		if locale, ok := i18n.matchAcceptLanguage(request.Header.Get("Accept-Language")); ok {
+			return locale, nil
+		}
+	}
+	return i18n.defaultLocale, nil
+}
+
+// IsI18nEnabled returns true if I18n is set in the config of the instance that the user context belongs to
+func IsI18nEnabled(userContext ...UserContext) bool {
+	instance, err := GetInstanceOrThrowError(userContext...)
+	return err == nil && instance.i18n != nil
+}
+
+func (s *Instance) getI18nConfig() *normalisedI18nConfig {
+	if s.i18n == nil {
+		return defaultI18nConfig
+	}
+	return s.i18n
+}
+
+// defaultI18nConfig is used if I18n is not set in the config
+var defaultI18nConfig = normaliseI18nConfig(nil)
+
+// matchAcceptLanguage returns the locale with a translation (or the default locale) that best matches the
+// Accept-Language header. A language range without a region (e.g. "de") matches translations for a region
+// of it (e.g. "de-at") and the other way around.
+func (i18n *normalisedI18nConfig) matchAcceptLanguage(header string) (string, bool) {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+	ranges := []languageRange{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := normaliseLocale(fields[0])
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, languageRange{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, languageRange := range ranges {
+		if languageRange.tag == "*" {
+			return i18n.defaultLocale, true
+		}
+		if i18n.hasLocale(languageRange.tag) {
+			return languageRange.tag, true
+		}
+		language := strings.Split(languageRange.tag, "-")[0]
+		if i18n.hasLocale(language) {
+			return language, true
+		}
+		for _, locale := range i18n.sortedLocales() {
+			if strings.Split(locale, "-")[0] == language {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (i18n *normalisedI18nConfig) hasLocale(locale string) bool {
+	_, ok := i18n.translations[locale]
+	return ok || locale == i18n.defaultLocale || locale == DefaultLocale
+}
+
+func (i18n *normalisedI18nConfig) sortedLocales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range i18n.translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Translate returns the message for the key in the locale of the request (see GetLocale). If there is no
+// translation for the key, the message in the default locale is returned, or the key itself if there is none.
+func Translate(key MessageKey, tenantId string, userContext ...UserContext) string {
+	instance, err := GetInstanceOrThrowError(userContext...)
+	if err != nil {
+		return translateInLocale(nil, DefaultLocale, key)
+	}
+	var _userContext UserContext
+	if len(userContext) > 0 {
+		_userContext = userContext[0]
+	}
+	return instance.translate(key, tenantId, _userContext)
+}
+
+// TranslateWithFallback returns the message for the key like Translate, but returns the fallback message
+// instead of the key if there is no message for the key. This is used for the messages of custom validators,
+// which may not be translated to every locale.
+func TranslateWithFallback(key MessageKey, fallback string, tenantId string, userContext ...UserContext) string {
+	instance, err := GetInstanceOrThrowError(userContext...)
+	if err != nil {
+		if message, ok := findMessageInLocale(nil, DefaultLocale, key); ok {
+			return message
+		}
+		return fallback
+	}
+	var _userContext UserContext
+	if len(userContext) > 0 {
+		_userContext = userContext[0]
+	}
+	if message, ok := findMessageInLocale(instance.getI18nConfig(), instance.resolveLocale(tenantId, _userContext), key); ok {
+		return message
+	}
+	return fallback
+}
+
+func (s *Instance) translate(key MessageKey, tenantId string, userContext UserContext) string {
+	return translateInLocale(s.getI18nConfig(), s.resolveLocale(tenantId, userContext), key)
+}
+
+// resolveLocale returns the locale of the request, or the default locale if it can not be resolved
+func (s *Instance) resolveLocale(tenantId string, userContext UserContext) string {
+	locale, err := s.getLocale(tenantId, userContext)
+	if err != nil {
+		LogWarn(userContext, "resolving the locale failed, using the default locale", "error", err.Error())
+		return s.getI18nConfig().defaultLocale
+	}
+	return locale
+}
+
+func translateInLocale(i18n *normalisedI18nConfig, locale string, key MessageKey) string {
+	if message, ok := findMessageInLocale(i18n, locale, key); ok {
+		return message
+	}
+	return string(key)
+}
+
+// findMessageInLocale returns the message for the key in the locale, falling back to the default locale
+func findMessageInLocale(i18n *normalisedI18nConfig, locale string, key MessageKey) (string, bool) {
+	if i18n != nil {
+		candidates := []string{locale, strings.Split(locale, "-")[0], i18n.defaultLocale}
+		for _, candidate := range candidates {
+			if message, ok := i18n.translations[candidate][key]; ok {
+				return message, true
+			}
+			if candidate == DefaultLocale {
+				// the messages of the SDK are in English
+				break
+			}
+		}
+	}
+	message, ok := defaultMessages[key]
+	return message, ok
+}
+
+// LocaliseMessage translates a message that is sent to the frontend, and returns it with its key. The message
+// can be a message key (of the SDK, or from the translations), or a message of the SDK in the default locale,
+// like the ones returned by the default form field validators. Other messages are returned as is, with an
+// empty key.
+func LocaliseMessage(message string, tenantId string, userContext ...UserContext) (MessageKey, string) {
+	var _userContext UserContext
+	if len(userContext) > 0 {
+		_userContext = userContext[0]
+	}
+	instance, err := GetInstanceOrThrowError(userContext...)
+	if err != nil {
+		if key, ok := defaultMessageKeys[message]; ok {
+			return key, message
+		}
+		return "", message
+	}
+	i18n := instance.getI18nConfig()
+
+	key, ok := defaultMessageKeys[message]
+	if !ok {
+		key = MessageKey(message)
+		if !i18n.isKnownKey(key) {
+			return "", message
+		}
+	}
+	return key, instance.translate(key, tenantId, _userContext)
+}
+
+func (i18n *normalisedI18nConfig) isKnownKey(key MessageKey) bool {
+	if _, ok := defaultMessages[key]; ok {
+		return true
+	}
+	for _, messages := range i18n.translations {
+		if _, ok := messages[key]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newI18nTestInstance(t *testing.T, i18n *I18nConfig) *Instance {
+	ResetForTest()
+	instance, err := New(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []Recipe{openAPITestRecipe},
+		I18n:       i18n,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return instance
+}
+
+func makeI18nTestUserContext(instance *Instance, acceptLanguage string) UserContext {
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", nil)
+	req.Header.Set("Accept-Language", acceptLanguage)
+	return MakeDefaultUserContextFromAPI(SetInstanceInRequest(req, instance))
+}
+
+func TestThatTheLocaleIsResolvedFromTheAcceptLanguageHeader(t *testing.T) {
+	instance := newI18nTestInstance(t, &I18nConfig{
+		Translations: map[string]map[MessageKey]string{
+			"de":    {MessageKeyEmailInvalid: "E-Mail ist ungültig"},
+			"pt-BR": {MessageKeyEmailInvalid: "E-mail inválido"},
+		},
+	})
+
+	testCases := map[string]string{
+		"de":                           "de",
+		"de-AT,en;q=0.5":               "de",
+		"fr;q=0.9, pt;q=0.8, de;q=0.1": "pt-br",
+		"fr, en-US;q=0.8":              "en",
+		"fr":                           "en",
+		"":                             "en",
+	}
+	for acceptLanguage, expected := range testCases {
+		locale, err := GetLocale("public", makeI18nTestUserContext(instance, acceptLanguage))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, locale, acceptLanguage)
+	}
+
+	assert.Equal(t, "E-mail inválido", Translate(MessageKeyEmailInvalid, "public", makeI18nTestUserContext(instance, "pt-BR")))
+	// messages that are missing from a translation are sent in English
+	assert.Equal(t, "Phone number is invalid", Translate(MessageKeyPhoneNumberInvalid, "public", makeI18nTestUserContext(instance, "de")))
+}
+
+func TestThatTheLocaleOfTheTenantAndTheUserContextTakePrecedence(t *testing.T) {
+	defaultLocale := "de"
+	instance := newI18nTestInstance(t, &I18nConfig{
+		Translations: map[string]map[MessageKey]string{
+			"de": {MessageKeyEmailInvalid: "E-Mail ist ungültig"},
+			"fr": {MessageKeyEmailInvalid: "L'adresse e-mail n'est pas valide"},
+		},
+		DefaultLocale: &defaultLocale,
+		GetLocaleForTenant: func(tenantId string, userContext UserContext) (*string, error) {
+			if tenantId == "paris" {
+				locale := "fr"
+				return &locale, nil
+			}
+			return nil, nil
+		},
+	})
+
+	userContext := makeI18nTestUserContext(instance, "en")
+	assert.Equal(t, "Email is invalid", Translate(MessageKeyEmailInvalid, "public", userContext))
+	assert.Equal(t, "L'adresse e-mail n'est pas valide", Translate(MessageKeyEmailInvalid, "paris", userContext))
+	assert.Equal(t, "E-Mail ist ungültig", Translate(MessageKeyEmailInvalid, "public", makeI18nTestUserContext(instance, "es")))
+
+	userContext = SetLocaleInUserContext(userContext, "de")
+	assert.Equal(t, "E-Mail ist ungültig", Translate(MessageKeyEmailInvalid, "paris", userContext))
+}
+
+func TestThatMessagesAreLocalisedWithTheirKeys(t *testing.T) {
+	instance := newI18nTestInstance(t, &I18nConfig{
+		Translations: map[string]map[MessageKey]string{
+			"de": {
+				MessageKeyPasswordTooShort: "Das Passwort muss mindestens 8 Zeichen enthalten",
+				"USERNAME_TAKEN":           "Der Benutzername ist vergeben",
+			},
+		},
+	})
+	userContext := makeI18nTestUserContext(instance, "de")
+
+	key, message := LocaliseMessage("Password must contain at least 8 characters, including a number", "public", userContext)
+	assert.Equal(t, MessageKeyPasswordTooShort, key)
+	assert.Equal(t, "Das Passwort muss mindestens 8 Zeichen enthalten", message)
+
+	// custom validators can return the keys of their messages
+	key, message = LocaliseMessage("USERNAME_TAKEN", "public", userContext)
+	assert.Equal(t, MessageKey("USERNAME_TAKEN"), key)
+	assert.Equal(t, "Der Benutzername ist vergeben", message)
+
+	key, message = LocaliseMessage("Username is taken", "public", userContext)
+	assert.Equal(t, MessageKey(""), key)
+	assert.Equal(t, "Username is taken", message)
+	assert.True(t, IsI18nEnabled(userContext))
+}
+
+func TestThatMessagesAreInEnglishIfI18nIsNotConfigured(t *testing.T) {
+	instance := newI18nTestInstance(t, nil)
+	userContext := makeI18nTestUserContext(instance, "de")
+
+	key, message := LocaliseMessage("Email is invalid", "public", userContext)
+	assert.Equal(t, MessageKeyEmailInvalid, key)
+	assert.Equal(t, "Email is invalid", message)
+	assert.False(t, IsI18nEnabled(userContext))
+}
@@ -1,33 +1,306 @@
 package supertokens
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"runtime"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const supertokens_namespace = "com.supertokens"
 
 /*
- The debug logger below can be used to log debug messages in the following format
-    com.supertokens {t: "2022-03-21T17:10:42+05:30", message: "Test Message", file: "/home/supertokens-golang/supertokens/supertokens.go:51" sdkVer: "0.5.2"}
+ Log entries are sent to a StructuredLogger, which can be configured using Logging in TypeInput. The
+ default one writes every entry as a JSON line to Logger, in the following format
+    {"t":"2022-03-21T17:10:42+05:30","level":"debug","namespace":"com.supertokens","message":"Test Message","file":"/home/supertokens-golang/supertokens/supertokens.go:51","sdkVer":"0.5.2"}
+
+ Values of fields that may contain tokens, passwords, emails or phone numbers are redacted before
+ the entry is passed to the logger, and so are emails and JWTs in the message itself.
 */
 
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+func (level LogLevel) String() string {
+	switch level {
+	case LogLevelDebug:
+		return "debug"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelWarn:
+		return "warn"
+	case LogLevelError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(level))
+}
+
+type LogEntry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	// Fields are the key value pairs passed along with the message, with sensitive values redacted
+	Fields map[string]interface{}
+	// CorrelationId identifies the request that the entry belongs to. It is empty if there is none.
+	CorrelationId string
+	// File is the location (file:line) of the log statement
+	File string
+}
+
+type StructuredLogger interface {
+	Log(entry LogEntry)
+}
+
+type LoggingConfig struct {
+	// Logger receives all the log entries. Defaults to a logger that writes JSON lines to supertokens.Logger.
+	Logger StructuredLogger
+	// Level is the minimum level of the entries that are logged. Defaults to LogLevelDebug if debug
+	// logging is enabled, and to LogLevelWarn otherwise.
+	Level *LogLevel
+	// CorrelationIdHeader is the request header that correlation IDs are read from. Defaults to X-Request-Id.
+	CorrelationIdHeader *string
+}
+
+const redactedLogValue = "[REDACTED]"
+
 var (
-	Logger       = log.New(os.Stdout, supertokens_namespace, 0)
+	// Logger is where the default StructuredLogger writes to
+	Logger       = log.New(os.Stdout, "", 0)
 	DebugEnabled = false
+
+	structuredLogger    StructuredLogger = defaultStructuredLogger{}
+	minimumLogLevel     *LogLevel
+	correlationIdHeader = "X-Request-Id"
 )
 
-func formatMessage(message string) string {
-	_, file, line, _ := runtime.Caller(2)
-	return fmt.Sprintf(" {t: \"%s\", message: \"%s\", file: \"%s:%d\" sdkVer: \"%s\"}\n\n", time.Now().Format(time.RFC3339), message, file, line, VERSION)
+func initLogging(config *LoggingConfig) {
+	structuredLogger = defaultStructuredLogger{}
+	minimumLogLevel = nil
+	correlationIdHeader = "X-Request-Id"
+	if config == nil {
+		return
+	}
+	if config.Logger != nil {
+		structuredLogger = config.Logger
+	}
+	minimumLogLevel = config.Level
+	if config.CorrelationIdHeader != nil {
+		correlationIdHeader = *config.CorrelationIdHeader
+	}
 }
 
-func LogDebugMessage(message string) {
+func isLogLevelEnabled(level LogLevel) bool {
+	if minimumLogLevel != nil {
+		return level >= *minimumLogLevel
+	}
+	if level >= LogLevelWarn {
+		return true
+	}
 	_, exists := os.LookupEnv("SUPERTOKENS_DEBUG")
-	if exists || DebugEnabled == true {
-		Logger.Printf(formatMessage(message))
+	return exists || DebugEnabled
+}
+
+func LogDebugMessage(message string) {
+	logEntry(nil, LogLevelDebug, message, nil)
+}
+
+// LogDebug logs a message along with fields given as alternating keys and values, for example:
+//
+//	supertokens.LogDebug(userContext, "session created", "sessionHandle", handle, "tenantId", tenantId)
+func LogDebug(userContext UserContext, message string, keysAndValues ...interface{}) {
+	logEntry(userContext, LogLevelDebug, message, keysAndValues)
+}
+
+func LogInfo(userContext UserContext, message string, keysAndValues ...interface{}) {
+	logEntry(userContext, LogLevelInfo, message, keysAndValues)
+}
+
+func LogWarn(userContext UserContext, message string, keysAndValues ...interface{}) {
+	logEntry(userContext, LogLevelWarn, message, keysAndValues)
+}
+
+func LogError(userContext UserContext, message string, keysAndValues ...interface{}) {
+	logEntry(userContext, LogLevelError, message, keysAndValues)
+}
+
+// logEntry must only be called by the exported log functions, since the location of the log statement is two frames up
+func logEntry(userContext UserContext, level LogLevel, message string, keysAndValues []interface{}) {
+	if !isLogLevelEnabled(level) {
+		return
+	}
+	entry := LogEntry{
+		Time:          time.Now(),
+		Level:         level,
+		Message:       redactLogMessage(message),
+		CorrelationId: GetCorrelationIdFromUserContext(userContext),
+	}
+	_, file, line, ok := runtime.Caller(2)
+	if ok {
+		entry.File = fmt.Sprintf("%s:%d", file, line)
+	}
+	if len(keysAndValues) > 0 {
+		entry.Fields = map[string]interface{}{}
+		for i := 0; i < len(keysAndValues); i += 2 {
+			key := fmt.Sprint(keysAndValues[i])
+			if i+1 >= len(keysAndValues) {
+				entry.Fields["!missingValue"] = key
+				break
+			}
+			entry.Fields[key] = redactLogValue(key, keysAndValues[i+1])
+		}
+	}
+	structuredLogger.Log(entry)
+}
+
+// SetCorrelationIdInUserContext sets the ID that is added to all log entries that are made with this user context
+func SetCorrelationIdInUserContext(userContext UserContext, correlationId string) UserContext {
+	var _userContext map[string]interface{}
+
+	if userContext == nil {
+		_userContext = map[string]interface{}{}
+	} else {
+		_userContext = *userContext
+	}
+
+	defaultObj, ok := _userContext["_default"].(map[string]interface{})
+
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		_userContext["_default"] = defaultObj
+	}
+
+	defaultObj["correlationId"] = correlationId
+
+	return &_userContext
+}
+
+// GetCorrelationIdFromUserContext returns the ID set using SetCorrelationIdInUserContext. If there is none, the
+// correlation ID header of the request is used, followed by the ID of the current trace.
+func GetCorrelationIdFromUserContext(userContext UserContext) string {
+	if userContext == nil {
+		return ""
+	}
+	defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+	if ok {
+		correlationId, ok := defaultObj["correlationId"].(string)
+		if ok && correlationId != "" {
+			return correlationId
+		}
+	}
+	request := getRequestFromUserContext(userContext)
+	if request != nil && correlationIdHeader != "" {
+		correlationId := request.Header.Get(correlationIdHeader)
+		if correlationId != "" {
+			return correlationId
+		}
+	}
+	spanContext := trace.SpanContextFromContext(getContextFromUserContext(userContext))
+	if spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
+var sensitiveLogKeyParts = []string{"token", "password", "secret", "apikey", "authorization", "cookie", "email", "phone", "otp", "linkcode"}
+var sensitiveLogKeys = []string{"code", "codeverifier", "userinputcode", "preauthsessionid"}
+
+func isSensitiveLogKey(key string) bool {
+	normalisedKey := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(key))
+	for _, part := range sensitiveLogKeyParts {
+		if strings.Contains(normalisedKey, part) {
+			return true
+		}
+	}
+	return DoesSliceContainString(normalisedKey, sensitiveLogKeys)
+}
+
+func redactLogValue(key string, value interface{}) interface{} {
+	if isSensitiveLogKey(key) {
+		return redactedLogValue
+	}
+	switch v := value.(type) {
+	case error:
+		return redactLogMessage(v.Error())
+	case string:
+		return redactLogMessage(v)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for nestedKey, nestedValue := range v {
+			result[nestedKey] = redactLogValue(nestedKey, nestedValue)
+		}
+		return result
+	case map[string]string:
+		result := make(map[string]interface{}, len(v))
+		for nestedKey, nestedValue := range v {
+			result[nestedKey] = redactLogValue(nestedKey, nestedValue)
+		}
+		return result
+	case map[string][]string:
+		result := make(map[string]interface{}, len(v))
+		for nestedKey, nestedValue := range v {
+			result[nestedKey] = redactLogValue(nestedKey, strings.Join(nestedValue, ", "))
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, nestedValue := range v {
+			result[i] = redactLogValue("", nestedValue)
+		}
+		return result
+	}
+	return value
+}
+
+var emailInLogRegex = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+var jwtInLogRegex = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+
+func redactLogMessage(message string) string {
+	message = jwtInLogRegex.ReplaceAllString(message, redactedLogValue)
+	return emailInLogRegex.ReplaceAllString(message, redactedLogValue)
+}
+
+type defaultStructuredLogger struct{}
+
+func (defaultStructuredLogger) Log(entry LogEntry) {
+	output := struct {
+		Time          string                 `json:"t"`
+		Level         string                 `json:"level"`
+		Namespace     string                 `json:"namespace"`
+		Message       string                 `json:"message"`
+		CorrelationId string                 `json:"correlationId,omitempty"`
+		Fields        map[string]interface{} `json:"fields,omitempty"`
+		File          string                 `json:"file"`
+		SDKVersion    string                 `json:"sdkVer"`
+	}{
+		Time:          entry.Time.Format(time.RFC3339),
+		Level:         entry.Level.String(),
+		Namespace:     supertokens_namespace,
+		Message:       entry.Message,
+		CorrelationId: entry.CorrelationId,
+		Fields:        entry.Fields,
+		File:          entry.File,
+		SDKVersion:    VERSION,
+	}
+	outputJSON, err := json.Marshal(output)
+	if err != nil {
+		outputJSON, _ = json.Marshal(map[string]interface{}{
+			"t":       output.Time,
+			"level":   output.Level,
+			"message": output.Message,
+			"error":   "could not serialise the fields of the log entry: " + err.Error(),
+		})
 	}
+	Logger.Println(string(outputJSON))
 }
@@ -16,12 +16,16 @@
 package supertokens
 
 import (
+	"context"
 	"net/http"
 )
 
 func Init(config TypeInput) error {
+	instanceInitLock.Lock()
+	defer instanceInitLock.Unlock()
 	err := supertokensInit(config)
 	if err != nil {
+		resetPostInitCallbacks()
 		return err
 	}
 	err = runPostInitCallbacks()
@@ -31,6 +35,27 @@ func Init(config TypeInput) error {
 	return nil
 }
 
+// New creates an instance that is independent of the one created by Init and of any other instance, with
+// its own app info, connection to the core and recipes. Requests that go through the middleware of the
+// instance use it automatically. Other calls need a user context made using SetInstanceInUserContext.
+//
+// Debug, Logging, Tracing and Metrics are shared by all the instances, so they are only changed if set.
+func New(config TypeInput) (*Instance, error) {
+	instanceInitLock.Lock()
+	defer instanceInitLock.Unlock()
+	instance, err := newInstance(config, false)
+	if err != nil {
+		resetPostInitCallbacks()
+		return nil, err
+	}
+	err = runPostInitCallbacks()
+	if err != nil {
+		instance.Close()
+		return nil, err
+	}
+	return instance, nil
+}
+
 func Middleware(theirHandler http.Handler) http.Handler {
 	instance, err := GetInstanceOrThrowError()
 	if err != nil {
@@ -38,18 +63,31 @@ func Middleware(theirHandler http.Handler) http.Handler {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 		})
 	}
-	return instance.middleware(theirHandler)
+	return instance.Middleware(theirHandler)
 }
 
-func ErrorHandler(err error, req *http.Request, res http.ResponseWriter, userContext ...UserContext) error {
-	instance, instanceErr := GetInstanceOrThrowError()
-	if instanceErr != nil {
-		return instanceErr
+// GetOpenAPIDocument returns the OpenAPI document of the APIs of the recipes of the instance that the user context
+// belongs to (see Instance.GetOpenAPIDocument).
+func GetOpenAPIDocument(userContext ...UserContext) (*OpenAPIDocument, error) {
+	instance, err := GetInstanceOrThrowError(userContext...)
+	if err != nil {
+		return nil, err
 	}
+	return instance.GetOpenAPIDocument()
+}
+
+func ErrorHandler(err error, req *http.Request, res http.ResponseWriter, userContext ...UserContext) error {
 	if len(userContext) == 0 {
 		userContext = append(userContext, &map[string]interface{}{})
 	}
-	return instance.errorHandler(err, req, res, userContext[0])
+	if req != nil {
+		userContext[0] = SetRequestInUserContextIfNotDefined(userContext[0], req)
+	}
+	instance, instanceErr := GetInstanceOrThrowError(userContext...)
+	if instanceErr != nil {
+		return instanceErr
+	}
+	return instance.ErrorHandler(err, req, res, userContext[0])
 }
 
 func GetAllCORSHeaders() []string {
@@ -57,10 +95,10 @@ func GetAllCORSHeaders() []string {
 	if err != nil {
 		panic("Please call supertokens.Init before using the GetAllCORSHeaders function")
 	}
-	return instance.getAllCORSHeaders()
+	return instance.GetAllCORSHeaders()
 }
 
-func GetUserCount(includeRecipeIds *[]string, tenantId *string) (float64, error) {
+func GetUserCount(includeRecipeIds *[]string, tenantId *string, userContext ...UserContext) (float64, error) {
 	var includeAllTenants *bool
 	if tenantId == nil {
 		defaultTenantId := DefaultTenantId
@@ -68,21 +106,25 @@ func GetUserCount(includeRecipeIds *[]string, tenantId *string) (float64, error)
 		True := true
 		includeAllTenants = &True
 	}
-	return getUserCount(includeRecipeIds, *tenantId, includeAllTenants)
+	return getUserCount(includeRecipeIds, *tenantId, includeAllTenants, userContext...)
 }
 
-func GetUsersOldestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string) (UserPaginationResult, error) {
-	return GetUsersWithSearchParams(tenantId, "ASC", paginationToken, limit, includeRecipeIds, query)
+func GetUsersOldestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string, userContext ...UserContext) (UserPaginationResult, error) {
+	return GetUsersWithSearchParams(tenantId, "ASC", paginationToken, limit, includeRecipeIds, query, userContext...)
 }
 
-func GetUsersNewestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string) (UserPaginationResult, error) {
-	return GetUsersWithSearchParams(tenantId, "DESC", paginationToken, limit, includeRecipeIds, query)
+func GetUsersNewestFirst(tenantId string, paginationToken *string, limit *int, includeRecipeIds *[]string, query map[string]string, userContext ...UserContext) (UserPaginationResult, error) {
+	return GetUsersWithSearchParams(tenantId, "DESC", paginationToken, limit, includeRecipeIds, query, userContext...)
 }
 
-func DeleteUser(userId string) error {
-	return deleteUser(userId)
+func DeleteUser(userId string, userContext ...UserContext) error {
+	return deleteUser(userId, userContext...)
 }
 
 func GetRequestFromUserContext(userContext UserContext) *http.Request {
 	return getRequestFromUserContext(userContext)
 }
+
+func GetContextFromUserContext(userContext UserContext) context.Context {
+	return getContextFromUserContext(userContext)
+}
@@ -0,0 +1,106 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatSeparateInstancesUseTheirOwnCore(t *testing.T) {
+	ResetForTest()
+	defer ResetForTest()
+
+	newCore := func(requestCount *int) *httptest.Server {
+		var lock sync.Mutex
+		mux := http.NewServeMux()
+		mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(200)
+			rw.Write([]byte(`{"versions":["3.0"]}`))
+		})
+		mux.HandleFunc("/recipe/session/remove", func(rw http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			*requestCount++
+			lock.Unlock()
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(200)
+			rw.Write([]byte(`{"status":"OK","sessionHandlesRevoked":["handle"]}`))
+		})
+		return httptest.NewServer(mux)
+	}
+
+	requestsToCoreA := 0
+	requestsToCoreB := 0
+	coreA := newCore(&requestsToCoreA)
+	coreB := newCore(&requestsToCoreB)
+	defer coreA.Close()
+	defer coreB.Close()
+
+	newConfig := func(connectionURI string, apiDomain string) TypeInput {
+		config := newQuerierTestConfig(ConnectionInfo{
+			ConnectionURI: connectionURI,
+		})
+		config.AppInfo.APIDomain = apiDomain
+		return config
+	}
+
+	instanceA, err := New(newConfig(coreA.URL, "api.a.supertokens.io"))
+	assert.NoError(t, err)
+	defer instanceA.Close()
+	instanceB, err := New(newConfig(coreB.URL, "api.b.supertokens.io"))
+	assert.NoError(t, err)
+	defer instanceB.Close()
+
+	// neither instance is the default one
+	_, err = GetInstanceOrThrowError()
+	assert.Error(t, err)
+
+	userContextA := SetInstanceInUserContext(nil, instanceA)
+	instance, err := GetInstanceOrThrowError(userContextA)
+	assert.NoError(t, err)
+	assert.Same(t, instanceA, instance)
+	assert.Equal(t, "https://api.a.supertokens.io", instance.AppInfo.APIDomain.GetAsStringDangerous())
+
+	revokeSession := func(userContext UserContext) {
+		q, err := GetNewQuerierInstanceOrThrowError("session", userContext)
+		assert.NoError(t, err)
+		_, err = q.SendPostRequest("/recipe/session/remove", map[string]interface{}{
+			"sessionHandles": []string{"handle"},
+		}, userContext)
+		assert.NoError(t, err)
+	}
+
+	revokeSession(userContextA)
+	assert.Equal(t, 1, requestsToCoreA)
+	assert.Equal(t, 0, requestsToCoreB)
+
+	// the instance is taken from the request when going through its middleware
+	handler := instanceB.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		revokeSession(MakeDefaultUserContextFromAPI(r))
+		rw.WriteHeader(200)
+	}))
+	req := httptest.NewRequest("GET", "/hello", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+	assert.Equal(t, 1, requestsToCoreA)
+	assert.Equal(t, 1, requestsToCoreB)
+}
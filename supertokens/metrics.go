@@ -0,0 +1,284 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metrics that are recorded by the SDK
+const (
+	MetricSignIns                 = "supertokens_sign_ins_total"
+	MetricSignUps                 = "supertokens_sign_ups_total"
+	MetricFailedSignIns           = "supertokens_failed_sign_ins_total"
+	MetricSessionsCreated         = "supertokens_sessions_created_total"
+	MetricSessionsRefreshed       = "supertokens_sessions_refreshed_total"
+	MetricSessionsRevoked         = "supertokens_sessions_revoked_total"
+	MetricTokenTheftDetected      = "supertokens_token_theft_detected_total"
+	MetricClaimValidationFailures = "supertokens_claim_validation_failures_total"
+	MetricEmailSendFailures       = "supertokens_email_send_failures_total"
+	MetricSmsSendFailures         = "supertokens_sms_send_failures_total"
+	MetricCoreRequestDuration     = "supertokens_core_request_duration_seconds"
+)
+
+var metricsHelp = map[string]string{
+	MetricSignIns:                 "Number of successful sign ins.",
+	MetricSignUps:                 "Number of successful sign ups.",
+	MetricFailedSignIns:           "Number of sign ins that failed because of wrong credentials.",
+	MetricSessionsCreated:         "Number of sessions created.",
+	MetricSessionsRefreshed:       "Number of sessions refreshed.",
+	MetricSessionsRevoked:         "Number of sessions revoked.",
+	MetricTokenTheftDetected:      "Number of times token theft was detected while refreshing a session.",
+	MetricClaimValidationFailures: "Number of session claim validations that failed.",
+	MetricEmailSendFailures:       "Number of emails that could not be sent.",
+	MetricSmsSendFailures:         "Number of SMS that could not be sent.",
+	MetricCoreRequestDuration:     "Duration of requests to the SuperTokens core in seconds.",
+}
+
+// DefaultMetricsHistogramBuckets are the upper bounds (in seconds) used by NewInMemoryMetricsRegistry
+var DefaultMetricsHistogramBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// MetricsRegistry receives the metrics recorded by the SDK. It can be implemented to forward
+// the metrics to another system, or NewInMemoryMetricsRegistry can be used.
+type MetricsRegistry interface {
+	AddToCounter(name string, labels map[string]string, value float64)
+	ObserveHistogram(name string, labels map[string]string, value float64)
+}
+
+type MetricsConfig struct {
+	Registry MetricsRegistry
+}
+
+var metricsRegistry MetricsRegistry
+
+func initMetrics(config *MetricsConfig) {
+	if config == nil {
+		metricsRegistry = nil
+		return
+	}
+	metricsRegistry = config.Registry
+}
+
+func IncrementCounterMetric(name string, labels map[string]string) {
+	AddToCounterMetric(name, labels, 1)
+}
+
+func AddToCounterMetric(name string, labels map[string]string, value float64) {
+	if metricsRegistry == nil || value == 0 {
+		return
+	}
+	metricsRegistry.AddToCounter(name, labels, value)
+}
+
+func ObserveHistogramMetric(name string, labels map[string]string, value float64) {
+	if metricsRegistry == nil {
+		return
+	}
+	metricsRegistry.ObserveHistogram(name, labels, value)
+}
+
+// RecordSignInUpMetric increments MetricSignUps if a new user was created, and MetricSignIns otherwise
+func RecordSignInUpMetric(recipeId string, tenantId string, createdNewUser bool) {
+	name := MetricSignIns
+	if createdNewUser {
+		name = MetricSignUps
+	}
+	IncrementCounterMetric(name, map[string]string{
+		"recipe":    recipeId,
+		"tenant_id": tenantId,
+	})
+}
+
+// InMemoryMetricsRegistry keeps the metrics in memory and serves them in the Prometheus text
+// format. It can be mounted as a handler, for example on /metrics.
+type InMemoryMetricsRegistry struct {
+	buckets    []float64
+	mutex      sync.Mutex
+	counters   map[string]map[string]*counterSeries
+	histograms map[string]map[string]*histogramSeries
+}
+
+type counterSeries struct {
+	value float64
+}
+
+type histogramSeries struct {
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// NewInMemoryMetricsRegistry creates a registry that uses the given histogram buckets, or
+// DefaultMetricsHistogramBuckets if none are given.
+func NewInMemoryMetricsRegistry(buckets ...float64) *InMemoryMetricsRegistry {
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsHistogramBuckets
+	}
+	sortedBuckets := append([]float64{}, buckets...)
+	sort.Float64s(sortedBuckets)
+	return &InMemoryMetricsRegistry{
+		buckets:    sortedBuckets,
+		counters:   map[string]map[string]*counterSeries{},
+		histograms: map[string]map[string]*histogramSeries{},
+	}
+}
+
+func (r *InMemoryMetricsRegistry) AddToCounter(name string, labels map[string]string, value float64) {
+	labelsStr := formatMetricLabels(labels)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	series, ok := r.counters[name]
+	if !ok {
+		series = map[string]*counterSeries{}
+		r.counters[name] = series
+	}
+	counter, ok := series[labelsStr]
+	if !ok {
+		counter = &counterSeries{}
+		series[labelsStr] = counter
+	}
+	counter.value += value
+}
+
+func (r *InMemoryMetricsRegistry) ObserveHistogram(name string, labels map[string]string, value float64) {
+	labelsStr := formatMetricLabels(labels)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	series, ok := r.histograms[name]
+	if !ok {
+		series = map[string]*histogramSeries{}
+		r.histograms[name] = series
+	}
+	histogram, ok := series[labelsStr]
+	if !ok {
+		histogram = &histogramSeries{bucketCounts: make([]uint64, len(r.buckets))}
+		series[labelsStr] = histogram
+	}
+	for i, upperBound := range r.buckets {
+		if value <= upperBound {
+			histogram.bucketCounts[i]++
+		}
+	}
+	histogram.sum += value
+	histogram.count++
+}
+
+// GetCounterValue returns the current value of a counter, or 0 if it has not been recorded
+func (r *InMemoryMetricsRegistry) GetCounterValue(name string, labels map[string]string) float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	counter, ok := r.counters[name][formatMetricLabels(labels)]
+	if !ok {
+		return 0
+	}
+	return counter.value
+}
+
+// WritePrometheusText writes all the metrics in the Prometheus text exposition format
+func (r *InMemoryMetricsRegistry) WritePrometheusText(w io.Writer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	writer := bufio.NewWriter(w)
+	for _, name := range sortedMapKeys(r.counters) {
+		writeMetricHeader(writer, name, "counter")
+		for _, labels := range sortedMapKeys(r.counters[name]) {
+			fmt.Fprintf(writer, "%s%s %s\n", name, labels, formatMetricValue(r.counters[name][labels].value))
+		}
+	}
+	for _, name := range sortedMapKeys(r.histograms) {
+		writeMetricHeader(writer, name, "histogram")
+		for _, labels := range sortedMapKeys(r.histograms[name]) {
+			histogram := r.histograms[name][labels]
+			for i, upperBound := range r.buckets {
+				fmt.Fprintf(writer, "%s_bucket%s %d\n", name, addMetricLabel(labels, "le", formatMetricValue(upperBound)), histogram.bucketCounts[i])
+			}
+			fmt.Fprintf(writer, "%s_bucket%s %d\n", name, addMetricLabel(labels, "le", "+Inf"), histogram.count)
+			fmt.Fprintf(writer, "%s_sum%s %s\n", name, labels, formatMetricValue(histogram.sum))
+			fmt.Fprintf(writer, "%s_count%s %d\n", name, labels, histogram.count)
+		}
+	}
+	return writer.Flush()
+}
+
+func (r *InMemoryMetricsRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	err := r.WritePrometheusText(w)
+	if err != nil {
+		LogDebugMessage("metrics: failed to write response: " + err.Error())
+	}
+}
+
+func writeMetricHeader(writer io.Writer, name string, metricType string) {
+	if help, ok := metricsHelp[name]; ok {
+		fmt.Fprintf(writer, "# HELP %s %s\n", name, help)
+	}
+	fmt.Fprintf(writer, "# TYPE %s %s\n", name, metricType)
+}
+
+// sortedMapKeys returns the keys of a map[string]T in order, so that the output is stable
+func sortedMapKeys(m interface{}) []string {
+	value := reflect.ValueOf(m)
+	keys := make([]string, 0, value.Len())
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatMetricLabels returns the labels in the Prometheus format (e.g. {a="1",b="2"}), sorted by name
+func formatMetricLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := sortedMapKeys(labels)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+`="`+escapeMetricLabelValue(labels[name])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func addMetricLabel(labels string, name string, value string) string {
+	pair := name + `="` + value + `"`
+	if labels == "" {
+		return "{" + pair + "}"
+	}
+	return labels[:len(labels)-1] + "," + pair + "}"
+}
+
+var metricLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeMetricLabelValue(value string) string {
+	return metricLabelValueReplacer.Replace(value)
+}
+
+func formatMetricValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,114 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMemoryMetricsRegistryWritesPrometheusText(t *testing.T) {
+	registry := NewInMemoryMetricsRegistry(0.1, 1)
+	registry.AddToCounter(MetricSignIns, map[string]string{"tenant_id": "public", "recipe": "emailpassword"}, 1)
+	registry.AddToCounter(MetricSignIns, map[string]string{"recipe": "emailpassword", "tenant_id": "public"}, 2)
+	registry.AddToCounter(MetricClaimValidationFailures, map[string]string{"claim": `st-"role"`}, 1)
+	registry.ObserveHistogram(MetricCoreRequestDuration, map[string]string{"path": "/recipe/signin"}, 0.05)
+	registry.ObserveHistogram(MetricCoreRequestDuration, map[string]string{"path": "/recipe/signin"}, 0.5)
+	registry.ObserveHistogram(MetricCoreRequestDuration, map[string]string{"path": "/recipe/signin"}, 5)
+
+	var output bytes.Buffer
+	err := registry.WritePrometheusText(&output)
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP supertokens_claim_validation_failures_total Number of session claim validations that failed.
+# TYPE supertokens_claim_validation_failures_total counter
+supertokens_claim_validation_failures_total{claim="st-\"role\""} 1
+# HELP supertokens_sign_ins_total Number of successful sign ins.
+# TYPE supertokens_sign_ins_total counter
+supertokens_sign_ins_total{recipe="emailpassword",tenant_id="public"} 3
+# HELP supertokens_core_request_duration_seconds Duration of requests to the SuperTokens core in seconds.
+# TYPE supertokens_core_request_duration_seconds histogram
+supertokens_core_request_duration_seconds_bucket{path="/recipe/signin",le="0.1"} 1
+supertokens_core_request_duration_seconds_bucket{path="/recipe/signin",le="1"} 2
+supertokens_core_request_duration_seconds_bucket{path="/recipe/signin",le="+Inf"} 3
+supertokens_core_request_duration_seconds_sum{path="/recipe/signin"} 5.55
+supertokens_core_request_duration_seconds_count{path="/recipe/signin"} 3
+`, output.String())
+
+	assert.Equal(t, float64(3), registry.GetCounterValue(MetricSignIns, map[string]string{"recipe": "emailpassword", "tenant_id": "public"}))
+	assert.Equal(t, float64(0), registry.GetCounterValue(MetricSignUps, nil))
+}
+
+func TestInMemoryMetricsRegistryServesPrometheusText(t *testing.T) {
+	registry := NewInMemoryMetricsRegistry()
+	registry.AddToCounter(MetricSmsSendFailures, nil, 1)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "supertokens_sms_send_failures_total 1\n")
+}
+
+func TestMetricsAreNotRecordedIfDisabled(t *testing.T) {
+	registry := NewInMemoryMetricsRegistry()
+	initMetrics(nil)
+	IncrementCounterMetric(MetricSignUps, nil)
+
+	initMetrics(&MetricsConfig{Registry: registry})
+	defer initMetrics(nil)
+	IncrementCounterMetric(MetricSignUps, nil)
+	RecordSignInUpMetric("thirdparty", "public", true)
+
+	assert.Equal(t, float64(1), registry.GetCounterValue(MetricSignUps, nil))
+	assert.Equal(t, float64(1), registry.GetCounterValue(MetricSignUps, map[string]string{"recipe": "thirdparty", "tenant_id": "public"}))
+}
+
+func TestThatMetricsAreRecordedForCoreCalls(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/recipe/session/remove", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"status":"OK","sessionHandlesRevoked":["handle1","handle2"]}`))
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	registry := NewInMemoryMetricsRegistry()
+	config := newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	})
+	config.Metrics = &MetricsConfig{
+		Registry: registry,
+	}
+	q := newQuerierForTest(t, config)
+
+	_, err := q.SendPostRequest("/recipe/session/remove", map[string]interface{}{
+		"sessionHandles": []string{"handle1", "handle2"},
+	}, nil)
+	assert.NoError(t, err)
+
+	var output strings.Builder
+	assert.NoError(t, registry.WritePrometheusText(&output))
+	assert.Contains(t, output.String(), `supertokens_core_request_duration_seconds_count{method="POST",path="/recipe/session/remove",status="200"} 1`)
+}
@@ -17,6 +17,7 @@ package supertokens
 
 import (
 	"net/http"
+	"time"
 )
 
 type NormalisedAppinfo struct {
@@ -28,6 +29,9 @@ type NormalisedAppinfo struct {
 	APIBasePath              NormalisedURLPath
 	APIGatewayPath           NormalisedURLPath
 	WebsiteBasePath          NormalisedURLPath
+
+	// instance is the SuperTokens instance that the recipes are being initialised for
+	instance *Instance
 }
 
 type AppInfo struct {
@@ -50,12 +54,40 @@ type TypeInput struct {
 	Telemetry             *bool
 	Debug                 bool
 	OnSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)
+	// Cache enables caching of frequently used, idempotent lookups from the core. Disabled if nil.
+	Cache *CacheConfig
+	// Tracing enables OpenTelemetry spans for the middleware, recipe functions and calls to the core. Disabled if nil.
+	Tracing *TracingConfig
+	// Metrics enables counters for authentication activity and histograms for the latency of calls to the core. Disabled if nil.
+	Metrics *MetricsConfig
+	// Logging configures where log entries are sent and which levels are logged
+	Logging *LoggingConfig
+	// OpenAPI serves the OpenAPI document of the APIs of the recipes under the API base path. Disabled if nil.
+	OpenAPI *OpenAPIConfig
+	// CORS makes the middleware add CORS headers to responses and answer preflight requests, for the APIs of the
+	// recipes and all other requests that go through it. Disabled if nil.
+	CORS *CORSConfig
+	// I18n configures the translations of the messages that are sent to the frontend. Messages are sent in
+	// English if nil.
+	I18n *I18nConfig
 }
 
 type ConnectionInfo struct {
 	ConnectionURI      string
 	APIKey             string
 	NetworkInterceptor func(*http.Request, UserContext) *http.Request
+	// RequestTimeout is the default deadline for a single call to the core, including
+	// any retries. A value of 0 means that calls are only bound by the context of the
+	// incoming request (see SetContextInUserContext).
+	RequestTimeout time.Duration
+	// HTTPClient is reused for every request to the core, including fetching the JWKS.
+	// It can be used to configure TLS roots, client certificates (mTLS), proxies and
+	// connection pooling. If nil, a client with its own pooled transport is created.
+	HTTPClient *http.Client
+	// HostHealth configures when a host in ConnectionURI is considered unhealthy and skipped.
+	HostHealth *HostHealthConfig
+	// RetryPolicy configures how requests that are rate limited by the core are retried.
+	RetryPolicy *RetryPolicy
 }
 
 type APIHandled struct {
@@ -63,6 +95,8 @@ type APIHandled struct {
 	Method                 string
 	ID                     string
 	Disabled               bool
+	// Spec describes the API in the OpenAPI document (see GetOpenAPIDocument). A generic description is used if nil.
+	Spec *APISpec
 }
 
 type UserContext = *map[string]interface{}
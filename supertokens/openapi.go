@@ -0,0 +1,352 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+const openAPIVersion = "3.0.3"
+
+const defaultOpenAPIPath = "/openapi.json"
+
+// OpenAPIConfig configures serving the OpenAPI document of the APIs of the recipes (see GetOpenAPIDocument)
+type OpenAPIConfig struct {
+	// Path is where the document is served (with GET), relative to the API base path. Defaults to "/openapi.json".
+	Path *string
+}
+
+// APISpec describes an API that a recipe handles, for the OpenAPI document. The 200 response is a JSON
+// object with the properties of Response, and a status property with one of Statuses (GENERAL_ERROR is
+// added to the statuses, since overrides can return it for every API).
+type APISpec struct {
+	Summary         string
+	QueryParameters []OpenAPIParameter
+	// RequestBody is the schema of the JSON request body, nil if there is none
+	RequestBody *OpenAPISchema
+	// RequestBodyContentType defaults to application/json
+	RequestBodyContentType string
+	Statuses               []string
+	Response               *OpenAPISchema
+	// OtherResponses are the descriptions of other responses by status code. The 200 response is left
+	// out if Statuses and Response are empty and there are other responses.
+	OtherResponses map[string]string
+	// SessionRequired adds the responses for requests without a valid session, and the security
+	// requirements
+	SessionRequired bool
+}
+
+type OpenAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Servers    []OpenAPIServer            `json:"servers,omitempty"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components *OpenAPIComponents         `json:"components,omitempty"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+// OpenAPIPathItem contains the operations of a path, keyed by the lower case method
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *OpenAPISchema `json:"schema,omitempty"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPISchema struct {
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Description          string                    `json:"description,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*OpenAPISchema          `json:"oneOf,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type OpenAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// GetOpenAPIDocument returns an OpenAPI 3 document that describes the APIs of all the recipes of the instance
+// that are not disabled. Each API is also described with a /{tenantId} path variant.
+func (s *Instance) GetOpenAPIDocument() (*OpenAPIDocument, error) {
+	document := &OpenAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: OpenAPIInfo{
+			Title:   s.AppInfo.AppName,
+			Version: VERSION,
+		},
+		Servers: []OpenAPIServer{{
+			URL: s.AppInfo.APIDomain.GetAsStringDangerous(),
+		}},
+		Paths: map[string]OpenAPIPathItem{},
+		Components: &OpenAPIComponents{
+			SecuritySchemes: map[string]OpenAPISecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+				},
+				"cookieAuth": {
+					Type: "apiKey",
+					In:   "cookie",
+					Name: "sAccessToken",
+				},
+			},
+		},
+	}
+
+	basePath := s.AppInfo.APIBasePath.GetAsStringDangerous()
+	for _, recipeModule := range s.RecipeModules {
+		apisHandled, err := recipeModule.GetAPIsHandled()
+		if err != nil {
+			return nil, err
+		}
+		for _, apiHandled := range apisHandled {
+			if apiHandled.Disabled {
+				continue
+			}
+			path := basePath + apiHandled.PathWithoutAPIBasePath.GetAsStringDangerous()
+			addOpenAPIOperation(document, path, apiHandled.Method, makeOpenAPIOperation(recipeModule.GetRecipeID(), apiHandled, false))
+			tenantPath := basePath + "/{tenantId}" + apiHandled.PathWithoutAPIBasePath.GetAsStringDangerous()
+			addOpenAPIOperation(document, tenantPath, apiHandled.Method, makeOpenAPIOperation(recipeModule.GetRecipeID(), apiHandled, true))
+		}
+	}
+	return document, nil
+}
+
+// addOpenAPIOperation keeps the first operation for a path and method, since the middleware uses the first
+// recipe that handles them (unless the rid header says otherwise)
+func addOpenAPIOperation(document *OpenAPIDocument, path string, method string, operation *OpenAPIOperation) {
+	pathItem, ok := document.Paths[path]
+	if !ok {
+		pathItem = OpenAPIPathItem{}
+		document.Paths[path] = pathItem
+	}
+	method = strings.ToLower(method)
+	if _, ok := pathItem[method]; !ok {
+		pathItem[method] = operation
+	}
+}
+
+func makeOpenAPIOperation(recipeID string, apiHandled APIHandled, forTenant bool) *OpenAPIOperation {
+	spec := apiHandled.Spec
+	if spec == nil {
+		spec = &APISpec{}
+	}
+
+	operation := &OpenAPIOperation{
+		OperationID: makeOpenAPIOperationID(recipeID, apiHandled.Method, apiHandled.ID, forTenant),
+		Summary:     spec.Summary,
+		Tags:        []string{recipeID},
+		Responses:   map[string]OpenAPIResponse{},
+	}
+
+	if forTenant {
+		operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+			Name:     "tenantId",
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		})
+	}
+	operation.Parameters = append(operation.Parameters, spec.QueryParameters...)
+	operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+		Name:        HeaderRID,
+		In:          "header",
+		Description: "The ID of the recipe that should handle the request, if several recipes handle the same path and method",
+		Schema:      &OpenAPISchema{Type: "string", Enum: []string{recipeID}},
+	})
+
+	if spec.RequestBody != nil {
+		contentType := spec.RequestBodyContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		operation.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]OpenAPIMediaType{
+				contentType: {Schema: spec.RequestBody},
+			},
+		}
+	}
+
+	if len(spec.Statuses) > 0 || spec.Response != nil || len(spec.OtherResponses) == 0 {
+		operation.Responses["200"] = OpenAPIResponse{
+			Description: "OK. The status property tells whether the request succeeded",
+			Content: map[string]OpenAPIMediaType{
+				"application/json": {Schema: makeOpenAPIResponseSchema(spec)},
+			},
+		}
+	}
+	for code, description := range spec.OtherResponses {
+		operation.Responses[code] = OpenAPIResponse{Description: description}
+	}
+	messageSchema := &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"message": {Type: "string"},
+		},
+	}
+	if spec.SessionRequired {
+		operation.Responses["401"] = OpenAPIResponse{
+			Description: "The session does not exist, or the access token has expired and the session must be refreshed",
+			Content:     map[string]OpenAPIMediaType{"application/json": {Schema: messageSchema}},
+		}
+		operation.Responses["403"] = OpenAPIResponse{
+			Description: "A claim of the session is invalid",
+			Content: map[string]OpenAPIMediaType{"application/json": {Schema: &OpenAPISchema{
+				Type: "object",
+				Properties: map[string]*OpenAPISchema{
+					"message": {Type: "string"},
+					"claimValidationErrors": {
+						Type: "array",
+						Items: &OpenAPISchema{
+							Type: "object",
+							Properties: map[string]*OpenAPISchema{
+								"id":     {Type: "string"},
+								"reason": {},
+							},
+						},
+					},
+				},
+			}}},
+		}
+		operation.Security = []map[string][]string{{"bearerAuth": {}}, {"cookieAuth": {}}}
+	}
+	operation.Responses["400"] = OpenAPIResponse{
+		Description: "The request is invalid, for example because of a missing field",
+		Content:     map[string]OpenAPIMediaType{"application/json": {Schema: messageSchema}},
+	}
+	operation.Responses["500"] = OpenAPIResponse{Description: "Internal error"}
+	return operation
+}
+
+func makeOpenAPIResponseSchema(spec *APISpec) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+	if spec.Response != nil {
+		*schema = *spec.Response
+		schema.Properties = map[string]*OpenAPISchema{}
+		for name, property := range spec.Response.Properties {
+			schema.Properties[name] = property
+		}
+	}
+	if len(spec.Statuses) == 0 {
+		return schema
+	}
+	statuses := append([]string{}, spec.Statuses...)
+	hasGeneralError := false
+	for _, status := range statuses {
+		if status == "GENERAL_ERROR" {
+			hasGeneralError = true
+		}
+	}
+	if !hasGeneralError {
+		statuses = append(statuses, "GENERAL_ERROR")
+	}
+	schema.Properties["status"] = &OpenAPISchema{Type: "string", Enum: statuses}
+	if _, ok := schema.Properties["message"]; !ok {
+		schema.Properties["message"] = &OpenAPISchema{Type: "string", Description: "Set if the status is GENERAL_ERROR"}
+	}
+	schema.Required = append(append([]string{}, schema.Required...), "status")
+	return schema
+}
+
+// makeOpenAPIOperationID turns the recipe ID, method and API ID into an identifier, e.g.
+// emailpasswordPostSignup for the sign up API
+func makeOpenAPIOperationID(recipeID string, method string, id string, forTenant bool) string {
+	result := recipeID + strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+	upperNext := true
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		result += string(r)
+	}
+	if forTenant {
+		result += "ForTenant"
+	}
+	return result
+}
+
+func (s *Instance) serveOpenAPIDocument(res http.ResponseWriter, req *http.Request, userContext UserContext) {
+	document, err := s.GetOpenAPIDocument()
+	if err == nil {
+		var body []byte
+		body, err = json.Marshal(document)
+		if err == nil {
+			res.Header().Set("Content-Type", "application/json; charset=utf-8")
+			res.WriteHeader(http.StatusOK)
+			res.Write(body)
+			return
+		}
+	}
+	LogError(userContext, "serving the OpenAPI document failed", "error", err.Error())
+	s.OnSuperTokensAPIError(err, req, res)
+}
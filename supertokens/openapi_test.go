@@ -0,0 +1,158 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func openAPITestRecipe(appInfo NormalisedAppinfo, onSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)) (*RecipeModule, error) {
+	getAPIsHandled := func() ([]APIHandled, error) {
+		signInPath, err := NewNormalisedURLPath("/signin")
+		if err != nil {
+			return nil, err
+		}
+		userPath, err := NewNormalisedURLPath("/user")
+		if err != nil {
+			return nil, err
+		}
+		disabledPath, err := NewNormalisedURLPath("/disabled")
+		if err != nil {
+			return nil, err
+		}
+		return []APIHandled{{
+			Method:                 http.MethodPost,
+			PathWithoutAPIBasePath: signInPath,
+			ID:                     "/signin",
+			Spec: &APISpec{
+				Summary: "Signs in",
+				RequestBody: &OpenAPISchema{
+					Type:       "object",
+					Properties: map[string]*OpenAPISchema{"email": {Type: "string"}},
+				},
+				Statuses: []string{"OK", "WRONG_CREDENTIALS_ERROR"},
+			},
+		}, {
+			Method:                 http.MethodGet,
+			PathWithoutAPIBasePath: userPath,
+			ID:                     "/user",
+			Spec: &APISpec{
+				SessionRequired: true,
+			},
+		}, {
+			Method:                 http.MethodDelete,
+			PathWithoutAPIBasePath: userPath,
+			ID:                     "/user",
+		}, {
+			Method:                 http.MethodGet,
+			PathWithoutAPIBasePath: disabledPath,
+			ID:                     "/disabled",
+			Disabled:               true,
+		}}, nil
+	}
+	handleAPIRequest := func(id string, tenantId string, req *http.Request, res http.ResponseWriter, theirHandler http.HandlerFunc, path NormalisedURLPath, method string, userContext UserContext) error {
+		return nil
+	}
+	handleError := func(err error, req *http.Request, res http.ResponseWriter, userContext UserContext) (bool, error) {
+		return false, nil
+	}
+	recipeModule := MakeRecipeModule("test", appInfo, handleAPIRequest, func() []string { return nil }, getAPIsHandled, nil, handleError, onSuperTokensAPIError)
+	return &recipeModule, nil
+}
+
+func newOpenAPITestInstance(t *testing.T, openAPI *OpenAPIConfig) *Instance {
+	ResetForTest()
+	apiBasePath := "/api/auth"
+	instance, err := New(TypeInput{
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+			APIBasePath:   &apiBasePath,
+		},
+		RecipeList: []Recipe{openAPITestRecipe},
+		OpenAPI:    openAPI,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return instance
+}
+
+func TestThatTheOpenAPIDocumentDescribesTheAPIsOfTheRecipes(t *testing.T) {
+	instance := newOpenAPITestInstance(t, nil)
+
+	document, err := instance.GetOpenAPIDocument()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+	assert.Equal(t, "SuperTokens", document.Info.Title)
+	assert.Equal(t, "https://api.supertokens.io", document.Servers[0].URL)
+	assert.Len(t, document.Paths, 4)
+	assert.NotContains(t, document.Paths, "/api/auth/disabled")
+
+	signIn := document.Paths["/api/auth/signin"]["post"]
+	assert.Equal(t, "testPostSignin", signIn.OperationID)
+	assert.Equal(t, "Signs in", signIn.Summary)
+	assert.Equal(t, "string", signIn.RequestBody.Content["application/json"].Schema.Properties["email"].Type)
+	assert.Equal(t, []string{"OK", "WRONG_CREDENTIALS_ERROR", "GENERAL_ERROR"}, signIn.Responses["200"].Content["application/json"].Schema.Properties["status"].Enum)
+	assert.NotContains(t, signIn.Responses, "401")
+
+	tenantSignIn := document.Paths["/api/auth/{tenantId}/signin"]["post"]
+	assert.Equal(t, "testPostSigninForTenant", tenantSignIn.OperationID)
+	assert.Equal(t, "tenantId", tenantSignIn.Parameters[0].Name)
+	assert.Equal(t, "path", tenantSignIn.Parameters[0].In)
+
+	user := document.Paths["/api/auth/user"]
+	assert.Len(t, user, 2)
+	assert.Contains(t, user["get"].Responses, "401")
+	assert.Contains(t, user["get"].Responses, "403")
+	assert.NotEmpty(t, user["get"].Security)
+	assert.Contains(t, user["delete"].Responses, "200")
+	assert.Empty(t, user["delete"].Security)
+}
+
+func TestThatTheOpenAPIDocumentIsServedIfEnabled(t *testing.T) {
+	path := "/docs/openapi.json"
+	handler := newOpenAPITestInstance(t, &OpenAPIConfig{Path: &path}).Middleware(http.NotFoundHandler())
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/auth/docs/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/json; charset=utf-8", res.Header().Get("Content-Type"))
+	var document map[string]interface{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &document))
+	assert.Equal(t, "3.0.3", document["openapi"])
+	assert.Contains(t, document["paths"], "/api/auth/signin")
+
+	// the default path is not served
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/auth/openapi.json", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	// and the document is not served if it is not enabled
+	handler = newOpenAPITestInstance(t, nil).Middleware(http.NotFoundHandler())
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/auth/docs/openapi.json", nil))
+	assert.Equal(t, http.StatusNotFound, res.Code)
+}
@@ -7,16 +7,23 @@ func AddPostInitCallback(cb func() error) {
 }
 
 func runPostInitCallbacks() error {
-	for _, cb := range postInitCallbacks {
+	// the callbacks are cleared first so that the ones of an instance that failed to
+	// initialise are not run again for the next instance
+	callbacks := postInitCallbacks
+	resetPostInitCallbacks()
+	for _, cb := range callbacks {
 		err := cb()
 		if err != nil {
 			return err
 		}
 	}
-	postInitCallbacks = []func() error{}
 	return nil
 }
 
-func resetPostInitCallbackForTest() {
+func resetPostInitCallbacks() {
 	postInitCallbacks = []func() error{}
 }
+
+func resetPostInitCallbackForTest() {
+	resetPostInitCallbacks()
+}
@@ -17,18 +17,23 @@ package supertokens
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Querier struct {
 	RIDToCore string
+	core      *coreConnection
 }
 
 type QuerierHost struct {
@@ -36,38 +41,73 @@ type QuerierHost struct {
 	BasePath NormalisedURLPath
 }
 
+// coreConnection is the state that is needed to talk to the cores of one SuperTokens instance
+type coreConnection struct {
+	initCalled     bool
+	hosts          []QuerierHost
+	apiKey         *string
+	apiVersion     string
+	lastTriedIndex int
+	lock           sync.Mutex
+	hostLock       sync.Mutex
+	interceptor    func(*http.Request, UserContext) *http.Request
+	requestTimeout time.Duration
+	httpClient     *http.Client
+	hostStates     []querierHostState
+	hostHealth     normalisedHostHealthConfig
+	retryPolicy    normalisedRetryPolicy
+	probeStop      chan struct{}
+	cache          *normalisedCacheConfig
+}
+
+// defaultCoreConnection is used by the instance created by Init
+var defaultCoreConnection = &coreConnection{}
+
 var (
-	querierInitCalled     bool          = false
-	QuerierHosts          []QuerierHost = nil
-	QuerierAPIKey         *string
-	querierAPIVersion     string
-	querierLastTriedIndex int
-	querierLock           sync.Mutex
-	querierHostLock       sync.Mutex
-	querierInterceptor    func(*http.Request, UserContext) *http.Request
+	// QuerierHosts and QuerierAPIKey are the ones of the instance created by Init
+	QuerierHosts  []QuerierHost = nil
+	QuerierAPIKey *string
 )
 
 func SetQuerierApiVersionForTests(version string) {
-	querierAPIVersion = version
+	defaultCoreConnection.apiVersion = version
+}
+
+func (q *Querier) getCoreConnection() *coreConnection {
+	if q.core == nil {
+		return defaultCoreConnection
+	}
+	return q.core
 }
 
 func (q *Querier) GetQuerierAPIVersion() (string, error) {
-	querierLock.Lock()
-	defer querierLock.Unlock()
-	if querierAPIVersion != "" {
-		return querierAPIVersion, nil
+	return q.getQuerierAPIVersion(context.Background())
+}
+
+// getQuerierAPIVersion is called before a request picks a host, so that failing to fetch the version
+// does not count as a failure of the host that the request is sent to. The lock is not held while
+// the version is fetched, so that a slow core does not block the requests that already have it.
+func (q *Querier) getQuerierAPIVersion(ctx context.Context) (string, error) {
+	core := q.getCoreConnection()
+	core.lock.Lock()
+	apiVersion := core.apiVersion
+	core.lock.Unlock()
+	if apiVersion != "" {
+		return apiVersion, nil
 	}
-	response, _, err := q.sendRequestHelper(NormalisedURLPath{value: "/apiversion"}, func(url string) (*http.Response, error) {
-		req, err := http.NewRequest("GET", url, nil)
+	ctx, cancel := core.getContextWithTimeout(ctx)
+	defer cancel()
+	apiVersionPath := NormalisedURLPath{value: "/apiversion"}
+	response, _, err := q.sendRequestHelper(ctx, "GET", apiVersionPath, func(attemptCtx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(attemptCtx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
+		if core.apiKey != nil {
+			req.Header.Set("api-key", *core.apiKey)
 		}
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return core.doRequest(req, apiVersionPath)
+	}, len(core.hosts), nil)
 
 	if err != nil {
 		return "", err
@@ -89,29 +129,119 @@ func (q *Querier) GetQuerierAPIVersion() (string, error) {
 		return "", errors.New("the running SuperTokens core version is not compatible with this Golang SDK. Please visit https://supertokens.io/docs/community/compatibility-table to find the right version")
 	}
 
-	querierAPIVersion = *supportedVersion
+	core.lock.Lock()
+	core.apiVersion = *supportedVersion
+	core.lock.Unlock()
 
-	return querierAPIVersion, nil
+	return *supportedVersion, nil
 }
 
-func GetNewQuerierInstanceOrThrowError(rIDToCore string) (*Querier, error) {
-	if !querierInitCalled {
-		return nil, errors.New("please call the supertokens.init function before using SuperTokens")
+// GetNewQuerierInstanceOrThrowError returns a querier for the cores of the SuperTokens instance that the
+// user context belongs to (see SetInstanceInUserContext), or of the instance created by Init if there is none.
+func GetNewQuerierInstanceOrThrowError(rIDToCore string, userContext ...UserContext) (*Querier, error) {
+	core := getCoreConnectionForUserContext(userContext...)
+	if !core.initCalled {
+		return nil, NotInitialisedError{Msg: "please call the supertokens.init function before using SuperTokens"}
 	}
-	return &Querier{RIDToCore: rIDToCore}, nil
+	return &Querier{RIDToCore: rIDToCore, core: core}, nil
 }
 
-func initQuerier(hosts []QuerierHost, APIKey string, interceptor func(*http.Request, UserContext) *http.Request) {
-	if !querierInitCalled {
-		querierInitCalled = true
-		QuerierHosts = hosts
-		if APIKey != "" {
-			QuerierAPIKey = &APIKey
+func getCoreConnectionForUserContext(userContext ...UserContext) *coreConnection {
+	instance := getInstanceForUserContext(userContext...)
+	if instance != nil && instance.core != nil {
+		return instance.core
+	}
+	return defaultCoreConnection
+}
+
+func initQuerier(hosts []QuerierHost, config ConnectionInfo) {
+	defaultCoreConnection.init(hosts, config)
+	if defaultCoreConnection.initCalled {
+		QuerierHosts = defaultCoreConnection.hosts
+		QuerierAPIKey = defaultCoreConnection.apiKey
+	}
+}
+
+func (c *coreConnection) init(hosts []QuerierHost, config ConnectionInfo) {
+	if !c.initCalled {
+		c.initCalled = true
+		c.hosts = hosts
+		c.apiKey = nil
+		if config.APIKey != "" {
+			APIKey := config.APIKey
+			c.apiKey = &APIKey
+		}
+		c.apiVersion = ""
+		c.lastTriedIndex = 0
+		c.interceptor = config.NetworkInterceptor
+		c.requestTimeout = config.RequestTimeout
+		httpClient := config.HTTPClient
+		if httpClient == nil {
+			// We use a dedicated transport so that connections to the core are pooled
+			// separately from any other traffic that goes through http.DefaultTransport
+			httpClient = &http.Client{
+				Transport: http.DefaultTransport.(*http.Transport).Clone(),
+			}
 		}
-		querierAPIVersion = ""
-		querierLastTriedIndex = 0
-		querierInterceptor = interceptor
+		c.httpClient = httpClient
+		c.hostStates = make([]querierHostState, len(hosts))
+		c.hostHealth = normaliseHostHealthConfig(config.HostHealth)
+		c.retryPolicy = normaliseRetryPolicy(config.RetryPolicy)
+		c.startHostProbing()
+	}
+}
+
+// GetQuerierHTTPClient returns the client that is used for all requests to the core.
+// Recipes that talk to the core without going through the Querier (for example to fetch
+// the JWKS) should use this so that the TLS and proxy settings in ConnectionInfo apply.
+func GetQuerierHTTPClient(userContext ...UserContext) *http.Client {
+	return getCoreConnectionForUserContext(userContext...).getHTTPClient()
+}
+
+func (q *Querier) GetHTTPClient() *http.Client {
+	return q.getCoreConnection().getHTTPClient()
+}
+
+func (c *coreConnection) getHTTPClient() *http.Client {
+	if c.httpClient == nil {
+		return http.DefaultClient
+	}
+	return c.httpClient
+}
+
+// getContextWithTimeout applies the RequestTimeout from ConnectionInfo (if any)
+// to ctx. The deadline covers the whole call to the core, including retries.
+func (c *coreConnection) getContextWithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout > 0 {
+		return context.WithTimeout(ctx, c.requestTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func startQuerierSpan(ctx context.Context, method string, path NormalisedURLPath) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "supertokens.core "+method+" "+path.GetAsStringDangerous(), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.method", method),
+		attribute.String("supertokens.core.path", path.GetAsStringDangerous()),
+	))
+}
+
+// doRequest sends a single request to a core host, propagating the trace context of the request
+// and recording its duration.
+func (c *coreConnection) doRequest(req *http.Request, path NormalisedURLPath) (*http.Response, error) {
+	injectTraceContext(req.Context(), req.Header)
+	start := time.Now()
+	resp, err := c.getHTTPClient().Do(req)
+	status := "error"
+	if err == nil {
+		trace.SpanFromContext(req.Context()).SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		status = strconv.Itoa(resp.StatusCode)
 	}
+	ObserveHistogramMetric(MetricCoreRequestDuration, map[string]string{
+		"method": req.Method,
+		"path":   path.GetAsStringDangerous(),
+		"status": status,
+	}, time.Since(start).Seconds())
+	return resp, err
 }
 
 func (q *Querier) SendPostRequest(path string, data map[string]interface{}, userContext UserContext) (map[string]interface{}, error) {
@@ -119,7 +249,16 @@ func (q *Querier) SendPostRequest(path string, data map[string]interface{}, user
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
+	core := q.getCoreConnection()
+	ctx, cancel := core.getContextWithTimeout(getContextFromUserContext(userContext))
+	defer cancel()
+	ctx, span := startQuerierSpan(ctx, "POST", nP)
+	apiVersion, err := q.getQuerierAPIVersion(ctx)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	resp, _, err := q.sendRequestHelper(ctx, "POST", nP, func(attemptCtx context.Context, url string) (*http.Response, error) {
 		if data == nil {
 			data = map[string]interface{}{}
 		}
@@ -127,32 +266,27 @@ func (q *Querier) SendPostRequest(path string, data map[string]interface{}, user
 		if err != nil {
 			return nil, err
 		}
-		req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(attemptCtx, "POST", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, err
 		}
 
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
-
 		req.Header.Set("content-type", "application/json; charset=utf-8")
 		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
+		if core.apiKey != nil {
+			req.Header.Set("api-key", *core.apiKey)
 		}
 		if nP.IsARecipePath() && q.RIDToCore != "" {
 			req.Header.Set("rid", q.RIDToCore)
 		}
 
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
+		if core.interceptor != nil {
+			req = core.interceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return core.doRequest(req, nP)
+	}, len(core.hosts), nil)
+	endSpan(span, err)
 	return resp, err
 }
 
@@ -161,12 +295,21 @@ func (q *Querier) SendDeleteRequest(path string, data map[string]interface{}, pa
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
+	core := q.getCoreConnection()
+	ctx, cancel := core.getContextWithTimeout(getContextFromUserContext(userContext))
+	defer cancel()
+	ctx, span := startQuerierSpan(ctx, "DELETE", nP)
+	apiVersion, err := q.getQuerierAPIVersion(ctx)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	resp, _, err := q.sendRequestHelper(ctx, "DELETE", nP, func(attemptCtx context.Context, url string) (*http.Response, error) {
 		jsonData, err := json.Marshal(data)
 		if err != nil {
 			return nil, err
 		}
-		req, err := http.NewRequest("DELETE", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(attemptCtx, "DELETE", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, err
 		}
@@ -178,27 +321,22 @@ func (q *Querier) SendDeleteRequest(path string, data map[string]interface{}, pa
 		}
 		req.URL.RawQuery = query.Encode()
 
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
-
 		req.Header.Set("content-type", "application/json; charset=utf-8")
 		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
+		if core.apiKey != nil {
+			req.Header.Set("api-key", *core.apiKey)
 		}
 		if nP.IsARecipePath() && q.RIDToCore != "" {
 			req.Header.Set("rid", q.RIDToCore)
 		}
 
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
+		if core.interceptor != nil {
+			req = core.interceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return core.doRequest(req, nP)
+	}, len(core.hosts), nil)
+	endSpan(span, err)
 	return resp, err
 }
 
@@ -207,8 +345,17 @@ func (q *Querier) SendGetRequest(path string, params map[string]string, userCont
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		req, err := http.NewRequest("GET", url, nil)
+	core := q.getCoreConnection()
+	ctx, cancel := core.getContextWithTimeout(getContextFromUserContext(userContext))
+	defer cancel()
+	ctx, span := startQuerierSpan(ctx, "GET", nP)
+	apiVersion, err := q.getQuerierAPIVersion(ctx)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	resp, _, err := q.sendRequestHelper(ctx, "GET", nP, func(attemptCtx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(attemptCtx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -220,25 +367,21 @@ func (q *Querier) SendGetRequest(path string, params map[string]string, userCont
 		}
 		req.URL.RawQuery = query.Encode()
 
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
 		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
+		if core.apiKey != nil {
+			req.Header.Set("api-key", *core.apiKey)
 		}
 		if nP.IsARecipePath() && q.RIDToCore != "" {
 			req.Header.Set("rid", q.RIDToCore)
 		}
 
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
+		if core.interceptor != nil {
+			req = core.interceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return core.doRequest(req, nP)
+	}, len(core.hosts), nil)
+	endSpan(span, err)
 	return resp, err
 }
 
@@ -247,9 +390,18 @@ func (q *Querier) SendGetRequestWithResponseHeaders(path string, params map[stri
 	if err != nil {
 		return nil, nil, err
 	}
+	core := q.getCoreConnection()
+	ctx, cancel := core.getContextWithTimeout(getContextFromUserContext(userContext))
+	defer cancel()
+	ctx, span := startQuerierSpan(ctx, "GET", nP)
+	apiVersion, err := q.getQuerierAPIVersion(ctx)
+	if err != nil {
+		endSpan(span, err)
+		return nil, nil, err
+	}
 
-	return q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
-		req, err := http.NewRequest("GET", url, nil)
+	resp, headers, err := q.sendRequestHelper(ctx, "GET", nP, func(attemptCtx context.Context, url string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(attemptCtx, "GET", url, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -261,25 +413,22 @@ func (q *Querier) SendGetRequestWithResponseHeaders(path string, params map[stri
 		}
 		req.URL.RawQuery = query.Encode()
 
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
 		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
+		if core.apiKey != nil {
+			req.Header.Set("api-key", *core.apiKey)
 		}
 		if nP.IsARecipePath() && q.RIDToCore != "" {
 			req.Header.Set("rid", q.RIDToCore)
 		}
 
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
+		if core.interceptor != nil {
+			req = core.interceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return core.doRequest(req, nP)
+	}, len(core.hosts), nil)
+	endSpan(span, err)
+	return resp, headers, err
 }
 
 func (q *Querier) SendPutRequest(path string, data map[string]interface{}, userContext UserContext) (map[string]interface{}, error) {
@@ -287,51 +436,65 @@ func (q *Querier) SendPutRequest(path string, data map[string]interface{}, userC
 	if err != nil {
 		return nil, err
 	}
-	resp, _, err := q.sendRequestHelper(nP, func(url string) (*http.Response, error) {
+	core := q.getCoreConnection()
+	ctx, cancel := core.getContextWithTimeout(getContextFromUserContext(userContext))
+	defer cancel()
+	ctx, span := startQuerierSpan(ctx, "PUT", nP)
+	apiVersion, err := q.getQuerierAPIVersion(ctx)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	resp, _, err := q.sendRequestHelper(ctx, "PUT", nP, func(attemptCtx context.Context, url string) (*http.Response, error) {
 		jsonData, err := json.Marshal(data)
 		if err != nil {
 			return nil, err
 		}
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+		req, err := http.NewRequestWithContext(attemptCtx, "PUT", url, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, err
 		}
 
-		apiVersion, querierAPIVersionError := q.GetQuerierAPIVersion()
-		if querierAPIVersionError != nil {
-			return nil, querierAPIVersionError
-		}
-
 		req.Header.Set("content-type", "application/json; charset=utf-8")
 		req.Header.Set("cdi-version", apiVersion)
-		if QuerierAPIKey != nil {
-			req.Header.Set("api-key", *QuerierAPIKey)
+		if core.apiKey != nil {
+			req.Header.Set("api-key", *core.apiKey)
 		}
 		if nP.IsARecipePath() && q.RIDToCore != "" {
 			req.Header.Set("rid", q.RIDToCore)
 		}
 
-		if querierInterceptor != nil {
-			req = querierInterceptor(req, userContext)
+		if core.interceptor != nil {
+			req = core.interceptor(req, userContext)
 		}
 
-		client := &http.Client{}
-		return client.Do(req)
-	}, len(QuerierHosts), nil)
+		return core.doRequest(req, nP)
+	}, len(core.hosts), nil)
+	endSpan(span, err)
 	return resp, err
 }
 
-type httpRequestFunction func(url string) (*http.Response, error)
+type httpRequestFunction func(attemptCtx context.Context, url string) (*http.Response, error)
+
+// GetAllCoreUrlsForPath returns the URLs of the given path on every core of the SuperTokens instance
+// that the user context belongs to, or of the instance created by Init if there is none.
+func GetAllCoreUrlsForPath(path string, userContext ...UserContext) []string {
+	return getCoreConnectionForUserContext(userContext...).getAllUrlsForPath(path)
+}
+
+func (q *Querier) GetAllCoreUrlsForPath(path string) []string {
+	return q.getCoreConnection().getAllUrlsForPath(path)
+}
 
-func GetAllCoreUrlsForPath(path string) []string {
-	if QuerierHosts == nil {
+func (c *coreConnection) getAllUrlsForPath(path string) []string {
+	if c.hosts == nil {
 		return []string{}
 	}
 
 	normalisedPath := NormalisedURLPath{value: path}
 	result := []string{}
 
-	for _, host := range QuerierHosts {
+	for _, host := range c.hosts {
 		currentDomain := host.Domain.GetAsStringDangerous()
 		currentBasePath := host.BasePath.GetAsStringDangerous()
 
@@ -341,17 +504,30 @@ func GetAllCoreUrlsForPath(path string) []string {
 	return result
 }
 
-func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequestFunction, numberOfTries int, retryInfoMap *map[string]int) (map[string]interface{}, http.Header, error) {
+// canReplayRequest returns whether a request with the method can be sent to another host after it failed
+// on one. Requests that change data can not, since they may have been handled before the failure.
+func canReplayRequest(method string) bool {
+	return method == http.MethodGet || method == http.MethodDelete
+}
+
+func (q *Querier) sendRequestHelper(ctx context.Context, method string, path NormalisedURLPath, httpRequest httpRequestFunction, numberOfTries int, retryInfoMap *map[string]int) (map[string]interface{}, http.Header, error) {
 	if numberOfTries == 0 {
-		return nil, nil, errors.New("no SuperTokens core available to query")
+		return nil, nil, CoreUnavailableError{Path: path.GetAsStringDangerous()}
+	}
+
+	// We do not try any more hosts if the caller is no longer waiting for the result
+	if ctx.Err() != nil {
+		return nil, nil, ctx.Err()
 	}
 
-	querierHostLock.Lock()
-	currentDomain := QuerierHosts[querierLastTriedIndex].Domain.GetAsStringDangerous()
-	currentBasePath := QuerierHosts[querierLastTriedIndex].BasePath.GetAsStringDangerous()
+	core := q.getCoreConnection()
+	core.hostLock.Lock()
+	hostIndex := core.pickHost()
+	currentDomain := core.hosts[hostIndex].Domain.GetAsStringDangerous()
+	currentBasePath := core.hosts[hostIndex].BasePath.GetAsStringDangerous()
 	url := currentDomain + currentBasePath + path.GetAsStringDangerous()
 
-	maxRetries := 5
+	maxRetries := core.retryPolicy.maxRetries
 	var _retryInfoMap map[string]int
 
 	if retryInfoMap != nil {
@@ -365,28 +541,53 @@ func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequ
 	if !ok {
 		_retryInfoMap[url] = maxRetries
 	}
+	core.hostLock.Unlock()
 
-	querierLastTriedIndex = (querierLastTriedIndex + 1) % len(QuerierHosts)
-	querierHostLock.Unlock()
-
-	resp, err := httpRequest(url)
+	attemptCtx, cancelAttempt := core.getAttemptContext(ctx)
+	defer cancelAttempt()
+	resp, err := httpRequest(attemptCtx, url)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "connection refused") {
-			return q.sendRequestHelper(path, httpRequest, numberOfTries-1, &_retryInfoMap)
-		}
 		if resp != nil {
 			resp.Body.Close()
 		}
-		return nil, nil, err
+		if ctx.Err() != nil {
+			// The host did not fail, the caller gave up waiting for it
+			core.releaseHostTrial(hostIndex)
+			return nil, nil, ctx.Err()
+		}
+		// Network errors (connection refused, DNS failures, timeouts of the attempt etc) mean that
+		// the host is unhealthy, so we try the next one. Requests that change data are only
+		// sent again if the host refused the connection, since the core may have already
+		// handled them otherwise.
+		core.recordHostFailure(hostIndex)
+		if numberOfTries > 1 && (canReplayRequest(method) || strings.Contains(err.Error(), "connection refused")) {
+			LogWarn(SetContextInUserContext(nil, ctx), "querier: request failed, trying the next core host", "host", currentDomain, "path", path.GetAsStringDangerous(), "error", err)
+			return q.sendRequestHelper(ctx, method, path, httpRequest, numberOfTries-1, &_retryInfoMap)
+		}
+		LogError(SetContextInUserContext(nil, ctx), "querier: request to the core failed", "host", currentDomain, "path", path.GetAsStringDangerous(), "error", err)
+		return nil, nil, CoreUnavailableError{Path: path.GetAsStringDangerous(), Err: err}
 	}
 
 	defer resp.Body.Close()
 
 	body, readErr := ioutil.ReadAll(resp.Body)
 	if readErr != nil {
-		return nil, nil, readErr
+		core.recordHostFailure(hostIndex)
+		LogError(SetContextInUserContext(nil, ctx), "querier: reading the response of the core failed", "host", currentDomain, "path", path.GetAsStringDangerous(), "error", readErr)
+		return nil, nil, CoreUnavailableError{Path: path.GetAsStringDangerous(), Err: readErr}
+	}
+
+	if resp.StatusCode >= 500 {
+		core.recordHostFailure(hostIndex)
+		if numberOfTries > 1 && canReplayRequest(method) {
+			LogWarn(SetContextInUserContext(nil, ctx), "querier: core host responded with an error, trying the next one", "host", currentDomain, "path", path.GetAsStringDangerous(), "status", resp.StatusCode)
+			return q.sendRequestHelper(ctx, method, path, httpRequest, numberOfTries-1, &_retryInfoMap)
+		}
+	} else {
+		core.recordHostSuccess(hostIndex)
 	}
+
 	if resp.StatusCode != 200 {
 		if resp.StatusCode == RateLimitStatusCode {
 			retriesLeft := _retryInfoMap[url]
@@ -395,15 +596,21 @@ func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequ
 				_retryInfoMap[url] = retriesLeft - 1
 
 				attemptsMade := maxRetries - retriesLeft
-				delay := 10 + (250 * attemptsMade)
 
-				time.Sleep(time.Millisecond * time.Duration(delay))
+				timer := time.NewTimer(core.retryPolicy.getDelay(attemptsMade))
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return nil, nil, ctx.Err()
+				}
 
-				return q.sendRequestHelper(path, httpRequest, numberOfTries, &_retryInfoMap)
+				return q.sendRequestHelper(ctx, method, path, httpRequest, numberOfTries, &_retryInfoMap)
 			}
 		}
 
-		return nil, nil, fmt.Errorf("SuperTokens core threw an error for a request to path: '%s' with status code: %v and message: %s", path.GetAsStringDangerous(), resp.StatusCode, body)
+		LogError(SetContextInUserContext(nil, ctx), "querier: core responded with an error", "path", path.GetAsStringDangerous(), "status", resp.StatusCode, "response", string(body))
+		return nil, nil, CoreError{StatusCode: resp.StatusCode, Path: path.GetAsStringDangerous(), Body: string(body)}
 	}
 
 	headers := resp.Header.Clone()
@@ -418,9 +625,12 @@ func (q *Querier) sendRequestHelper(path NormalisedURLPath, httpRequest httpRequ
 }
 
 func ResetQuerierForTest() {
-	querierInitCalled = false
+	defaultCoreConnection.stopHostProbing()
+	defaultCoreConnection = &coreConnection{}
+	QuerierHosts = nil
+	QuerierAPIKey = nil
 }
 
 func (q *Querier) SetApiVersionForTests(apiVersion string) {
-	querierAPIVersion = apiVersion
+	q.getCoreConnection().apiVersion = apiVersion
 }
@@ -0,0 +1,79 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatRequestTimeoutAbortsCallsToTheCore(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		rw.WriteHeader(200)
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI:  testServer.URL,
+		RequestTimeout: 100 * time.Millisecond,
+	}))
+
+	start := time.Now()
+	_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestThatCancellingTheContextStopsRetries(t *testing.T) {
+	mux := http.NewServeMux()
+
+	numberOfTimesCalled := 0
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesCalled++
+		if numberOfTimesCalled == 2 {
+			cancel()
+		}
+		rw.WriteHeader(RateLimitStatusCode)
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	}))
+
+	userContext := SetContextInUserContext(nil, ctx)
+	_, err := q.SendGetRequest("/testing", map[string]string{}, userContext)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 2, numberOfTimesCalled)
+}
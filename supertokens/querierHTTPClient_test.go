@@ -0,0 +1,64 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRoundTripper struct {
+	count int
+}
+
+func (c *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestThatTheConfiguredHTTPClientIsUsedForAllRequests(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	transport := &countingRoundTripper{}
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+		HTTPClient:    httpClient,
+	}))
+
+	_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.NoError(t, err)
+	_, err = q.SendPostRequest("/testing", map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, transport.count)
+	assert.Equal(t, httpClient, q.GetHTTPClient())
+}
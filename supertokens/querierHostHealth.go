@@ -0,0 +1,249 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultHostFailureThreshold = 3
+	defaultHostEjectionDuration = 30 * time.Second
+	defaultMaxRetries           = 5
+)
+
+type HostHealthConfig struct {
+	// FailureThreshold is the number of consecutive failures (network errors, timeouts
+	// or 5xx responses) after which a host is ejected. Defaults to 3.
+	FailureThreshold int
+	// EjectionDuration is how long an ejected host is skipped before a single trial
+	// request is allowed through again. Defaults to 30 seconds.
+	EjectionDuration time.Duration
+	// ProbeInterval enables active probing of the /hello API of ejected hosts so that
+	// they can be re-admitted before a real request is sent to them. Disabled if 0.
+	ProbeInterval time.Duration
+	// AttemptTimeout is how long a single host is waited for before the request counts as
+	// failed on it and the next host is tried. Disabled if 0.
+	AttemptTimeout time.Duration
+}
+
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request that is rate limited by the core is retried. Defaults to 5.
+	MaxRetries *int
+	// GetDelay returns how long to wait before retrying a rate limited request, given the number of
+	// retries that have already been made. Defaults to 10ms + 250ms for every retry made so far.
+	GetDelay func(attemptsMade int) time.Duration
+}
+
+type normalisedHostHealthConfig struct {
+	failureThreshold int
+	ejectionDuration time.Duration
+	probeInterval    time.Duration
+	attemptTimeout   time.Duration
+}
+
+type normalisedRetryPolicy struct {
+	maxRetries int
+	getDelay   func(attemptsMade int) time.Duration
+}
+
+type querierHostState struct {
+	consecutiveFailures int
+	ejectedUntil        time.Time
+	trialInFlight       bool
+}
+
+func normaliseHostHealthConfig(config *HostHealthConfig) normalisedHostHealthConfig {
+	result := normalisedHostHealthConfig{
+		failureThreshold: defaultHostFailureThreshold,
+		ejectionDuration: defaultHostEjectionDuration,
+	}
+	if config == nil {
+		return result
+	}
+	if config.FailureThreshold > 0 {
+		result.failureThreshold = config.FailureThreshold
+	}
+	if config.EjectionDuration > 0 {
+		result.ejectionDuration = config.EjectionDuration
+	}
+	result.probeInterval = config.ProbeInterval
+	result.attemptTimeout = config.AttemptTimeout
+	return result
+}
+
+func normaliseRetryPolicy(config *RetryPolicy) normalisedRetryPolicy {
+	result := normalisedRetryPolicy{
+		maxRetries: defaultMaxRetries,
+		getDelay: func(attemptsMade int) time.Duration {
+			return time.Millisecond * time.Duration(10+(250*attemptsMade))
+		},
+	}
+	if config == nil {
+		return result
+	}
+	if config.MaxRetries != nil {
+		result.maxRetries = *config.MaxRetries
+	}
+	if config.GetDelay != nil {
+		result.getDelay = config.GetDelay
+	}
+	return result
+}
+
+// getAttemptContext returns the context for sending a request to a single host. It is
+// done when the AttemptTimeout has passed, even if the caller is still waiting.
+func (c *coreConnection) getAttemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.hostHealth.attemptTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.hostHealth.attemptTimeout)
+}
+
+// pickHost returns the index of the next host to query. Hosts whose circuit is
+// open are skipped. Once the ejection duration has passed, a single trial request is let
+// through. If no host is available we fall back to plain round robin, since trying a host
+// that may be down is better than not trying at all.
+//
+// This must be called with hostLock held.
+func (c *coreConnection) pickHost() int {
+	if len(c.hostStates) != len(c.hosts) {
+		c.hostStates = make([]querierHostState, len(c.hosts))
+	}
+	if c.lastTriedIndex >= len(c.hosts) {
+		c.lastTriedIndex = 0
+	}
+	now := time.Now()
+	for i := 0; i < len(c.hosts); i++ {
+		index := (c.lastTriedIndex + i) % len(c.hosts)
+		state := &c.hostStates[index]
+		if state.consecutiveFailures < c.hostHealth.failureThreshold {
+			c.lastTriedIndex = (index + 1) % len(c.hosts)
+			return index
+		}
+		if now.After(state.ejectedUntil) && !state.trialInFlight {
+			state.trialInFlight = true
+			c.lastTriedIndex = (index + 1) % len(c.hosts)
+			return index
+		}
+	}
+
+	index := c.lastTriedIndex
+	c.lastTriedIndex = (c.lastTriedIndex + 1) % len(c.hosts)
+	return index
+}
+
+func (c *coreConnection) recordHostSuccess(index int) {
+	c.hostLock.Lock()
+	defer c.hostLock.Unlock()
+	if index >= len(c.hostStates) {
+		return
+	}
+	if c.hostStates[index].consecutiveFailures >= c.hostHealth.failureThreshold {
+		LogInfo(nil, "querier: re-admitting core host", "host", c.hosts[index].Domain.GetAsStringDangerous())
+	}
+	c.hostStates[index] = querierHostState{}
+}
+
+func (c *coreConnection) recordHostFailure(index int) {
+	c.hostLock.Lock()
+	defer c.hostLock.Unlock()
+	if index >= len(c.hostStates) {
+		return
+	}
+	state := &c.hostStates[index]
+	state.consecutiveFailures++
+	state.trialInFlight = false
+	if state.consecutiveFailures >= c.hostHealth.failureThreshold {
+		if state.consecutiveFailures == c.hostHealth.failureThreshold {
+			LogWarn(nil, "querier: ejecting core host", "host", c.hosts[index].Domain.GetAsStringDangerous())
+		}
+		state.ejectedUntil = time.Now().Add(c.hostHealth.ejectionDuration)
+	}
+}
+
+func (c *coreConnection) releaseHostTrial(index int) {
+	c.hostLock.Lock()
+	defer c.hostLock.Unlock()
+	if index >= len(c.hostStates) {
+		return
+	}
+	c.hostStates[index].trialInFlight = false
+}
+
+func (c *coreConnection) isHostEjected(index int) bool {
+	c.hostLock.Lock()
+	defer c.hostLock.Unlock()
+	if index >= len(c.hostStates) {
+		return false
+	}
+	return c.hostStates[index].consecutiveFailures >= c.hostHealth.failureThreshold
+}
+
+func (c *coreConnection) startHostProbing() {
+	if c.hostHealth.probeInterval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	c.probeStop = stop
+	interval := c.hostHealth.probeInterval
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				c.probeEjectedHosts(interval)
+			}
+		}
+	}()
+}
+
+func (c *coreConnection) stopHostProbing() {
+	if c.probeStop != nil {
+		close(c.probeStop)
+		c.probeStop = nil
+	}
+}
+
+func (c *coreConnection) probeEjectedHosts(timeout time.Duration) {
+	for index, host := range c.hosts {
+		if !c.isHostEjected(index) {
+			continue
+		}
+		url := host.Domain.GetAsStringDangerous() + host.BasePath.GetAsStringDangerous() + "/hello"
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			cancel()
+			continue
+		}
+		resp, err := c.getHTTPClient().Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		if err == nil && resp.StatusCode == 200 {
+			c.recordHostSuccess(index)
+		} else {
+			c.recordHostFailure(index)
+		}
+	}
+}
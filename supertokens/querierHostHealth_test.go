@@ -0,0 +1,378 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThatUnhealthyCoreHostsAreEjected(t *testing.T) {
+	numberOfTimesFirstCalled := 0
+	numberOfTimesSecondCalled := 0
+
+	firstMux := http.NewServeMux()
+	firstMux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesFirstCalled++
+		rw.WriteHeader(503)
+	})
+	secondMux := http.NewServeMux()
+	secondMux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesSecondCalled++
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+
+	firstServer := httptest.NewServer(firstMux)
+	defer firstServer.Close()
+	secondServer := httptest.NewServer(secondMux)
+	defer secondServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: firstServer.URL + ";" + secondServer.URL,
+		HostHealth: &HostHealthConfig{
+			FailureThreshold: 2,
+			EjectionDuration: time.Minute,
+		},
+	}))
+
+	for i := 0; i < 6; i++ {
+		_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+		assert.NoError(t, err)
+	}
+
+	// The first host fails twice and is then skipped
+	assert.Equal(t, 2, numberOfTimesFirstCalled)
+	assert.Equal(t, 6, numberOfTimesSecondCalled)
+}
+
+func TestThatUnhealthyCoreHostsThatHangAreGivenUpOnAfterTheAttemptTimeout(t *testing.T) {
+	numberOfTimesFirstCalled := 0
+	numberOfTimesSecondCalled := 0
+
+	firstMux := http.NewServeMux()
+	firstMux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesFirstCalled++
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	secondMux := http.NewServeMux()
+	secondMux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesSecondCalled++
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+
+	firstServer := httptest.NewServer(firstMux)
+	defer firstServer.Close()
+	secondServer := httptest.NewServer(secondMux)
+	defer secondServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: firstServer.URL + ";" + secondServer.URL,
+		HostHealth: &HostHealthConfig{
+			FailureThreshold: 1,
+			EjectionDuration: time.Minute,
+			AttemptTimeout:   100 * time.Millisecond,
+		},
+	}))
+
+	start := time.Now()
+	_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	_, err = q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.NoError(t, err)
+
+	// The first host timed out once and was then ejected
+	assert.Equal(t, 1, numberOfTimesFirstCalled)
+	assert.Equal(t, 2, numberOfTimesSecondCalled)
+}
+
+func TestThatUnhealthyCoreHostsAreNotReplacedForRequestsThatChangeData(t *testing.T) {
+	numberOfTimesFirstCalled := 0
+	numberOfTimesSecondCalled := 0
+
+	firstMux := http.NewServeMux()
+	firstMux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesFirstCalled++
+		rw.WriteHeader(503)
+	})
+	secondMux := http.NewServeMux()
+	secondMux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesSecondCalled++
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+
+	firstServer := httptest.NewServer(firstMux)
+	defer firstServer.Close()
+	secondServer := httptest.NewServer(secondMux)
+	defer secondServer.Close()
+	// nothing listens on the address of a closed server, so the connection is refused
+	closedServer := httptest.NewServer(http.NewServeMux())
+	closedServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: firstServer.URL + ";" + secondServer.URL,
+	}))
+
+	// the first host may have handled the request before failing
+	_, err := q.SendPostRequest("/testing", map[string]interface{}{}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, numberOfTimesFirstCalled)
+	assert.Equal(t, 0, numberOfTimesSecondCalled)
+
+	// the second host is tried for requests that do not change data
+	_, err = q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, numberOfTimesSecondCalled)
+
+	// if the connection is refused, the request did not reach the host
+	q = newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: closedServer.URL + ";" + secondServer.URL,
+	}))
+	_, err = q.SendPostRequest("/testing", map[string]interface{}{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, numberOfTimesSecondCalled)
+}
+
+func TestThatEjectedCoreHostsAreReadmittedByProbing(t *testing.T) {
+	var lock sync.Mutex
+	healthy := false
+	numberOfTimesCalled := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		numberOfTimesCalled++
+		if !healthy {
+			rw.WriteHeader(503)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+	mux.HandleFunc("/hello", func(rw http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if !healthy {
+			rw.WriteHeader(503)
+			return
+		}
+		rw.WriteHeader(200)
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+		HostHealth: &HostHealthConfig{
+			FailureThreshold: 1,
+			EjectionDuration: time.Hour,
+			ProbeInterval:    50 * time.Millisecond,
+		},
+	}))
+
+	_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.Error(t, err)
+
+	lock.Lock()
+	healthy = true
+	lock.Unlock()
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, err = q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.NoError(t, err)
+}
+
+func TestThatTheRetryPolicyIsUsedForRateLimitedRequests(t *testing.T) {
+	mux := http.NewServeMux()
+
+	numberOfTimesCalled := 0
+	delaysRequested := []int{}
+
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		numberOfTimesCalled++
+		rw.WriteHeader(RateLimitStatusCode)
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	maxRetries := 2
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+		RetryPolicy: &RetryPolicy{
+			MaxRetries: &maxRetries,
+			GetDelay: func(attemptsMade int) time.Duration {
+				delaysRequested = append(delaysRequested, attemptsMade)
+				return time.Millisecond
+			},
+		},
+	}))
+
+	_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.Error(t, err)
+	assert.Equal(t, 3, numberOfTimesCalled)
+	assert.Equal(t, []int{0, 1}, delaysRequested)
+}
+
+func TestThatFailingToFetchTheAPIVersionDoesNotMakeTheCoreHostsUnhealthy(t *testing.T) {
+	var lock sync.Mutex
+	compatible := false
+	numberOfTimesVersionFetched := 0
+	numberOfTimesCalled := []int{0, 0}
+
+	newCore := func(index int) *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			numberOfTimesVersionFetched++
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(200)
+			if compatible {
+				rw.Write([]byte(`{"versions":["3.0"]}`))
+			} else {
+				rw.Write([]byte(`{"versions":["0.1"]}`))
+			}
+		})
+		mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			defer lock.Unlock()
+			numberOfTimesCalled[index]++
+			rw.Header().Set("Content-Type", "application/json")
+			rw.WriteHeader(200)
+			rw.Write([]byte("{}"))
+		})
+		return httptest.NewServer(mux)
+	}
+	firstServer := newCore(0)
+	defer firstServer.Close()
+	secondServer := newCore(1)
+	defer secondServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: firstServer.URL + ";" + secondServer.URL,
+		HostHealth: &HostHealthConfig{
+			FailureThreshold: 1,
+			EjectionDuration: time.Hour,
+		},
+	}))
+	q.SetApiVersionForTests("")
+
+	for i := 0; i < 2; i++ {
+		_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+		assert.Error(t, err)
+	}
+	// the requests are not sent to the next host when the version can not be fetched
+	assert.Equal(t, 2, numberOfTimesVersionFetched)
+	assert.Equal(t, []int{0, 0}, numberOfTimesCalled)
+
+	lock.Lock()
+	compatible = true
+	lock.Unlock()
+	for i := 0; i < 4; i++ {
+		_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+		assert.NoError(t, err)
+	}
+	// neither host was ejected
+	assert.Equal(t, []int{2, 2}, numberOfTimesCalled)
+}
+
+func TestThatFetchingTheAPIVersionDoesNotBlockOtherRequestsUntilItIsDone(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/apiversion", func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"versions":["3.0"]}`))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	}))
+	q.SetApiVersionForTests("")
+
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		q.GetQuerierAPIVersion()
+	}()
+	// so that the first request is fetching the version
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := q.SendGetRequest("/testing", map[string]string{}, SetContextInUserContext(nil, ctx))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+
+	close(release)
+	<-firstDone
+	version, err := q.GetQuerierAPIVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.0", version)
+}
+
+func TestThatFailingToReadTheResponseOfTheCoreReturnsACoreUnavailableError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/testing", func(rw http.ResponseWriter, r *http.Request) {
+		// the connection is closed before the whole body is sent
+		rw.Header().Set("Content-Length", "100")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	q := newQuerierForTest(t, newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	}))
+
+	_, err := q.SendGetRequest("/testing", map[string]string{}, nil)
+	assert.True(t, errors.Is(err, ErrCoreUnavailable))
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	var coreUnavailableError CoreUnavailableError
+	assert.True(t, errors.As(err, &coreUnavailableError))
+	assert.Equal(t, "/testing", coreUnavailableError.Path)
+}
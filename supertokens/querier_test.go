@@ -0,0 +1,50 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"testing"
+)
+
+func newQuerierTestConfig(connectionInfo ConnectionInfo) TypeInput {
+	return TypeInput{
+		Supertokens: &connectionInfo,
+		AppInfo: AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []Recipe{openAPITestRecipe},
+	}
+}
+
+// newQuerierForTest returns the querier of a new instance, which already knows the version of the
+// core so that the test servers do not need to handle /apiversion
+func newQuerierForTest(t *testing.T, config TypeInput) *Querier {
+	ResetForTest()
+	instance, err := New(config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	t.Cleanup(ResetForTest)
+	q, err := GetNewQuerierInstanceOrThrowError("", SetInstanceInUserContext(nil, instance))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	q.SetApiVersionForTests("3.0")
+	return q
+}
@@ -16,6 +16,7 @@
 package supertokens
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -23,38 +24,85 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // This function is required to be here because calling multitenancy recipe from this module causes cyclic dependency
 // this function is initialized by the init function in multitenancy recipe
 var GetTenantIdFuncFromUsingMultitenancyRecipe func(tenantIdFromFrontend string, userContext UserContext) (string, error)
 
-type superTokens struct {
+// Instance is a SuperTokens app with its own config, connection to the core and recipes. The package level
+// functions (Middleware, ErrorHandler etc) use the instance created by Init, and New can be used to create
+// more of them, for example to serve several apps with different cores from one process.
+type Instance struct {
 	AppInfo               NormalisedAppinfo
 	SuperTokens           ConnectionInfo
 	RecipeModules         []RecipeModule
 	OnSuperTokensAPIError func(err error, req *http.Request, res http.ResponseWriter)
 	Telemetry             *bool
+
+	core               *coreConnection
+	recipeInstances    map[string]interface{}
+	openAPIPath        *NormalisedURLPath
+	cors               *CORSConfig
+	corsAllowedOrigins []NormalisedURLDomain
+	i18n               *normalisedI18nConfig
 }
 
 // this will be set to true if this is used in a test app environment
 var IsTestFlag = false
 
-var superTokensInstance *superTokens
+// superTokensInstance is the instance created by Init
+var superTokensInstance *Instance
+
+// defaultInstanceBeingInitialised is the instance that Init is creating, so that recipes
+// can get each other before Init returns
+var defaultInstanceBeingInitialised *Instance
+
+// instanceInitLock makes sure that instances are created one at a time, since the post init callbacks are shared
+var instanceInitLock sync.Mutex
 
 func supertokensInit(config TypeInput) error {
 	if superTokensInstance != nil {
 		return nil
 	}
 
-	superTokens := &superTokens{}
+	instance, err := newInstance(config, true)
+	if err != nil {
+		return err
+	}
+	superTokensInstance = instance
+
+	return nil
+}
+
+// newInstance creates an instance from the config. Debug, Logging, Tracing and Metrics are shared by all the
+// instances in a process, so for instances that are not the default one they are only applied if they are set.
+func newInstance(config TypeInput, isDefault bool) (*Instance, error) {
+	superTokens := &Instance{
+		recipeInstances: map[string]interface{}{},
+	}
 
 	superTokens.OnSuperTokensAPIError = defaultOnSuperTokensAPIError
 	if config.OnSuperTokensAPIError != nil {
 		superTokens.OnSuperTokensAPIError = config.OnSuperTokensAPIError
 	}
 
-	DebugEnabled = config.Debug
+	if isDefault || config.Debug {
+		DebugEnabled = config.Debug
+	}
+	if isDefault || config.Logging != nil {
+		initLogging(config.Logging)
+	}
+	// this needs to be done before the recipes are initialised so that their functions can be instrumented
+	if isDefault || config.Tracing != nil {
+		initTracing(config.Tracing)
+	}
+	if isDefault || config.Metrics != nil {
+		initMetrics(config.Metrics)
+	}
 
 	LogDebugMessage("Started SuperTokens with debug logging (supertokens.Init called)")
 
@@ -77,7 +125,15 @@ func supertokensInit(config TypeInput) error {
 	var err error
 	superTokens.AppInfo, err = NormaliseInputAppInfoOrThrowError(config.AppInfo)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	// recipes use this to find the instance that they are being initialised for
+	superTokens.AppInfo.instance = superTokens
+
+	if isDefault {
+		superTokens.core = defaultCoreConnection
+	} else {
+		superTokens.core = &coreConnection{}
 	}
 
 	if config.Supertokens != nil {
@@ -87,28 +143,40 @@ func supertokensInit(config TypeInput) error {
 			for _, h := range hostList {
 				domain, err := NewNormalisedURLDomain(h)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				basePath, err := NewNormalisedURLPath(h)
 				if err != nil {
-					return err
+					return nil, err
 				}
 				hosts = append(hosts, QuerierHost{
 					Domain:   domain,
 					BasePath: basePath,
 				})
 			}
-			initQuerier(hosts, config.Supertokens.APIKey, config.Supertokens.NetworkInterceptor)
+			if isDefault {
+				initQuerier(hosts, *config.Supertokens)
+			} else {
+				superTokens.core.init(hosts, *config.Supertokens)
+			}
 			superTokens.SuperTokens = *config.Supertokens
 		} else {
-			return errors.New("please provide 'ConnectionURI' value. If you do not want to provide a connection URI, then set config.Supertokens to nil")
+			return nil, errors.New("please provide 'ConnectionURI' value. If you do not want to provide a connection URI, then set config.Supertokens to nil")
 		}
 	} else {
 		// TODO: Add tests for init without supertokens core.
 	}
 
 	if config.RecipeList == nil || len(config.RecipeList) == 0 {
-		return errors.New("please provide at least one recipe to the supertokens.init function call")
+		superTokens.Close()
+		return nil, errors.New("please provide at least one recipe to the supertokens.init function call")
+	}
+
+	if isDefault {
+		defaultInstanceBeingInitialised = superTokens
+		defer func() {
+			defaultInstanceBeingInitialised = nil
+		}()
 	}
 
 	multitenancyFound := false
@@ -116,7 +184,8 @@ func supertokensInit(config TypeInput) error {
 	for _, elem := range config.RecipeList {
 		recipeModule, err := elem(superTokens.AppInfo, superTokens.OnSuperTokensAPIError)
 		if err != nil {
-			return err
+			superTokens.Close()
+			return nil, err
 		}
 		superTokens.RecipeModules = append(superTokens.RecipeModules, *recipeModule)
 
@@ -128,39 +197,194 @@ func supertokensInit(config TypeInput) error {
 	if !multitenancyFound && DefaultMultitenancyRecipe != nil {
 		recipeModule, err := DefaultMultitenancyRecipe(superTokens.AppInfo, superTokens.OnSuperTokensAPIError)
 		if err != nil {
-			return err
+			superTokens.Close()
+			return nil, err
 		}
 		superTokens.RecipeModules = append(superTokens.RecipeModules, *recipeModule)
 	}
 
+	superTokens.core.cache = normaliseCacheConfig(config.Cache)
+
+	if config.OpenAPI != nil {
+		openAPIPath := defaultOpenAPIPath
+		if config.OpenAPI.Path != nil {
+			openAPIPath = *config.OpenAPI.Path
+		}
+		normalisedOpenAPIPath, err := NewNormalisedURLPath(openAPIPath)
+		if err != nil {
+			superTokens.Close()
+			return nil, err
+		}
+		normalisedOpenAPIPath = superTokens.AppInfo.APIBasePath.AppendPath(normalisedOpenAPIPath)
+		superTokens.openAPIPath = &normalisedOpenAPIPath
+	}
+
+	corsAllowedOrigins, err := normaliseCORSAllowedOrigins(config.CORS)
+	if err != nil {
+		superTokens.Close()
+		return nil, err
+	}
+	superTokens.cors = config.CORS
+	superTokens.corsAllowedOrigins = corsAllowedOrigins
+	if config.I18n != nil {
+		superTokens.i18n = normaliseI18nConfig(config.I18n)
+	}
+
 	superTokens.Telemetry = config.Telemetry
-	superTokensInstance = superTokens
 
-	return nil
+	return superTokens, nil
 }
 
 func defaultOnSuperTokensAPIError(err error, req *http.Request, res http.ResponseWriter) {
 	http.Error(res, err.Error(), 500)
 }
 
-func GetInstanceOrThrowError() (*superTokens, error) {
-	if superTokensInstance != nil {
-		return superTokensInstance, nil
+// GetInstanceOrThrowError returns the instance that the user context belongs to (see SetInstanceInUserContext),
+// or the one created by Init if there is none.
+func GetInstanceOrThrowError(userContext ...UserContext) (*Instance, error) {
+	instance := getInstanceForUserContext(userContext...)
+	if instance != nil {
+		return instance, nil
+	}
+	return nil, NotInitialisedError{Msg: "initialisation not done. Did you forget to call the SuperTokens.init function?"}
+}
+
+type instanceContextKey struct{}
+
+func getInstanceForUserContext(userContext ...UserContext) *Instance {
+	if len(userContext) > 0 {
+		instance := getInstanceFromUserContext(userContext[0])
+		if instance != nil {
+			return instance
+		}
+	}
+	return superTokensInstance
+}
+
+// getInstanceFromUserContext looks for the instance set using SetInstanceInUserContext, followed by the one
+// that the middleware adds to the context of the request.
+func getInstanceFromUserContext(userContext UserContext) *Instance {
+	if userContext == nil {
+		return nil
+	}
+	defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+	if ok {
+		instance, ok := defaultObj["instance"].(*Instance)
+		if ok && instance != nil {
+			return instance
+		}
+	}
+	request := getRequestFromUserContext(userContext)
+	if request != nil {
+		instance, ok := request.Context().Value(instanceContextKey{}).(*Instance)
+		if ok {
+			return instance
+		}
+	}
+	if ok {
+		ctx, ok := defaultObj["context"].(context.Context)
+		if ok && ctx != nil {
+			instance, ok := ctx.Value(instanceContextKey{}).(*Instance)
+			if ok {
+				return instance
+			}
+		}
+	}
+	return nil
+}
+
+// SetInstanceInUserContext makes all the calls that are made with this user context use the given instance.
+// This is only needed outside of requests that go through the middleware of the instance, since the
+// middleware adds the instance to the context of the request.
+func SetInstanceInUserContext(userContext UserContext, instance *Instance) UserContext {
+	var _userContext map[string]interface{}
+
+	if userContext == nil {
+		_userContext = map[string]interface{}{}
+	} else {
+		_userContext = *userContext
+	}
+
+	defaultObj, ok := _userContext["_default"].(map[string]interface{})
+
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		_userContext["_default"] = defaultObj
+	}
+
+	defaultObj["instance"] = instance
+
+	return &_userContext
+}
+
+// SetInstanceInRequest returns a copy of the request with the given instance in its context, like the
+// middleware of the instance does. This is for frameworks that can't pass the request created by the
+// middleware on to the next handlers.
+func SetInstanceInRequest(req *http.Request, instance *Instance) *http.Request {
+	return req.WithContext(SetInstanceInContext(req.Context(), instance))
+}
+
+// SetInstanceInContext returns a copy of ctx with the given instance in it. The instance is used for the
+// calls that are made with a user context that has ctx in it (see SetContextInUserContext).
+func SetInstanceInContext(ctx context.Context, instance *Instance) context.Context {
+	return context.WithValue(ctx, instanceContextKey{}, instance)
+}
+
+// MakeUserContextForAppInfo returns a user context for the instance that a recipe is being initialised for.
+// Recipes must use this for any call that they make while they are being initialised, including in post
+// init callbacks.
+func MakeUserContextForAppInfo(appInfo NormalisedAppinfo) UserContext {
+	return SetInstanceInUserContext(nil, appInfo.instance)
+}
+
+// RegisterRecipeInstance stores the recipe so that it can be returned by GetRecipeInstance. It must be called
+// by the init function of the recipe with the app info that it receives.
+func RegisterRecipeInstance(appInfo NormalisedAppinfo, recipeId string, recipe interface{}) error {
+	instance := appInfo.instance
+	if instance == nil {
+		return errors.New("recipes can only be initialised using supertokens.Init or supertokens.New")
+	}
+	if _, ok := instance.recipeInstances[recipeId]; ok {
+		return errors.New(recipeId + " recipe has already been initialised. Please check your code for bugs.")
 	}
-	return nil, errors.New("initialisation not done. Did you forget to call the SuperTokens.init function?")
+	instance.recipeInstances[recipeId] = recipe
+	return nil
 }
 
-func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
+// GetRecipeInstance returns the recipe registered using RegisterRecipeInstance for the instance that the user
+// context belongs to, or nil if that instance does not have the recipe.
+func GetRecipeInstance(recipeId string, userContext ...UserContext) interface{} {
+	instance := getInstanceForUserContext(userContext...)
+	if instance == nil {
+		instance = defaultInstanceBeingInitialised
+	}
+	if instance == nil {
+		return nil
+	}
+	return instance.recipeInstances[recipeId]
+}
+
+// Close stops the background work of the instance, like probing unhealthy cores
+func (s *Instance) Close() {
+	if s.core != nil {
+		s.core.stopHostProbing()
+	}
+}
+
+// Middleware handles the APIs of the recipes of this instance, and passes all other requests to theirHandler
+func (s *Instance) Middleware(theirHandler http.Handler) http.Handler {
 	LogDebugMessage("middleware: Started")
 	if theirHandler == nil {
 		theirHandler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {})
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// this is how recipe functions that are called with the request find this instance
+		r = SetInstanceInRequest(r, s)
 		dw := MakeDoneWriter(w)
 		userContext := MakeDefaultUserContextFromAPI(r)
 		reqURL, err := NewNormalisedURLPath(r.URL.Path)
 		if err != nil {
-			err = s.errorHandler(err, r, dw, userContext)
+			err = s.ErrorHandler(err, r, dw, userContext)
 			if err != nil && !dw.IsDone() {
 				s.OnSuperTokensAPIError(err, r, dw)
 			}
@@ -169,11 +393,26 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 		path := s.AppInfo.APIGatewayPath.AppendPath(reqURL)
 		method := r.Method
 
+		if s.cors != nil && s.handleCORS(dw, r, path, userContext) {
+			return
+		}
+
 		if !strings.HasPrefix(path.GetAsStringDangerous(), s.AppInfo.APIBasePath.GetAsStringDangerous()) {
 			LogDebugMessage("middleware: Not handling because request path did not start with config path. Request path: " + path.GetAsStringDangerous())
 			theirHandler.ServeHTTP(dw, r)
 			return
 		}
+		if s.openAPIPath != nil && method == http.MethodGet && path.Equals(*s.openAPIPath) {
+			s.serveOpenAPIDocument(dw, r, userContext)
+			return
+		}
+		if tracingEnabled {
+			SetContextInUserContext(userContext, extractTraceContext(r))
+		}
+		middlewareSpan := StartSpan(userContext, "supertokens.middleware", attribute.String("http.method", method), attribute.String("http.target", path.GetAsStringDangerous()))
+		defer middlewareSpan.End(nil)
+		routeMatchingSpan := StartSpan(userContext, "supertokens.middleware.matchRoute")
+		defer routeMatchingSpan.End(nil)
 		requestRID := getRIDFromRequest(r)
 		LogDebugMessage("middleware: requestRID is: " + requestRID)
 		if requestRID == "anti-csrf" {
@@ -200,7 +439,7 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 			}
 			if len(matchedRecipes) == 0 {
 				LogDebugMessage("middleware: Not handling because no recipe matched. Trying without rid")
-				s.middlewareHelperHandleWithoutRid(path, method, userContext, theirHandler, dw, r)
+				s.middlewareHelperHandleWithoutRid(path, method, userContext, theirHandler, dw, r, routeMatchingSpan)
 				return
 			}
 
@@ -215,7 +454,7 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 			for _, matchedRecipe := range matchedRecipes {
 				currId, currTenantId, err := matchedRecipe.ReturnAPIIdIfCanHandleRequest(path, method, userContext)
 				if err != nil {
-					err = s.errorHandler(err, r, dw, userContext)
+					err = s.ErrorHandler(err, r, dw, userContext)
 					if err != nil && !dw.IsDone() {
 						s.OnSuperTokensAPIError(err, r, dw)
 					}
@@ -237,7 +476,7 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 			}
 
 			if id == nil || finalTenantId == nil {
-				s.middlewareHelperHandleWithoutRid(path, method, userContext, theirHandler, dw, r)
+				s.middlewareHelperHandleWithoutRid(path, method, userContext, theirHandler, dw, r, routeMatchingSpan)
 				return
 			}
 
@@ -250,7 +489,7 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 				// which happens if they are only using the session recipe.
 				tenantId, err = GetTenantIdFuncFromUsingMultitenancyRecipe(*finalTenantId, userContext)
 				if err != nil {
-					err = s.errorHandler(err, r, dw, userContext)
+					err = s.ErrorHandler(err, r, dw, userContext)
 					if err != nil && !dw.IsDone() {
 						s.OnSuperTokensAPIError(err, r, dw)
 					}
@@ -258,9 +497,10 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 				}
 			}
 
-			apiErr := finalMatchedRecipe.HandleAPIRequest(*id, tenantId, r, dw, theirHandler.ServeHTTP, path, method, userContext)
+			routeMatchingSpan.End(nil)
+			apiErr := s.handleAPIRequest(finalMatchedRecipe, *id, tenantId, r, dw, theirHandler, path, method, userContext)
 			if apiErr != nil {
-				apiErr = s.errorHandler(apiErr, r, dw, userContext)
+				apiErr = s.ErrorHandler(apiErr, r, dw, userContext)
 				if apiErr != nil && !dw.IsDone() {
 					s.OnSuperTokensAPIError(apiErr, r, dw)
 				}
@@ -268,17 +508,24 @@ func (s *superTokens) middleware(theirHandler http.Handler) http.Handler {
 			}
 			LogDebugMessage("middleware: Ended")
 		} else {
-			s.middlewareHelperHandleWithoutRid(path, method, userContext, theirHandler, dw, r)
+			s.middlewareHelperHandleWithoutRid(path, method, userContext, theirHandler, dw, r, routeMatchingSpan)
 		}
 	})
 }
 
-func (s *superTokens) middlewareHelperHandleWithoutRid(path NormalisedURLPath, method string, userContext *map[string]interface{}, theirHandler http.Handler, dw DoneWriter, r *http.Request) {
+func (s *Instance) handleAPIRequest(recipeModule RecipeModule, id string, tenantId string, r *http.Request, dw DoneWriter, theirHandler http.Handler, path NormalisedURLPath, method string, userContext UserContext) error {
+	span := StartSpan(userContext, recipeModule.GetRecipeID()+".HandleAPIRequest", attribute.String("supertokens.api_id", id), attribute.String("supertokens.tenant_id", tenantId))
+	err := recipeModule.HandleAPIRequest(id, tenantId, r, dw, theirHandler.ServeHTTP, path, method, userContext)
+	span.End(err)
+	return err
+}
+
+func (s *Instance) middlewareHelperHandleWithoutRid(path NormalisedURLPath, method string, userContext *map[string]interface{}, theirHandler http.Handler, dw DoneWriter, r *http.Request, routeMatchingSpan *Span) {
 	for _, recipeModule := range s.RecipeModules {
 		id, tenantId, err := recipeModule.ReturnAPIIdIfCanHandleRequest(path, method, userContext)
 		LogDebugMessage("middleware: Checking recipe ID for match: " + recipeModule.GetRecipeID())
 		if err != nil {
-			err = s.errorHandler(err, r, dw, userContext)
+			err = s.ErrorHandler(err, r, dw, userContext)
 			if err != nil && !dw.IsDone() {
 				s.OnSuperTokensAPIError(err, r, dw)
 			}
@@ -287,9 +534,10 @@ func (s *superTokens) middlewareHelperHandleWithoutRid(path NormalisedURLPath, m
 
 		if id != nil {
 			LogDebugMessage("middleware: Request being handled by recipe. ID is: " + *id)
-			err := recipeModule.HandleAPIRequest(*id, tenantId, r, dw, theirHandler.ServeHTTP, path, method, userContext)
+			routeMatchingSpan.End(nil)
+			err := s.handleAPIRequest(recipeModule, *id, tenantId, r, dw, theirHandler, path, method, userContext)
 			if err != nil {
-				err = s.errorHandler(err, r, dw, userContext)
+				err = s.ErrorHandler(err, r, dw, userContext)
 				if err != nil && !dw.IsDone() {
 					s.OnSuperTokensAPIError(err, r, dw)
 				}
@@ -304,7 +552,7 @@ func (s *superTokens) middlewareHelperHandleWithoutRid(path NormalisedURLPath, m
 	theirHandler.ServeHTTP(dw, r)
 }
 
-func (s *superTokens) getAllCORSHeaders() []string {
+func (s *Instance) GetAllCORSHeaders() []string {
 	headerMap := map[string]bool{HeaderRID: true, HeaderFDI: true}
 	for _, recipe := range s.RecipeModules {
 		headers := recipe.GetAllCORSHeaders()
@@ -319,7 +567,9 @@ func (s *superTokens) getAllCORSHeaders() []string {
 	return headers
 }
 
-func (s *superTokens) errorHandler(originalError error, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
+// ErrorHandler sends the response for errors returned by the recipes of this instance. Errors that are not
+// handled by any recipe are returned.
+func (s *Instance) ErrorHandler(originalError error, req *http.Request, res http.ResponseWriter, userContext UserContext) error {
 	LogDebugMessage("errorHandler: Started")
 	if errors.As(originalError, &BadInputError{}) {
 		LogDebugMessage("errorHandler: Sending 400 status code response")
@@ -358,9 +608,9 @@ type UserPaginationResult struct {
 }
 
 // TODO: Add tests
-func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginationToken *string, limit *int, includeRecipeIds *[]string, searchParams map[string]string) (UserPaginationResult, error) {
+func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginationToken *string, limit *int, includeRecipeIds *[]string, searchParams map[string]string, userContext ...UserContext) (UserPaginationResult, error) {
 
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return UserPaginationResult{}, err
 	}
@@ -381,21 +631,8 @@ func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginatio
 		requestBody["includeRecipeIds"] = strings.Join((*includeRecipeIds)[:], ",")
 	}
 
-	resp, err := querier.SendGetRequest(tenantId+"/users", requestBody, nil)
-
-	if err != nil {
-		return UserPaginationResult{}, err
-	}
-
-	temporaryVariable, err := json.Marshal(resp)
-	if err != nil {
-		return UserPaginationResult{}, err
-	}
-
 	var result = UserPaginationResult{}
-
-	err = json.Unmarshal(temporaryVariable, &result)
-
+	err = querier.SendGetRequestTyped(tenantId+"/users", requestBody, &result, nil)
 	if err != nil {
 		return UserPaginationResult{}, err
 	}
@@ -404,9 +641,9 @@ func GetUsersWithSearchParams(tenantId string, timeJoinedOrder string, paginatio
 }
 
 // TODO: Add tests
-func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants *bool) (float64, error) {
+func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants *bool, userContext ...UserContext) (float64, error) {
 
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return -1, err
 	}
@@ -420,17 +657,18 @@ func getUserCount(includeRecipeIds *[]string, tenantId string, includeAllTenants
 		requestBody["includeAllTenants"] = strconv.FormatBool(*includeAllTenants)
 	}
 
-	resp, err := querier.SendGetRequest(tenantId+"/users/count", requestBody, nil)
+	var resp CoreUserCountResponse
+	err = querier.SendGetRequestTyped(tenantId+"/users/count", requestBody, &resp, nil)
 
 	if err != nil {
 		return -1, err
 	}
 
-	return resp["count"].(float64), nil
+	return resp.Count, nil
 }
 
-func deleteUser(userId string) error {
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+func deleteUser(userId string, userContext ...UserContext) error {
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return err
 	}
@@ -441,14 +679,17 @@ func deleteUser(userId string) error {
 	}
 
 	if MaxVersion(cdiVersion, "2.10") == cdiVersion {
-		_, err = querier.SendPostRequest("/user/remove", map[string]interface{}{
-			"userId": userId,
-		}, nil)
+		var resp CoreStatusResponse
+		err = querier.SendPostRequestTyped("/user/remove", CoreDeleteUserRequest{
+			UserId: userId,
+		}, &resp, nil)
 
 		if err != nil {
 			return err
 		}
 
+		querier.InvalidateCacheForUser(userId)
+
 		return nil
 	} else {
 		return errors.New("please upgrade the SuperTokens core to >= 3.7.0")
@@ -457,10 +698,16 @@ func deleteUser(userId string) error {
 
 func ResetForTest() {
 	ResetQuerierForTest()
+	initCache(nil)
+	initTracing(nil)
+	initMetrics(nil)
+	initLogging(nil)
 	resetPostInitCallbackForTest()
 	if superTokensInstance != nil {
 		for _, recipeModule := range superTokensInstance.RecipeModules {
-			recipeModule.ResetForTest()
+			if recipeModule.ResetForTest != nil {
+				recipeModule.ResetForTest()
+			}
 		}
 		superTokensInstance = nil
 	}
@@ -487,5 +734,30 @@ func getRequestFromUserContext(userContext UserContext) *http.Request {
 		return nil
 	}
 
-	return defaultObj.(map[string]interface{})["request"].(*http.Request)
+	request, ok := defaultObj.(map[string]interface{})["request"].(*http.Request)
+	if !ok {
+		return nil
+	}
+	return request
+}
+
+// getContextFromUserContext returns the context that calls to the core should be bound to.
+// A context set explicitly via SetContextInUserContext takes priority over the context of
+// the request that is stored in the user context by the middleware.
+func getContextFromUserContext(userContext UserContext) context.Context {
+	if userContext != nil {
+		defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+		if ok {
+			ctx, ok := defaultObj["context"].(context.Context)
+			if ok && ctx != nil {
+				return ctx
+			}
+		}
+	}
+
+	request := getRequestFromUserContext(userContext)
+	if request != nil {
+		return request.Context()
+	}
+	return context.Background()
 }
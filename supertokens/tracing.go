@@ -0,0 +1,197 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/supertokens/supertokens-golang"
+
+type TracingConfig struct {
+	// TracerProvider is used to create the spans of the SDK. Tracing is disabled if this is nil.
+	TracerProvider trace.TracerProvider
+	// Propagator is used to read the trace context of incoming requests and to send it to the core.
+	// Defaults to W3C trace context and baggage.
+	Propagator propagation.TextMapPropagator
+}
+
+var (
+	tracingEnabled  bool
+	tracer          trace.Tracer = trace.NewNoopTracerProvider().Tracer(tracerName)
+	tracePropagator propagation.TextMapPropagator
+)
+
+func initTracing(config *TracingConfig) {
+	if config == nil || config.TracerProvider == nil {
+		tracingEnabled = false
+		tracer = trace.NewNoopTracerProvider().Tracer(tracerName)
+		tracePropagator = nil
+		return
+	}
+	tracingEnabled = true
+	tracer = config.TracerProvider.Tracer(tracerName, trace.WithInstrumentationVersion(VERSION))
+	tracePropagator = config.Propagator
+	if tracePropagator == nil {
+		tracePropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+}
+
+// Span is a span that is the current span of a user context until it is ended, so that
+// spans started further down (including the ones for calls to the core) become its children.
+// All methods can be called on a nil Span, which is what StartSpan returns if tracing is disabled.
+type Span struct {
+	span            trace.Span
+	userContext     UserContext
+	previousContext interface{}
+	hadContext      bool
+	ended           bool
+}
+
+func StartSpan(userContext UserContext, name string, attributes ...attribute.KeyValue) *Span {
+	if !tracingEnabled {
+		return nil
+	}
+	ctx, span := tracer.Start(getContextFromUserContext(userContext), name, trace.WithAttributes(attributes...))
+	result := &Span{
+		span:        span,
+		userContext: userContext,
+	}
+	if userContext != nil {
+		defaultObj, ok := (*userContext)["_default"].(map[string]interface{})
+		if ok {
+			result.previousContext, result.hadContext = defaultObj["context"]
+		}
+		SetContextInUserContext(userContext, ctx)
+	}
+	return result
+}
+
+func (s *Span) SetAttributes(attributes ...attribute.KeyValue) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attributes...)
+}
+
+// End ends the span, marking it as failed if err is not nil. Calling End more than once has no effect.
+func (s *Span) End(err error) {
+	if s == nil || s.ended {
+		return
+	}
+	s.ended = true
+	endSpan(s.span, err)
+	if s.userContext != nil {
+		defaultObj, ok := (*s.userContext)["_default"].(map[string]interface{})
+		if ok {
+			if s.hadContext {
+				defaultObj["context"] = s.previousContext
+			} else {
+				delete(defaultObj, "context")
+			}
+		}
+	}
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// extractTraceContext returns the context of the request with the trace context sent by the client
+func extractTraceContext(r *http.Request) context.Context {
+	if !tracingEnabled {
+		return r.Context()
+	}
+	return tracePropagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+}
+
+func injectTraceContext(ctx context.Context, header http.Header) {
+	if !tracingEnabled {
+		return
+	}
+	tracePropagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+var userContextType = reflect.TypeOf((*map[string]interface{})(nil))
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// InstrumentRecipeInterface wraps every function of the given RecipeInterface (which must be a pointer
+// to a struct of function pointers) so that each call creates a span named <recipeId>.<function name>.
+// This does nothing if tracing is disabled.
+func InstrumentRecipeInterface(recipeId string, recipeInterface interface{}) {
+	if !tracingEnabled {
+		return
+	}
+	value := reflect.ValueOf(recipeInterface)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+	value = value.Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		fieldType := value.Type().Field(i)
+		if !fieldType.IsExported() || field.Kind() != reflect.Ptr || field.IsNil() || field.Elem().Kind() != reflect.Func || field.Elem().IsNil() {
+			continue
+		}
+		original := field.Elem()
+		name := recipeId + "." + fieldType.Name
+		traced := reflect.MakeFunc(original.Type(), func(args []reflect.Value) []reflect.Value {
+			span := StartSpan(findUserContextInArgs(args), name)
+			var results []reflect.Value
+			if original.Type().IsVariadic() {
+				results = original.CallSlice(args)
+			} else {
+				results = original.Call(args)
+			}
+			span.End(findErrorInResults(results))
+			return results
+		})
+		tracedPointer := reflect.New(original.Type())
+		tracedPointer.Elem().Set(traced)
+		field.Set(tracedPointer)
+	}
+}
+
+func findUserContextInArgs(args []reflect.Value) UserContext {
+	for i := len(args) - 1; i >= 0; i-- {
+		if args[i].Type() == userContextType && !args[i].IsNil() {
+			return args[i].Interface().(*map[string]interface{})
+		}
+	}
+	return nil
+}
+
+func findErrorInResults(results []reflect.Value) error {
+	if len(results) == 0 {
+		return nil
+	}
+	last := results[len(results)-1]
+	if last.Type() != errorType || last.IsNil() {
+		return nil
+	}
+	return last.Interface().(error)
+}
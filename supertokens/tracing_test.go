@@ -0,0 +1,67 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package supertokens
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestThatTracingCreatesSpansForCoreCalls(t *testing.T) {
+	mux := http.NewServeMux()
+
+	traceParent := ""
+	mux.HandleFunc("/recipe/session", func(rw http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte("{}"))
+	})
+
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	config := newQuerierTestConfig(ConnectionInfo{
+		ConnectionURI: testServer.URL,
+	})
+	config.Tracing = &TracingConfig{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)),
+	}
+	q := newQuerierForTest(t, config)
+
+	userContext := MakeDefaultUserContextFromAPI(httptest.NewRequest("GET", "/", nil))
+	span := StartSpan(userContext, "test.GetSessionInformation")
+	_, err := q.SendGetRequest("/recipe/session", map[string]string{}, userContext)
+	assert.NoError(t, err)
+	span.End(nil)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	coreSpan := spans[0]
+	parentSpan := spans[1]
+	assert.Equal(t, "supertokens.core GET /recipe/session", coreSpan.Name)
+	assert.Equal(t, "test.GetSessionInformation", parentSpan.Name)
+	assert.Equal(t, parentSpan.SpanContext.SpanID(), coreSpan.Parent.SpanID())
+	assert.Equal(t, parentSpan.SpanContext.TraceID(), coreSpan.SpanContext.TraceID())
+	assert.True(t, strings.Contains(traceParent, coreSpan.SpanContext.TraceID().String()))
+}
@@ -21,8 +21,8 @@ type CreateUserIdMappingResult struct {
 	}
 }
 
-func CreateUserIdMapping(supertokensUserId string, externalUserId string, externalUserIdInfo *string, force *bool) (CreateUserIdMappingResult, error) {
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+func CreateUserIdMapping(supertokensUserId string, externalUserId string, externalUserIdInfo *string, force *bool, userContext ...UserContext) (CreateUserIdMappingResult, error) {
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
@@ -34,25 +34,23 @@ func CreateUserIdMapping(supertokensUserId string, externalUserId string, extern
 		return CreateUserIdMappingResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
 	}
 
-	data := map[string]interface{}{
-		"superTokensUserId": supertokensUserId,
-		"externalUserId":    externalUserId,
+	data := CoreCreateUserIdMappingRequest{
+		SuperTokensUserId:  supertokensUserId,
+		ExternalUserId:     externalUserId,
+		ExternalUserIdInfo: externalUserIdInfo,
+		Force:              force,
 	}
-	if force != nil {
-		data["force"] = *force
-	}
-	if externalUserIdInfo != nil {
-		data["externalUserIdInfo"] = *externalUserIdInfo
-	}
-	resp, err := querier.SendPostRequest("/recipe/userid/map", data, nil)
+	var resp CoreCreateUserIdMappingResponse
+	err = querier.SendPostRequestTyped("/recipe/userid/map", data, &resp, nil)
 	if err != nil {
 		return CreateUserIdMappingResult{}, err
 	}
-	if resp["status"] == "OK" {
+	if resp.Status == "OK" {
+		querier.InvalidateCacheForUser(supertokensUserId, externalUserId)
 		return CreateUserIdMappingResult{
 			OK: &struct{}{},
 		}, nil
-	} else if resp["status"] == "UNKNOWN_SUPERTOKENS_USER_ID_ERROR" {
+	} else if resp.Status == "UNKNOWN_SUPERTOKENS_USER_ID_ERROR" {
 		return CreateUserIdMappingResult{
 			UnknownSupertokensUserIdError: &struct{}{},
 		}, nil
@@ -62,8 +60,8 @@ func CreateUserIdMapping(supertokensUserId string, externalUserId string, extern
 				DoesSuperTokensUserIdExist bool
 				DoesExternalUserIdExist    bool
 			}{
-				DoesSuperTokensUserIdExist: resp["doesSuperTokensUserIdExist"].(bool),
-				DoesExternalUserIdExist:    resp["doesExternalUserIdExist"].(bool),
+				DoesSuperTokensUserIdExist: resp.DoesSuperTokensUserIdExist,
+				DoesExternalUserIdExist:    resp.DoesExternalUserIdExist,
 			},
 		}, nil
 	}
@@ -78,9 +76,9 @@ type GetUserIdMappingResult struct {
 	UnknownMappingError *struct{}
 }
 
-func GetUserIdMapping(userId string, userIdType *UserIdType) (GetUserIdMappingResult, error) {
+func GetUserIdMapping(userId string, userIdType *UserIdType, userContext ...UserContext) (GetUserIdMappingResult, error) {
 
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
@@ -98,24 +96,21 @@ func GetUserIdMapping(userId string, userIdType *UserIdType) (GetUserIdMappingRe
 	if userIdType != nil {
 		data["userIdType"] = string(*userIdType)
 	}
-	resp, err := querier.SendGetRequest("/recipe/userid/map", data, nil)
+	var resp CoreGetUserIdMappingResponse
+	err = querier.SendGetRequestTyped("/recipe/userid/map", data, &resp, nil)
 	if err != nil {
 		return GetUserIdMappingResult{}, err
 	}
-	if resp["status"] == "OK" {
-		var externalUserIdInfo *string = nil
-		if v, ok := resp["externalUserIdInfo"].(string); ok {
-			externalUserIdInfo = &v
-		}
+	if resp.Status == "OK" {
 		return GetUserIdMappingResult{
 			OK: &struct {
 				SupertokensUserId  string
 				ExternalUserId     string
 				ExternalUserIdInfo *string
 			}{
-				SupertokensUserId:  resp["superTokensUserId"].(string),
-				ExternalUserId:     resp["externalUserId"].(string),
-				ExternalUserIdInfo: externalUserIdInfo,
+				SupertokensUserId:  resp.SuperTokensUserId,
+				ExternalUserId:     resp.ExternalUserId,
+				ExternalUserIdInfo: resp.ExternalUserIdInfo,
 			},
 		}, nil
 	} else {
@@ -131,8 +126,8 @@ type DeleteUserIdMappingResult struct {
 	}
 }
 
-func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool) (DeleteUserIdMappingResult, error) {
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool, userContext ...UserContext) (DeleteUserIdMappingResult, error) {
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
@@ -144,22 +139,23 @@ func DeleteUserIdMapping(userId string, userIdType *UserIdType, force *bool) (De
 		return DeleteUserIdMappingResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
 	}
 
-	data := map[string]interface{}{
-		"userId": userId,
+	data := CoreDeleteUserIdMappingRequest{
+		UserId: userId,
+		Force:  force,
 	}
 	if userIdType != nil {
-		data["userIdType"] = string(*userIdType)
-	}
-	if force != nil {
-		data["force"] = *force
+		userIdTypeStr := string(*userIdType)
+		data.UserIdType = &userIdTypeStr
 	}
-	resp, err := querier.SendPostRequest("/recipe/userid/map/remove", data, nil)
+	var resp CoreDeleteUserIdMappingResponse
+	err = querier.SendPostRequestTyped("/recipe/userid/map/remove", data, &resp, nil)
 	if err != nil {
 		return DeleteUserIdMappingResult{}, err
 	}
+	querier.InvalidateCacheForUser(userId)
 	return DeleteUserIdMappingResult{
 		OK: &struct{ DidMappingExist bool }{
-			DidMappingExist: resp["didMappingExist"].(bool),
+			DidMappingExist: resp.DidMappingExist,
 		},
 	}, nil
 }
@@ -169,8 +165,8 @@ type UpdateOrDeleteUserIdMappingInfoResult struct {
 	UnknownMappingError *struct{}
 }
 
-func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, externalUserIdInfo *string) (UpdateOrDeleteUserIdMappingInfoResult, error) {
-	querier, err := GetNewQuerierInstanceOrThrowError("")
+func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, externalUserIdInfo *string, userContext ...UserContext) (UpdateOrDeleteUserIdMappingInfoResult, error) {
+	querier, err := GetNewQuerierInstanceOrThrowError("", userContext...)
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
@@ -182,20 +178,22 @@ func UpdateOrDeleteUserIdMappingInfo(userId string, userIdType *UserIdType, exte
 		return UpdateOrDeleteUserIdMappingInfoResult{}, errors.New("Please upgrade the SuperTokens core to >= 3.15.0")
 	}
 
-	data := map[string]interface{}{
-		"userId":             userId,
-		"externalUserIdInfo": externalUserIdInfo,
+	data := CoreUpdateOrDeleteUserIdMappingInfoRequest{
+		UserId:             userId,
+		ExternalUserIdInfo: externalUserIdInfo,
 	}
 	if userIdType != nil {
-		data["userIdType"] = string(*userIdType)
+		userIdTypeStr := string(*userIdType)
+		data.UserIdType = &userIdTypeStr
 	}
 
-	resp, err := querier.SendPutRequest("/recipe/userid/external-user-id-info", data, nil)
+	var resp CoreStatusResponse
+	err = querier.SendPutRequestTyped("/recipe/userid/external-user-id-info", data, &resp, nil)
 	if err != nil {
 		return UpdateOrDeleteUserIdMappingInfoResult{}, err
 	}
 
-	if resp["status"] == "OK" {
+	if resp.Status == "OK" {
 		return UpdateOrDeleteUserIdMappingInfoResult{
 			OK: &struct{}{},
 		}, nil
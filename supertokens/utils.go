@@ -17,6 +17,7 @@ package supertokens
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -324,6 +325,29 @@ func SetRequestInUserContextIfNotDefined(userContext *map[string]interface{}, r
 	return &_userContext
 }
 
+// SetContextInUserContext makes all calls to the core that use this user context
+// honour the deadline and cancellation of ctx.
+func SetContextInUserContext(userContext *map[string]interface{}, ctx context.Context) UserContext {
+	var _userContext map[string]interface{}
+
+	if userContext == nil {
+		_userContext = map[string]interface{}{}
+	} else {
+		_userContext = *userContext
+	}
+
+	defaultObj, ok := _userContext["_default"].(map[string]interface{})
+
+	if !ok {
+		defaultObj = map[string]interface{}{}
+		_userContext["_default"] = defaultObj
+	}
+
+	defaultObj["context"] = ctx
+
+	return &_userContext
+}
+
 func GetTopLevelDomainForSameSiteResolution(URL string) (string, error) {
 	urlObj, err := url.Parse(URL)
 	if err != nil {
@@ -0,0 +1,308 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package cassette records the requests that the SDK sends to the core, along with the responses, into
+// a cassette file, and replays them later so that tests can run without a core:
+//
+//	recorder := cassette.Start(t, "testdata/signin.json")
+//
+//	supertokens.Init(supertokens.TypeInput{
+//		Supertokens: &supertokens.ConnectionInfo{
+//			ConnectionURI: "http://localhost:8080",
+//			HTTPClient:    recorder.Client(),
+//		},
+//		...
+//	})
+//
+// The cassette is recorded against the core at ConnectionURI if the file does not exist yet, or if the
+// SUPERTOKENS_CASSETTE_RECORD environment variable is set to "true". Otherwise it is replayed, and the
+// core is never contacted.
+//
+// Requests are matched using their method, path, query, cdi-version and rid headers, and body. Requests
+// with the same match are replayed in the order in which they were recorded, and once they are used up,
+// the last one is repeated (which is needed for requests that are sent in the background, like fetching
+// the JWKS). A request that was never recorded fails, and is reported when the recorder is stopped.
+//
+// Note that replayed access tokens keep the expiry that they had when they were recorded, so tests that
+// verify them without the core should use the coreemulator package instead.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// RecordEnvVar forces the cassettes to be recorded again when it is set to "true".
+const RecordEnvVar = "SUPERTOKENS_CASSETTE_RECORD"
+
+// Mode is whether a Recorder records the requests to the core or replays them from the cassette.
+type Mode int
+
+const (
+	ModeReplay Mode = iota
+	ModeRecord
+)
+
+type Request struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Query      string `json:"query,omitempty"`
+	CDIVersion string `json:"cdiVersion,omitempty"`
+	RID        string `json:"rid,omitempty"`
+	Body       string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records or replays the requests to the core.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+
+	mutex        sync.Mutex
+	interactions []Interaction
+	// used counts how many of the interactions of each request have been replayed
+	used      map[string]int
+	unmatched []Request
+}
+
+// New creates a recorder for the cassette at path. In ModeRecord, the requests are sent using
+// transport (http.DefaultTransport if nil), and the cassette is written by Stop. In ModeReplay, the
+// cassette is read straight away.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:         path,
+		mode:         mode,
+		transport:    transport,
+		interactions: []Interaction{},
+		used:         map[string]int{},
+	}
+	if mode == ModeReplay {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var file cassetteFile
+		if err := json.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("could not parse cassette %s: %w", path, err)
+		}
+		r.interactions = file.Interactions
+	}
+	return r, nil
+}
+
+// Start creates a recorder for a test, and stops it when the test finishes, failing the test if Stop
+// returns an error. See the package documentation for how the mode is picked.
+func Start(t testing.TB, path string) *Recorder {
+	t.Helper()
+	mode := ModeReplay
+	if _, err := os.Stat(path); os.IsNotExist(err) || os.Getenv(RecordEnvVar) == "true" {
+		mode = ModeRecord
+	}
+	r, err := New(path, mode, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() {
+		if err := r.Stop(); err != nil {
+			t.Error(err.Error())
+		}
+	})
+	return r
+}
+
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client that uses the recorder, to be set as HTTPClient in
+// supertokens.ConnectionInfo.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Unmatched returns the requests that were sent during replay but are not in the cassette.
+func (r *Recorder) Unmatched() []Request {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]Request{}, r.unmatched...)
+}
+
+// Stop writes the cassette in ModeRecord. In ModeReplay, it returns an error if any request did not
+// match the cassette.
+func (r *Recorder) Stop() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.mode == ModeRecord {
+		content, err := json.MarshalIndent(cassetteFile{Interactions: r.interactions}, "", "    ")
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(r.path, append(content, '\n'), 0644)
+	}
+	if len(r.unmatched) == 0 {
+		return nil
+	}
+	requests := []string{}
+	for _, request := range r.unmatched {
+		requests = append(requests, request.Method+" "+request.Path)
+	}
+	return fmt.Errorf("%d request(s) to the core did not match the cassette %s: %s", len(r.unmatched), r.path, strings.Join(requests, ", "))
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := toRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, request)
+	}
+	return r.replay(req, request)
+}
+
+func (r *Recorder) record(req *http.Request, request Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		// network errors are not recorded, since they can't be replayed reliably
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	r.mutex.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        string(body),
+		},
+	})
+	r.mutex.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, request Request) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := request.key()
+	matches := []Interaction{}
+	for _, interaction := range r.interactions {
+		if interaction.Request.key() == key {
+			matches = append(matches, interaction)
+		}
+	}
+	if len(matches) == 0 {
+		r.unmatched = append(r.unmatched, request)
+		return nil, errors.New("cassette: no recorded response for " + request.Method + " " + request.Path)
+	}
+	index := r.used[key]
+	if index >= len(matches) {
+		index = len(matches) - 1
+	}
+	r.used[key]++
+
+	response := matches[index].Response
+	header := http.Header{}
+	if response.ContentType != "" {
+		header.Set("Content-Type", response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		StatusCode:    response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(response.Body)),
+		ContentLength: int64(len(response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func toRequest(req *http.Request) (Request, error) {
+	request := Request{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Query:      req.URL.Query().Encode(),
+		CDIVersion: req.Header.Get("cdi-version"),
+		RID:        req.Header.Get("rid"),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return request, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return Request{}, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	request.Body = normaliseBody(body)
+	return request, nil
+}
+
+// normaliseBody sorts the keys of JSON bodies, so that they can be compared as strings
+func normaliseBody(body []byte) string {
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return string(body)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return string(body)
+	}
+	normalised, err := json.Marshal(value)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalised)
+}
+
+func (r Request) key() string {
+	return strings.Join([]string{r.Method, r.Path, r.Query, r.CDIVersion, r.RID, r.Body}, "\n")
+}
@@ -0,0 +1,152 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package cassette_test
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword/epmodels"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/cassette"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newInstance(t *testing.T, connectionURI string, client *http.Client, signInCount *int) supertokens.UserContext {
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: connectionURI,
+			HTTPClient:    client,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			emailpassword.Init(&epmodels.TypeInput{
+				Override: &epmodels.OverrideStruct{
+					Functions: func(originalImplementation epmodels.RecipeInterface) epmodels.RecipeInterface {
+						originalSignIn := *originalImplementation.SignIn
+						*originalImplementation.SignIn = func(email, password, tenantId string, userContext supertokens.UserContext) (epmodels.SignInResponse, error) {
+							*signInCount++
+							return originalSignIn(email, password, tenantId, userContext)
+						}
+						return originalImplementation
+					},
+				},
+			}),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return supertokens.SetInstanceInUserContext(nil, instance)
+}
+
+func signUpAndSignIn(t *testing.T, userContext supertokens.UserContext) (epmodels.SignUpResponse, epmodels.SignInResponse, epmodels.SignInResponse) {
+	signUp, err := emailpassword.SignUp("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	wrongSignIn, err := emailpassword.SignIn("public", "test@example.com", "wrongPassword", userContext)
+	assert.NoError(t, err)
+	signIn, err := emailpassword.SignIn("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	return signUp, wrongSignIn, signIn
+}
+
+func TestThatRecordedCoreResponsesAreReplayedWithoutTheCore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	core := coreemulator.Start(nil)
+	recorder, err := cassette.New(path, cassette.ModeRecord, nil)
+	assert.NoError(t, err)
+	recordedSignInCount := 0
+	recordedSignUp, recordedWrongSignIn, recordedSignIn := signUpAndSignIn(t, newInstance(t, core.URL, recorder.Client(), &recordedSignInCount))
+	assert.NoError(t, recorder.Stop())
+	core.Close()
+
+	assert.NotNil(t, recordedSignUp.OK)
+	assert.NotNil(t, recordedWrongSignIn.WrongCredentialsError)
+	assert.NotNil(t, recordedSignIn.OK)
+	assert.Equal(t, 2, recordedSignInCount)
+
+	// the core is no longer running, so every response has to come from the cassette
+	replayer, err := cassette.New(path, cassette.ModeReplay, nil)
+	assert.NoError(t, err)
+	replayedSignInCount := 0
+	replayedSignUp, replayedWrongSignIn, replayedSignIn := signUpAndSignIn(t, newInstance(t, core.URL, replayer.Client(), &replayedSignInCount))
+	assert.NoError(t, replayer.Stop())
+
+	assert.Equal(t, recordedSignUp, replayedSignUp)
+	assert.Equal(t, recordedWrongSignIn, replayedWrongSignIn)
+	assert.Equal(t, recordedSignIn, replayedSignIn)
+	assert.Equal(t, 2, replayedSignInCount)
+	assert.Empty(t, replayer.Unmatched())
+}
+
+func TestThatRequestsThatWereNotRecordedFail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	recorder, err := cassette.New(path, cassette.ModeRecord, nil)
+	assert.NoError(t, err)
+	signInCount := 0
+	_, err = emailpassword.SignUp("public", "test@example.com", "password123", newInstance(t, core.URL, recorder.Client(), &signInCount))
+	assert.NoError(t, err)
+	assert.NoError(t, recorder.Stop())
+
+	replayer, err := cassette.New(path, cassette.ModeReplay, nil)
+	assert.NoError(t, err)
+	userContext := newInstance(t, core.URL, replayer.Client(), &signInCount)
+
+	// the same request with a different body does not match
+	_, err = emailpassword.SignUp("public", "other@example.com", "password123", userContext)
+	assert.Error(t, err)
+
+	unmatched := replayer.Unmatched()
+	assert.Len(t, unmatched, 1)
+	assert.Equal(t, "/public/recipe/signup", unmatched[0].Path)
+	assert.Error(t, replayer.Stop())
+}
+
+func TestThatStartRecordsACassetteThatDoesNotExist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	t.Setenv(cassette.RecordEnvVar, "")
+
+	t.Run("record", func(t *testing.T) {
+		core := coreemulator.Start(nil)
+		defer core.Close()
+		recorder := cassette.Start(t, path)
+		assert.Equal(t, cassette.ModeRecord, recorder.Mode())
+		signInCount := 0
+		_, err := emailpassword.SignUp("public", "test@example.com", "password123", newInstance(t, core.URL, recorder.Client(), &signInCount))
+		assert.NoError(t, err)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		recorder := cassette.Start(t, path)
+		assert.Equal(t, cassette.ModeReplay, recorder.Mode())
+		signInCount := 0
+		signUp, err := emailpassword.SignUp("public", "test@example.com", "password123", newInstance(t, "http://localhost:1", recorder.Client(), &signInCount))
+		assert.NoError(t, err)
+		assert.NotNil(t, signUp.OK)
+	})
+}
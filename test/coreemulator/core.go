@@ -0,0 +1,476 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Package coreemulator is an in-memory implementation of the parts of the SuperTokens core API (CDI 3.0)
+// that are used by this SDK, so that the SDK can be tested without running the Java core:
+//
+//	core := coreemulator.Start(nil)
+//	defer core.Close()
+//
+//	supertokens.Init(supertokens.TypeInput{
+//		Supertokens: &supertokens.ConnectionInfo{ConnectionURI: core.URL},
+//		...
+//	})
+//
+// It covers sessions (with access tokens signed by real RSA keys that are served in the JWKS), JWTs,
+// emailpassword, passwordless, thirdparty, email verification, user roles, user metadata, tenants and
+// user id mapping. Nothing is persisted, and Reset can be used to clear all the data between tests.
+package coreemulator
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+const publicTenantId = "public"
+
+const (
+	defaultAccessTokenValidity         = time.Hour
+	defaultRefreshTokenValidity        = 100 * 24 * time.Hour
+	defaultPasswordlessCodeLifetime    = 15 * time.Minute
+	defaultPasswordlessMaxInputAttempt = 5
+	defaultTokenLifetime               = time.Hour
+)
+
+type Config struct {
+	// APIKeys are the keys that are accepted in the api-key header. If empty, no API key is required.
+	APIKeys []string
+	// AccessTokenValidity defaults to 1 hour
+	AccessTokenValidity time.Duration
+	// RefreshTokenValidity defaults to 100 days
+	RefreshTokenValidity time.Duration
+	// PasswordlessCodeLifetime defaults to 15 minutes
+	PasswordlessCodeLifetime time.Duration
+	// PasswordlessMaxCodeInputAttempts defaults to 5
+	PasswordlessMaxCodeInputAttempts int
+}
+
+// Core is the emulated core. It is an http.Handler, and the embedded httptest.Server is set if it was
+// created using Start.
+type Core struct {
+	*httptest.Server
+
+	config Config
+	mutex  sync.Mutex
+	routes []route
+
+	staticKey  signingKey
+	dynamicKey signingKey
+
+	tenants                 map[string]*tenant
+	sessions                map[string]*session
+	refreshTokens           map[string]refreshTokenInfo
+	users                   map[string]*user
+	passwordResetTokens     map[string]passwordResetToken
+	passwordlessDevices     map[string]*passwordlessDevice
+	emailVerificationTokens map[string]emailVerificationToken
+	verifiedEmails          map[string]bool
+	// roles maps a role to its permissions
+	roles map[string][]string
+	// userRoles maps a tenant id to the roles of each user in the tenant
+	userRoles      map[string]map[string][]string
+	userMetadata   map[string]map[string]interface{}
+	userIdMappings []*userIdMapping
+}
+
+type signingKey struct {
+	kid        string
+	privateKey *rsa.PrivateKey
+}
+
+type route struct {
+	method  string
+	path    string
+	handler func(request *coreRequest) (interface{}, error)
+	// tenantless routes do not accept a tenant id in the path
+	tenantless bool
+}
+
+// coreRequest is a request to the emulated core, with the tenant id removed from the path
+type coreRequest struct {
+	*http.Request
+	tenantId string
+	body     map[string]interface{}
+}
+
+// httpError is returned by handlers to send a non 200 response
+type httpError struct {
+	statusCode int
+	message    string
+}
+
+func (e httpError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return httpError{statusCode: 400, message: fmt.Sprintf(format, args...)}
+}
+
+// New creates a core without starting a server for it, which can be used as an http.Handler
+func New(config *Config) *Core {
+	c := &Core{}
+	if config != nil {
+		c.config = *config
+	}
+	if c.config.AccessTokenValidity == 0 {
+		c.config.AccessTokenValidity = defaultAccessTokenValidity
+	}
+	if c.config.RefreshTokenValidity == 0 {
+		c.config.RefreshTokenValidity = defaultRefreshTokenValidity
+	}
+	if c.config.PasswordlessCodeLifetime == 0 {
+		c.config.PasswordlessCodeLifetime = defaultPasswordlessCodeLifetime
+	}
+	if c.config.PasswordlessMaxCodeInputAttempts == 0 {
+		c.config.PasswordlessMaxCodeInputAttempts = defaultPasswordlessMaxInputAttempt
+	}
+	c.staticKey = newSigningKey("s-")
+	c.dynamicKey = newSigningKey("d-")
+	c.routes = c.getRoutes()
+	c.Reset()
+	return c
+}
+
+// Start creates a core and serves it using an httptest.Server. The server must be closed using Close.
+func Start(config *Config) *Core {
+	c := New(config)
+	c.Server = httptest.NewServer(c)
+	return c
+}
+
+// Reset removes all the data from the core, except for the signing keys
+func (c *Core) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.tenants = map[string]*tenant{
+		publicTenantId: newTenant(publicTenantId, true),
+	}
+	c.sessions = map[string]*session{}
+	c.refreshTokens = map[string]refreshTokenInfo{}
+	c.users = map[string]*user{}
+	c.passwordResetTokens = map[string]passwordResetToken{}
+	c.passwordlessDevices = map[string]*passwordlessDevice{}
+	c.emailVerificationTokens = map[string]emailVerificationToken{}
+	c.verifiedEmails = map[string]bool{}
+	c.roles = map[string][]string{}
+	c.userRoles = map[string]map[string][]string{}
+	c.userMetadata = map[string]map[string]interface{}{}
+	c.userIdMappings = []*userIdMapping{}
+}
+
+func (c *Core) getRoutes() []route {
+	routes := []route{
+		{method: "GET", path: "/hello", handler: c.hello, tenantless: true},
+		{method: "GET", path: "/apiversion", handler: c.apiVersion, tenantless: true},
+		{method: "GET", path: "/telemetry", handler: c.telemetry, tenantless: true},
+		{method: "GET", path: "/.well-known/jwks.json", handler: c.getJWKS, tenantless: true},
+		{method: "POST", path: "/recipe/jwt", handler: c.createJWT, tenantless: true},
+
+		{method: "POST", path: "/recipe/session", handler: c.createSession},
+		{method: "GET", path: "/recipe/session", handler: c.getSessionInformation, tenantless: true},
+		{method: "POST", path: "/recipe/session/verify", handler: c.verifySession, tenantless: true},
+		{method: "POST", path: "/recipe/session/refresh", handler: c.refreshSession, tenantless: true},
+		{method: "POST", path: "/recipe/session/regenerate", handler: c.regenerateAccessToken, tenantless: true},
+		{method: "POST", path: "/recipe/session/remove", handler: c.revokeSessions},
+		{method: "GET", path: "/recipe/session/user", handler: c.getSessionHandlesForUser},
+		{method: "PUT", path: "/recipe/session/data", handler: c.updateSessionData, tenantless: true},
+		{method: "PUT", path: "/recipe/jwt/data", handler: c.updateAccessTokenPayload, tenantless: true},
+
+		{method: "POST", path: "/recipe/signup", handler: c.emailPasswordSignUp},
+		{method: "POST", path: "/recipe/signin", handler: c.emailPasswordSignIn},
+		{method: "POST", path: "/recipe/user/password/reset/token", handler: c.createPasswordResetToken},
+		{method: "POST", path: "/recipe/user/password/reset", handler: c.resetPassword},
+		{method: "GET", path: "/recipe/user", handler: c.getUser},
+		{method: "PUT", path: "/recipe/user", handler: c.updateUser, tenantless: true},
+		{method: "POST", path: "/recipe/signinup", handler: c.thirdPartySignInUp},
+		{method: "GET", path: "/recipe/users/by-email", handler: c.getThirdPartyUsersByEmail},
+
+		{method: "POST", path: "/recipe/signinup/code", handler: c.createPasswordlessCode},
+		{method: "POST", path: "/recipe/signinup/code/consume", handler: c.consumePasswordlessCode},
+		{method: "GET", path: "/recipe/signinup/codes", handler: c.listPasswordlessCodes},
+		{method: "POST", path: "/recipe/signinup/codes/remove", handler: c.revokeAllPasswordlessCodes},
+		{method: "POST", path: "/recipe/signinup/code/remove", handler: c.revokePasswordlessCode},
+
+		{method: "POST", path: "/recipe/user/email/verify/token", handler: c.createEmailVerificationToken},
+		{method: "POST", path: "/recipe/user/email/verify", handler: c.verifyEmail},
+		{method: "GET", path: "/recipe/user/email/verify", handler: c.isEmailVerified, tenantless: true},
+		{method: "POST", path: "/recipe/user/email/verify/token/remove", handler: c.revokeEmailVerificationTokens},
+		{method: "POST", path: "/recipe/user/email/verify/remove", handler: c.unverifyEmail, tenantless: true},
+
+		{method: "PUT", path: "/recipe/role", handler: c.createOrUpdateRole, tenantless: true},
+		{method: "GET", path: "/recipe/role/permissions", handler: c.getPermissionsForRole, tenantless: true},
+		{method: "POST", path: "/recipe/role/permissions/remove", handler: c.removePermissionsFromRole, tenantless: true},
+		{method: "GET", path: "/recipe/permission/roles", handler: c.getRolesThatHavePermission, tenantless: true},
+		{method: "POST", path: "/recipe/role/remove", handler: c.deleteRole, tenantless: true},
+		{method: "GET", path: "/recipe/roles", handler: c.getAllRoles, tenantless: true},
+		{method: "PUT", path: "/recipe/user/role", handler: c.addRoleToUser},
+		{method: "POST", path: "/recipe/user/role/remove", handler: c.removeUserRole},
+		{method: "GET", path: "/recipe/user/roles", handler: c.getRolesForUser},
+		{method: "GET", path: "/recipe/role/users", handler: c.getUsersThatHaveRole},
+
+		{method: "GET", path: "/recipe/user/metadata", handler: c.getUserMetadata, tenantless: true},
+		{method: "PUT", path: "/recipe/user/metadata", handler: c.updateUserMetadata, tenantless: true},
+		{method: "POST", path: "/recipe/user/metadata/remove", handler: c.clearUserMetadata, tenantless: true},
+
+		{method: "PUT", path: "/recipe/multitenancy/tenant", handler: c.createOrUpdateTenant, tenantless: true},
+		{method: "POST", path: "/recipe/multitenancy/tenant/remove", handler: c.deleteTenant, tenantless: true},
+		{method: "GET", path: "/recipe/multitenancy/tenant", handler: c.getTenant},
+		{method: "GET", path: "/recipe/multitenancy/tenant/list", handler: c.listAllTenants, tenantless: true},
+		{method: "PUT", path: "/recipe/multitenancy/config/thirdparty", handler: c.createOrUpdateThirdPartyConfig},
+		{method: "POST", path: "/recipe/multitenancy/config/thirdparty/remove", handler: c.deleteThirdPartyConfig},
+		{method: "POST", path: "/recipe/multitenancy/tenant/user", handler: c.associateUserToTenant},
+		{method: "POST", path: "/recipe/multitenancy/tenant/user/remove", handler: c.disassociateUserFromTenant},
+
+		{method: "POST", path: "/recipe/userid/map", handler: c.createUserIdMapping, tenantless: true},
+		{method: "GET", path: "/recipe/userid/map", handler: c.getUserIdMapping, tenantless: true},
+		{method: "POST", path: "/recipe/userid/map/remove", handler: c.deleteUserIdMapping, tenantless: true},
+		{method: "PUT", path: "/recipe/userid/external-user-id-info", handler: c.updateExternalUserIdInfo, tenantless: true},
+
+		{method: "GET", path: "/users", handler: c.getUsers},
+		{method: "GET", path: "/users/count", handler: c.getUserCount},
+		{method: "POST", path: "/user/remove", handler: c.deleteUser, tenantless: true},
+	}
+	return routes
+}
+
+func (c *Core) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(c.config.APIKeys) > 0 && !containsString(c.config.APIKeys, r.Header.Get("api-key")) {
+		http.Error(w, "Invalid API key", http.StatusUnauthorized)
+		return
+	}
+
+	matchedRoute, tenantId, found := c.findRoute(r.Method, r.URL.Path)
+	if !found {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+
+	request := &coreRequest{
+		Request:  r,
+		tenantId: tenantId,
+	}
+	if r.Method == "POST" || r.Method == "PUT" {
+		err := json.NewDecoder(r.Body).Decode(&request.body)
+		if err != nil {
+			http.Error(w, "Invalid JSON input", http.StatusBadRequest)
+			return
+		}
+	}
+
+	c.mutex.Lock()
+	var response interface{}
+	var err error
+	if _, ok := c.tenants[tenantId]; !ok && matchedRoute.path != "/recipe/multitenancy/tenant" {
+		err = badRequest("Tenant with the following connectionURIDomain, appId and tenantId combination not found: (, public, %s)", tenantId)
+	} else {
+		response, err = matchedRoute.handler(request)
+	}
+	c.mutex.Unlock()
+
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if httpErr, ok := err.(httpError); ok {
+			statusCode = httpErr.statusCode
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+
+	if text, ok := response.(string); ok {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(200)
+		w.Write([]byte(text))
+		return
+	}
+	if matchedRoute.path == "/.well-known/jwks.json" {
+		w.Header().Set("Cache-Control", "max-age=60, must-revalidate")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(200)
+	json.NewEncoder(w).Encode(response)
+}
+
+// findRoute returns the route for a path, which may start with a tenant id (e.g. /tenant1/recipe/signin)
+func (c *Core) findRoute(method string, path string) (route, string, bool) {
+	for _, r := range c.routes {
+		if r.method == method && r.path == path {
+			return r, publicTenantId, true
+		}
+	}
+	pathParts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(pathParts) != 2 {
+		return route{}, "", false
+	}
+	pathWithoutTenant := "/" + pathParts[1]
+	for _, r := range c.routes {
+		if r.method == method && r.path == pathWithoutTenant && !r.tenantless {
+			return r, pathParts[0], true
+		}
+	}
+	return route{}, "", false
+}
+
+func (c *Core) hello(request *coreRequest) (interface{}, error) {
+	return "Hello", nil
+}
+
+func (c *Core) apiVersion(request *coreRequest) (interface{}, error) {
+	return map[string]interface{}{
+		"versions": []string{"3.0"},
+	}, nil
+}
+
+func (c *Core) telemetry(request *coreRequest) (interface{}, error) {
+	return map[string]interface{}{
+		"exists": false,
+	}, nil
+}
+
+func (r *coreRequest) getString(key string) (string, bool) {
+	value, ok := r.body[key].(string)
+	return value, ok
+}
+
+func (r *coreRequest) requireString(key string) (string, error) {
+	value, ok := r.getString(key)
+	if !ok {
+		return "", badRequest("Field name '%s' is invalid in JSON input", key)
+	}
+	return value, nil
+}
+
+func (r *coreRequest) getBool(key string) bool {
+	value, _ := r.body[key].(bool)
+	return value
+}
+
+func (r *coreRequest) getMap(key string) map[string]interface{} {
+	value, ok := r.body[key].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return value
+}
+
+func (r *coreRequest) getStringSlice(key string) ([]string, bool) {
+	values, ok := r.body[key].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	result := []string{}
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result, true
+}
+
+func (r *coreRequest) query(key string) string {
+	return r.URL.Query().Get(key)
+}
+
+func (r *coreRequest) requireQuery(key string) (string, error) {
+	value := r.query(key)
+	if value == "" {
+		return "", badRequest("Field name '%s' is missing in GET request", key)
+	}
+	return value, nil
+}
+
+func okResponse(fields map[string]interface{}) map[string]interface{} {
+	fields["status"] = "OK"
+	return fields
+}
+
+func statusResponse(status string) map[string]interface{} {
+	return map[string]interface{}{
+		"status": status,
+	}
+}
+
+func newSigningKey(kidPrefix string) signingKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return signingKey{
+		kid:        kidPrefix + newId(),
+		privateKey: privateKey,
+	}
+}
+
+// newId returns a random id in the format of a UUID
+func newId() string {
+	b := randomBytes(16)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func newToken() string {
+	return hex.EncodeToString(randomBytes(32))
+}
+
+func randomBytes(length int) []byte {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func hashHex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+func hashBase64URL(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func nowInMS() uint64 {
+	return uint64(time.Now().UnixNano() / int64(time.Millisecond))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
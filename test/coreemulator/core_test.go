@@ -0,0 +1,414 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/supertokens/supertokens-golang/recipe/emailpassword"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification"
+	"github.com/supertokens/supertokens-golang/recipe/emailverification/evmodels"
+	"github.com/supertokens/supertokens-golang/recipe/multitenancy"
+	"github.com/supertokens/supertokens-golang/recipe/multitenancy/multitenancymodels"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless"
+	"github.com/supertokens/supertokens-golang/recipe/passwordless/plessmodels"
+	"github.com/supertokens/supertokens-golang/recipe/session"
+	"github.com/supertokens/supertokens-golang/recipe/thirdparty"
+	"github.com/supertokens/supertokens-golang/recipe/usermetadata"
+	"github.com/supertokens/supertokens-golang/recipe/userroles"
+	"github.com/supertokens/supertokens-golang/supertokens"
+	"github.com/supertokens/supertokens-golang/test/coreemulator"
+)
+
+func newInstance(t *testing.T, core *coreemulator.Core, apiKey string) supertokens.UserContext {
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+			APIKey:        apiKey,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: []supertokens.Recipe{
+			session.Init(nil),
+			emailpassword.Init(nil),
+			passwordless.Init(plessmodels.TypeInput{
+				FlowType: "USER_INPUT_CODE",
+				ContactMethodEmail: plessmodels.ContactMethodEmailConfig{
+					Enabled: true,
+				},
+			}),
+			thirdparty.Init(nil),
+			emailverification.Init(evmodels.TypeInput{
+				Mode: evmodels.ModeOptional,
+			}),
+			userroles.Init(nil),
+			usermetadata.Init(nil),
+			multitenancy.Init(nil),
+		},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return supertokens.SetInstanceInUserContext(nil, instance)
+}
+
+func TestThatSessionsCanBeCreatedVerifiedRefreshedAndRevoked(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	// the email verification claim is added to new sessions, so the user has to exist
+	signUp, err := emailpassword.SignUp("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	userId := signUp.OK.User.ID
+
+	disableAntiCSRF := true
+	sess, err := session.CreateNewSessionWithoutRequestResponse("public", userId, map[string]interface{}{"role": "admin"}, map[string]interface{}{"key": "value"}, &disableAntiCSRF, userContext)
+	assert.NoError(t, err)
+	tokens := sess.GetAllSessionTokensDangerously()
+	assert.NotNil(t, tokens.RefreshToken)
+
+	verified, err := session.GetSessionWithoutRequestResponse(tokens.AccessToken, nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, verified.GetUserID())
+	assert.Equal(t, "admin", verified.GetAccessTokenPayload()["role"])
+
+	refreshed, err := session.RefreshSessionWithoutRequestResponse(*tokens.RefreshToken, &disableAntiCSRF, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, sess.GetHandle(), refreshed.GetHandle())
+	assert.NotEqual(t, tokens.AccessToken, refreshed.GetAllSessionTokensDangerously().AccessToken)
+
+	// using the old refresh token after the new one is used is treated as token theft
+	_, err = session.GetSessionWithoutRequestResponse(refreshed.GetAllSessionTokensDangerously().AccessToken, nil, nil, userContext)
+	assert.NoError(t, err)
+	_, err = session.RefreshSessionWithoutRequestResponse(*tokens.RefreshToken, &disableAntiCSRF, nil, userContext)
+	assert.Error(t, err)
+
+	sess, err = session.CreateNewSessionWithoutRequestResponse("public", userId, nil, nil, &disableAntiCSRF, userContext)
+	assert.NoError(t, err)
+	data, err := session.GetSessionInformation(sess.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, data.UserId)
+
+	handles, err := session.GetAllSessionHandlesForUser(userId, nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{sess.GetHandle()}, handles)
+
+	revoked, err := session.RevokeSession(sess.GetHandle(), userContext)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+	handles, err = session.GetAllSessionHandlesForUser(userId, nil, userContext)
+	assert.NoError(t, err)
+	assert.Empty(t, handles)
+}
+
+func TestThatRequestsWithTheWrongAPIKeyAreRejected(t *testing.T) {
+	core := coreemulator.Start(&coreemulator.Config{
+		APIKeys: []string{"someKey"},
+	})
+	defer core.Close()
+
+	_, err := emailpassword.SignUp("public", "test@example.com", "password123", newInstance(t, core, "wrongKey"))
+	assert.Error(t, err)
+
+	response, err := emailpassword.SignUp("public", "test@example.com", "password123", newInstance(t, core, "someKey"))
+	assert.NoError(t, err)
+	assert.NotNil(t, response.OK)
+}
+
+func TestThatEmailPasswordUsersCanSignUpAndSignIn(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	signUp, err := emailpassword.SignUp("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signUp.OK)
+	assert.Equal(t, "test@example.com", signUp.OK.User.Email)
+
+	signUp, err = emailpassword.SignUp("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signUp.EmailAlreadyExistsError)
+
+	signIn, err := emailpassword.SignIn("public", "test@example.com", "wrongPassword", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signIn.WrongCredentialsError)
+
+	signIn, err = emailpassword.SignIn("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signIn.OK)
+	userId := signIn.OK.User.ID
+
+	resetToken, err := emailpassword.CreateResetPasswordToken("public", userId, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, resetToken.OK)
+	reset, err := emailpassword.ResetPasswordUsingToken("public", resetToken.OK.Token, "newPassword123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, reset.OK)
+
+	signIn, err = emailpassword.SignIn("public", "test@example.com", "newPassword123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signIn.OK)
+
+	user, err := emailpassword.GetUserByID(userId, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", user.Email)
+
+	// the core is emptied when it is reset
+	core.Reset()
+	user, err = emailpassword.GetUserByID(userId, userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+func TestThatPasswordlessCodesCanBeCreatedAndConsumed(t *testing.T) {
+	core := coreemulator.Start(&coreemulator.Config{
+		PasswordlessMaxCodeInputAttempts: 2,
+	})
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	code, err := passwordless.CreateCodeWithEmail("public", "test@example.com", nil, userContext)
+	assert.NoError(t, err)
+	assert.Len(t, code.OK.UserInputCode, 6)
+
+	devices, err := passwordless.ListCodesByEmail("public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+	assert.Equal(t, code.OK.PreAuthSessionID, devices[0].PreAuthSessionID)
+
+	consumed, err := passwordless.ConsumeCodeWithUserInputCode("public", code.OK.DeviceID, "wrong", code.OK.PreAuthSessionID, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, consumed.IncorrectUserInputCodeError)
+	assert.Equal(t, 1, consumed.IncorrectUserInputCodeError.FailedCodeInputAttemptCount)
+
+	consumed, err = passwordless.ConsumeCodeWithUserInputCode("public", code.OK.DeviceID, code.OK.UserInputCode, code.OK.PreAuthSessionID, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, consumed.OK)
+	assert.True(t, consumed.OK.CreatedNewUser)
+	assert.Equal(t, "test@example.com", *consumed.OK.User.Email)
+
+	// the device is removed once it is used
+	devices, err = passwordless.ListCodesByEmail("public", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Empty(t, devices)
+
+	code, err = passwordless.CreateCodeWithEmail("public", "test@example.com", nil, userContext)
+	assert.NoError(t, err)
+	consumed, err = passwordless.ConsumeCodeWithLinkCode("public", code.OK.LinkCode, code.OK.PreAuthSessionID, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, consumed.OK)
+	assert.False(t, consumed.OK.CreatedNewUser)
+
+	// the flow has to be restarted once the maximum number of attempts is reached
+	code, err = passwordless.CreateCodeWithEmail("public", "test@example.com", nil, userContext)
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		consumed, err = passwordless.ConsumeCodeWithUserInputCode("public", code.OK.DeviceID, "wrong", code.OK.PreAuthSessionID, userContext)
+		assert.NoError(t, err)
+	}
+	assert.NotNil(t, consumed.RestartFlowError)
+}
+
+func TestThatThirdPartyUsersAreCreatedOrUpdated(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	created, err := thirdparty.ManuallyCreateOrUpdateUser("public", "google", "googleUserId", "test@example.com", userContext)
+	assert.NoError(t, err)
+	assert.True(t, created.OK.CreatedNewUser)
+
+	updated, err := thirdparty.ManuallyCreateOrUpdateUser("public", "google", "googleUserId", "new@example.com", userContext)
+	assert.NoError(t, err)
+	assert.False(t, updated.OK.CreatedNewUser)
+	assert.Equal(t, created.OK.User.ID, updated.OK.User.ID)
+
+	user, err := thirdparty.GetUserByThirdPartyInfo("public", "google", "googleUserId", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+
+	users, err := thirdparty.GetUsersByEmail("public", "new@example.com", userContext)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+}
+
+func TestThatEmailsCanBeVerified(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	email := "test@example.com"
+	token, err := emailverification.CreateEmailVerificationToken("public", "userId", &email, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, token.OK)
+
+	verified, err := emailverification.VerifyEmailUsingToken("public", token.OK.Token, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, verified.OK)
+	assert.Equal(t, "userId", verified.OK.User.ID)
+
+	isVerified, err := emailverification.IsEmailVerified("userId", &email, userContext)
+	assert.NoError(t, err)
+	assert.True(t, isVerified)
+
+	token, err = emailverification.CreateEmailVerificationToken("public", "userId", &email, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, token.EmailAlreadyVerifiedError)
+
+	_, err = emailverification.UnverifyEmail("userId", &email, userContext)
+	assert.NoError(t, err)
+	isVerified, err = emailverification.IsEmailVerified("userId", &email, userContext)
+	assert.NoError(t, err)
+	assert.False(t, isVerified)
+}
+
+func TestThatRolesAndMetadataAreStored(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	createdRole, err := userroles.CreateNewRoleOrAddPermissions("admin", []string{"read", "write"}, userContext)
+	assert.NoError(t, err)
+	assert.True(t, createdRole.OK.CreatedNewRole)
+
+	addedRole, err := userroles.AddRoleToUser("public", "userId", "admin", userContext)
+	assert.NoError(t, err)
+	assert.False(t, addedRole.OK.DidUserAlreadyHaveRole)
+
+	unknownRole, err := userroles.AddRoleToUser("public", "userId", "unknown", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, unknownRole.UnknownRoleError)
+
+	roles, err := userroles.GetRolesForUser("public", "userId", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, roles.OK.Roles)
+
+	permissions, err := userroles.GetPermissionsForRole("admin", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read", "write"}, permissions.OK.Permissions)
+
+	metadata, err := usermetadata.UpdateUserMetadata("userId", map[string]interface{}{"first": "a", "second": "b"}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"first": "a", "second": "b"}, metadata)
+
+	metadata, err = usermetadata.UpdateUserMetadata("userId", map[string]interface{}{"first": nil}, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"second": "b"}, metadata)
+
+	assert.NoError(t, usermetadata.ClearUserMetadata("userId", userContext))
+	metadata, err = usermetadata.GetUserMetadata("userId", userContext)
+	assert.NoError(t, err)
+	assert.Empty(t, metadata)
+}
+
+func TestThatTenantsScopeUsers(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	enabled := true
+	created, err := multitenancy.CreateOrUpdateTenant("customer", multitenancymodels.TenantConfig{
+		EmailPasswordEnabled: &enabled,
+	}, userContext)
+	assert.NoError(t, err)
+	assert.True(t, created.OK.CreatedNew)
+
+	tenant, err := multitenancy.GetTenant("customer", userContext)
+	assert.NoError(t, err)
+	assert.True(t, tenant.EmailPassword.Enabled)
+	assert.False(t, tenant.Passwordless.Enabled)
+
+	signUp, err := emailpassword.SignUp("customer", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signUp.OK)
+
+	signIn, err := emailpassword.SignIn("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signIn.WrongCredentialsError)
+
+	associated, err := multitenancy.AssociateUserToTenant("public", signUp.OK.User.ID, userContext)
+	assert.NoError(t, err)
+	assert.False(t, associated.OK.WasAlreadyAssociated)
+
+	signIn, err = emailpassword.SignIn("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, signIn.OK)
+
+	tenants, err := multitenancy.ListAllTenants(userContext)
+	assert.NoError(t, err)
+	assert.Len(t, tenants.OK.Tenants, 2)
+
+	deleted, err := multitenancy.DeleteTenant("customer", userContext)
+	assert.NoError(t, err)
+	assert.True(t, deleted.OK.DidExist)
+
+	// requests to a tenant that does not exist are rejected by the core
+	_, err = emailpassword.SignUp("customer", "other@example.com", "password123", userContext)
+	assert.Error(t, err)
+}
+
+func TestThatUserIdsCanBeMapped(t *testing.T) {
+	core := coreemulator.Start(nil)
+	defer core.Close()
+	userContext := newInstance(t, core, "")
+
+	signUp, err := emailpassword.SignUp("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	superTokensUserId := signUp.OK.User.ID
+
+	info := "info"
+	created, err := supertokens.CreateUserIdMapping(superTokensUserId, "externalId", &info, nil, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, created.OK)
+
+	created, err = supertokens.CreateUserIdMapping(superTokensUserId, "otherId", nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.True(t, created.UserIdMappingAlreadyExistsError.DoesSuperTokensUserIdExist)
+
+	created, err = supertokens.CreateUserIdMapping("unknown", "otherId", nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, created.UnknownSupertokensUserIdError)
+
+	mapping, err := supertokens.GetUserIdMapping("externalId", nil, userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, superTokensUserId, mapping.OK.SupertokensUserId)
+	assert.Equal(t, "info", *mapping.OK.ExternalUserIdInfo)
+
+	// users are returned with their external user id
+	signIn, err := emailpassword.SignIn("public", "test@example.com", "password123", userContext)
+	assert.NoError(t, err)
+	assert.Equal(t, "externalId", signIn.OK.User.ID)
+
+	updated, err := supertokens.UpdateOrDeleteUserIdMappingInfo("externalId", nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, updated.OK)
+	mapping, err = supertokens.GetUserIdMapping(superTokensUserId, nil, userContext)
+	assert.NoError(t, err)
+	assert.Nil(t, mapping.OK.ExternalUserIdInfo)
+
+	deleted, err := supertokens.DeleteUserIdMapping("externalId", nil, nil, userContext)
+	assert.NoError(t, err)
+	assert.True(t, deleted.OK.DidMappingExist)
+	mapping, err = supertokens.GetUserIdMapping("externalId", nil, userContext)
+	assert.NoError(t, err)
+	assert.NotNil(t, mapping.UnknownMappingError)
+}
@@ -0,0 +1,118 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import "time"
+
+type emailVerificationToken struct {
+	userId   string
+	email    string
+	tenantId string
+	expiry   time.Time
+}
+
+func verifiedEmailKey(userId string, email string) string {
+	return userId + "\n" + email
+}
+
+func (c *Core) createEmailVerificationToken(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	email, err := request.requireString("email")
+	if err != nil {
+		return nil, err
+	}
+	if c.verifiedEmails[verifiedEmailKey(userId, email)] {
+		return statusResponse("EMAIL_ALREADY_VERIFIED_ERROR"), nil
+	}
+	token := newToken()
+	c.emailVerificationTokens[token] = emailVerificationToken{
+		userId:   userId,
+		email:    email,
+		tenantId: request.tenantId,
+		expiry:   time.Now().Add(24 * time.Hour),
+	}
+	return okResponse(map[string]interface{}{
+		"token": token,
+	}), nil
+}
+
+func (c *Core) verifyEmail(request *coreRequest) (interface{}, error) {
+	token, err := request.requireString("token")
+	if err != nil {
+		return nil, err
+	}
+	verificationToken, ok := c.emailVerificationTokens[token]
+	if !ok || verificationToken.tenantId != request.tenantId || verificationToken.expiry.Before(time.Now()) {
+		return statusResponse("EMAIL_VERIFICATION_INVALID_TOKEN_ERROR"), nil
+	}
+	c.removeEmailVerificationTokens(verificationToken.userId, verificationToken.email)
+	c.verifiedEmails[verifiedEmailKey(verificationToken.userId, verificationToken.email)] = true
+	return okResponse(map[string]interface{}{
+		"userId": verificationToken.userId,
+		"email":  verificationToken.email,
+	}), nil
+}
+
+func (c *Core) isEmailVerified(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireQuery("userId")
+	if err != nil {
+		return nil, err
+	}
+	email, err := request.requireQuery("email")
+	if err != nil {
+		return nil, err
+	}
+	return okResponse(map[string]interface{}{
+		"isVerified": c.verifiedEmails[verifiedEmailKey(userId, email)],
+	}), nil
+}
+
+func (c *Core) revokeEmailVerificationTokens(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	email, err := request.requireString("email")
+	if err != nil {
+		return nil, err
+	}
+	c.removeEmailVerificationTokens(userId, email)
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) unverifyEmail(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	email, err := request.requireString("email")
+	if err != nil {
+		return nil, err
+	}
+	delete(c.verifiedEmails, verifiedEmailKey(userId, email))
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) removeEmailVerificationTokens(userId string, email string) {
+	for token, verificationToken := range c.emailVerificationTokens {
+		if verificationToken.userId == userId && verificationToken.email == email {
+			delete(c.emailVerificationTokens, token)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import "sort"
+
+type tenant struct {
+	tenantId             string
+	emailPasswordEnabled bool
+	passwordlessEnabled  bool
+	thirdPartyEnabled    bool
+	coreConfig           map[string]interface{}
+	// thirdPartyProviders are kept in the order in which they were added
+	thirdPartyProviders []map[string]interface{}
+}
+
+func newTenant(tenantId string, enabled bool) *tenant {
+	return &tenant{
+		tenantId:             tenantId,
+		emailPasswordEnabled: enabled,
+		passwordlessEnabled:  enabled,
+		thirdPartyEnabled:    enabled,
+		coreConfig:           map[string]interface{}{},
+		thirdPartyProviders:  []map[string]interface{}{},
+	}
+}
+
+func (t *tenant) toJSON() map[string]interface{} {
+	return map[string]interface{}{
+		"tenantId": t.tenantId,
+		"emailPassword": map[string]interface{}{
+			"enabled": t.emailPasswordEnabled,
+		},
+		"passwordless": map[string]interface{}{
+			"enabled": t.passwordlessEnabled,
+		},
+		"thirdParty": map[string]interface{}{
+			"enabled":   t.thirdPartyEnabled,
+			"providers": t.thirdPartyProviders,
+		},
+		"coreConfig": t.coreConfig,
+	}
+}
+
+func (c *Core) createOrUpdateTenant(request *coreRequest) (interface{}, error) {
+	tenantId, err := request.requireString("tenantId")
+	if err != nil {
+		return nil, err
+	}
+	t, exists := c.tenants[tenantId]
+	if !exists {
+		t = newTenant(tenantId, false)
+		c.tenants[tenantId] = t
+	}
+	if enabled, ok := request.body["emailPasswordEnabled"].(bool); ok {
+		t.emailPasswordEnabled = enabled
+	}
+	if enabled, ok := request.body["passwordlessEnabled"].(bool); ok {
+		t.passwordlessEnabled = enabled
+	}
+	if enabled, ok := request.body["thirdPartyEnabled"].(bool); ok {
+		t.thirdPartyEnabled = enabled
+	}
+	if coreConfig, ok := request.body["coreConfig"].(map[string]interface{}); ok {
+		for key, value := range coreConfig {
+			if value == nil {
+				delete(t.coreConfig, key)
+			} else {
+				t.coreConfig[key] = value
+			}
+		}
+	}
+	return okResponse(map[string]interface{}{
+		"createdNew": !exists,
+	}), nil
+}
+
+func (c *Core) deleteTenant(request *coreRequest) (interface{}, error) {
+	tenantId, err := request.requireString("tenantId")
+	if err != nil {
+		return nil, err
+	}
+	if tenantId == publicTenantId {
+		return nil, badRequest("Cannot delete public tenant, use remove app instead")
+	}
+	_, exists := c.tenants[tenantId]
+	delete(c.tenants, tenantId)
+	delete(c.userRoles, tenantId)
+	for _, u := range c.users {
+		u.tenantIds = removeString(u.tenantIds, tenantId)
+	}
+	return okResponse(map[string]interface{}{
+		"didExist": exists,
+	}), nil
+}
+
+func (c *Core) getTenant(request *coreRequest) (interface{}, error) {
+	t, exists := c.tenants[request.tenantId]
+	if !exists {
+		return statusResponse("TENANT_NOT_FOUND_ERROR"), nil
+	}
+	return okResponse(t.toJSON()), nil
+}
+
+func (c *Core) listAllTenants(request *coreRequest) (interface{}, error) {
+	tenantIds := []string{}
+	for tenantId := range c.tenants {
+		tenantIds = append(tenantIds, tenantId)
+	}
+	sort.Strings(tenantIds)
+	tenants := []map[string]interface{}{}
+	for _, tenantId := range tenantIds {
+		tenants = append(tenants, c.tenants[tenantId].toJSON())
+	}
+	return okResponse(map[string]interface{}{
+		"tenants": tenants,
+	}), nil
+}
+
+func (c *Core) createOrUpdateThirdPartyConfig(request *coreRequest) (interface{}, error) {
+	config := request.getMap("config")
+	thirdPartyId, ok := config["thirdPartyId"].(string)
+	if !ok || thirdPartyId == "" {
+		return nil, badRequest("thirdPartyId is required in config")
+	}
+	t := c.tenants[request.tenantId]
+	for i, provider := range t.thirdPartyProviders {
+		if provider["thirdPartyId"] == thirdPartyId {
+			t.thirdPartyProviders[i] = config
+			return okResponse(map[string]interface{}{
+				"createdNew": false,
+			}), nil
+		}
+	}
+	t.thirdPartyProviders = append(t.thirdPartyProviders, config)
+	return okResponse(map[string]interface{}{
+		"createdNew": true,
+	}), nil
+}
+
+func (c *Core) deleteThirdPartyConfig(request *coreRequest) (interface{}, error) {
+	thirdPartyId, err := request.requireString("thirdPartyId")
+	if err != nil {
+		return nil, err
+	}
+	t := c.tenants[request.tenantId]
+	for i, provider := range t.thirdPartyProviders {
+		if provider["thirdPartyId"] == thirdPartyId {
+			t.thirdPartyProviders = append(t.thirdPartyProviders[:i], t.thirdPartyProviders[i+1:]...)
+			return okResponse(map[string]interface{}{
+				"didConfigExist": true,
+			}), nil
+		}
+	}
+	return okResponse(map[string]interface{}{
+		"didConfigExist": false,
+	}), nil
+}
+
+func (c *Core) associateUserToTenant(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	u := c.getUserById(userId)
+	if u == nil {
+		return statusResponse("UNKNOWN_USER_ID_ERROR"), nil
+	}
+	if u.isInTenant(request.tenantId) {
+		return okResponse(map[string]interface{}{
+			"wasAlreadyAssociated": true,
+		}), nil
+	}
+	// the login methods of a user must be unique within a tenant
+	if u.email != nil && u.recipeId != recipeThirdParty && c.findUserByEmail(u.recipeId, request.tenantId, *u.email) != nil {
+		return statusResponse("EMAIL_ALREADY_EXISTS_ERROR"), nil
+	}
+	if u.phoneNumber != nil && c.findUser(func(other *user) bool {
+		return other.recipeId == u.recipeId && other.isInTenant(request.tenantId) && other.phoneNumber != nil && *other.phoneNumber == *u.phoneNumber
+	}) != nil {
+		return statusResponse("PHONE_NUMBER_ALREADY_EXISTS_ERROR"), nil
+	}
+	if u.recipeId == recipeThirdParty && c.findUser(func(other *user) bool {
+		return other.recipeId == recipeThirdParty && other.isInTenant(request.tenantId) && other.thirdPartyId == u.thirdPartyId && other.thirdPartyUserId == u.thirdPartyUserId
+	}) != nil {
+		return statusResponse("THIRD_PARTY_USER_ALREADY_EXISTS_ERROR"), nil
+	}
+	u.tenantIds = append(u.tenantIds, request.tenantId)
+	return okResponse(map[string]interface{}{
+		"wasAlreadyAssociated": false,
+	}), nil
+}
+
+func (c *Core) disassociateUserFromTenant(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	u := c.getUserById(userId)
+	if u == nil || !u.isInTenant(request.tenantId) {
+		return okResponse(map[string]interface{}{
+			"wasAssociated": false,
+		}), nil
+	}
+	u.tenantIds = removeString(u.tenantIds, request.tenantId)
+	return okResponse(map[string]interface{}{
+		"wasAssociated": true,
+	}), nil
+}
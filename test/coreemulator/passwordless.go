@@ -0,0 +1,309 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// passwordlessDevice is a sign in / up attempt, which can have several codes. It is stored using the
+// preAuthSessionId, which is the hash of the device id.
+type passwordlessDevice struct {
+	preAuthSessionId            string
+	tenantId                    string
+	email                       *string
+	phoneNumber                 *string
+	failedCodeInputAttemptCount int
+	codes                       []*passwordlessCode
+}
+
+type passwordlessCode struct {
+	codeId        string
+	userInputCode string
+	linkCodeHash  string
+	timeCreated   uint64
+}
+
+func (c *Core) isCodeExpired(code *passwordlessCode) bool {
+	return code.timeCreated+c.codeLifetimeInMS() < nowInMS()
+}
+
+func (c *Core) codeLifetimeInMS() uint64 {
+	return uint64(c.config.PasswordlessCodeLifetime.Milliseconds())
+}
+
+func (c *Core) createPasswordlessCode(request *coreRequest) (interface{}, error) {
+	var device *passwordlessDevice
+	var deviceId string
+
+	if existingDeviceId, ok := request.getString("deviceId"); ok {
+		deviceId = existingDeviceId
+		device = c.passwordlessDevices[hashBase64URL(deviceId)]
+		if device == nil || device.tenantId != request.tenantId {
+			return statusResponse("RESTART_FLOW_ERROR"), nil
+		}
+	} else {
+		deviceId = base64Token()
+		device = &passwordlessDevice{
+			preAuthSessionId: hashBase64URL(deviceId),
+			tenantId:         request.tenantId,
+		}
+		if email, ok := request.getString("email"); ok {
+			email = normaliseEmail(email)
+			device.email = &email
+		} else if phoneNumber, ok := request.getString("phoneNumber"); ok {
+			device.phoneNumber = &phoneNumber
+		} else {
+			return nil, badRequest("Please provide exactly one of email or phoneNumber")
+		}
+		c.passwordlessDevices[device.preAuthSessionId] = device
+	}
+
+	userInputCode, ok := request.getString("userInputCode")
+	if ok {
+		for _, code := range device.codes {
+			if code.userInputCode == userInputCode {
+				return statusResponse("USER_INPUT_CODE_ALREADY_USED_ERROR"), nil
+			}
+		}
+	} else {
+		userInputCode = randomDigits(6)
+	}
+
+	linkCode := base64Token()
+	code := &passwordlessCode{
+		codeId:        newId(),
+		userInputCode: userInputCode,
+		linkCodeHash:  hashBase64URL(linkCode),
+		timeCreated:   nowInMS(),
+	}
+	device.codes = append(device.codes, code)
+
+	return okResponse(map[string]interface{}{
+		"preAuthSessionId": device.preAuthSessionId,
+		"codeId":           code.codeId,
+		"deviceId":         deviceId,
+		"userInputCode":    userInputCode,
+		"linkCode":         linkCode,
+		"codeLifetime":     c.codeLifetimeInMS(),
+		"timeCreated":      code.timeCreated,
+	}), nil
+}
+
+func (c *Core) consumePasswordlessCode(request *coreRequest) (interface{}, error) {
+	preAuthSessionId, err := request.requireString("preAuthSessionId")
+	if err != nil {
+		return nil, err
+	}
+
+	var device *passwordlessDevice
+	if linkCode, ok := request.getString("linkCode"); ok {
+		device = c.passwordlessDevices[preAuthSessionId]
+		if device == nil || device.tenantId != request.tenantId {
+			return statusResponse("RESTART_FLOW_ERROR"), nil
+		}
+		var code *passwordlessCode
+		for _, deviceCode := range device.codes {
+			if deviceCode.linkCodeHash == hashBase64URL(linkCode) {
+				code = deviceCode
+			}
+		}
+		if code == nil || c.isCodeExpired(code) {
+			return statusResponse("RESTART_FLOW_ERROR"), nil
+		}
+	} else {
+		deviceId, err := request.requireString("deviceId")
+		if err != nil {
+			return nil, err
+		}
+		userInputCode, err := request.requireString("userInputCode")
+		if err != nil {
+			return nil, err
+		}
+		device = c.passwordlessDevices[hashBase64URL(deviceId)]
+		if device == nil || device.preAuthSessionId != preAuthSessionId || device.tenantId != request.tenantId {
+			return statusResponse("RESTART_FLOW_ERROR"), nil
+		}
+		var code *passwordlessCode
+		for _, deviceCode := range device.codes {
+			if deviceCode.userInputCode == userInputCode {
+				code = deviceCode
+			}
+		}
+		if code == nil || c.isCodeExpired(code) {
+			device.failedCodeInputAttemptCount++
+			if device.failedCodeInputAttemptCount >= c.config.PasswordlessMaxCodeInputAttempts {
+				delete(c.passwordlessDevices, device.preAuthSessionId)
+				return statusResponse("RESTART_FLOW_ERROR"), nil
+			}
+			status := "INCORRECT_USER_INPUT_CODE_ERROR"
+			if code != nil {
+				status = "EXPIRED_USER_INPUT_CODE_ERROR"
+			}
+			return map[string]interface{}{
+				"status":                      status,
+				"failedCodeInputAttemptCount": device.failedCodeInputAttemptCount,
+				"maximumCodeInputAttempts":    c.config.PasswordlessMaxCodeInputAttempts,
+			}, nil
+		}
+	}
+
+	// all the devices of the email / phone number are removed once one of them is used to sign in
+	c.removePasswordlessDevices(device.tenantId, device.email, device.phoneNumber)
+
+	createdNewUser := false
+	u := c.findUser(func(u *user) bool {
+		if u.recipeId != recipePasswordless || !u.isInTenant(device.tenantId) {
+			return false
+		}
+		if device.email != nil {
+			return u.email != nil && *u.email == *device.email
+		}
+		return u.phoneNumber != nil && *u.phoneNumber == *device.phoneNumber
+	})
+	if u == nil {
+		createdNewUser = true
+		u = c.newUser(recipePasswordless, device.tenantId)
+		u.email = device.email
+		u.phoneNumber = device.phoneNumber
+	}
+	return okResponse(map[string]interface{}{
+		"createdNewUser": createdNewUser,
+		"user":           c.userToJSON(u),
+	}), nil
+}
+
+func (c *Core) removePasswordlessDevices(tenantId string, email *string, phoneNumber *string) {
+	for preAuthSessionId, device := range c.passwordlessDevices {
+		if device.tenantId != tenantId {
+			continue
+		}
+		if (email != nil && device.email != nil && *device.email == *email) ||
+			(phoneNumber != nil && device.phoneNumber != nil && *device.phoneNumber == *phoneNumber) {
+			delete(c.passwordlessDevices, preAuthSessionId)
+		}
+	}
+}
+
+func (c *Core) listPasswordlessCodes(request *coreRequest) (interface{}, error) {
+	var matches func(device *passwordlessDevice) bool
+	if deviceId := request.query("deviceId"); deviceId != "" {
+		preAuthSessionId := hashBase64URL(deviceId)
+		matches = func(device *passwordlessDevice) bool { return device.preAuthSessionId == preAuthSessionId }
+	} else if preAuthSessionId := request.query("preAuthSessionId"); preAuthSessionId != "" {
+		matches = func(device *passwordlessDevice) bool { return device.preAuthSessionId == preAuthSessionId }
+	} else if email := request.query("email"); email != "" {
+		email = normaliseEmail(email)
+		matches = func(device *passwordlessDevice) bool { return device.email != nil && *device.email == email }
+	} else if phoneNumber := request.query("phoneNumber"); phoneNumber != "" {
+		matches = func(device *passwordlessDevice) bool {
+			return device.phoneNumber != nil && *device.phoneNumber == phoneNumber
+		}
+	} else {
+		return nil, badRequest("Please provide exactly one of deviceId, preAuthSessionId, email or phoneNumber")
+	}
+
+	preAuthSessionIds := []string{}
+	for preAuthSessionId, device := range c.passwordlessDevices {
+		if device.tenantId == request.tenantId && matches(device) {
+			preAuthSessionIds = append(preAuthSessionIds, preAuthSessionId)
+		}
+	}
+	sort.Strings(preAuthSessionIds)
+
+	devices := []map[string]interface{}{}
+	for _, preAuthSessionId := range preAuthSessionIds {
+		device := c.passwordlessDevices[preAuthSessionId]
+		codes := []map[string]interface{}{}
+		for _, code := range device.codes {
+			codes = append(codes, map[string]interface{}{
+				"codeId":       code.codeId,
+				"timeCreated":  code.timeCreated,
+				"codeLifetime": c.codeLifetimeInMS(),
+			})
+		}
+		deviceJSON := map[string]interface{}{
+			"preAuthSessionId":            device.preAuthSessionId,
+			"failedCodeInputAttemptCount": device.failedCodeInputAttemptCount,
+			"codes":                       codes,
+		}
+		if device.email != nil {
+			deviceJSON["email"] = *device.email
+		}
+		if device.phoneNumber != nil {
+			deviceJSON["phoneNumber"] = *device.phoneNumber
+		}
+		devices = append(devices, deviceJSON)
+	}
+	return okResponse(map[string]interface{}{
+		"devices": devices,
+	}), nil
+}
+
+func (c *Core) revokeAllPasswordlessCodes(request *coreRequest) (interface{}, error) {
+	var email, phoneNumber *string
+	if value, ok := request.getString("email"); ok {
+		value = normaliseEmail(value)
+		email = &value
+	} else if value, ok := request.getString("phoneNumber"); ok {
+		phoneNumber = &value
+	} else {
+		return nil, badRequest("Please provide exactly one of email or phoneNumber")
+	}
+	c.removePasswordlessDevices(request.tenantId, email, phoneNumber)
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) revokePasswordlessCode(request *coreRequest) (interface{}, error) {
+	codeId, err := request.requireString("codeId")
+	if err != nil {
+		return nil, err
+	}
+	for preAuthSessionId, device := range c.passwordlessDevices {
+		if device.tenantId != request.tenantId {
+			continue
+		}
+		for i, code := range device.codes {
+			if code.codeId == codeId {
+				device.codes = append(device.codes[:i], device.codes[i+1:]...)
+				if len(device.codes) == 0 {
+					delete(c.passwordlessDevices, preAuthSessionId)
+				}
+				return statusResponse("OK"), nil
+			}
+		}
+	}
+	return statusResponse("OK"), nil
+}
+
+func base64Token() string {
+	return hashBase64URL(newToken())
+}
+
+func randomDigits(length int) string {
+	result := ""
+	for i := 0; i < length; i++ {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			panic(err)
+		}
+		result += fmt.Sprint(digit.Int64())
+	}
+	return result
+}
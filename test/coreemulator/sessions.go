@@ -0,0 +1,549 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import (
+	"encoding/base64"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const accessTokenVersion = "5"
+
+// protectedAccessTokenClaims are set by the core and cannot be changed using userDataInJWT
+var protectedAccessTokenClaims = []string{"sub", "iat", "exp", "sessionHandle", "parentRefreshTokenHash1", "refreshTokenHash1", "antiCsrfToken", "rsub", "tId"}
+
+type session struct {
+	handle             string
+	userId             string
+	tenantId           string
+	userDataInJWT      map[string]interface{}
+	userDataInDatabase map[string]interface{}
+	timeCreated        uint64
+	expiry             uint64
+	useDynamicKey      bool
+	// refreshTokenHash2 is the hash of the hash of the refresh token that was last confirmed by the client,
+	// either by using the access token that was created with it, or by refreshing using it
+	refreshTokenHash2 string
+}
+
+type refreshTokenInfo struct {
+	sessionHandle     string
+	parentTokenHash2  string
+	antiCsrfToken     *string
+	enableAntiCsrf    bool
+	refreshTokenHash1 string
+}
+
+func (c *Core) getJWKS(request *coreRequest) (interface{}, error) {
+	keys := []map[string]interface{}{}
+	for _, key := range []signingKey{c.dynamicKey, c.staticKey} {
+		publicKey := key.privateKey.PublicKey
+		keys = append(keys, map[string]interface{}{
+			"kty": "RSA",
+			"kid": key.kid,
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			"alg": "RS256",
+			"use": "sig",
+		})
+	}
+	return map[string]interface{}{
+		"keys": keys,
+	}, nil
+}
+
+func (c *Core) signJWT(claims map[string]interface{}, useDynamicKey bool, version string) (string, error) {
+	key := c.staticKey
+	if useDynamicKey {
+		key = c.dynamicKey
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims(claims))
+	token.Header["kid"] = key.kid
+	if version != "" {
+		token.Header["version"] = version
+	}
+	return token.SignedString(key.privateKey)
+}
+
+// parseJWT verifies the signature of a JWT signed by this core. The expiry is checked by the callers.
+func (c *Core) parseJWT(tokenString string) (map[string]interface{}, bool) {
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation(), jwt.WithValidMethods([]string{"RS256"}))
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		for _, key := range []signingKey{c.dynamicKey, c.staticKey} {
+			if key.kid == kid {
+				return &key.privateKey.PublicKey, nil
+			}
+		}
+		return nil, jwt.ErrTokenUnverifiable
+	})
+	if err != nil || !token.Valid {
+		return nil, false
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, false
+	}
+	return claims, true
+}
+
+func (c *Core) createJWT(request *coreRequest) (interface{}, error) {
+	algorithm, err := request.requireString("algorithm")
+	if err != nil {
+		return nil, err
+	}
+	if algorithm != "RS256" {
+		return statusResponse("UNSUPPORTED_ALGORITHM_ERROR"), nil
+	}
+	validity, ok := request.body["validity"].(float64)
+	if !ok {
+		return nil, badRequest("Field name 'validity' is invalid in JSON input")
+	}
+	claims := map[string]interface{}{}
+	for key, value := range request.getMap("payload") {
+		claims[key] = value
+	}
+	now := time.Now().Unix()
+	claims["iat"] = now
+	claims["exp"] = now + int64(validity)
+	if jwksDomain, ok := request.getString("jwksDomain"); ok {
+		claims["iss"] = jwksDomain
+	}
+	useStaticSigningKey, ok := request.body["useStaticSigningKey"].(bool)
+	token, err := c.signJWT(claims, ok && !useStaticSigningKey, "")
+	if err != nil {
+		return nil, err
+	}
+	return okResponse(map[string]interface{}{
+		"jwt": token,
+	}), nil
+}
+
+func (c *Core) createSession(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	now := nowInMS()
+	s := &session{
+		handle:             newId(),
+		userId:             userId,
+		tenantId:           request.tenantId,
+		userDataInJWT:      withoutProtectedClaims(request.getMap("userDataInJWT")),
+		userDataInDatabase: request.getMap("userDataInDatabase"),
+		timeCreated:        now,
+		expiry:             now + uint64(c.config.RefreshTokenValidity/time.Millisecond),
+		useDynamicKey:      true,
+	}
+	if useDynamicSigningKey, ok := request.body["useDynamicSigningKey"].(bool); ok {
+		s.useDynamicKey = useDynamicSigningKey
+	}
+	c.sessions[s.handle] = s
+
+	var antiCsrfToken *string
+	enableAntiCsrf := request.getBool("enableAntiCsrf")
+	if enableAntiCsrf {
+		token := newId()
+		antiCsrfToken = &token
+	}
+
+	refreshToken, refreshTokenHash1 := c.newRefreshToken(s, "", antiCsrfToken, enableAntiCsrf)
+	s.refreshTokenHash2 = hashHex(refreshTokenHash1)
+
+	accessToken, err := c.newAccessToken(s, refreshTokenHash1, nil, antiCsrfToken)
+	if err != nil {
+		return nil, err
+	}
+	response := okResponse(map[string]interface{}{
+		"session":      c.sessionStruct(s, accessToken.payload),
+		"accessToken":  accessToken.toResponse(),
+		"refreshToken": refreshToken,
+	})
+	if antiCsrfToken != nil {
+		response["antiCsrfToken"] = *antiCsrfToken
+	}
+	return response, nil
+}
+
+// newRefreshToken creates a refresh token, and returns it along with the hash that is put in the access token
+func (c *Core) newRefreshToken(s *session, parentTokenHash2 string, antiCsrfToken *string, enableAntiCsrf bool) (map[string]interface{}, string) {
+	token := newToken()
+	hash1 := hashHex(token)
+	c.refreshTokens[hashHex(hash1)] = refreshTokenInfo{
+		sessionHandle:     s.handle,
+		parentTokenHash2:  parentTokenHash2,
+		antiCsrfToken:     antiCsrfToken,
+		enableAntiCsrf:    enableAntiCsrf,
+		refreshTokenHash1: hash1,
+	}
+	return map[string]interface{}{
+		"token":       token,
+		"expiry":      s.expiry,
+		"createdTime": nowInMS(),
+	}, hash1
+}
+
+type accessToken struct {
+	token       string
+	payload     map[string]interface{}
+	expiry      uint64
+	createdTime uint64
+}
+
+func (t accessToken) toResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"token":       t.token,
+		"expiry":      t.expiry,
+		"createdTime": t.createdTime,
+	}
+}
+
+func (c *Core) newAccessToken(s *session, refreshTokenHash1 string, parentRefreshTokenHash1 *string, antiCsrfToken *string) (accessToken, error) {
+	now := time.Now()
+	expiry := now.Add(c.config.AccessTokenValidity)
+	payload := map[string]interface{}{}
+	for key, value := range s.userDataInJWT {
+		payload[key] = value
+	}
+	payload["sub"] = s.userId
+	payload["rsub"] = s.userId
+	payload["tId"] = s.tenantId
+	payload["iat"] = now.Unix()
+	payload["exp"] = expiry.Unix()
+	payload["sessionHandle"] = s.handle
+	payload["refreshTokenHash1"] = refreshTokenHash1
+	if parentRefreshTokenHash1 != nil {
+		payload["parentRefreshTokenHash1"] = *parentRefreshTokenHash1
+	}
+	if antiCsrfToken != nil {
+		payload["antiCsrfToken"] = *antiCsrfToken
+	}
+	token, err := c.signJWT(payload, s.useDynamicKey, accessTokenVersion)
+	if err != nil {
+		return accessToken{}, err
+	}
+	// the payload is sent back to the SDK the way it is decoded from the token
+	payload["iat"] = float64(now.Unix())
+	payload["exp"] = float64(expiry.Unix())
+	return accessToken{
+		token:       token,
+		payload:     payload,
+		expiry:      uint64(expiry.Unix()) * 1000,
+		createdTime: uint64(now.Unix()) * 1000,
+	}, nil
+}
+
+func (c *Core) sessionStruct(s *session, accessTokenPayload map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"handle":        s.handle,
+		"userId":        s.userId,
+		"tenantId":      s.tenantId,
+		"userDataInJWT": accessTokenPayload,
+	}
+}
+
+// getActiveSession returns the session if it exists and has not expired
+func (c *Core) getActiveSession(handle string) *session {
+	s, ok := c.sessions[handle]
+	if !ok {
+		return nil
+	}
+	if s.expiry < nowInMS() {
+		c.removeSession(handle)
+		return nil
+	}
+	return s
+}
+
+func (c *Core) removeSession(handle string) {
+	delete(c.sessions, handle)
+	for hash2, info := range c.refreshTokens {
+		if info.sessionHandle == handle {
+			delete(c.refreshTokens, hash2)
+		}
+	}
+}
+
+func (c *Core) verifySession(request *coreRequest) (interface{}, error) {
+	tokenString, err := request.requireString("accessToken")
+	if err != nil {
+		return nil, err
+	}
+	payload, valid := c.parseJWT(tokenString)
+	if !valid {
+		return tryRefreshToken("Invalid access token"), nil
+	}
+	exp, _ := payload["exp"].(float64)
+	if int64(exp) < time.Now().Unix() {
+		return tryRefreshToken("Access token expired"), nil
+	}
+	if request.getBool("doAntiCsrfCheck") && request.getBool("enableAntiCsrf") {
+		antiCsrfTokenInPayload, _ := payload["antiCsrfToken"].(string)
+		antiCsrfToken, _ := request.getString("antiCsrfToken")
+		if antiCsrfTokenInPayload == "" || antiCsrfToken != antiCsrfTokenInPayload {
+			return tryRefreshToken("anti-csrf check failed"), nil
+		}
+	}
+
+	handle, _ := payload["sessionHandle"].(string)
+	s := c.getActiveSession(handle)
+	if s == nil {
+		return unauthorised("Either the session has ended or has been blacklisted"), nil
+	}
+
+	response := okResponse(map[string]interface{}{})
+	refreshTokenHash1, _ := payload["refreshTokenHash1"].(string)
+	parentRefreshTokenHash1, hasParent := payload["parentRefreshTokenHash1"].(string)
+	if hasParent {
+		// the first use of an access token created by a refresh confirms the new refresh token
+		if hashHex(parentRefreshTokenHash1) == s.refreshTokenHash2 {
+			s.refreshTokenHash2 = hashHex(refreshTokenHash1)
+		} else if hashHex(refreshTokenHash1) != s.refreshTokenHash2 {
+			return unauthorised("Either the session has ended or has been blacklisted"), nil
+		}
+		var antiCsrfToken *string
+		if value, ok := payload["antiCsrfToken"].(string); ok {
+			antiCsrfToken = &value
+		}
+		newAccessToken, err := c.newAccessToken(s, refreshTokenHash1, nil, antiCsrfToken)
+		if err != nil {
+			return nil, err
+		}
+		response["accessToken"] = newAccessToken.toResponse()
+		payload = newAccessToken.payload
+	}
+	response["session"] = c.sessionStruct(s, payload)
+	return response, nil
+}
+
+func (c *Core) refreshSession(request *coreRequest) (interface{}, error) {
+	refreshToken, err := request.requireString("refreshToken")
+	if err != nil {
+		return nil, err
+	}
+	hash2 := hashHex(hashHex(refreshToken))
+	info, ok := c.refreshTokens[hash2]
+	if !ok {
+		return unauthorised("Refresh token not found"), nil
+	}
+	s := c.getActiveSession(info.sessionHandle)
+	if s == nil {
+		return unauthorised("Session expired"), nil
+	}
+	if request.getBool("enableAntiCsrf") && info.enableAntiCsrf {
+		antiCsrfToken, _ := request.getString("antiCsrfToken")
+		if info.antiCsrfToken == nil || antiCsrfToken != *info.antiCsrfToken {
+			return unauthorised("Anti CSRF token missing, or not matching"), nil
+		}
+	}
+
+	if s.refreshTokenHash2 != hash2 {
+		if info.parentTokenHash2 != s.refreshTokenHash2 {
+			// an older refresh token was used after it had been rotated
+			c.removeSession(s.handle)
+			return map[string]interface{}{
+				"status": "TOKEN_THEFT_DETECTED",
+				"session": map[string]interface{}{
+					"handle":   s.handle,
+					"userId":   s.userId,
+					"tenantId": s.tenantId,
+				},
+			}, nil
+		}
+		s.refreshTokenHash2 = hash2
+	}
+
+	if useDynamicSigningKey, ok := request.body["useDynamicSigningKey"].(bool); ok {
+		s.useDynamicKey = useDynamicSigningKey
+	}
+	var antiCsrfToken *string
+	enableAntiCsrf := request.getBool("enableAntiCsrf")
+	if enableAntiCsrf {
+		token := newId()
+		antiCsrfToken = &token
+	}
+	newRefreshToken, newRefreshTokenHash1 := c.newRefreshToken(s, hash2, antiCsrfToken, enableAntiCsrf)
+	parentRefreshTokenHash1 := info.refreshTokenHash1
+	newAccessToken, err := c.newAccessToken(s, newRefreshTokenHash1, &parentRefreshTokenHash1, antiCsrfToken)
+	if err != nil {
+		return nil, err
+	}
+	response := okResponse(map[string]interface{}{
+		"session":      c.sessionStruct(s, newAccessToken.payload),
+		"accessToken":  newAccessToken.toResponse(),
+		"refreshToken": newRefreshToken,
+	})
+	if antiCsrfToken != nil {
+		response["antiCsrfToken"] = *antiCsrfToken
+	}
+	return response, nil
+}
+
+func (c *Core) regenerateAccessToken(request *coreRequest) (interface{}, error) {
+	tokenString, err := request.requireString("accessToken")
+	if err != nil {
+		return nil, err
+	}
+	payload, valid := c.parseJWT(tokenString)
+	if !valid {
+		return unauthorised("Invalid access token"), nil
+	}
+	handle, _ := payload["sessionHandle"].(string)
+	s := c.getActiveSession(handle)
+	if s == nil {
+		return unauthorised("Session does not exist."), nil
+	}
+	if userDataInJWT, ok := request.body["userDataInJWT"].(map[string]interface{}); ok {
+		s.userDataInJWT = withoutProtectedClaims(userDataInJWT)
+	}
+	refreshTokenHash1, _ := payload["refreshTokenHash1"].(string)
+	var parentRefreshTokenHash1 *string
+	if value, ok := payload["parentRefreshTokenHash1"].(string); ok {
+		parentRefreshTokenHash1 = &value
+	}
+	var antiCsrfToken *string
+	if value, ok := payload["antiCsrfToken"].(string); ok {
+		antiCsrfToken = &value
+	}
+	newAccessToken, err := c.newAccessToken(s, refreshTokenHash1, parentRefreshTokenHash1, antiCsrfToken)
+	if err != nil {
+		return nil, err
+	}
+	return okResponse(map[string]interface{}{
+		"session":     c.sessionStruct(s, newAccessToken.payload),
+		"accessToken": newAccessToken.toResponse(),
+	}), nil
+}
+
+func (c *Core) getSessionInformation(request *coreRequest) (interface{}, error) {
+	handle, err := request.requireQuery("sessionHandle")
+	if err != nil {
+		return nil, err
+	}
+	s := c.getActiveSession(handle)
+	if s == nil {
+		return unauthorised("Session does not exist."), nil
+	}
+	return okResponse(map[string]interface{}{
+		"sessionHandle":      s.handle,
+		"userId":             s.userId,
+		"tenantId":           s.tenantId,
+		"userDataInDatabase": s.userDataInDatabase,
+		"userDataInJWT":      s.userDataInJWT,
+		"expiry":             s.expiry,
+		"timeCreated":        s.timeCreated,
+	}), nil
+}
+
+func (c *Core) getSessionHandlesForUser(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireQuery("userId")
+	if err != nil {
+		return nil, err
+	}
+	acrossAllTenants := request.query("fetchAcrossAllTenants") == "true"
+	return okResponse(map[string]interface{}{
+		"sessionHandles": c.getSessionHandles(userId, request.tenantId, acrossAllTenants),
+	}), nil
+}
+
+func (c *Core) getSessionHandles(userId string, tenantId string, acrossAllTenants bool) []string {
+	handles := []string{}
+	for handle := range c.sessions {
+		s := c.getActiveSession(handle)
+		if s != nil && s.userId == userId && (acrossAllTenants || s.tenantId == tenantId) {
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+func (c *Core) revokeSessions(request *coreRequest) (interface{}, error) {
+	revoked := []string{}
+	if userId, ok := request.getString("userId"); ok {
+		revokeAcrossAllTenants := true
+		if value, ok := request.body["revokeAcrossAllTenants"].(bool); ok {
+			revokeAcrossAllTenants = value
+		}
+		revoked = c.getSessionHandles(userId, request.tenantId, revokeAcrossAllTenants)
+	} else if handles, ok := request.getStringSlice("sessionHandles"); ok {
+		for _, handle := range handles {
+			if c.getActiveSession(handle) != nil {
+				revoked = append(revoked, handle)
+			}
+		}
+	} else {
+		return nil, badRequest("Field name 'userId' or 'sessionHandles' is missing in JSON input")
+	}
+	for _, handle := range revoked {
+		c.removeSession(handle)
+	}
+	return okResponse(map[string]interface{}{
+		"sessionHandlesRevoked": revoked,
+	}), nil
+}
+
+func (c *Core) updateSessionData(request *coreRequest) (interface{}, error) {
+	handle, err := request.requireString("sessionHandle")
+	if err != nil {
+		return nil, err
+	}
+	s := c.getActiveSession(handle)
+	if s == nil {
+		return unauthorised("Session does not exist."), nil
+	}
+	s.userDataInDatabase = request.getMap("userDataInDatabase")
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) updateAccessTokenPayload(request *coreRequest) (interface{}, error) {
+	handle, err := request.requireString("sessionHandle")
+	if err != nil {
+		return nil, err
+	}
+	s := c.getActiveSession(handle)
+	if s == nil {
+		return unauthorised("Session does not exist."), nil
+	}
+	s.userDataInJWT = withoutProtectedClaims(request.getMap("userDataInJWT"))
+	return statusResponse("OK"), nil
+}
+
+func withoutProtectedClaims(payload map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, value := range payload {
+		if !containsString(protectedAccessTokenClaims, key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+func unauthorised(message string) map[string]interface{} {
+	return map[string]interface{}{
+		"status":  "UNAUTHORISED",
+		"message": message,
+	}
+}
+
+func tryRefreshToken(message string) map[string]interface{} {
+	return map[string]interface{}{
+		"status":  "TRY_REFRESH_TOKEN",
+		"message": message,
+	}
+}
@@ -0,0 +1,51 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import (
+	"testing"
+
+	"github.com/supertokens/supertokens-golang/supertokens"
+)
+
+// NewTestInstance starts a core and creates a SuperTokens instance with the given recipes that uses it.
+// Both are closed when the test finishes.
+func NewTestInstance(t testing.TB, recipes ...supertokens.Recipe) *supertokens.Instance {
+	core := Start(nil)
+	t.Cleanup(core.Close)
+	return NewTestInstanceForCore(t, core, recipes...)
+}
+
+// NewTestInstanceForCore creates a SuperTokens instance with the given recipes that uses an already
+// started core, so that several instances can share it. The instance is closed when the test finishes.
+func NewTestInstanceForCore(t testing.TB, core *Core, recipes ...supertokens.Recipe) *supertokens.Instance {
+	instance, err := supertokens.New(supertokens.TypeInput{
+		Supertokens: &supertokens.ConnectionInfo{
+			ConnectionURI: core.URL,
+		},
+		AppInfo: supertokens.AppInfo{
+			AppName:       "SuperTokens",
+			WebsiteDomain: "supertokens.io",
+			APIDomain:     "api.supertokens.io",
+		},
+		RecipeList: recipes,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(instance.Close)
+	return instance
+}
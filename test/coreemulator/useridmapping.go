@@ -0,0 +1,175 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+type userIdMapping struct {
+	superTokensUserId  string
+	externalUserId     string
+	externalUserIdInfo *string
+}
+
+func (m *userIdMapping) toJSON() map[string]interface{} {
+	result := map[string]interface{}{
+		"superTokensUserId": m.superTokensUserId,
+		"externalUserId":    m.externalUserId,
+	}
+	if m.externalUserIdInfo != nil {
+		result["externalUserIdInfo"] = *m.externalUserIdInfo
+	}
+	return result
+}
+
+// findUserIdMapping looks the mapping up using the type of user id that is given, which is one of
+// SUPERTOKENS, EXTERNAL or ANY. ANY prefers a match on the SuperTokens user id.
+func (c *Core) findUserIdMapping(userId string, userIdType string) *userIdMapping {
+	if userIdType != "EXTERNAL" {
+		for _, m := range c.userIdMappings {
+			if m.superTokensUserId == userId {
+				return m
+			}
+		}
+	}
+	if userIdType != "SUPERTOKENS" {
+		for _, m := range c.userIdMappings {
+			if m.externalUserId == userId {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Core) toExternalUserId(superTokensUserId string) string {
+	if m := c.findUserIdMapping(superTokensUserId, "SUPERTOKENS"); m != nil {
+		return m.externalUserId
+	}
+	return superTokensUserId
+}
+
+func (c *Core) toSuperTokensUserId(userId string) string {
+	if m := c.findUserIdMapping(userId, "EXTERNAL"); m != nil {
+		return m.superTokensUserId
+	}
+	return userId
+}
+
+func (c *Core) removeUserIdMapping(superTokensUserId string) bool {
+	for i, m := range c.userIdMappings {
+		if m.superTokensUserId == superTokensUserId {
+			c.userIdMappings = append(c.userIdMappings[:i], c.userIdMappings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func getUserIdType(value string) (string, error) {
+	switch value {
+	case "":
+		return "ANY", nil
+	case "SUPERTOKENS", "EXTERNAL", "ANY":
+		return value, nil
+	}
+	return "", badRequest("userIdType can only be one of SUPERTOKENS, EXTERNAL or ANY")
+}
+
+func (c *Core) createUserIdMapping(request *coreRequest) (interface{}, error) {
+	superTokensUserId, err := request.requireString("superTokensUserId")
+	if err != nil {
+		return nil, err
+	}
+	externalUserId, err := request.requireString("externalUserId")
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := c.users[superTokensUserId]; !exists {
+		return statusResponse("UNKNOWN_SUPERTOKENS_USER_ID_ERROR"), nil
+	}
+	superTokensUserIdExists := c.findUserIdMapping(superTokensUserId, "SUPERTOKENS") != nil
+	externalUserIdExists := c.findUserIdMapping(externalUserId, "EXTERNAL") != nil
+	if superTokensUserIdExists || externalUserIdExists {
+		return map[string]interface{}{
+			"status":                     "USER_ID_MAPPING_ALREADY_EXISTS_ERROR",
+			"doesSuperTokensUserIdExist": superTokensUserIdExists,
+			"doesExternalUserIdExist":    externalUserIdExists,
+		}, nil
+	}
+	m := &userIdMapping{
+		superTokensUserId: superTokensUserId,
+		externalUserId:    externalUserId,
+	}
+	if externalUserIdInfo, ok := request.getString("externalUserIdInfo"); ok {
+		m.externalUserIdInfo = &externalUserIdInfo
+	}
+	c.userIdMappings = append(c.userIdMappings, m)
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) getUserIdMapping(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireQuery("userId")
+	if err != nil {
+		return nil, err
+	}
+	userIdType, err := getUserIdType(request.query("userIdType"))
+	if err != nil {
+		return nil, err
+	}
+	m := c.findUserIdMapping(userId, userIdType)
+	if m == nil {
+		return statusResponse("UNKNOWN_MAPPING_ERROR"), nil
+	}
+	return okResponse(m.toJSON()), nil
+}
+
+func (c *Core) deleteUserIdMapping(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	userIdType, _ := request.getString("userIdType")
+	userIdType, err = getUserIdType(userIdType)
+	if err != nil {
+		return nil, err
+	}
+	m := c.findUserIdMapping(userId, userIdType)
+	didMappingExist := m != nil && c.removeUserIdMapping(m.superTokensUserId)
+	return okResponse(map[string]interface{}{
+		"didMappingExist": didMappingExist,
+	}), nil
+}
+
+// updateExternalUserIdInfo removes the info when it is set to null
+func (c *Core) updateExternalUserIdInfo(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	userIdType, _ := request.getString("userIdType")
+	userIdType, err = getUserIdType(userIdType)
+	if err != nil {
+		return nil, err
+	}
+	m := c.findUserIdMapping(userId, userIdType)
+	if m == nil {
+		return statusResponse("UNKNOWN_MAPPING_ERROR"), nil
+	}
+	if externalUserIdInfo, ok := request.getString("externalUserIdInfo"); ok {
+		m.externalUserIdInfo = &externalUserIdInfo
+	} else {
+		m.externalUserIdInfo = nil
+	}
+	return statusResponse("OK"), nil
+}
@@ -0,0 +1,62 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+func (c *Core) getUserMetadata(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireQuery("userId")
+	if err != nil {
+		return nil, err
+	}
+	metadata, ok := c.userMetadata[userId]
+	if !ok {
+		metadata = map[string]interface{}{}
+	}
+	return okResponse(map[string]interface{}{
+		"metadata": metadata,
+	}), nil
+}
+
+// updateUserMetadata does a shallow merge of the update into the metadata, and removes the keys that are set to null
+func (c *Core) updateUserMetadata(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	metadata, ok := c.userMetadata[userId]
+	if !ok {
+		metadata = map[string]interface{}{}
+	}
+	for key, value := range request.getMap("metadataUpdate") {
+		if value == nil {
+			delete(metadata, key)
+		} else {
+			metadata[key] = value
+		}
+	}
+	c.userMetadata[userId] = metadata
+	return okResponse(map[string]interface{}{
+		"metadata": metadata,
+	}), nil
+}
+
+func (c *Core) clearUserMetadata(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	delete(c.userMetadata, userId)
+	return statusResponse("OK"), nil
+}
@@ -0,0 +1,202 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import "sort"
+
+func (c *Core) createOrUpdateRole(request *coreRequest) (interface{}, error) {
+	role, err := request.requireString("role")
+	if err != nil {
+		return nil, err
+	}
+	permissions, _ := request.getStringSlice("permissions")
+	existingPermissions, exists := c.roles[role]
+	if !exists {
+		existingPermissions = []string{}
+	}
+	for _, permission := range permissions {
+		if !containsString(existingPermissions, permission) {
+			existingPermissions = append(existingPermissions, permission)
+		}
+	}
+	c.roles[role] = existingPermissions
+	return okResponse(map[string]interface{}{
+		"createdNewRole": !exists,
+	}), nil
+}
+
+func (c *Core) getPermissionsForRole(request *coreRequest) (interface{}, error) {
+	role, err := request.requireQuery("role")
+	if err != nil {
+		return nil, err
+	}
+	permissions, exists := c.roles[role]
+	if !exists {
+		return statusResponse("UNKNOWN_ROLE_ERROR"), nil
+	}
+	return okResponse(map[string]interface{}{
+		"permissions": permissions,
+	}), nil
+}
+
+func (c *Core) removePermissionsFromRole(request *coreRequest) (interface{}, error) {
+	role, err := request.requireString("role")
+	if err != nil {
+		return nil, err
+	}
+	existingPermissions, exists := c.roles[role]
+	if !exists {
+		return statusResponse("UNKNOWN_ROLE_ERROR"), nil
+	}
+	permissions, given := request.getStringSlice("permissions")
+	if !given {
+		// all the permissions are removed if none are given
+		c.roles[role] = []string{}
+		return statusResponse("OK"), nil
+	}
+	for _, permission := range permissions {
+		existingPermissions = removeString(existingPermissions, permission)
+	}
+	c.roles[role] = existingPermissions
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) getRolesThatHavePermission(request *coreRequest) (interface{}, error) {
+	permission, err := request.requireQuery("permission")
+	if err != nil {
+		return nil, err
+	}
+	roles := []string{}
+	for role, permissions := range c.roles {
+		if containsString(permissions, permission) {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return okResponse(map[string]interface{}{
+		"roles": roles,
+	}), nil
+}
+
+func (c *Core) deleteRole(request *coreRequest) (interface{}, error) {
+	role, err := request.requireString("role")
+	if err != nil {
+		return nil, err
+	}
+	_, exists := c.roles[role]
+	delete(c.roles, role)
+	for _, rolesOfUsers := range c.userRoles {
+		for userId, roles := range rolesOfUsers {
+			rolesOfUsers[userId] = removeString(roles, role)
+		}
+	}
+	return okResponse(map[string]interface{}{
+		"didRoleExist": exists,
+	}), nil
+}
+
+func (c *Core) getAllRoles(request *coreRequest) (interface{}, error) {
+	roles := []string{}
+	for role := range c.roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return okResponse(map[string]interface{}{
+		"roles": roles,
+	}), nil
+}
+
+func (c *Core) getRolesOfUsersInTenant(tenantId string) map[string][]string {
+	rolesOfUsers, ok := c.userRoles[tenantId]
+	if !ok {
+		rolesOfUsers = map[string][]string{}
+		c.userRoles[tenantId] = rolesOfUsers
+	}
+	return rolesOfUsers
+}
+
+func (c *Core) addRoleToUser(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	role, err := request.requireString("role")
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := c.roles[role]; !exists {
+		return statusResponse("UNKNOWN_ROLE_ERROR"), nil
+	}
+	rolesOfUsers := c.getRolesOfUsersInTenant(request.tenantId)
+	hadRole := containsString(rolesOfUsers[userId], role)
+	if !hadRole {
+		rolesOfUsers[userId] = append(rolesOfUsers[userId], role)
+	}
+	return okResponse(map[string]interface{}{
+		"didUserAlreadyHaveRole": hadRole,
+	}), nil
+}
+
+func (c *Core) removeUserRole(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	role, err := request.requireString("role")
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := c.roles[role]; !exists {
+		return statusResponse("UNKNOWN_ROLE_ERROR"), nil
+	}
+	rolesOfUsers := c.getRolesOfUsersInTenant(request.tenantId)
+	hadRole := containsString(rolesOfUsers[userId], role)
+	rolesOfUsers[userId] = removeString(rolesOfUsers[userId], role)
+	return okResponse(map[string]interface{}{
+		"didUserHaveRole": hadRole,
+	}), nil
+}
+
+func (c *Core) getRolesForUser(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireQuery("userId")
+	if err != nil {
+		return nil, err
+	}
+	roles := append([]string{}, c.getRolesOfUsersInTenant(request.tenantId)[userId]...)
+	return okResponse(map[string]interface{}{
+		"roles": roles,
+	}), nil
+}
+
+func (c *Core) getUsersThatHaveRole(request *coreRequest) (interface{}, error) {
+	role, err := request.requireQuery("role")
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := c.roles[role]; !exists {
+		return statusResponse("UNKNOWN_ROLE_ERROR"), nil
+	}
+	users := []string{}
+	for userId, roles := range c.getRolesOfUsersInTenant(request.tenantId) {
+		if containsString(roles, role) {
+			users = append(users, userId)
+		}
+	}
+	sort.Strings(users)
+	return okResponse(map[string]interface{}{
+		"users": users,
+	}), nil
+}
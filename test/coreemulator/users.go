@@ -0,0 +1,466 @@
+/* Copyright (c) 2021, VRAI Labs and/or its affiliates. All rights reserved.
+ *
+ * This software is licensed under the Apache License, Version 2.0 (the
+ * "License") as published by the Apache Software Foundation.
+ *
+ * You may not use this file except in compliance with the License. You may
+ * obtain a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+ * WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+ * License for the specific language governing permissions and limitations
+ * under the License.
+ */
+
+package coreemulator
+
+import (
+	"encoding/base64"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recipeEmailPassword = "emailpassword"
+	recipeThirdParty    = "thirdparty"
+	recipePasswordless  = "passwordless"
+)
+
+type user struct {
+	id         string
+	recipeId   string
+	timeJoined uint64
+	tenantIds  []string
+
+	email       *string
+	phoneNumber *string
+
+	passwordHash string
+	passwordSalt string
+
+	thirdPartyId     string
+	thirdPartyUserId string
+}
+
+type passwordResetToken struct {
+	userId string
+	expiry time.Time
+}
+
+func (u *user) isInTenant(tenantId string) bool {
+	return containsString(u.tenantIds, tenantId)
+}
+
+func (u *user) setPassword(password string) {
+	u.passwordSalt = newToken()
+	u.passwordHash = hashHex(u.passwordSalt + password)
+}
+
+func (u *user) isPasswordCorrect(password string) bool {
+	return u.passwordHash != "" && hashHex(u.passwordSalt+password) == u.passwordHash
+}
+
+// userToJSON returns the user in the format of the recipe that it belongs to, using the external user id if it is mapped
+func (c *Core) userToJSON(u *user) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":         c.toExternalUserId(u.id),
+		"timeJoined": u.timeJoined,
+		"tenantIds":  append([]string{}, u.tenantIds...),
+	}
+	switch u.recipeId {
+	case recipeEmailPassword:
+		result["email"] = *u.email
+	case recipeThirdParty:
+		result["email"] = *u.email
+		result["thirdParty"] = map[string]interface{}{
+			"id":     u.thirdPartyId,
+			"userId": u.thirdPartyUserId,
+		}
+	case recipePasswordless:
+		if u.email != nil {
+			result["email"] = *u.email
+		}
+		if u.phoneNumber != nil {
+			result["phoneNumber"] = *u.phoneNumber
+		}
+	}
+	return result
+}
+
+func (c *Core) newUser(recipeId string, tenantId string) *user {
+	u := &user{
+		id:         newId(),
+		recipeId:   recipeId,
+		timeJoined: nowInMS(),
+		tenantIds:  []string{tenantId},
+	}
+	c.users[u.id] = u
+	return u
+}
+
+// getUserById accepts both SuperTokens and external user ids
+func (c *Core) getUserById(userId string) *user {
+	u, ok := c.users[c.toSuperTokensUserId(userId)]
+	if !ok {
+		return nil
+	}
+	return u
+}
+
+func (c *Core) findUser(matches func(u *user) bool) *user {
+	for _, u := range c.users {
+		if matches(u) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (c *Core) findUserByEmail(recipeId string, tenantId string, email string) *user {
+	return c.findUser(func(u *user) bool {
+		return u.recipeId == recipeId && u.isInTenant(tenantId) && u.email != nil && *u.email == email
+	})
+}
+
+func (c *Core) emailPasswordSignUp(request *coreRequest) (interface{}, error) {
+	email, err := request.requireString("email")
+	if err != nil {
+		return nil, err
+	}
+	password, err := request.requireString("password")
+	if err != nil {
+		return nil, err
+	}
+	email = normaliseEmail(email)
+	if c.findUserByEmail(recipeEmailPassword, request.tenantId, email) != nil {
+		return statusResponse("EMAIL_ALREADY_EXISTS_ERROR"), nil
+	}
+	u := c.newUser(recipeEmailPassword, request.tenantId)
+	u.email = &email
+	u.setPassword(password)
+	return okResponse(map[string]interface{}{
+		"user": c.userToJSON(u),
+	}), nil
+}
+
+func (c *Core) emailPasswordSignIn(request *coreRequest) (interface{}, error) {
+	email, err := request.requireString("email")
+	if err != nil {
+		return nil, err
+	}
+	password, err := request.requireString("password")
+	if err != nil {
+		return nil, err
+	}
+	u := c.findUserByEmail(recipeEmailPassword, request.tenantId, normaliseEmail(email))
+	if u == nil || !u.isPasswordCorrect(password) {
+		return statusResponse("WRONG_CREDENTIALS_ERROR"), nil
+	}
+	return okResponse(map[string]interface{}{
+		"user": c.userToJSON(u),
+	}), nil
+}
+
+func (c *Core) createPasswordResetToken(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	u := c.getUserById(userId)
+	if u == nil || u.recipeId != recipeEmailPassword || !u.isInTenant(request.tenantId) {
+		return statusResponse("UNKNOWN_USER_ID_ERROR"), nil
+	}
+	token := newToken()
+	c.passwordResetTokens[token] = passwordResetToken{
+		userId: u.id,
+		expiry: time.Now().Add(defaultTokenLifetime),
+	}
+	return okResponse(map[string]interface{}{
+		"token": token,
+	}), nil
+}
+
+func (c *Core) resetPassword(request *coreRequest) (interface{}, error) {
+	token, err := request.requireString("token")
+	if err != nil {
+		return nil, err
+	}
+	newPassword, err := request.requireString("newPassword")
+	if err != nil {
+		return nil, err
+	}
+	resetToken, ok := c.passwordResetTokens[token]
+	u := c.getUserById(resetToken.userId)
+	if !ok || resetToken.expiry.Before(time.Now()) || u == nil || !u.isInTenant(request.tenantId) {
+		return statusResponse("RESET_PASSWORD_INVALID_TOKEN_ERROR"), nil
+	}
+	// all the tokens of the user are invalidated once one of them is used
+	for t, other := range c.passwordResetTokens {
+		if other.userId == u.id {
+			delete(c.passwordResetTokens, t)
+		}
+	}
+	u.setPassword(newPassword)
+	return okResponse(map[string]interface{}{
+		"userId": c.toExternalUserId(u.id),
+	}), nil
+}
+
+// getUser handles the GET /recipe/user API of all the recipes, which is told apart using the rid header
+func (c *Core) getUser(request *coreRequest) (interface{}, error) {
+	recipeId := request.Header.Get("rid")
+	var u *user
+	notFoundStatus := "UNKNOWN_USER_ID_ERROR"
+
+	if userId := request.query("userId"); userId != "" {
+		u = c.getUserById(userId)
+	} else if email := request.query("email"); email != "" {
+		u = c.findUserByEmail(recipeId, request.tenantId, normaliseEmail(email))
+		notFoundStatus = "UNKNOWN_EMAIL_ERROR"
+	} else if phoneNumber := request.query("phoneNumber"); phoneNumber != "" {
+		u = c.findUser(func(u *user) bool {
+			return u.recipeId == recipeId && u.isInTenant(request.tenantId) && u.phoneNumber != nil && *u.phoneNumber == phoneNumber
+		})
+		notFoundStatus = "UNKNOWN_PHONE_NUMBER_ERROR"
+	} else if thirdPartyId := request.query("thirdPartyId"); thirdPartyId != "" {
+		thirdPartyUserId := request.query("thirdPartyUserId")
+		u = c.findUser(func(u *user) bool {
+			return u.recipeId == recipeId && u.isInTenant(request.tenantId) && u.thirdPartyId == thirdPartyId && u.thirdPartyUserId == thirdPartyUserId
+		})
+		notFoundStatus = "UNKNOWN_THIRD_PARTY_USER_ERROR"
+	} else {
+		return nil, badRequest("Please provide one of userId, email, phoneNumber or thirdPartyId")
+	}
+
+	if u == nil || (recipeId != "" && u.recipeId != recipeId) {
+		return statusResponse(notFoundStatus), nil
+	}
+	return okResponse(map[string]interface{}{
+		"user": c.userToJSON(u),
+	}), nil
+}
+
+// updateUser handles the PUT /recipe/user API of emailpassword and passwordless
+func (c *Core) updateUser(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	u := c.getUserById(userId)
+	if u == nil || u.recipeId != request.Header.Get("rid") {
+		return statusResponse("UNKNOWN_USER_ID_ERROR"), nil
+	}
+
+	// a field that is set to null removes the value (passwordless only)
+	email, emailGiven := request.body["email"]
+	phoneNumber, phoneNumberGiven := request.body["phoneNumber"]
+
+	if emailGiven && email != nil {
+		normalisedEmail := normaliseEmail(email.(string))
+		for _, tenantId := range u.tenantIds {
+			other := c.findUserByEmail(u.recipeId, tenantId, normalisedEmail)
+			if other != nil && other != u {
+				return statusResponse("EMAIL_ALREADY_EXISTS_ERROR"), nil
+			}
+		}
+	}
+	if phoneNumberGiven && phoneNumber != nil {
+		for _, tenantId := range u.tenantIds {
+			other := c.findUser(func(other *user) bool {
+				return other.recipeId == u.recipeId && other.isInTenant(tenantId) && other.phoneNumber != nil && *other.phoneNumber == phoneNumber.(string)
+			})
+			if other != nil && other != u {
+				return statusResponse("PHONE_NUMBER_ALREADY_EXISTS_ERROR"), nil
+			}
+		}
+	}
+
+	if emailGiven {
+		if email == nil {
+			u.email = nil
+		} else {
+			normalisedEmail := normaliseEmail(email.(string))
+			u.email = &normalisedEmail
+		}
+	}
+	if phoneNumberGiven {
+		if phoneNumber == nil {
+			u.phoneNumber = nil
+		} else {
+			value := phoneNumber.(string)
+			u.phoneNumber = &value
+		}
+	}
+	if password, ok := request.getString("password"); ok {
+		u.setPassword(password)
+	}
+	return statusResponse("OK"), nil
+}
+
+func (c *Core) thirdPartySignInUp(request *coreRequest) (interface{}, error) {
+	thirdPartyId, err := request.requireString("thirdPartyId")
+	if err != nil {
+		return nil, err
+	}
+	thirdPartyUserId, err := request.requireString("thirdPartyUserId")
+	if err != nil {
+		return nil, err
+	}
+	email, ok := request.getMap("email")["id"].(string)
+	if !ok {
+		return nil, badRequest("Field name 'email' is invalid in JSON input")
+	}
+	email = normaliseEmail(email)
+
+	createdNewUser := false
+	u := c.findUser(func(u *user) bool {
+		return u.recipeId == recipeThirdParty && u.isInTenant(request.tenantId) && u.thirdPartyId == thirdPartyId && u.thirdPartyUserId == thirdPartyUserId
+	})
+	if u == nil {
+		createdNewUser = true
+		u = c.newUser(recipeThirdParty, request.tenantId)
+		u.thirdPartyId = thirdPartyId
+		u.thirdPartyUserId = thirdPartyUserId
+	}
+	u.email = &email
+	return okResponse(map[string]interface{}{
+		"createdNewUser": createdNewUser,
+		"user":           c.userToJSON(u),
+	}), nil
+}
+
+func (c *Core) getThirdPartyUsersByEmail(request *coreRequest) (interface{}, error) {
+	email, err := request.requireQuery("email")
+	if err != nil {
+		return nil, err
+	}
+	email = normaliseEmail(email)
+	users := []map[string]interface{}{}
+	for _, u := range c.sortedUsers(false) {
+		if u.recipeId == recipeThirdParty && u.isInTenant(request.tenantId) && *u.email == email {
+			users = append(users, c.userToJSON(u))
+		}
+	}
+	return okResponse(map[string]interface{}{
+		"users": users,
+	}), nil
+}
+
+func (c *Core) sortedUsers(newestFirst bool) []*user {
+	users := []*user{}
+	for _, u := range c.users {
+		users = append(users, u)
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		if users[i].timeJoined == users[j].timeJoined {
+			return users[i].id < users[j].id
+		}
+		if newestFirst {
+			return users[i].timeJoined > users[j].timeJoined
+		}
+		return users[i].timeJoined < users[j].timeJoined
+	})
+	return users
+}
+
+func (c *Core) filterUsers(tenantId string, includeAllTenants bool, includeRecipeIds string, newestFirst bool) []*user {
+	recipeIds := []string{}
+	if includeRecipeIds != "" {
+		recipeIds = strings.Split(includeRecipeIds, ",")
+	}
+	result := []*user{}
+	for _, u := range c.sortedUsers(newestFirst) {
+		if !includeAllTenants && !u.isInTenant(tenantId) {
+			continue
+		}
+		if len(recipeIds) > 0 && !containsString(recipeIds, u.recipeId) {
+			continue
+		}
+		result = append(result, u)
+	}
+	return result
+}
+
+func (c *Core) getUsers(request *coreRequest) (interface{}, error) {
+	users := c.filterUsers(request.tenantId, false, request.query("includeRecipeIds"), request.query("timeJoinedOrder") == "DESC")
+
+	limit := 100
+	if limitStr := request.query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, badRequest("limit must a positive integer")
+		}
+	}
+	offset := 0
+	if paginationToken := request.query("paginationToken"); paginationToken != "" {
+		decoded, err := base64.StdEncoding.DecodeString(paginationToken)
+		if err == nil {
+			offset, err = strconv.Atoi(string(decoded))
+		}
+		if err != nil || offset < 0 || offset > len(users) {
+			return nil, badRequest("invalid pagination token")
+		}
+	}
+
+	end := offset + limit
+	if end > len(users) {
+		end = len(users)
+	}
+	result := []map[string]interface{}{}
+	for _, u := range users[offset:end] {
+		result = append(result, map[string]interface{}{
+			"recipeId": u.recipeId,
+			"user":     c.userToJSON(u),
+		})
+	}
+	response := okResponse(map[string]interface{}{
+		"users": result,
+	})
+	if end < len(users) {
+		response["nextPaginationToken"] = base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+	}
+	return response, nil
+}
+
+func (c *Core) getUserCount(request *coreRequest) (interface{}, error) {
+	users := c.filterUsers(request.tenantId, request.query("includeAllTenants") == "true", request.query("includeRecipeIds"), false)
+	return okResponse(map[string]interface{}{
+		"count": len(users),
+	}), nil
+}
+
+// deleteUser removes the user along with all of its data, like the core does
+func (c *Core) deleteUser(request *coreRequest) (interface{}, error) {
+	userId, err := request.requireString("userId")
+	if err != nil {
+		return nil, err
+	}
+	superTokensUserId := c.toSuperTokensUserId(userId)
+	externalUserId := c.toExternalUserId(superTokensUserId)
+	for _, id := range []string{superTokensUserId, externalUserId} {
+		for _, handle := range c.getSessionHandles(id, "", true) {
+			c.removeSession(handle)
+		}
+		delete(c.userMetadata, id)
+		for _, rolesOfUsers := range c.userRoles {
+			delete(rolesOfUsers, id)
+		}
+		for key := range c.verifiedEmails {
+			if strings.HasPrefix(key, id+"\n") {
+				delete(c.verifiedEmails, key)
+			}
+		}
+	}
+	delete(c.users, superTokensUserId)
+	c.removeUserIdMapping(superTokensUserId)
+	return statusResponse("OK"), nil
+}
+
+// normaliseEmail only trims the email, since the SDK takes care of normalising it before it is sent
+func normaliseEmail(email string) string {
+	return strings.TrimSpace(email)
+}